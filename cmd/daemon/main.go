@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/openkruise/kruise/pkg/daemon"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
+)
+
+var (
+	nodeName        = flag.String("node-name", os.Getenv("NODE_NAME"), "The name of the node that the daemon runs on, defaults to env NODE_NAME.")
+	runtimeEndpoint = flag.String("runtime-endpoint", "unix:///var/run/dockershim.sock", "The endpoint of the CRI image service.")
+	healthProbeAddr = flag.String("health-probe-addr", ":10221", "The address the healthz endpoint binds to.")
+	restConfigQPS   = flag.Int("rest-config-qps", 30, "QPS of rest config.")
+	restConfigBurst = flag.Int("rest-config-burst", 50, "Burst of rest config.")
+)
+
+func main() {
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		klog.Fatalf("Failed to get rest config: %v", err)
+	}
+	setRestConfig(cfg)
+
+	d, err := daemon.NewDaemon(cfg, *nodeName, *runtimeEndpoint, *healthProbeAddr)
+	if err != nil {
+		klog.Fatalf("Failed to new daemon: %v", err)
+	}
+
+	if err := d.Run(signals.SetupSignalHandler()); err != nil {
+		klog.Fatalf("Failed to run daemon: %v", err)
+	}
+}
+
+func setRestConfig(c *rest.Config) {
+	if *restConfigQPS > 0 {
+		c.QPS = float32(*restConfigQPS)
+	}
+	if *restConfigBurst > 0 {
+		c.Burst = *restConfigBurst
+	}
+}
@@ -51,6 +51,12 @@ type CloneSetSpec struct {
 	// Note that PVC will be deleted when its pod has been deleted.
 	VolumeClaimTemplates []v1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
 
+	// VolumeClaimUpdateStrategy indicates how to update the existing PVCs when the
+	// storage requests in volumeClaimTemplates are increased.
+	// Only the storage requests in volumeClaimTemplates can be changed, and can not be decreased.
+	// +optional
+	VolumeClaimUpdateStrategy *VolumeClaimUpdateStrategy `json:"volumeClaimUpdateStrategy,omitempty"`
+
 	// ScaleStrategy indicates the ScaleStrategy that will be employed to
 	// create and delete Pods in the CloneSet.
 	ScaleStrategy CloneSetScaleStrategy `json:"scaleStrategy,omitempty"`
@@ -69,6 +75,16 @@ type CloneSetSpec struct {
 	// without any of its container crashing, for it to be considered available.
 	// Defaults to 0 (pod will be considered available as soon as it is ready)
 	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+
+	// ProgressDeadlineSeconds is the maximum time in seconds for a CloneSet to make progress before it
+	// is considered to be failed. The CloneSet controller will continue to process failed CloneSets and
+	// a condition with a ProgressDeadlineExceeded reason will be surfaced in the CloneSet status.
+	// Note that progress will not be estimated during the time a CloneSet is paused.
+	// It is disabled if not specified.
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// Lifecycle defines the lifecycle hooks for Pods pre-delete and in-place update.
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
 }
 
 // CloneSetScaleStrategy defines strategies for pods scale.
@@ -76,6 +92,13 @@ type CloneSetScaleStrategy struct {
 	// PodsToDelete is the names of Pod should be deleted.
 	// Note that this list will be truncated for non-existing pod names.
 	PodsToDelete []string `json:"podsToDelete,omitempty"`
+	// DeletionPriority is the weight terms to choose pods to delete when scaling in.
+	// Pods with the larger sum of weights will be deleted first, after those unscheduled, pending or not-ready ones.
+	DeletionPriority []UpdatePriorityWeightTerm `json:"deletionPriority,omitempty"`
+	// TopologyKeys is the label keys of nodes, such as kubernetes.io/hostname and topology.kubernetes.io/zone.
+	// If it is set, pods will be deleted from the topology domain which has the most pods first when scaling in,
+	// so that the pods left are spread across topology domains.
+	TopologyKeys []string `json:"topologyKeys,omitempty"`
 }
 
 // CloneSetUpdateStrategy defines strategies for pods update.
@@ -111,6 +134,50 @@ type CloneSetUpdateStrategy struct {
 	ScatterStrategy CloneSetUpdateScatterStrategy `json:"scatterStrategy,omitempty"`
 	// InPlaceUpdateStrategy contains strategies for in-place update.
 	InPlaceUpdateStrategy *InPlaceUpdateStrategy `json:"inPlaceUpdateStrategy,omitempty"`
+	// ImagePreDownload indicates the strategy to pre-download images of the update revision on the nodes
+	// hosting pods that are waiting to be updated in-place. It is disabled if not specified.
+	ImagePreDownload *ImagePreDownloadStrategy `json:"imagePreDownload,omitempty"`
+	// CanarySteps defines the steps of canary update. If it is set, the controller moves the partition
+	// down step by step, so that the number of updated pods reaches the replicas of each step in order.
+	// The partition of each step works together with the partition above, and the larger one takes effect.
+	CanarySteps []CloneSetCanaryStep `json:"canarySteps,omitempty"`
+	// AutoRollback indicates the controller should roll back the template to the current revision,
+	// if the update revision has failed to progress before progressDeadlineSeconds.
+	// Default value is false.
+	AutoRollback bool `json:"autoRollback,omitempty"`
+}
+
+// CloneSetCanaryStep defines a step of canary update.
+type CloneSetCanaryStep struct {
+	// Replicas is the number of pods that should be updated in this step.
+	// Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%).
+	Replicas intstr.IntOrString `json:"replicas"`
+	// Pause indicates the update should be paused after pods in this step have been updated and ready.
+	// If it is not set, the update goes to next step without pausing.
+	Pause *CloneSetCanaryPause `json:"pause,omitempty"`
+}
+
+// CloneSetCanaryPause defines the pause of a canary step.
+type CloneSetCanaryPause struct {
+	// Duration is how long the update should be paused for.
+	// If it is not set, the update is paused until the user resumes it by setting the
+	// apps.kruise.io/canary-resume-step annotation of CloneSet to {updateRevision}/{index of this step}.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+const (
+	// CloneSetCanaryResumeStepAnnotationKey is the annotation of CloneSet to resume the canary update
+	// paused in a step. Its value is {revision}/{step index}, and it only resumes the step of that update revision,
+	// so that it will not resume the steps of the next revision by mistake.
+	CloneSetCanaryResumeStepAnnotationKey = "apps.kruise.io/canary-resume-step"
+)
+
+// ImagePreDownloadStrategy defines the strategy to pre-download images before pods are updated in-place.
+type ImagePreDownloadStrategy struct {
+	// Parallelism is the number of nodes that pull images at the same time.
+	// Value can be an absolute number (ex: 5) or a percentage of nodes (ex: 10%).
+	// Defaults to 1.
+	Parallelism *intstr.IntOrString `json:"parallelism,omitempty"`
 }
 
 // CloneSetUpdateStrategyType defines strategies for pods in-place update.
@@ -157,7 +224,8 @@ type CloneSetStatus struct {
 	// ReadyReplicas is the number of Pods created by the CloneSet controller that have a Ready Condition.
 	ReadyReplicas int32 `json:"readyReplicas"`
 
-	// AvailableReplicas is the number of Pods created by the CloneSet controller that have a Ready Condition for at least minReadySeconds.
+	// AvailableReplicas is the number of Pods created by the CloneSet controller that have a Ready Condition for at least minReadySeconds,
+	// excluding Pods that are not in Normal lifecycle state.
 	AvailableReplicas int32 `json:"availableReplicas"`
 
 	// UpdatedReplicas is the number of Pods created by the CloneSet controller from the CloneSet version
@@ -165,17 +233,40 @@ type CloneSetStatus struct {
 	UpdatedReplicas int32 `json:"updatedReplicas"`
 
 	// UpdatedReadyReplicas is the number of Pods created by the CloneSet controller from the CloneSet version
-	// indicated by updateRevision and have a Ready Condition.
+	// indicated by updateRevision and have a Ready Condition, excluding Pods that are not in Normal lifecycle state.
 	UpdatedReadyReplicas int32 `json:"updatedReadyReplicas"`
 
+	// CurrentRevision, if not empty, indicates the stable revision of the CloneSet, which all pods
+	// have been updated to and been ready in.
+	CurrentRevision string `json:"currentRevision,omitempty"`
+
 	// UpdateRevision, if not empty, indicates the latest revision of the CloneSet.
 	UpdateRevision string `json:"updateRevision,omitempty"`
 
+	// LastRolledBackRevision, if not empty, indicates the last update revision which has been rolled back
+	// by the CloneSet controller because it failed to progress before the deadline.
+	LastRolledBackRevision string `json:"lastRolledBackRevision,omitempty"`
+
+	// LifecycleStateReplicas is the number of Pods created by the CloneSet controller in each lifecycle state,
+	// excluding the Normal state.
+	LifecycleStateReplicas map[LifecycleStateType]int32 `json:"lifecycleStateReplicas,omitempty"`
+
 	// CollisionCount is the count of hash collisions for the CloneSet. The CloneSet controller
 	// uses this field as a collision avoidance mechanism when it needs to create the name for the
 	// newest ControllerRevision.
 	CollisionCount *int32 `json:"collisionCount,omitempty"`
 
+	// InPlaceUpdateFallbackCount is the count of Pods that have been recreated by the CloneSet controller
+	// because their in-place update did not complete before the deadline.
+	InPlaceUpdateFallbackCount int32 `json:"inPlaceUpdateFallbackCount,omitempty"`
+
+	// VolumeClaimUpdatedReplicas is the number of Pods whose PVCs have been expanded to
+	// the storage requests in volumeClaimTemplates.
+	VolumeClaimUpdatedReplicas int32 `json:"volumeClaimUpdatedReplicas,omitempty"`
+
+	// CanaryStatus is the state of canary update of the update revision, only if canarySteps set.
+	CanaryStatus *CloneSetCanaryStatus `json:"canaryStatus,omitempty"`
+
 	// Conditions represents the latest available observations of a CloneSet's current state.
 	Conditions []CloneSetCondition `json:"conditions,omitempty"`
 
@@ -183,6 +274,30 @@ type CloneSetStatus struct {
 	LabelSelector string `json:"labelSelector,omitempty"`
 }
 
+// CloneSetCanaryStatus defines the state of canary update.
+type CloneSetCanaryStatus struct {
+	// Revision is the update revision which the canary update is for.
+	Revision string `json:"revision"`
+	// CurrentStep is the index of the current step in canarySteps, beginning from 0.
+	CurrentStep int32 `json:"currentStep"`
+	// State is the state of the current step.
+	State CloneSetCanaryStepState `json:"state"`
+	// PauseStartTime is the time when the current step began to be paused.
+	PauseStartTime *metav1.Time `json:"pauseStartTime,omitempty"`
+}
+
+// CloneSetCanaryStepState is the state of a canary step.
+type CloneSetCanaryStepState string
+
+const (
+	// CanaryStepStateUpgrading indicates pods in the current step are being updated.
+	CanaryStepStateUpgrading CloneSetCanaryStepState = "StepUpgrading"
+	// CanaryStepStatePaused indicates pods in the current step have been updated and the update is paused.
+	CanaryStepStatePaused CloneSetCanaryStepState = "StepPaused"
+	// CanaryStepStateCompleted indicates all the steps have been completed.
+	CanaryStepStateCompleted CloneSetCanaryStepState = "Completed"
+)
+
 // CloneSetConditionType is type for CloneSet conditions.
 type CloneSetConditionType string
 
@@ -191,6 +306,15 @@ const (
 	CloneSetConditionFailedScale CloneSetConditionType = "FailedScale"
 	// CloneSetConditionFailedUpdate indicates cloneset controller failed to update pods.
 	CloneSetConditionFailedUpdate CloneSetConditionType = "FailedUpdate"
+	// CloneSetConditionImagePreDownload indicates the progress of pre-downloading images of the update revision.
+	CloneSetConditionImagePreDownload CloneSetConditionType = "ImagePreDownload"
+	// CloneSetConditionProgressing indicates whether the CloneSet is progressing to its desired state.
+	CloneSetConditionProgressing CloneSetConditionType = WorkloadConditionProgressing
+	// CloneSetConditionAvailable indicates whether the CloneSet has minimum available pods,
+	// which is replicas minus maxUnavailable.
+	CloneSetConditionAvailable CloneSetConditionType = WorkloadConditionAvailable
+	// CloneSetConditionReplicaFailure indicates cloneset controller failed to scale or update pods.
+	CloneSetConditionReplicaFailure CloneSetConditionType = WorkloadConditionReplicaFailure
 )
 
 // CloneSetCondition describes the state of a CloneSet at a certain point.
@@ -199,6 +323,8 @@ type CloneSetCondition struct {
 	Type CloneSetConditionType `json:"type"`
 	// Status of the condition, one of True, False, Unknown.
 	Status v1.ConditionStatus `json:"status"`
+	// The last time this condition was updated.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
 	// Last time the condition transitioned from one status to another.
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 	// The reason for the condition's last transition.
@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ContainerRecreateRequestNodeNameKey is the label of ContainerRecreateRequest, which indicates the node
+	// that the Pod is running on. It is set by the controller and used by kruise-daemon to watch requests of its node.
+	ContainerRecreateRequestNodeNameKey = "crr.apps.kruise.io/node-name"
+	// ContainerRecreateRequestPodUIDKey is the label of ContainerRecreateRequest, which indicates the UID of the Pod.
+	ContainerRecreateRequestPodUIDKey = "crr.apps.kruise.io/pod-uid"
+
+	// ContainerRecreatingKey is the annotation of Pod, which indicates some containers of the Pod are being recreated
+	// by the ContainerRecreateRequest in value. Workload controllers will treat the Pod as not ready.
+	ContainerRecreatingKey = "apps.kruise.io/container-recreating"
+)
+
+// ContainerRecreateRequestSpec defines the desired state of ContainerRecreateRequest
+type ContainerRecreateRequestSpec struct {
+	// PodName is name of the Pod that owns the recreated containers.
+	PodName string `json:"podName"`
+	// Containers contains the containers that need to recreate in the Pod.
+	Containers []ContainerRecreateRequestContainer `json:"containers"`
+	// Strategy defines strategies for containers recreation.
+	// +optional
+	Strategy *ContainerRecreateRequestStrategy `json:"strategy,omitempty"`
+	// ActiveDeadlineSeconds is the deadline duration of this ContainerRecreateRequest.
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// TTLSecondsAfterFinished is the TTL duration after this ContainerRecreateRequest has completed.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// ContainerRecreateRequestContainer defines the container that need to recreate.
+type ContainerRecreateRequestContainer struct {
+	// Name of the container that need to recreate.
+	// It must be existing in the real pod.Spec.Containers.
+	Name string `json:"name"`
+	// PreStop is the handler executed before the container is stopped.
+	// Only exec and httpGet are supported.
+	// +optional
+	PreStop *v1.Handler `json:"preStop,omitempty"`
+	// StatusContext is synced from the real Pod status when the ContainerRecreateRequest is accepted by controller.
+	// Populated by the system.
+	// Read-only.
+	// +optional
+	StatusContext *ContainerRecreateRequestContainerContext `json:"statusContext,omitempty"`
+}
+
+// ContainerRecreateRequestContainerContext contains context status of the container that need to recreate.
+type ContainerRecreateRequestContainerContext struct {
+	// Container's ID in the format 'docker://<container_id>'.
+	ContainerID string `json:"containerID"`
+	// The number of times the container has been restarted.
+	RestartCount int32 `json:"restartCount"`
+}
+
+// ContainerRecreateRequestStrategy contains the strategies for containers recreation.
+type ContainerRecreateRequestStrategy struct {
+	// FailurePolicy decides whether to continue if one container fails to recreate.
+	// Default is Fail.
+	// +optional
+	FailurePolicy ContainerRecreateRequestFailurePolicyType `json:"failurePolicy,omitempty"`
+	// OrderedRecreate indicates whether to recreate the next container only if the previous one has recreated completely.
+	// Default is false, which means all containers are recreated at the same time.
+	// +optional
+	OrderedRecreate bool `json:"orderedRecreate,omitempty"`
+	// TerminationGracePeriodSeconds is the optional duration in seconds to wait the container terminating gracefully.
+	// Value must be non-negative integer. The value zero indicates delete immediately.
+	// If this value is nil, we will use pod.Spec.TerminationGracePeriodSeconds as default value.
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+}
+
+// ContainerRecreateRequestFailurePolicyType is the type of failure policy.
+type ContainerRecreateRequestFailurePolicyType string
+
+const (
+	// ContainerRecreateRequestFailurePolicyFail indicates to stop recreating other containers and mark the request failed.
+	ContainerRecreateRequestFailurePolicyFail ContainerRecreateRequestFailurePolicyType = "Fail"
+	// ContainerRecreateRequestFailurePolicyIgnore indicates to continue recreating other containers.
+	ContainerRecreateRequestFailurePolicyIgnore ContainerRecreateRequestFailurePolicyType = "Ignore"
+)
+
+// ContainerRecreateRequestStatus defines the observed state of ContainerRecreateRequest
+type ContainerRecreateRequestStatus struct {
+	// Phase of this ContainerRecreateRequest, e.g. Pending, Recreating, Completed
+	Phase ContainerRecreateRequestPhase `json:"phase"`
+	// Represents time when the ContainerRecreateRequest was completed. It is not guaranteed to
+	// be set in happens-before order across separate operations.
+	// It is represented in RFC3339 form and is in UTC.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// A human readable message indicating details about this ContainerRecreateRequest.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// ContainerRecreateStates contains the recreation states of the containers.
+	// +optional
+	ContainerRecreateStates []ContainerRecreateRequestContainerRecreateState `json:"containerRecreateStates,omitempty"`
+}
+
+// ContainerRecreateRequestPhase is the phase of ContainerRecreateRequest.
+type ContainerRecreateRequestPhase string
+
+const (
+	// ContainerRecreateRequestPending indicates the request has not been started by kruise-daemon.
+	ContainerRecreateRequestPending ContainerRecreateRequestPhase = "Pending"
+	// ContainerRecreateRequestRecreating indicates the containers are being recreated.
+	ContainerRecreateRequestRecreating ContainerRecreateRequestPhase = "Recreating"
+	// ContainerRecreateRequestSucceeded indicates all the containers have been recreated.
+	ContainerRecreateRequestSucceeded ContainerRecreateRequestPhase = "Succeeded"
+	// ContainerRecreateRequestFailed indicates the request has failed.
+	ContainerRecreateRequestFailed ContainerRecreateRequestPhase = "Failed"
+)
+
+// ContainerRecreateRequestContainerRecreateState contains the recreation state of the container.
+type ContainerRecreateRequestContainerRecreateState struct {
+	// Name of the container.
+	Name string `json:"name"`
+	// Phase indicates the recreation phase of the container.
+	Phase ContainerRecreateRequestContainerPhase `json:"phase"`
+	// A human readable message indicating details about this state.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ContainerRecreateRequestContainerPhase is the phase of a container to recreate.
+type ContainerRecreateRequestContainerPhase string
+
+const (
+	// ContainerRecreatePending indicates the container has not been stopped.
+	ContainerRecreatePending ContainerRecreateRequestContainerPhase = "Pending"
+	// ContainerRecreateRecreating indicates the container has been stopped and is waiting to be started by kubelet.
+	ContainerRecreateRecreating ContainerRecreateRequestContainerPhase = "Recreating"
+	// ContainerRecreateSucceeded indicates the container has been recreated.
+	ContainerRecreateSucceeded ContainerRecreateRequestContainerPhase = "Succeeded"
+	// ContainerRecreateFailed indicates the container failed to be recreated.
+	ContainerRecreateFailed ContainerRecreateRequestContainerPhase = "Failed"
+)
+
+// IsContainerRecreateRequestCompleted returns whether the ContainerRecreateRequest has completed.
+func IsContainerRecreateRequestCompleted(crr *ContainerRecreateRequest) bool {
+	return crr.Status.Phase == ContainerRecreateRequestSucceeded || crr.Status.Phase == ContainerRecreateRequestFailed
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ContainerRecreateRequest is the Schema for the containerrecreaterequests API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=crr
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.phase",description="Phase of this ContainerRecreateRequest."
+// +kubebuilder:printcolumn:name="POD",type="string",JSONPath=".spec.podName",description="Pod name of this ContainerRecreateRequest."
+// +kubebuilder:printcolumn:name="NODE",type="string",JSONPath=".metadata.labels.crr\\.apps\\.kruise\\.io/node-name",description="Node name of this ContainerRecreateRequest."
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp",description="CreationTimestamp is a timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+type ContainerRecreateRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ContainerRecreateRequestSpec   `json:"spec,omitempty"`
+	Status ContainerRecreateRequestStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ContainerRecreateRequestList contains a list of ContainerRecreateRequest
+type ContainerRecreateRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ContainerRecreateRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ContainerRecreateRequest{}, &ContainerRecreateRequestList{})
+}
@@ -108,6 +108,25 @@ type RollingUpdateDaemonSet struct {
 	// times during the update.
 	// +optional
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty" protobuf:"bytes,7,opt,name=maxSurge"`
+
+	// Only when type=InplaceRollingUpdateType, it works.
+	// InPlaceUpdateStrategy contains strategies for in-place update.
+	// +optional
+	InPlaceUpdateStrategy *InPlaceUpdateStrategy `json:"inPlaceUpdateStrategy,omitempty" protobuf:"bytes,8,opt,name=inPlaceUpdateStrategy"`
+
+	// TopologyKey is the key of node labels, and nodes with the same value of this label are in
+	// the same failure domain, such as topology.kubernetes.io/zone.
+	// If it is set, the DaemonSet pods are updated one failure domain at a time. MaxUnavailable is
+	// calculated within the domain being updated, and the next domain is not updated until all the
+	// pods in the current domain are updated and available.
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty" protobuf:"bytes,9,opt,name=topologyKey"`
+
+	// TopologyDomainOrder is the order of failure domains to update, which are the values of TopologyKey.
+	// Domains not in the list are updated afterwards in alphabetical order, and nodes without
+	// TopologyKey label are updated at last.
+	// +optional
+	TopologyDomainOrder []string `json:"topologyDomainOrder,omitempty" protobuf:"bytes,10,rep,name=topologyDomainOrder"`
 }
 
 // DaemonSetSpec defines the desired state of DaemonSet
@@ -207,11 +226,33 @@ type DaemonSetStatus struct {
 
 	// DaemonSetHash is the controller-revision-hash, which represents the latest version of the DaemonSet.
 	DaemonSetHash string `json:"daemonSetHash" protobuf:"bytes,11,opt,name=daemonSetHash"`
+
+	// The count of daemon pods that have been recreated because their in-place update
+	// did not complete before the deadline.
+	// +optional
+	InPlaceUpdateFallbackCount int32 `json:"inPlaceUpdateFallbackCount,omitempty" protobuf:"varint,12,opt,name=inPlaceUpdateFallbackCount"`
+
+	// UpdatingTopologyDomain is the failure domain being updated, when rollingUpdate.topologyKey is set.
+	// +optional
+	UpdatingTopologyDomain string `json:"updatingTopologyDomain,omitempty" protobuf:"bytes,13,opt,name=updatingTopologyDomain"`
+
+	// The number of nodes that should be running the daemon pod, but the daemon pod
+	// has been marked unschedulable by kube-scheduler.
+	// +optional
+	NumberUnschedulable int32 `json:"numberUnschedulable,omitempty" protobuf:"varint,14,opt,name=numberUnschedulable"`
 }
 
 type DaemonSetConditionType string
 
-// TODO: Add valid condition types of a DaemonSet.
+const (
+	// DaemonSetConditionProgressing indicates whether the DaemonSet is progressing to its update revision.
+	DaemonSetConditionProgressing DaemonSetConditionType = WorkloadConditionProgressing
+	// DaemonSetConditionAvailable indicates whether the DaemonSet has minimum available daemon pods,
+	// which is the desired number minus maxUnavailable.
+	DaemonSetConditionAvailable DaemonSetConditionType = WorkloadConditionAvailable
+	// DaemonSetConditionReplicaFailure indicates daemon pods of the DaemonSet are unschedulable.
+	DaemonSetConditionReplicaFailure DaemonSetConditionType = WorkloadConditionReplicaFailure
+)
 
 // DaemonSetCondition describes the state of a DaemonSet at a certain point.
 type DaemonSetCondition struct {
@@ -219,6 +260,9 @@ type DaemonSetCondition struct {
 	Type DaemonSetConditionType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=DaemonSetConditionType"`
 	// Status of the condition, one of True, False, Unknown.
 	Status corev1.ConditionStatus `json:"status" protobuf:"bytes,2,opt,name=status,casttype=k8s.io/api/core/v1.ConditionStatus"`
+	// The last time this condition was updated.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty" protobuf:"bytes,6,opt,name=lastUpdateTime"`
 	// Last time the condition transitioned from one status to another.
 	// +optional
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty" protobuf:"bytes,3,opt,name=lastTransitionTime"`
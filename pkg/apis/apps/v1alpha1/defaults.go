@@ -33,6 +33,9 @@ func addDefaultingFuncs(scheme *runtime.Scheme) error {
 func SetDefaults_SidecarSet(obj *SidecarSet) {
 	setSidecarSetUpdateStratety(&obj.Spec.Strategy)
 
+	for i := range obj.Spec.InitContainers {
+		setSidecarDefaultContainer(&obj.Spec.InitContainers[i])
+	}
 	for i := range obj.Spec.Containers {
 		setSidecarDefaultContainer(&obj.Spec.Containers[i])
 	}
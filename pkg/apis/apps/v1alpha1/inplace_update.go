@@ -61,4 +61,8 @@ type InPlaceUpdateStrategy struct {
 	// GracePeriodSeconds is the timespan between set Pod status to not-ready and update images in Pod spec
 	// when in-place update a Pod.
 	GracePeriodSeconds int32 `json:"gracePeriodSeconds,omitempty"`
+	// TimeoutSeconds is the deadline for a Pod to complete in-place update after its images have been updated
+	// in Pod spec. If the new containers have not been started when the deadline passes, the Pod will be recreated.
+	// Defaults to 0, which means no deadline.
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
 }
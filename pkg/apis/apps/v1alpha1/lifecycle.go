@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// LifecycleStateKey is the label key of the lifecycle state of Pod.
+	LifecycleStateKey = "lifecycle.apps.kruise.io/state"
+	// LifecycleTimestampKey is the annotation key of the time when Pod moved into its current lifecycle state.
+	LifecycleTimestampKey = "lifecycle.apps.kruise.io/timestamp"
+)
+
+// LifecycleStateType is the state of Pod in its lifecycle.
+type LifecycleStateType string
+
+const (
+	// LifecycleStateNormal indicates the Pod is running normally and has not been chosen to delete or update.
+	LifecycleStateNormal LifecycleStateType = "Normal"
+	// LifecycleStatePreparingUpdate indicates the Pod is waiting for its inPlaceUpdate hook to be removed
+	// before it is updated in-place.
+	LifecycleStatePreparingUpdate LifecycleStateType = "PreparingUpdate"
+	// LifecycleStateUpdating indicates the Pod is being updated in-place.
+	LifecycleStateUpdating LifecycleStateType = "Updating"
+	// LifecycleStateUpdated indicates the in-place update of the Pod has completed, and the Pod is waiting for
+	// its inPlaceUpdate hook to be added back.
+	LifecycleStateUpdated LifecycleStateType = "Updated"
+	// LifecycleStatePreparingDelete indicates the Pod is waiting for its preDelete hook to be removed
+	// before it is deleted.
+	LifecycleStatePreparingDelete LifecycleStateType = "PreparingDelete"
+)
+
+// Lifecycle contains the hooks for Pod lifecycle.
+type Lifecycle struct {
+	// PreDelete is the hook before Pod to be deleted.
+	// The Pod will be moved into PreparingDelete state and will not be deleted until
+	// all labels and finalizers in this hook have been removed from it.
+	PreDelete *LifecycleHook `json:"preDelete,omitempty"`
+	// InPlaceUpdate is the hook before Pod to update and after Pod has been updated.
+	// The Pod will be moved into PreparingUpdate state and will not be updated until all labels and
+	// finalizers in this hook have been removed from it. After in-place update completed, the Pod stays
+	// in Updated state until the hook has been added back.
+	InPlaceUpdate *LifecycleHook `json:"inPlaceUpdate,omitempty"`
+}
+
+// LifecycleHook contains the labels and finalizers that hook a Pod.
+// A Pod is considered to be hooked if it has any of these labels or finalizers.
+type LifecycleHook struct {
+	// LabelsHandler is the labels that should be removed from Pod to unhook it.
+	LabelsHandler map[string]string `json:"labelsHandler,omitempty"`
+	// FinalizersHandler is the finalizers that should be removed from Pod to unhook it.
+	FinalizersHandler []string `json:"finalizersHandler,omitempty"`
+}
@@ -130,10 +130,6 @@ type ImageTagStatus struct {
 	// Represents the image pulling task phase.
 	Phase ImagePullPhase `json:"phase"`
 
-	// Represents the pulling progress of this tag, which is beetween 0-100. There is no guarantee
-	// of monotonic consistency, and it may be a rollback due to retry during pulling.
-	Progress int32 `json:"progress,omitempty"`
-
 	// Represents time when the pulling task was acknowledged by the image puller.
 	// It is not guaranteed to be set in happens-before order across separate operations.
 	// It is represented in RFC3339 form and is in UTC.
@@ -28,76 +28,98 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.AdvancedStatefulSetTemplateSpec":  schema_pkg_apis_apps_v1alpha1_AdvancedStatefulSetTemplateSpec(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.BroadcastJob":                     schema_pkg_apis_apps_v1alpha1_BroadcastJob(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.BroadcastJobList":                 schema_pkg_apis_apps_v1alpha1_BroadcastJobList(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.BroadcastJobSpec":                 schema_pkg_apis_apps_v1alpha1_BroadcastJobSpec(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.BroadcastJobStatus":               schema_pkg_apis_apps_v1alpha1_BroadcastJobStatus(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSet":                         schema_pkg_apis_apps_v1alpha1_CloneSet(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCondition":                schema_pkg_apis_apps_v1alpha1_CloneSetCondition(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetList":                     schema_pkg_apis_apps_v1alpha1_CloneSetList(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetScaleStrategy":            schema_pkg_apis_apps_v1alpha1_CloneSetScaleStrategy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetSpec":                     schema_pkg_apis_apps_v1alpha1_CloneSetSpec(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetStatus":                   schema_pkg_apis_apps_v1alpha1_CloneSetStatus(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetUpdateScatterTerm":        schema_pkg_apis_apps_v1alpha1_CloneSetUpdateScatterTerm(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetUpdateStrategy":           schema_pkg_apis_apps_v1alpha1_CloneSetUpdateStrategy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CompletionPolicy":                 schema_pkg_apis_apps_v1alpha1_CompletionPolicy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.DaemonSet":                        schema_pkg_apis_apps_v1alpha1_DaemonSet(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.DaemonSetCondition":               schema_pkg_apis_apps_v1alpha1_DaemonSetCondition(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.DaemonSetList":                    schema_pkg_apis_apps_v1alpha1_DaemonSetList(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.DaemonSetSpec":                    schema_pkg_apis_apps_v1alpha1_DaemonSetSpec(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.DaemonSetStatus":                  schema_pkg_apis_apps_v1alpha1_DaemonSetStatus(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.DaemonSetUpdateStrategy":          schema_pkg_apis_apps_v1alpha1_DaemonSetUpdateStrategy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.FailurePolicy":                    schema_pkg_apis_apps_v1alpha1_FailurePolicy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImagePullJob":                     schema_pkg_apis_apps_v1alpha1_ImagePullJob(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImagePullJobList":                 schema_pkg_apis_apps_v1alpha1_ImagePullJobList(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImagePullJobSpec":                 schema_pkg_apis_apps_v1alpha1_ImagePullJobSpec(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImagePullJobStatus":               schema_pkg_apis_apps_v1alpha1_ImagePullJobStatus(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImageSpec":                        schema_pkg_apis_apps_v1alpha1_ImageSpec(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImageStatus":                      schema_pkg_apis_apps_v1alpha1_ImageStatus(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImageTagPullPolicy":               schema_pkg_apis_apps_v1alpha1_ImageTagPullPolicy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImageTagSpec":                     schema_pkg_apis_apps_v1alpha1_ImageTagSpec(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImageTagStatus":                   schema_pkg_apis_apps_v1alpha1_ImageTagStatus(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.InPlaceUpdateContainerStatus":     schema_pkg_apis_apps_v1alpha1_InPlaceUpdateContainerStatus(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.InPlaceUpdateState":               schema_pkg_apis_apps_v1alpha1_InPlaceUpdateState(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.InPlaceUpdateStrategy":            schema_pkg_apis_apps_v1alpha1_InPlaceUpdateStrategy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.JobCondition":                     schema_pkg_apis_apps_v1alpha1_JobCondition(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ManualUpdate":                     schema_pkg_apis_apps_v1alpha1_ManualUpdate(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.NodeImage":                        schema_pkg_apis_apps_v1alpha1_NodeImage(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.NodeImageList":                    schema_pkg_apis_apps_v1alpha1_NodeImageList(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.NodeImageSpec":                    schema_pkg_apis_apps_v1alpha1_NodeImageSpec(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.NodeImageStatus":                  schema_pkg_apis_apps_v1alpha1_NodeImageStatus(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.NodeSelector":                     schema_pkg_apis_apps_v1alpha1_NodeSelector(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.PullPolicy":                       schema_pkg_apis_apps_v1alpha1_PullPolicy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ReferenceObject":                  schema_pkg_apis_apps_v1alpha1_ReferenceObject(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.RollingUpdateSidecarSet":          schema_pkg_apis_apps_v1alpha1_RollingUpdateSidecarSet(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.RollingUpdateStatefulSetStrategy": schema_pkg_apis_apps_v1alpha1_RollingUpdateStatefulSetStrategy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarContainer":                 schema_pkg_apis_apps_v1alpha1_SidecarContainer(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSet":                       schema_pkg_apis_apps_v1alpha1_SidecarSet(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSetList":                   schema_pkg_apis_apps_v1alpha1_SidecarSetList(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSetSpec":                   schema_pkg_apis_apps_v1alpha1_SidecarSetSpec(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSetStatus":                 schema_pkg_apis_apps_v1alpha1_SidecarSetStatus(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSetUpdateStrategy":         schema_pkg_apis_apps_v1alpha1_SidecarSetUpdateStrategy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSet":                      schema_pkg_apis_apps_v1alpha1_StatefulSet(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetList":                  schema_pkg_apis_apps_v1alpha1_StatefulSetList(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetSpec":                  schema_pkg_apis_apps_v1alpha1_StatefulSetSpec(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetStatus":                schema_pkg_apis_apps_v1alpha1_StatefulSetStatus(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetTemplateSpec":          schema_pkg_apis_apps_v1alpha1_StatefulSetTemplateSpec(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetUpdateStrategy":        schema_pkg_apis_apps_v1alpha1_StatefulSetUpdateStrategy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.Subset":                           schema_pkg_apis_apps_v1alpha1_Subset(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SubsetTemplate":                   schema_pkg_apis_apps_v1alpha1_SubsetTemplate(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.Topology":                         schema_pkg_apis_apps_v1alpha1_Topology(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnitedDeployment":                 schema_pkg_apis_apps_v1alpha1_UnitedDeployment(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnitedDeploymentCondition":        schema_pkg_apis_apps_v1alpha1_UnitedDeploymentCondition(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnitedDeploymentList":             schema_pkg_apis_apps_v1alpha1_UnitedDeploymentList(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnitedDeploymentSpec":             schema_pkg_apis_apps_v1alpha1_UnitedDeploymentSpec(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnitedDeploymentStatus":           schema_pkg_apis_apps_v1alpha1_UnitedDeploymentStatus(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnitedDeploymentUpdateStrategy":   schema_pkg_apis_apps_v1alpha1_UnitedDeploymentUpdateStrategy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnorderedUpdateStrategy":          schema_pkg_apis_apps_v1alpha1_UnorderedUpdateStrategy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdatePriorityOrderTerm":          schema_pkg_apis_apps_v1alpha1_UpdatePriorityOrderTerm(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdatePriorityStrategy":           schema_pkg_apis_apps_v1alpha1_UpdatePriorityStrategy(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdatePriorityWeightTerm":         schema_pkg_apis_apps_v1alpha1_UpdatePriorityWeightTerm(ref),
-		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdateStatus":                     schema_pkg_apis_apps_v1alpha1_UpdateStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.AdvancedStatefulSetTemplateSpec":                 schema_pkg_apis_apps_v1alpha1_AdvancedStatefulSetTemplateSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.BroadcastJob":                                    schema_pkg_apis_apps_v1alpha1_BroadcastJob(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.BroadcastJobList":                                schema_pkg_apis_apps_v1alpha1_BroadcastJobList(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.BroadcastJobSpec":                                schema_pkg_apis_apps_v1alpha1_BroadcastJobSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.BroadcastJobStatus":                              schema_pkg_apis_apps_v1alpha1_BroadcastJobStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSet":                                        schema_pkg_apis_apps_v1alpha1_CloneSet(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCanaryPause":                             schema_pkg_apis_apps_v1alpha1_CloneSetCanaryPause(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCanaryStatus":                            schema_pkg_apis_apps_v1alpha1_CloneSetCanaryStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCanaryStep":                              schema_pkg_apis_apps_v1alpha1_CloneSetCanaryStep(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCondition":                               schema_pkg_apis_apps_v1alpha1_CloneSetCondition(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetList":                                    schema_pkg_apis_apps_v1alpha1_CloneSetList(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetScaleStrategy":                           schema_pkg_apis_apps_v1alpha1_CloneSetScaleStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetSpec":                                    schema_pkg_apis_apps_v1alpha1_CloneSetSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetStatus":                                  schema_pkg_apis_apps_v1alpha1_CloneSetStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetUpdateScatterTerm":                       schema_pkg_apis_apps_v1alpha1_CloneSetUpdateScatterTerm(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetUpdateStrategy":                          schema_pkg_apis_apps_v1alpha1_CloneSetUpdateStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CompletionPolicy":                                schema_pkg_apis_apps_v1alpha1_CompletionPolicy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequest":                        schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequest(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestContainer":               schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestContainer(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestContainerContext":        schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestContainerContext(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestContainerRecreateState":  schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestContainerRecreateState(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestList":                    schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestList(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestSpec":                    schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestStatus":                  schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestStrategy":                schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.DaemonSet":                                       schema_pkg_apis_apps_v1alpha1_DaemonSet(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.DaemonSetCondition":                              schema_pkg_apis_apps_v1alpha1_DaemonSetCondition(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.DaemonSetList":                                   schema_pkg_apis_apps_v1alpha1_DaemonSetList(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.DaemonSetSpec":                                   schema_pkg_apis_apps_v1alpha1_DaemonSetSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.DaemonSetStatus":                                 schema_pkg_apis_apps_v1alpha1_DaemonSetStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.DaemonSetUpdateStrategy":                         schema_pkg_apis_apps_v1alpha1_DaemonSetUpdateStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.FailurePolicy":                                   schema_pkg_apis_apps_v1alpha1_FailurePolicy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImagePreDownloadStrategy":                        schema_pkg_apis_apps_v1alpha1_ImagePreDownloadStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImagePullJob":                                    schema_pkg_apis_apps_v1alpha1_ImagePullJob(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImagePullJobList":                                schema_pkg_apis_apps_v1alpha1_ImagePullJobList(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImagePullJobSpec":                                schema_pkg_apis_apps_v1alpha1_ImagePullJobSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImagePullJobStatus":                              schema_pkg_apis_apps_v1alpha1_ImagePullJobStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImageSpec":                                       schema_pkg_apis_apps_v1alpha1_ImageSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImageStatus":                                     schema_pkg_apis_apps_v1alpha1_ImageStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImageTagPullPolicy":                              schema_pkg_apis_apps_v1alpha1_ImageTagPullPolicy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImageTagSpec":                                    schema_pkg_apis_apps_v1alpha1_ImageTagSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImageTagStatus":                                  schema_pkg_apis_apps_v1alpha1_ImageTagStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.InPlaceUpdateContainerStatus":                    schema_pkg_apis_apps_v1alpha1_InPlaceUpdateContainerStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.InPlaceUpdateState":                              schema_pkg_apis_apps_v1alpha1_InPlaceUpdateState(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.InPlaceUpdateStrategy":                           schema_pkg_apis_apps_v1alpha1_InPlaceUpdateStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.JobCondition":                                    schema_pkg_apis_apps_v1alpha1_JobCondition(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.Lifecycle":                                       schema_pkg_apis_apps_v1alpha1_Lifecycle(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.LifecycleHook":                                   schema_pkg_apis_apps_v1alpha1_LifecycleHook(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ManualUpdate":                                    schema_pkg_apis_apps_v1alpha1_ManualUpdate(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.NodeImage":                                       schema_pkg_apis_apps_v1alpha1_NodeImage(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.NodeImageList":                                   schema_pkg_apis_apps_v1alpha1_NodeImageList(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.NodeImageSpec":                                   schema_pkg_apis_apps_v1alpha1_NodeImageSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.NodeImageStatus":                                 schema_pkg_apis_apps_v1alpha1_NodeImageStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.NodeSelector":                                    schema_pkg_apis_apps_v1alpha1_NodeSelector(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.PullPolicy":                                      schema_pkg_apis_apps_v1alpha1_PullPolicy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ReferenceObject":                                 schema_pkg_apis_apps_v1alpha1_ReferenceObject(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.RollingUpdateDaemonSet":                          schema_pkg_apis_apps_v1alpha1_RollingUpdateDaemonSet(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.RollingUpdateSidecarSet":                         schema_pkg_apis_apps_v1alpha1_RollingUpdateSidecarSet(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.RollingUpdateStatefulSetStrategy":                schema_pkg_apis_apps_v1alpha1_RollingUpdateStatefulSetStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarContainer":                                schema_pkg_apis_apps_v1alpha1_SidecarContainer(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarContainerUpgradeStrategy":                 schema_pkg_apis_apps_v1alpha1_SidecarContainerUpgradeStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSet":                                      schema_pkg_apis_apps_v1alpha1_SidecarSet(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSetCondition":                             schema_pkg_apis_apps_v1alpha1_SidecarSetCondition(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSetList":                                  schema_pkg_apis_apps_v1alpha1_SidecarSetList(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSetSpec":                                  schema_pkg_apis_apps_v1alpha1_SidecarSetSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSetStatus":                                schema_pkg_apis_apps_v1alpha1_SidecarSetStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSetUpdateStrategy":                        schema_pkg_apis_apps_v1alpha1_SidecarSetUpdateStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSet":                                     schema_pkg_apis_apps_v1alpha1_StatefulSet(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetList":                                 schema_pkg_apis_apps_v1alpha1_StatefulSetList(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetOrdinals":                             schema_pkg_apis_apps_v1alpha1_StatefulSetOrdinals(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy": schema_pkg_apis_apps_v1alpha1_StatefulSetPersistentVolumeClaimRetentionPolicy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetPodOverride":                          schema_pkg_apis_apps_v1alpha1_StatefulSetPodOverride(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetScaleStrategy":                        schema_pkg_apis_apps_v1alpha1_StatefulSetScaleStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetSpec":                                 schema_pkg_apis_apps_v1alpha1_StatefulSetSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetStatus":                               schema_pkg_apis_apps_v1alpha1_StatefulSetStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetTemplateSpec":                         schema_pkg_apis_apps_v1alpha1_StatefulSetTemplateSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetUpdateStrategy":                       schema_pkg_apis_apps_v1alpha1_StatefulSetUpdateStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.Subset":                                          schema_pkg_apis_apps_v1alpha1_Subset(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SubsetTemplate":                                  schema_pkg_apis_apps_v1alpha1_SubsetTemplate(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.Topology":                                        schema_pkg_apis_apps_v1alpha1_Topology(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnitedDeployment":                                schema_pkg_apis_apps_v1alpha1_UnitedDeployment(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnitedDeploymentCondition":                       schema_pkg_apis_apps_v1alpha1_UnitedDeploymentCondition(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnitedDeploymentList":                            schema_pkg_apis_apps_v1alpha1_UnitedDeploymentList(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnitedDeploymentSpec":                            schema_pkg_apis_apps_v1alpha1_UnitedDeploymentSpec(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnitedDeploymentStatus":                          schema_pkg_apis_apps_v1alpha1_UnitedDeploymentStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnitedDeploymentUpdateStrategy":                  schema_pkg_apis_apps_v1alpha1_UnitedDeploymentUpdateStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UnorderedUpdateStrategy":                         schema_pkg_apis_apps_v1alpha1_UnorderedUpdateStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdatePriorityOrderTerm":                         schema_pkg_apis_apps_v1alpha1_UpdatePriorityOrderTerm(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdatePriorityStrategy":                          schema_pkg_apis_apps_v1alpha1_UpdatePriorityStrategy(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdatePriorityWeightTerm":                        schema_pkg_apis_apps_v1alpha1_UpdatePriorityWeightTerm(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdateStatus":                                    schema_pkg_apis_apps_v1alpha1_UpdateStatus(ref),
+		"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.VolumeClaimUpdateStrategy":                       schema_pkg_apis_apps_v1alpha1_VolumeClaimUpdateStrategy(ref),
 	}
 }
 
@@ -390,6 +412,98 @@ func schema_pkg_apis_apps_v1alpha1_CloneSet(ref common.ReferenceCallback) common
 	}
 }
 
+func schema_pkg_apis_apps_v1alpha1_CloneSetCanaryPause(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CloneSetCanaryPause defines the pause of a canary step.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"duration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Duration is how long the update should be paused for. If it is not set, the update is paused until the user resumes it by setting the apps.kruise.io/canary-resume-step annotation of CloneSet to {updateRevision}/{index of this step}.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_CloneSetCanaryStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CloneSetCanaryStatus defines the state of canary update.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"revision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Revision is the update revision which the canary update is for.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"currentStep": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CurrentStep is the index of the current step in canarySteps, beginning from 0.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State is the state of the current step.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pauseStartTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PauseStartTime is the time when the current step began to be paused.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"revision", "currentStep", "state"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_CloneSetCanaryStep(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CloneSetCanaryStep defines a step of canary update.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"replicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Replicas is the number of pods that should be updated in this step. Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%).",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+					"pause": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Pause indicates the update should be paused after pods in this step have been updated and ready. If it is not set, the update goes to next step without pausing.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCanaryPause"),
+						},
+					},
+				},
+				Required: []string{"replicas"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCanaryPause", "k8s.io/apimachinery/pkg/util/intstr.IntOrString"},
+	}
+}
+
 func schema_pkg_apis_apps_v1alpha1_CloneSetCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -411,6 +525,12 @@ func schema_pkg_apis_apps_v1alpha1_CloneSetCondition(ref common.ReferenceCallbac
 							Format:      "",
 						},
 					},
+					"lastUpdateTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The last time this condition was updated.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
 					"lastTransitionTime": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Last time the condition transitioned from one status to another.",
@@ -508,9 +628,38 @@ func schema_pkg_apis_apps_v1alpha1_CloneSetScaleStrategy(ref common.ReferenceCal
 							},
 						},
 					},
+					"deletionPriority": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionPriority is the weight terms to choose pods to delete when scaling in. Pods with the larger sum of weights will be deleted first, after those unscheduled, pending or not-ready ones.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdatePriorityWeightTerm"),
+									},
+								},
+							},
+						},
+					},
+					"topologyKeys": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TopologyKeys is the label keys of nodes, such as kubernetes.io/hostname and topology.kubernetes.io/zone. If it is set, pods will be deleted from the topology domain which has the most pods first when scaling in, so that the pods left are spread across topology domains.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdatePriorityWeightTerm"},
 	}
 }
 
@@ -553,6 +702,12 @@ func schema_pkg_apis_apps_v1alpha1_CloneSetSpec(ref common.ReferenceCallback) co
 							},
 						},
 					},
+					"volumeClaimUpdateStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VolumeClaimUpdateStrategy indicates how to update the existing PVCs when the storage requests in volumeClaimTemplates are increased. Only the storage requests in volumeClaimTemplates can be changed, and can not be decreased.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.VolumeClaimUpdateStrategy"),
+						},
+					},
 					"scaleStrategy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "ScaleStrategy indicates the ScaleStrategy that will be employed to create and delete Pods in the CloneSet.",
@@ -579,12 +734,25 @@ func schema_pkg_apis_apps_v1alpha1_CloneSetSpec(ref common.ReferenceCallback) co
 							Format:      "int32",
 						},
 					},
+					"progressDeadlineSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ProgressDeadlineSeconds is the maximum time in seconds for a CloneSet to make progress before it is considered to be failed. The CloneSet controller will continue to process failed CloneSets and a condition with a ProgressDeadlineExceeded reason will be surfaced in the CloneSet status. Note that progress will not be estimated during the time a CloneSet is paused. It is disabled if not specified.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"lifecycle": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Lifecycle defines the lifecycle hooks for Pods pre-delete and in-place update.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.Lifecycle"),
+						},
+					},
 				},
 				Required: []string{"replicas", "selector", "template"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetScaleStrategy", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetUpdateStrategy", "k8s.io/api/core/v1.PersistentVolumeClaim", "k8s.io/api/core/v1.PodTemplateSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetScaleStrategy", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetUpdateStrategy", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.Lifecycle", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.VolumeClaimUpdateStrategy", "k8s.io/api/core/v1.PersistentVolumeClaim", "k8s.io/api/core/v1.PodTemplateSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
 	}
 }
 
@@ -618,7 +786,7 @@ func schema_pkg_apis_apps_v1alpha1_CloneSetStatus(ref common.ReferenceCallback)
 					},
 					"availableReplicas": {
 						SchemaProps: spec.SchemaProps{
-							Description: "AvailableReplicas is the number of Pods created by the CloneSet controller that have a Ready Condition for at least minReadySeconds.",
+							Description: "AvailableReplicas is the number of Pods created by the CloneSet controller that have a Ready Condition for at least minReadySeconds, excluding Pods that are not in Normal lifecycle state.",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
@@ -632,11 +800,18 @@ func schema_pkg_apis_apps_v1alpha1_CloneSetStatus(ref common.ReferenceCallback)
 					},
 					"updatedReadyReplicas": {
 						SchemaProps: spec.SchemaProps{
-							Description: "UpdatedReadyReplicas is the number of Pods created by the CloneSet controller from the CloneSet version indicated by updateRevision and have a Ready Condition.",
+							Description: "UpdatedReadyReplicas is the number of Pods created by the CloneSet controller from the CloneSet version indicated by updateRevision and have a Ready Condition, excluding Pods that are not in Normal lifecycle state.",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
+					"currentRevision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CurrentRevision, if not empty, indicates the stable revision of the CloneSet, which all pods have been updated to and been ready in.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"updateRevision": {
 						SchemaProps: spec.SchemaProps{
 							Description: "UpdateRevision, if not empty, indicates the latest revision of the CloneSet.",
@@ -644,6 +819,28 @@ func schema_pkg_apis_apps_v1alpha1_CloneSetStatus(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"lastRolledBackRevision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastRolledBackRevision, if not empty, indicates the last update revision which has been rolled back by the CloneSet controller because it failed to progress before the deadline.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lifecycleStateReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LifecycleStateReplicas is the number of Pods created by the CloneSet controller in each lifecycle state, excluding the Normal state.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"integer"},
+										Format: "int32",
+									},
+								},
+							},
+						},
+					},
 					"collisionCount": {
 						SchemaProps: spec.SchemaProps{
 							Description: "CollisionCount is the count of hash collisions for the CloneSet. The CloneSet controller uses this field as a collision avoidance mechanism when it needs to create the name for the newest ControllerRevision.",
@@ -651,6 +848,26 @@ func schema_pkg_apis_apps_v1alpha1_CloneSetStatus(ref common.ReferenceCallback)
 							Format:      "int32",
 						},
 					},
+					"inPlaceUpdateFallbackCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InPlaceUpdateFallbackCount is the count of Pods that have been recreated by the CloneSet controller because their in-place update did not complete before the deadline.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"volumeClaimUpdatedReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VolumeClaimUpdatedReplicas is the number of Pods whose PVCs have been expanded to the storage requests in volumeClaimTemplates.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"canaryStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CanaryStatus is the state of canary update of the update revision, only if canarySteps set.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCanaryStatus"),
+						},
+					},
 					"conditions": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Conditions represents the latest available observations of a CloneSet's current state.",
@@ -676,7 +893,7 @@ func schema_pkg_apis_apps_v1alpha1_CloneSetStatus(ref common.ReferenceCallback)
 			},
 		},
 		Dependencies: []string{
-			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCondition"},
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCanaryStatus", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCondition"},
 	}
 }
 
@@ -705,105 +922,459 @@ func schema_pkg_apis_apps_v1alpha1_CloneSetUpdateScatterTerm(ref common.Referenc
 	}
 }
 
-func schema_pkg_apis_apps_v1alpha1_CloneSetUpdateStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_apps_v1alpha1_CloneSetUpdateStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CloneSetUpdateStrategy defines strategies for pods update.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type indicates the type of the CloneSetUpdateStrategy. Default is ReCreate.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"partition": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Partition is the desired number of pods in old revisions. It means when partition is set during pods updating, (replicas - partition) number of pods will be updated. Default value is 0.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"maxUnavailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The maximum number of pods that can be unavailable during the update. Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%). Absolute number is calculated from percentage by rounding up. Defaults to 20%.",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+					"maxSurge": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The maximum number of pods that can be scheduled above the desired replicas during the update. Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%). Absolute number is calculated from percentage by rounding up. Defaults to 0.",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+					"paused": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Paused indicates that the CloneSet is paused. Default value is false",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"priorityStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Priorities are the rules for calculating the priority of updating pods. Each pod to be updated, will pass through these terms and get a sum of weights.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdatePriorityStrategy"),
+						},
+					},
+					"scatterStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ScatterStrategy defines the scatter rules to make pods been scattered when update. This will avoid pods with the same key-value to be updated in one batch. - Note that pods will be scattered after priority sort. So, although priority strategy and scatter strategy can be applied together, we suggest to use either one of them. - If scatterStrategy is used, we suggest to just use one term. Otherwise, the update order can be hard to understand.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetUpdateScatterTerm"),
+									},
+								},
+							},
+						},
+					},
+					"inPlaceUpdateStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InPlaceUpdateStrategy contains strategies for in-place update.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.InPlaceUpdateStrategy"),
+						},
+					},
+					"imagePreDownload": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImagePreDownload indicates the strategy to pre-download images of the update revision on the nodes hosting pods that are waiting to be updated in-place. It is disabled if not specified.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImagePreDownloadStrategy"),
+						},
+					},
+					"canarySteps": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CanarySteps defines the steps of canary update. If it is set, the controller moves the partition down step by step, so that the number of updated pods reaches the replicas of each step in order. The partition of each step works together with the partition above, and the larger one takes effect.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCanaryStep"),
+									},
+								},
+							},
+						},
+					},
+					"autoRollback": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AutoRollback indicates the controller should roll back the template to the current revision, if the update revision has failed to progress before progressDeadlineSeconds. Default value is false.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetCanaryStep", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetUpdateScatterTerm", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ImagePreDownloadStrategy", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.InPlaceUpdateStrategy", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdatePriorityStrategy", "k8s.io/apimachinery/pkg/util/intstr.IntOrString"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_CompletionPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CompletionPolicy indicates the completion policy for the job",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type indicates the type of the CompletionPolicy Default is Always",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"activeDeadlineSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ActiveDeadlineSeconds specifies the duration in seconds relative to the startTime that the job may be active before the system tries to terminate it; value must be positive integer. Only works for Always type.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"ttlSecondsAfterFinished": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ttlSecondsAfterFinished limits the lifetime of a Job that has finished execution (either Complete or Failed). If this field is set, ttlSecondsAfterFinished after the Job finishes, it is eligible to be automatically deleted. When the Job is being deleted, its lifecycle guarantees (e.g. finalizers) will be honored. If this field is unset, the Job won't be automatically deleted. If this field is set to zero, the Job becomes eligible to be deleted immediately after it finishes. This field is alpha-level and is only honored by servers that enable the TTLAfterFinished feature. Only works for Always type",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ContainerRecreateRequest is the Schema for the containerrecreaterequests API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestSpec", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestContainer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ContainerRecreateRequestContainer defines the container that need to recreate.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the container that need to recreate. It must be existing in the real pod.Spec.Containers.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"preStop": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PreStop is the handler executed before the container is stopped. Only exec and httpGet are supported.",
+							Ref:         ref("k8s.io/api/core/v1.Handler"),
+						},
+					},
+					"statusContext": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StatusContext is synced from the real Pod status when the ContainerRecreateRequest is accepted by controller. Populated by the system. Read-only.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestContainerContext"),
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestContainerContext", "k8s.io/api/core/v1.Handler"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestContainerContext(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ContainerRecreateRequestContainerContext contains context status of the container that need to recreate.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"containerID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Container's ID in the format 'docker://<container_id>'.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"restartCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The number of times the container has been restarted.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"containerID", "restartCount"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestContainerRecreateState(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ContainerRecreateRequestContainerRecreateState contains the recreation state of the container.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the container.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase indicates the recreation phase of the container.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A human readable message indicating details about this state.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "phase"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ContainerRecreateRequestList contains a list of ContainerRecreateRequest",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequest"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequest", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ContainerRecreateRequestSpec defines the desired state of ContainerRecreateRequest",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"podName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodName is name of the Pod that owns the recreated containers.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"containers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Containers contains the containers that need to recreate in the Pod.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestContainer"),
+									},
+								},
+							},
+						},
+					},
+					"strategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Strategy defines strategies for containers recreation.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestStrategy"),
+						},
+					},
+					"activeDeadlineSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ActiveDeadlineSeconds is the deadline duration of this ContainerRecreateRequest.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"ttlSecondsAfterFinished": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TTLSecondsAfterFinished is the TTL duration after this ContainerRecreateRequest has completed.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"podName", "containers"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestContainer", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestStrategy"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CloneSetUpdateStrategy defines strategies for pods update.",
+				Description: "ContainerRecreateRequestStatus defines the observed state of ContainerRecreateRequest",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"type": {
+					"phase": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Type indicates the type of the CloneSetUpdateStrategy. Default is ReCreate.",
+							Description: "Phase of this ContainerRecreateRequest, e.g. Pending, Recreating, Completed",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"partition": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Partition is the desired number of pods in old revisions. It means when partition is set during pods updating, (replicas - partition) number of pods will be updated. Default value is 0.",
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
-					"maxUnavailable": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The maximum number of pods that can be unavailable during the update. Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%). Absolute number is calculated from percentage by rounding up. Defaults to 20%.",
-							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
-						},
-					},
-					"maxSurge": {
+					"completionTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The maximum number of pods that can be scheduled above the desired replicas during the update. Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%). Absolute number is calculated from percentage by rounding up. Defaults to 0.",
-							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+							Description: "Represents time when the ContainerRecreateRequest was completed. It is not guaranteed to be set in happens-before order across separate operations. It is represented in RFC3339 form and is in UTC.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"paused": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Paused indicates that the CloneSet is paused. Default value is false",
-							Type:        []string{"boolean"},
+							Description: "A human readable message indicating details about this ContainerRecreateRequest.",
+							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"priorityStrategy": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Priorities are the rules for calculating the priority of updating pods. Each pod to be updated, will pass through these terms and get a sum of weights.",
-							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdatePriorityStrategy"),
-						},
-					},
-					"scatterStrategy": {
+					"containerRecreateStates": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ScatterStrategy defines the scatter rules to make pods been scattered when update. This will avoid pods with the same key-value to be updated in one batch. - Note that pods will be scattered after priority sort. So, although priority strategy and scatter strategy can be applied together, we suggest to use either one of them. - If scatterStrategy is used, we suggest to just use one term. Otherwise, the update order can be hard to understand.",
+							Description: "ContainerRecreateStates contains the recreation states of the containers.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetUpdateScatterTerm"),
+										Ref: ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestContainerRecreateState"),
 									},
 								},
 							},
 						},
 					},
-					"inPlaceUpdateStrategy": {
-						SchemaProps: spec.SchemaProps{
-							Description: "InPlaceUpdateStrategy contains strategies for in-place update.",
-							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.InPlaceUpdateStrategy"),
-						},
-					},
 				},
+				Required: []string{"phase"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.CloneSetUpdateScatterTerm", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.InPlaceUpdateStrategy", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.UpdatePriorityStrategy", "k8s.io/apimachinery/pkg/util/intstr.IntOrString"},
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.ContainerRecreateRequestContainerRecreateState", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_apps_v1alpha1_CompletionPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_apps_v1alpha1_ContainerRecreateRequestStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "CompletionPolicy indicates the completion policy for the job",
+				Description: "ContainerRecreateRequestStrategy contains the strategies for containers recreation.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"type": {
+					"failurePolicy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Type indicates the type of the CompletionPolicy Default is Always",
+							Description: "FailurePolicy decides whether to continue if one container fails to recreate. Default is Fail.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"activeDeadlineSeconds": {
+					"orderedRecreate": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ActiveDeadlineSeconds specifies the duration in seconds relative to the startTime that the job may be active before the system tries to terminate it; value must be positive integer. Only works for Always type.",
-							Type:        []string{"integer"},
-							Format:      "int64",
+							Description: "OrderedRecreate indicates whether to recreate the next container only if the previous one has recreated completely. Default is false, which means all containers are recreated at the same time.",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"ttlSecondsAfterFinished": {
+					"terminationGracePeriodSeconds": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ttlSecondsAfterFinished limits the lifetime of a Job that has finished execution (either Complete or Failed). If this field is set, ttlSecondsAfterFinished after the Job finishes, it is eligible to be automatically deleted. When the Job is being deleted, its lifecycle guarantees (e.g. finalizers) will be honored. If this field is unset, the Job won't be automatically deleted. If this field is set to zero, the Job becomes eligible to be deleted immediately after it finishes. This field is alpha-level and is only honored by servers that enable the TTLAfterFinished feature. Only works for Always type",
+							Description: "TerminationGracePeriodSeconds is the optional duration in seconds to wait the container terminating gracefully. Value must be non-negative integer. The value zero indicates delete immediately. If this value is nil, we will use pod.Spec.TerminationGracePeriodSeconds as default value.",
 							Type:        []string{"integer"},
-							Format:      "int32",
+							Format:      "int64",
 						},
 					},
 				},
@@ -877,6 +1448,12 @@ func schema_pkg_apis_apps_v1alpha1_DaemonSetCondition(ref common.ReferenceCallba
 							Format:      "",
 						},
 					},
+					"lastUpdateTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The last time this condition was updated.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
 					"lastTransitionTime": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Last time the condition transitioned from one status to another.",
@@ -1103,6 +1680,27 @@ func schema_pkg_apis_apps_v1alpha1_DaemonSetStatus(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"inPlaceUpdateFallbackCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The count of daemon pods that have been recreated because their in-place update did not complete before the deadline.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"updatingTopologyDomain": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UpdatingTopologyDomain is the failure domain being updated, when rollingUpdate.topologyKey is set.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"numberUnschedulable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The number of nodes that should be running the daemon pod, but the daemon pod has been marked unschedulable by kube-scheduler.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 				},
 				Required: []string{"currentNumberScheduled", "numberMisscheduled", "desiredNumberScheduled", "numberReady", "updatedNumberScheduled", "daemonSetHash"},
 			},
@@ -1167,6 +1765,27 @@ func schema_pkg_apis_apps_v1alpha1_FailurePolicy(ref common.ReferenceCallback) c
 	}
 }
 
+func schema_pkg_apis_apps_v1alpha1_ImagePreDownloadStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImagePreDownloadStrategy defines the strategy to pre-download images before pods are updated in-place.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"parallelism": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Parallelism is the number of nodes that pull images at the same time. Value can be an absolute number (ex: 5) or a percentage of nodes (ex: 10%). Defaults to 1.",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/util/intstr.IntOrString"},
+	}
+}
+
 func schema_pkg_apis_apps_v1alpha1_ImagePullJob(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -1563,13 +2182,6 @@ func schema_pkg_apis_apps_v1alpha1_ImageTagStatus(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
-					"progress": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Represents the pulling progress of this tag, which is beetween 0-100. There is no guarantee of monotonic consistency, and it may be a rollback due to retry during pulling.",
-							Type:        []string{"integer"},
-							Format:      "int32",
-						},
-					},
 					"startTime": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Represents time when the pulling task was acknowledged by the image puller. It is not guaranteed to be set in happens-before order across separate operations. It is represented in RFC3339 form and is in UTC.",
@@ -1674,6 +2286,13 @@ func schema_pkg_apis_apps_v1alpha1_InPlaceUpdateStrategy(ref common.ReferenceCal
 							Format:      "int32",
 						},
 					},
+					"timeoutSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TimeoutSeconds is the deadline for a Pod to complete in-place update after its images have been updated in Pod spec. If the new containers have not been started when the deadline passes, the Pod will be recreated. Defaults to 0, which means no deadline.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 				},
 			},
 		},
@@ -1736,6 +2355,75 @@ func schema_pkg_apis_apps_v1alpha1_JobCondition(ref common.ReferenceCallback) co
 	}
 }
 
+func schema_pkg_apis_apps_v1alpha1_Lifecycle(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Lifecycle contains the hooks for Pod lifecycle.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"preDelete": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PreDelete is the hook before Pod to be deleted. The Pod will be moved into PreparingDelete state and will not be deleted until all labels and finalizers in this hook have been removed from it.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.LifecycleHook"),
+						},
+					},
+					"inPlaceUpdate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InPlaceUpdate is the hook before Pod to update and after Pod has been updated. The Pod will be moved into PreparingUpdate state and will not be updated until all labels and finalizers in this hook have been removed from it. After in-place update completed, the Pod stays in Updated state until the hook has been added back.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.LifecycleHook"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.LifecycleHook"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_LifecycleHook(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LifecycleHook contains the labels and finalizers that hook a Pod. A Pod is considered to be hooked if it has any of these labels or finalizers.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"labelsHandler": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LabelsHandler is the labels that should be removed from Pod to unhook it.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"finalizersHandler": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FinalizersHandler is the finalizers that should be removed from Pod to unhook it.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_apps_v1alpha1_ManualUpdate(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2058,6 +2746,87 @@ func schema_pkg_apis_apps_v1alpha1_ReferenceObject(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_apps_v1alpha1_RollingUpdateDaemonSet(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Spec to control the desired behavior of daemon set rolling update.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"rollingUpdateType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type is to specify which kind of rollingUpdate.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maxUnavailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The maximum number of DaemonSet pods that can be unavailable during the update. Value can be an absolute number (ex: 5) or a percentage of total number of DaemonSet pods at the start of the update (ex: 10%). Absolute number is calculated from percentage by rounding up. This cannot be 0. Default value is 1. Example: when this is set to 30%, at most 30% of the total number of nodes that should be running the daemon pod (i.e. status.desiredNumberScheduled) can have their pods stopped for an update at any given time. The update starts by stopping at most 30% of those DaemonSet pods and then brings up new DaemonSet pods in their place. Once the new pods are available, it then proceeds onto other DaemonSet pods, thus ensuring that at least 70% of original number of DaemonSet pods are available at all times during the update.",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+					"selector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A label query over nodes that are managed by the daemon set RollingUpdate. Must match in order to be controlled. It must match the node's labels.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"partition": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The number of DaemonSet pods remained to be old version. Default value is 0. Maximum value is status.DesiredNumberScheduled, which means no pod will be updated.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"paused": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Indicates that the daemon set is paused and will not be processed by the daemon set controller.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"maxSurge": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Only when type=SurgingRollingUpdateType, it works. The maximum number of DaemonSet pods that can be scheduled above the desired number of pods during the update. Value can be an absolute number (ex: 5) or a percentage of the total number of DaemonSet pods at the start of the update (ex: 10%). The absolute number is calculated from the percentage by rounding up. This cannot be 0. The default value is 1. Example: when this is set to 30%, at most 30% of the total number of nodes that should be running the daemon pod (i.e. status.desiredNumberScheduled) can have 2 pods running at any given time. The update starts by starting replacements for at most 30% of those DaemonSet pods. Once the new pods are available it then stops the existing pods before proceeding onto other DaemonSet pods, thus ensuring that at most 130% of the desired final number of DaemonSet  pods are running at all times during the update.",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+					"inPlaceUpdateStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Only when type=InplaceRollingUpdateType, it works. InPlaceUpdateStrategy contains strategies for in-place update.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.InPlaceUpdateStrategy"),
+						},
+					},
+					"topologyKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TopologyKey is the key of node labels, and nodes with the same value of this label are in the same failure domain, such as topology.kubernetes.io/zone. If it is set, the DaemonSet pods are updated one failure domain at a time. MaxUnavailable is calculated within the domain being updated, and the next domain is not updated until all the pods in the current domain are updated and available.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"topologyDomainOrder": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TopologyDomainOrder is the order of failure domains to update, which are the values of TopologyKey. Domains not in the list are updated afterwards in alphabetical order, and nodes without TopologyKey label are updated at last.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.InPlaceUpdateStrategy", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "k8s.io/apimachinery/pkg/util/intstr.IntOrString"},
+	}
+}
+
 func schema_pkg_apis_apps_v1alpha1_RollingUpdateSidecarSet(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2141,15 +2910,55 @@ func schema_pkg_apis_apps_v1alpha1_SidecarContainer(ref common.ReferenceCallback
 				Properties: map[string]spec.Schema{
 					"Container": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/api/core/v1.Container"),
+							Ref: ref("k8s.io/api/core/v1.Container"),
+						},
+					},
+					"podInjectPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodInjectPolicy decides whether the sidecar container is injected before or after the app containers of pod. Defaults to AfterAppContainer, and it is ignored for init containers.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"upgradeStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UpgradeStrategy decides how the sidecar container is upgraded in the existing pods. Defaults to ColdUpgrade, and it is ignored for init containers.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarContainerUpgradeStrategy"),
+						},
+					},
+				},
+				Required: []string{"Container"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarContainerUpgradeStrategy", "k8s.io/api/core/v1.Container"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_SidecarContainerUpgradeStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SidecarContainerUpgradeStrategy defines the upgrade strategy of a sidecar container.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"upgradeType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UpgradeType is ColdUpgrade or HotUpgrade. Defaults to ColdUpgrade.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"hotUpgradeEmptyImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HotUpgradeEmptyImage is the image run by the idle container of a HotUpgrade sidecar. It should be able to run with the same configuration as the sidecar container, e.g. the command and probes, and do nothing. It is required for HotUpgrade.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"Container"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/api/core/v1.Container"},
 	}
 }
 
@@ -2197,6 +3006,62 @@ func schema_pkg_apis_apps_v1alpha1_SidecarSet(ref common.ReferenceCallback) comm
 	}
 }
 
+func schema_pkg_apis_apps_v1alpha1_SidecarSetCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SidecarSetCondition describes the state of a SidecarSet at a certain point.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of SidecarSet condition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status of the condition, one of True, False, Unknown.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastUpdateTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The last time this condition was updated.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Last time the condition transitioned from one status to another.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The reason for the condition's last transition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A human readable message indicating details about the transition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"type", "status"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_apps_v1alpha1_SidecarSetList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2257,6 +3122,32 @@ func schema_pkg_apis_apps_v1alpha1_SidecarSetSpec(ref common.ReferenceCallback)
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
 						},
 					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace sidecarSet will only match the pods in the namespace, otherwise match pods in all namespaces (in cluster).",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespaceSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NamespaceSelector is a label query over the namespaces whose pods should be injected. It can not be set together with Namespace.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"initContainers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InitContainers is the list of init containers to be injected into the selected pod, after the init containers of pod and in the order listed. They are only injected when pod creates, and never updated in existing pods.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarContainer"),
+									},
+								},
+							},
+						},
+					},
 					"containers": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Containers is the list of sidecar containers to be injected into the selected pod",
@@ -2339,10 +3230,25 @@ func schema_pkg_apis_apps_v1alpha1_SidecarSetStatus(ref common.ReferenceCallback
 							Format:      "int32",
 						},
 					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Represents the latest available observations of a SidecarSet's current state.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSetCondition"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"matchedPods", "updatedPods", "readyPods"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.SidecarSetCondition"},
 	}
 }
 
@@ -2457,6 +3363,103 @@ func schema_pkg_apis_apps_v1alpha1_StatefulSetList(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_apps_v1alpha1_StatefulSetOrdinals(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StatefulSetOrdinals describes the policy used for replica ordinal assignment in this StatefulSet.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"start": {
+						SchemaProps: spec.SchemaProps{
+							Description: "start is the number representing the first replica's index. It may be used to number replicas from an alternate index (eg: 1-indexed) over the default 0-indexed names, or to orchestrate progressive movement of replicas from one StatefulSet to another. If set, replica indices will be in the range:\n  [.spec.ordinals.start, .spec.ordinals.start + .spec.replicas).\nIf unset, defaults to 0. Replica indices will be in the range:\n  [0, .spec.replicas).\nOrdinals in reserveOrdinals are skipped and replaced by the ordinals after the range.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_StatefulSetPersistentVolumeClaimRetentionPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StatefulSetPersistentVolumeClaimRetentionPolicy describes the policy used for PVCs created from the StatefulSet VolumeClaimTemplates.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"whenDeleted": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WhenDeleted specifies what happens to PVCs created from StatefulSet VolumeClaimTemplates when the StatefulSet is deleted. The default policy of `Retain` causes PVCs to not be affected by StatefulSet deletion. The `Delete` policy causes those PVCs to be deleted.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"whenScaled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WhenScaled specifies what happens to PVCs created from StatefulSet VolumeClaimTemplates when the StatefulSet is scaled down. The default policy of `Retain` causes PVCs to not be affected by a scaledown. The `Delete` policy causes the associated PVCs for any excess pods above the replica count (or with reserved ordinals) to be deleted. PVCs of Pods that are recreated for update are always kept.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_StatefulSetPodOverride(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StatefulSetPodOverride describes a patch of the Pod template for specific ordinals.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"ordinals": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ordinals is a comma-separated list of ordinals and ordinal ranges that the patch applies to, such as \"0\", \"0-2\" or \"0,3-5\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"patch": {
+						SchemaProps: spec.SchemaProps{
+							Description: "patch is a strategic merge patch applied to the Pod template, such as {\"spec\":{\"containers\":[{\"name\":\"main\",\"resources\":{\"limits\":{\"cpu\":\"2\"}}}]}}.",
+							Ref:         ref("k8s.io/apimachinery/pkg/runtime.RawExtension"),
+						},
+					},
+				},
+				Required: []string{"ordinals", "patch"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/runtime.RawExtension"},
+	}
+}
+
+func schema_pkg_apis_apps_v1alpha1_StatefulSetScaleStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StatefulSetScaleStrategy defines strategies for pods scale.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxUnavailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The maximum number of pods that can be unavailable during scaling. Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%). Absolute number is calculated from percentage by rounding down. It only works with OrderedReady podManagementPolicy, in which Pods are still created and deleted in ordinal order, but up to maxUnavailable Pods can be in flight at the same time. Defaults to 1, which means Pods are scaled one by one.",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/util/intstr.IntOrString"},
+	}
+}
+
 func schema_pkg_apis_apps_v1alpha1_StatefulSetSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2496,6 +3499,12 @@ func schema_pkg_apis_apps_v1alpha1_StatefulSetSpec(ref common.ReferenceCallback)
 							},
 						},
 					},
+					"volumeClaimUpdateStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "volumeClaimUpdateStrategy indicates how to update the existing PVCs when the storage requests in volumeClaimTemplates are increased. Only the storage requests in volumeClaimTemplates can be changed, and can not be decreased.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.VolumeClaimUpdateStrategy"),
+						},
+					},
 					"serviceName": {
 						SchemaProps: spec.SchemaProps{
 							Description: "serviceName is the name of the service that governs this StatefulSet. This service must exist before the StatefulSet, and is responsible for the network identity of the set. Pods get DNS/hostnames that follow the pattern: pod-specific-string.serviceName.default.svc.cluster.local where \"pod-specific-string\" is managed by the StatefulSet controller.",
@@ -2503,6 +3512,13 @@ func schema_pkg_apis_apps_v1alpha1_StatefulSetSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"serviceManagementPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "serviceManagementPolicy indicates whether the headless Service named serviceName is created and maintained by the controller. Defaults to Manual, which means the Service is created by users.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"podManagementPolicy": {
 						SchemaProps: spec.SchemaProps{
 							Description: "podManagementPolicy controls how pods are created during initial scale up, when replacing pods on nodes, or when scaling down. The default policy is `OrderedReady`, where pods are created in increasing order (pod-0, then pod-1, etc) and the controller will wait until each pod is ready before continuing. When scaling down, the pods are removed in the opposite order. The alternative policy is `Parallel` which will create pods in parallel to match the desired scale without waiting, and on scale down will delete all pods at once.",
@@ -2516,6 +3532,12 @@ func schema_pkg_apis_apps_v1alpha1_StatefulSetSpec(ref common.ReferenceCallback)
 							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetUpdateStrategy"),
 						},
 					},
+					"scaleStrategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "scaleStrategy indicates the StatefulSetScaleStrategy that will be employed to scale Pods in the StatefulSet.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetScaleStrategy"),
+						},
+					},
 					"revisionHistoryLimit": {
 						SchemaProps: spec.SchemaProps{
 							Description: "revisionHistoryLimit is the maximum number of revisions that will be maintained in the StatefulSet's revision history. The revision history consists of all revisions not represented by a currently applied StatefulSetSpec version. The default value is 10.",
@@ -2523,12 +3545,58 @@ func schema_pkg_apis_apps_v1alpha1_StatefulSetSpec(ref common.ReferenceCallback)
 							Format:      "int32",
 						},
 					},
+					"minReadySeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "minReadySeconds is the minimum number of seconds for which a newly created pod should be ready without any of its container crashing, for it to be considered available. Defaults to 0 (pod will be considered available as soon as it is ready)",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"ordinals": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ordinals controls the numbering of replica indices in a StatefulSet. The default ordinals behavior assigns a \"0\" index to the first replica and increments the index by one for each additional replica requested.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetOrdinals"),
+						},
+					},
+					"reserveOrdinals": {
+						SchemaProps: spec.SchemaProps{
+							Description: "reserveOrdinals controls the ordinal numbers that should be skipped. StatefulSet will not create Pods with these ordinals, and Pods with them will be deleted. To keep the number of replicas, Pods are created with the ordinals after the last one instead. For example, replicas=4 and reserveOrdinals=[1] means Pods with ordinals 0, 2, 3, 4, and if ordinals.start=1 it means Pods with ordinals 2, 3, 4, 5. Partition of update strategy is counted on the actual ordinals, e.g., partition=1 means only the Pod with ordinal 0 is kept in current revision.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"integer"},
+										Format: "int32",
+									},
+								},
+							},
+						},
+					},
+					"persistentVolumeClaimRetentionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "persistentVolumeClaimRetentionPolicy describes the lifecycle of persistent volume claims created from volumeClaimTemplates. By default, all persistent volume claims are created as needed and retained until manually deleted.",
+							Ref:         ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy"),
+						},
+					},
+					"podOverrides": {
+						SchemaProps: spec.SchemaProps{
+							Description: "podOverrides is a list of patches applied to the template of Pods with specific ordinals. The patches are applied in order, so a later one takes precedence over an earlier one on the same ordinal.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetPodOverride"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"selector", "template"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetUpdateStrategy", "k8s.io/api/core/v1.PersistentVolumeClaim", "k8s.io/api/core/v1.PodTemplateSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+			"github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetOrdinals", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetPodOverride", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetScaleStrategy", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.StatefulSetUpdateStrategy", "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1.VolumeClaimUpdateStrategy", "k8s.io/api/core/v1.PersistentVolumeClaim", "k8s.io/api/core/v1.PodTemplateSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
 	}
 }
 
@@ -2560,6 +3628,13 @@ func schema_pkg_apis_apps_v1alpha1_StatefulSetStatus(ref common.ReferenceCallbac
 							Format:      "int32",
 						},
 					},
+					"availableReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "availableReplicas is the number of Pods created by the StatefulSet controller that have a Ready Condition for at least minReadySeconds.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 					"currentReplicas": {
 						SchemaProps: spec.SchemaProps{
 							Description: "currentReplicas is the number of Pods created by the StatefulSet controller from the StatefulSet version indicated by currentRevision.",
@@ -2574,6 +3649,13 @@ func schema_pkg_apis_apps_v1alpha1_StatefulSetStatus(ref common.ReferenceCallbac
 							Format:      "int32",
 						},
 					},
+					"updatedAvailableReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "updatedAvailableReplicas is the number of Pods created by the StatefulSet controller from the StatefulSet version indicated by updateRevision and have a Ready Condition for at least minReadySeconds.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 					"currentRevision": {
 						SchemaProps: spec.SchemaProps{
 							Description: "currentRevision, if not empty, indicates the version of the StatefulSet used to generate Pods in the sequence [0,currentReplicas).",
@@ -2595,6 +3677,20 @@ func schema_pkg_apis_apps_v1alpha1_StatefulSetStatus(ref common.ReferenceCallbac
 							Format:      "int32",
 						},
 					},
+					"inPlaceUpdateFallbackCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "inPlaceUpdateFallbackCount is the count of Pods that have been recreated by the StatefulSet controller because their in-place update did not complete before the deadline.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"volumeClaimUpdatedReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "volumeClaimUpdatedReplicas is the number of Pods whose PVCs have been expanded to the storage requests in volumeClaimTemplates.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 					"conditions": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -2622,7 +3718,7 @@ func schema_pkg_apis_apps_v1alpha1_StatefulSetStatus(ref common.ReferenceCallbac
 						},
 					},
 				},
-				Required: []string{"replicas", "readyReplicas", "currentReplicas", "updatedReplicas"},
+				Required: []string{"replicas", "readyReplicas", "availableReplicas", "currentReplicas", "updatedReplicas"},
 			},
 		},
 		Dependencies: []string{
@@ -2852,6 +3948,12 @@ func schema_pkg_apis_apps_v1alpha1_UnitedDeploymentCondition(ref common.Referenc
 							Format:      "",
 						},
 					},
+					"lastUpdateTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The last time this condition was updated.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
 					"lastTransitionTime": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Last time the condition transitioned from one status to another.",
@@ -2972,6 +4074,13 @@ func schema_pkg_apis_apps_v1alpha1_UnitedDeploymentSpec(ref common.ReferenceCall
 							Format:      "int32",
 						},
 					},
+					"serviceManagementPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServiceManagementPolicy indicates whether the headless Service named serviceName in the subset template is created and maintained by the UnitedDeployment. The Service selects the Pods of all the subsets. Defaults to Manual, which means the Service is created by users.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"selector"},
 			},
@@ -3254,3 +4363,23 @@ func schema_pkg_apis_apps_v1alpha1_UpdateStatus(ref common.ReferenceCallback) co
 		},
 	}
 }
+
+func schema_pkg_apis_apps_v1alpha1_VolumeClaimUpdateStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VolumeClaimUpdateStrategy defines how the existing PVCs are updated, when the storage requests in volumeClaimTemplates are increased. The storage requests of PVCs are always expanded if their StorageClasses allow volume expansion.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"recreatePodOnFileSystemResizePending": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RecreatePodOnFileSystemResizePending indicates to recreate the Pods whose PVCs are waiting for the filesystem resize, which can only be finished when the volumes are mounted again. Pods are recreated within the maxUnavailable of update strategy. Default to false, which means the filesystem resize will not be finished until the Pods are recreated for other reasons.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -27,6 +27,21 @@ type SidecarSetSpec struct {
 	// selector is a label query over pods that should be injected
 	Selector *metav1.LabelSelector `json:"selector,omitempty"`
 
+	// Namespace sidecarSet will only match the pods in the namespace, otherwise match pods in all namespaces
+	// (in cluster).
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// NamespaceSelector is a label query over the namespaces whose pods should be injected.
+	// It can not be set together with Namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// InitContainers is the list of init containers to be injected into the selected pod, after the init containers
+	// of pod and in the order listed. They are only injected when pod creates, and never updated in existing pods.
+	// +optional
+	InitContainers []SidecarContainer `json:"initContainers,omitempty"`
+
 	// Containers is the list of sidecar containers to be injected into the selected pod
 	Containers []SidecarContainer `json:"containers,omitempty"`
 
@@ -43,8 +58,54 @@ type SidecarSetSpec struct {
 // SidecarContainer defines the container of Sidecar
 type SidecarContainer struct {
 	corev1.Container `json:",inline"`
+
+	// PodInjectPolicy decides whether the sidecar container is injected before or after the app containers of pod.
+	// Defaults to AfterAppContainer, and it is ignored for init containers.
+	// +optional
+	PodInjectPolicy PodInjectPolicyType `json:"podInjectPolicy,omitempty"`
+
+	// UpgradeStrategy decides how the sidecar container is upgraded in the existing pods.
+	// Defaults to ColdUpgrade, and it is ignored for init containers.
+	// +optional
+	UpgradeStrategy *SidecarContainerUpgradeStrategy `json:"upgradeStrategy,omitempty"`
+}
+
+// SidecarContainerUpgradeType is the way to upgrade a sidecar container.
+type SidecarContainerUpgradeType string
+
+const (
+	// SidecarContainerColdUpgrade updates the image of sidecar container in place, which restarts the container.
+	SidecarContainerColdUpgrade SidecarContainerUpgradeType = "ColdUpgrade"
+	// SidecarContainerHotUpgrade injects two containers for the sidecar, one working and the other running the empty image.
+	// To upgrade the sidecar, the new image is started in the idle container, which migrates the state from the working
+	// one in its postStart hook. Then the idle container becomes the working one, and the old one runs the empty image.
+	SidecarContainerHotUpgrade SidecarContainerUpgradeType = "HotUpgrade"
+)
+
+// SidecarContainerUpgradeStrategy defines the upgrade strategy of a sidecar container.
+type SidecarContainerUpgradeStrategy struct {
+	// UpgradeType is ColdUpgrade or HotUpgrade. Defaults to ColdUpgrade.
+	// +optional
+	UpgradeType SidecarContainerUpgradeType `json:"upgradeType,omitempty"`
+
+	// HotUpgradeEmptyImage is the image run by the idle container of a HotUpgrade sidecar. It should be able to run
+	// with the same configuration as the sidecar container, e.g. the command and probes, and do nothing.
+	// It is required for HotUpgrade.
+	// +optional
+	HotUpgradeEmptyImage string `json:"hotUpgradeEmptyImage,omitempty"`
 }
 
+// PodInjectPolicyType is the position where a sidecar container is injected into the containers of pod.
+type PodInjectPolicyType string
+
+const (
+	// BeforeAppContainerType means the sidecar container is injected before the app containers,
+	// so that it starts before them.
+	BeforeAppContainerType PodInjectPolicyType = "BeforeAppContainer"
+	// AfterAppContainerType means the sidecar container is injected after the app containers.
+	AfterAppContainerType PodInjectPolicyType = "AfterAppContainer"
+)
+
 // SidecarSetUpdateStrategy indicates the strategy that the SidecarSet
 // controller will use to perform updates. It includes any additional parameters
 // necessary to perform the update for the indicated strategy.
@@ -71,6 +132,39 @@ type SidecarSetStatus struct {
 
 	// readyPods is the number of matched Pods that have a ready condition
 	ReadyPods int32 `json:"readyPods"`
+
+	// Represents the latest available observations of a SidecarSet's current state.
+	// +optional
+	Conditions []SidecarSetCondition `json:"conditions,omitempty"`
+}
+
+// SidecarSetConditionType is type for SidecarSet conditions.
+type SidecarSetConditionType string
+
+const (
+	// SidecarSetConditionProgressing indicates whether the SidecarSet is progressing to update the sidecars of matched pods.
+	SidecarSetConditionProgressing SidecarSetConditionType = WorkloadConditionProgressing
+	// SidecarSetConditionAvailable indicates whether the SidecarSet has minimum ready pods,
+	// which is matched pods minus maxUnavailable.
+	SidecarSetConditionAvailable SidecarSetConditionType = WorkloadConditionAvailable
+	// SidecarSetConditionReplicaFailure indicates sidecarset controller failed to update the sidecars of pods.
+	SidecarSetConditionReplicaFailure SidecarSetConditionType = WorkloadConditionReplicaFailure
+)
+
+// SidecarSetCondition describes the state of a SidecarSet at a certain point.
+type SidecarSetCondition struct {
+	// Type of SidecarSet condition.
+	Type SidecarSetConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// The last time this condition was updated.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// Last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// The reason for the condition's last transition.
+	Reason string `json:"reason,omitempty"`
+	// A human readable message indicating details about the transition.
+	Message string `json:"message,omitempty"`
 }
 
 // +genclient
@@ -21,6 +21,7 @@ import (
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -37,6 +38,18 @@ type StatefulSetUpdateStrategy struct {
 	RollingUpdate *RollingUpdateStatefulSetStrategy `json:"rollingUpdate,omitempty"`
 }
 
+// StatefulSetScaleStrategy defines strategies for pods scale.
+type StatefulSetScaleStrategy struct {
+	// The maximum number of pods that can be unavailable during scaling.
+	// Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%).
+	// Absolute number is calculated from percentage by rounding down.
+	// It only works with OrderedReady podManagementPolicy, in which Pods are still created
+	// and deleted in ordinal order, but up to maxUnavailable Pods can be in flight at the same time.
+	// Defaults to 1, which means Pods are scaled one by one.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
 // RollingUpdateStatefulSetStrategy is used to communicate parameter for RollingUpdateStatefulSetStrategyType.
 type RollingUpdateStatefulSetStrategy struct {
 	// Partition indicates the ordinal at which the StatefulSet should be partitioned by default.
@@ -128,6 +141,12 @@ type StatefulSetSpec struct {
 	// +optional
 	VolumeClaimTemplates []v1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
 
+	// volumeClaimUpdateStrategy indicates how to update the existing PVCs when the
+	// storage requests in volumeClaimTemplates are increased.
+	// Only the storage requests in volumeClaimTemplates can be changed, and can not be decreased.
+	// +optional
+	VolumeClaimUpdateStrategy *VolumeClaimUpdateStrategy `json:"volumeClaimUpdateStrategy,omitempty"`
+
 	// serviceName is the name of the service that governs this StatefulSet.
 	// This service must exist before the StatefulSet, and is responsible for
 	// the network identity of the set. Pods get DNS/hostnames that follow the
@@ -135,6 +154,12 @@ type StatefulSetSpec struct {
 	// where "pod-specific-string" is managed by the StatefulSet controller.
 	ServiceName string `json:"serviceName,omitempty"`
 
+	// serviceManagementPolicy indicates whether the headless Service named serviceName
+	// is created and maintained by the controller.
+	// Defaults to Manual, which means the Service is created by users.
+	// +optional
+	ServiceManagementPolicy ServiceManagementPolicyType `json:"serviceManagementPolicy,omitempty"`
+
 	// podManagementPolicy controls how pods are created during initial scale up,
 	// when replacing pods on nodes, or when scaling down. The default policy is
 	// `OrderedReady`, where pods are created in increasing order (pod-0, then
@@ -151,11 +176,125 @@ type StatefulSetSpec struct {
 	// Template.
 	UpdateStrategy StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
 
+	// scaleStrategy indicates the StatefulSetScaleStrategy that will be
+	// employed to scale Pods in the StatefulSet.
+	// +optional
+	ScaleStrategy *StatefulSetScaleStrategy `json:"scaleStrategy,omitempty"`
+
 	// revisionHistoryLimit is the maximum number of revisions that will
 	// be maintained in the StatefulSet's revision history. The revision history
 	// consists of all revisions not represented by a currently applied
 	// StatefulSetSpec version. The default value is 10.
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// minReadySeconds is the minimum number of seconds for which a newly created pod should be ready
+	// without any of its container crashing, for it to be considered available.
+	// Defaults to 0 (pod will be considered available as soon as it is ready)
+	// +optional
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+
+	// ordinals controls the numbering of replica indices in a StatefulSet. The
+	// default ordinals behavior assigns a "0" index to the first replica and
+	// increments the index by one for each additional replica requested.
+	// +optional
+	Ordinals *StatefulSetOrdinals `json:"ordinals,omitempty"`
+
+	// reserveOrdinals controls the ordinal numbers that should be skipped.
+	// StatefulSet will not create Pods with these ordinals, and Pods with them will be deleted.
+	// To keep the number of replicas, Pods are created with the ordinals after the last one instead.
+	// For example, replicas=4 and reserveOrdinals=[1] means Pods with ordinals 0, 2, 3, 4,
+	// and if ordinals.start=1 it means Pods with ordinals 2, 3, 4, 5.
+	// Partition of update strategy is counted on the actual ordinals, e.g., partition=1 means only
+	// the Pod with ordinal 0 is kept in current revision.
+	// +optional
+	ReserveOrdinals []int `json:"reserveOrdinals,omitempty"`
+
+	// persistentVolumeClaimRetentionPolicy describes the lifecycle of persistent
+	// volume claims created from volumeClaimTemplates. By default, all persistent
+	// volume claims are created as needed and retained until manually deleted.
+	// +optional
+	PersistentVolumeClaimRetentionPolicy *StatefulSetPersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+
+	// podOverrides is a list of patches applied to the template of Pods with specific ordinals.
+	// The patches are applied in order, so a later one takes precedence over an earlier one on the same ordinal.
+	// +optional
+	PodOverrides []StatefulSetPodOverride `json:"podOverrides,omitempty"`
+}
+
+// ServiceManagementPolicyType defines who maintains the headless Service that governs the Pods.
+type ServiceManagementPolicyType string
+
+const (
+	// ManualServiceManagementPolicyType means the headless Service is created and maintained by users.
+	ManualServiceManagementPolicyType ServiceManagementPolicyType = "Manual"
+	// ManagedServiceManagementPolicyType means the controller creates and owns the headless Service,
+	// and keeps its selector and ports consistent with the workload.
+	ManagedServiceManagementPolicyType ServiceManagementPolicyType = "Managed"
+)
+
+// StatefulSetPodOverride describes a patch of the Pod template for specific ordinals.
+type StatefulSetPodOverride struct {
+	// ordinals is a comma-separated list of ordinals and ordinal ranges that the patch applies to,
+	// such as "0", "0-2" or "0,3-5".
+	Ordinals string `json:"ordinals"`
+
+	// patch is a strategic merge patch applied to the Pod template, such as
+	// {"spec":{"containers":[{"name":"main","resources":{"limits":{"cpu":"2"}}}]}}.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Patch runtime.RawExtension `json:"patch"`
+}
+
+// StatefulSetOrdinals describes the policy used for replica ordinal assignment
+// in this StatefulSet.
+type StatefulSetOrdinals struct {
+	// start is the number representing the first replica's index. It may be used
+	// to number replicas from an alternate index (eg: 1-indexed) over the default
+	// 0-indexed names, or to orchestrate progressive movement of replicas from
+	// one StatefulSet to another.
+	// If set, replica indices will be in the range:
+	//   [.spec.ordinals.start, .spec.ordinals.start + .spec.replicas).
+	// If unset, defaults to 0. Replica indices will be in the range:
+	//   [0, .spec.replicas).
+	// Ordinals in reserveOrdinals are skipped and replaced by the ordinals after the range.
+	// +optional
+	Start int32 `json:"start"`
+}
+
+// PersistentVolumeClaimRetentionPolicyType is a string enumeration of the policies that will determine
+// when volumes from the VolumeClaimTemplates will be deleted when the controlling StatefulSet is
+// deleted or scaled down.
+type PersistentVolumeClaimRetentionPolicyType string
+
+const (
+	// RetainPersistentVolumeClaimRetentionPolicyType is the default
+	// PersistentVolumeClaimRetentionPolicy and specifies that
+	// PersistentVolumeClaims associated with StatefulSet VolumeClaimTemplates
+	// will not be deleted.
+	RetainPersistentVolumeClaimRetentionPolicyType PersistentVolumeClaimRetentionPolicyType = "Retain"
+	// DeletePersistentVolumeClaimRetentionPolicyType specifies that
+	// PersistentVolumeClaims associated with StatefulSet VolumeClaimTemplates
+	// will be deleted in the scenario specified in
+	// StatefulSetPersistentVolumeClaimRetentionPolicy.
+	DeletePersistentVolumeClaimRetentionPolicyType PersistentVolumeClaimRetentionPolicyType = "Delete"
+)
+
+// StatefulSetPersistentVolumeClaimRetentionPolicy describes the policy used for PVCs
+// created from the StatefulSet VolumeClaimTemplates.
+type StatefulSetPersistentVolumeClaimRetentionPolicy struct {
+	// WhenDeleted specifies what happens to PVCs created from StatefulSet
+	// VolumeClaimTemplates when the StatefulSet is deleted. The default policy
+	// of `Retain` causes PVCs to not be affected by StatefulSet deletion. The
+	// `Delete` policy causes those PVCs to be deleted.
+	// +optional
+	WhenDeleted PersistentVolumeClaimRetentionPolicyType `json:"whenDeleted,omitempty"`
+	// WhenScaled specifies what happens to PVCs created from StatefulSet
+	// VolumeClaimTemplates when the StatefulSet is scaled down. The default
+	// policy of `Retain` causes PVCs to not be affected by a scaledown. The
+	// `Delete` policy causes the associated PVCs for any excess pods above
+	// the replica count (or with reserved ordinals) to be deleted.
+	// PVCs of Pods that are recreated for update are always kept.
+	// +optional
+	WhenScaled PersistentVolumeClaimRetentionPolicyType `json:"whenScaled,omitempty"`
 }
 
 // StatefulSetStatus defines the observed state of StatefulSet
@@ -171,6 +310,10 @@ type StatefulSetStatus struct {
 	// readyReplicas is the number of Pods created by the StatefulSet controller that have a Ready Condition.
 	ReadyReplicas int32 `json:"readyReplicas"`
 
+	// availableReplicas is the number of Pods created by the StatefulSet controller that have a Ready Condition
+	// for at least minReadySeconds.
+	AvailableReplicas int32 `json:"availableReplicas"`
+
 	// currentReplicas is the number of Pods created by the StatefulSet controller from the StatefulSet version
 	// indicated by currentRevision.
 	CurrentReplicas int32 `json:"currentReplicas"`
@@ -179,6 +322,11 @@ type StatefulSetStatus struct {
 	// indicated by updateRevision.
 	UpdatedReplicas int32 `json:"updatedReplicas"`
 
+	// updatedAvailableReplicas is the number of Pods created by the StatefulSet controller from the StatefulSet
+	// version indicated by updateRevision and have a Ready Condition for at least minReadySeconds.
+	// +optional
+	UpdatedAvailableReplicas int32 `json:"updatedAvailableReplicas,omitempty"`
+
 	// currentRevision, if not empty, indicates the version of the StatefulSet used to generate Pods in the
 	// sequence [0,currentReplicas).
 	CurrentRevision string `json:"currentRevision,omitempty"`
@@ -193,6 +341,16 @@ type StatefulSetStatus struct {
 	// +optional
 	CollisionCount *int32 `json:"collisionCount,omitempty"`
 
+	// inPlaceUpdateFallbackCount is the count of Pods that have been recreated by the StatefulSet controller
+	// because their in-place update did not complete before the deadline.
+	// +optional
+	InPlaceUpdateFallbackCount int32 `json:"inPlaceUpdateFallbackCount,omitempty"`
+
+	// volumeClaimUpdatedReplicas is the number of Pods whose PVCs have been expanded to
+	// the storage requests in volumeClaimTemplates.
+	// +optional
+	VolumeClaimUpdatedReplicas int32 `json:"volumeClaimUpdatedReplicas,omitempty"`
+
 	// Represents the latest available observations of a statefulset's current state.
 	// +optional
 	// +patchMergeKey=type
@@ -207,6 +365,14 @@ type StatefulSetStatus struct {
 const (
 	FailedCreatePod apps.StatefulSetConditionType = "FailedCreatePod"
 	FailedUpdatePod apps.StatefulSetConditionType = "FailedUpdatePod"
+
+	// StatefulSetConditionProgressing indicates whether the statefulset is progressing to its update revision.
+	StatefulSetConditionProgressing apps.StatefulSetConditionType = WorkloadConditionProgressing
+	// StatefulSetConditionAvailable indicates whether the statefulset has minimum available pods,
+	// which is replicas minus maxUnavailable.
+	StatefulSetConditionAvailable apps.StatefulSetConditionType = WorkloadConditionAvailable
+	// StatefulSetConditionReplicaFailure indicates the statefulset failed to create or update pods.
+	StatefulSetConditionReplicaFailure apps.StatefulSetConditionType = WorkloadConditionReplicaFailure
 )
 
 // +genclient
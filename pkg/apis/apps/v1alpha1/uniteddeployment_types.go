@@ -44,6 +44,14 @@ const (
 	SubsetUpdated UnitedDeploymentConditionType = "SubsetUpdated"
 	// SubsetFailure is added to a UnitedDeployment when one of its subsets has failure during its own reconciling.
 	SubsetFailure UnitedDeploymentConditionType = "SubsetFailure"
+
+	// UnitedDeploymentConditionProgressing indicates whether the UnitedDeployment is progressing to its update revision.
+	UnitedDeploymentConditionProgressing UnitedDeploymentConditionType = WorkloadConditionProgressing
+	// UnitedDeploymentConditionAvailable indicates whether all the replicas of UnitedDeployment are ready.
+	UnitedDeploymentConditionAvailable UnitedDeploymentConditionType = WorkloadConditionAvailable
+	// UnitedDeploymentConditionReplicaFailure indicates the UnitedDeployment failed to provision or update subsets,
+	// or one of its subsets has failure.
+	UnitedDeploymentConditionReplicaFailure UnitedDeploymentConditionType = WorkloadConditionReplicaFailure
 )
 
 // UnitedDeploymentSpec defines the desired state of UnitedDeployment.
@@ -74,6 +82,13 @@ type UnitedDeploymentSpec struct {
 	// If unspecified, defaults to 10.
 	// +optional
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// ServiceManagementPolicy indicates whether the headless Service named serviceName
+	// in the subset template is created and maintained by the UnitedDeployment.
+	// The Service selects the Pods of all the subsets.
+	// Defaults to Manual, which means the Service is created by users.
+	// +optional
+	ServiceManagementPolicy ServiceManagementPolicyType `json:"serviceManagementPolicy,omitempty"`
 }
 
 // SubsetTemplate defines the subset template under the UnitedDeployment.
@@ -207,6 +222,10 @@ type UnitedDeploymentCondition struct {
 	// Status of the condition, one of True, False, Unknown.
 	Status corev1.ConditionStatus `json:"status,omitempty"`
 
+	// The last time this condition was updated.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+
 	// Last time the condition transitioned from one status to another.
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 
@@ -0,0 +1,30 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// VolumeClaimUpdateStrategy defines how the existing PVCs are updated, when the storage requests
+// in volumeClaimTemplates are increased.
+// The storage requests of PVCs are always expanded if their StorageClasses allow volume expansion.
+type VolumeClaimUpdateStrategy struct {
+	// RecreatePodOnFileSystemResizePending indicates to recreate the Pods whose PVCs are waiting for
+	// the filesystem resize, which can only be finished when the volumes are mounted again.
+	// Pods are recreated within the maxUnavailable of update strategy.
+	// Default to false, which means the filesystem resize will not be finished until the Pods are
+	// recreated for other reasons.
+	// +optional
+	RecreatePodOnFileSystemResizePending bool `json:"recreatePodOnFileSystemResizePending,omitempty"`
+}
@@ -6,4 +6,8 @@ const (
 
 	// SubSetNameLabelKey is used to record the name of current subset.
 	SubSetNameLabelKey = "apps.kruise.io/subset-name"
+
+	// PodDeletionCostAnnotationKey is the annotation of pod to set the cost of deleting it when scaling in.
+	// The value should be an integer, and pods with the lower cost will be deleted first.
+	PodDeletionCostAnnotationKey = "apps.kruise.io/deletion-cost"
 )
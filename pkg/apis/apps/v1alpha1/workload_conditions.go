@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Condition types shared by CloneSet, Advanced StatefulSet, Advanced DaemonSet, UnitedDeployment and SidecarSet,
+// so that the rollout of any Kruise workload can be observed in the same way.
+const (
+	// WorkloadConditionProgressing indicates whether the workload is progressing to its update revision.
+	WorkloadConditionProgressing = "Progressing"
+	// WorkloadConditionAvailable indicates whether the workload has minimum available replicas.
+	WorkloadConditionAvailable = "Available"
+	// WorkloadConditionReplicaFailure indicates the workload failed to scale, update or schedule its replicas.
+	// It only exists when there is a failure.
+	WorkloadConditionReplicaFailure = "ReplicaFailure"
+)
+
+// Reasons of the shared workload conditions.
+const (
+	// WorkloadReasonMinimumReplicasAvailable is added in the Available condition when the workload has minimum availability.
+	WorkloadReasonMinimumReplicasAvailable = "MinimumReplicasAvailable"
+	// WorkloadReasonMinimumReplicasUnavailable is added in the Available condition when the workload does not have
+	// minimum availability.
+	WorkloadReasonMinimumReplicasUnavailable = "MinimumReplicasUnavailable"
+
+	// WorkloadReasonPaused is added in the Progressing condition when the update of workload is paused.
+	WorkloadReasonPaused = "Paused"
+	// WorkloadReasonNewRevisionProgressing is added in the Progressing condition when the workload has made progress recently.
+	WorkloadReasonNewRevisionProgressing = "NewRevisionProgressing"
+	// WorkloadReasonNewRevisionAvailable is added in the Progressing condition when replicas of the update revision
+	// are all ready, excluding those kept in old revisions by partition.
+	WorkloadReasonNewRevisionAvailable = "NewRevisionAvailable"
+	// WorkloadReasonProgressDeadlineExceeded is added in the Progressing condition when the workload has not made
+	// any progress for longer than its progress deadline.
+	WorkloadReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+
+	// WorkloadReasonFailedScale is added in the ReplicaFailure condition when the workload failed to create or delete replicas.
+	WorkloadReasonFailedScale = "FailedScale"
+	// WorkloadReasonFailedUpdate is added in the ReplicaFailure condition when the workload failed to update replicas.
+	WorkloadReasonFailedUpdate = "FailedUpdate"
+	// WorkloadReasonUnschedulable is added in the ReplicaFailure condition when replicas of the workload are unschedulable.
+	WorkloadReasonUnschedulable = "Unschedulable"
+)
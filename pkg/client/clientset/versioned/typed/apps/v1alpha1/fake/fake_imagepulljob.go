@@ -0,0 +1,140 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeImagePullJobs implements ImagePullJobInterface
+type FakeImagePullJobs struct {
+	Fake *FakeAppsV1alpha1
+	ns   string
+}
+
+var imagepulljobsResource = schema.GroupVersionResource{Group: "apps.kruise.io", Version: "v1alpha1", Resource: "imagepulljobs"}
+
+var imagepulljobsKind = schema.GroupVersionKind{Group: "apps.kruise.io", Version: "v1alpha1", Kind: "ImagePullJob"}
+
+// Get takes name of the imagePullJob, and returns the corresponding imagePullJob object, and an error if there is any.
+func (c *FakeImagePullJobs) Get(name string, options v1.GetOptions) (result *v1alpha1.ImagePullJob, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(imagepulljobsResource, c.ns, name), &v1alpha1.ImagePullJob{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ImagePullJob), err
+}
+
+// List takes label and field selectors, and returns the list of ImagePullJobs that match those selectors.
+func (c *FakeImagePullJobs) List(opts v1.ListOptions) (result *v1alpha1.ImagePullJobList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(imagepulljobsResource, imagepulljobsKind, c.ns, opts), &v1alpha1.ImagePullJobList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.ImagePullJobList{ListMeta: obj.(*v1alpha1.ImagePullJobList).ListMeta}
+	for _, item := range obj.(*v1alpha1.ImagePullJobList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested imagePullJobs.
+func (c *FakeImagePullJobs) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(imagepulljobsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a imagePullJob and creates it.  Returns the server's representation of the imagePullJob, and an error, if there is any.
+func (c *FakeImagePullJobs) Create(imagePullJob *v1alpha1.ImagePullJob) (result *v1alpha1.ImagePullJob, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(imagepulljobsResource, c.ns, imagePullJob), &v1alpha1.ImagePullJob{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ImagePullJob), err
+}
+
+// Update takes the representation of a imagePullJob and updates it. Returns the server's representation of the imagePullJob, and an error, if there is any.
+func (c *FakeImagePullJobs) Update(imagePullJob *v1alpha1.ImagePullJob) (result *v1alpha1.ImagePullJob, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(imagepulljobsResource, c.ns, imagePullJob), &v1alpha1.ImagePullJob{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ImagePullJob), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeImagePullJobs) UpdateStatus(imagePullJob *v1alpha1.ImagePullJob) (*v1alpha1.ImagePullJob, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(imagepulljobsResource, "status", c.ns, imagePullJob), &v1alpha1.ImagePullJob{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ImagePullJob), err
+}
+
+// Delete takes name of the imagePullJob and deletes it. Returns an error if one occurs.
+func (c *FakeImagePullJobs) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(imagepulljobsResource, c.ns, name), &v1alpha1.ImagePullJob{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeImagePullJobs) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(imagepulljobsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.ImagePullJobList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched imagePullJob.
+func (c *FakeImagePullJobs) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.ImagePullJob, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(imagepulljobsResource, c.ns, name, pt, data, subresources...), &v1alpha1.ImagePullJob{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ImagePullJob), err
+}
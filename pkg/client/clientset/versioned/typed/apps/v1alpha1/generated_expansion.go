@@ -22,8 +22,14 @@ type BroadcastJobExpansion interface{}
 
 type CloneSetExpansion interface{}
 
+type ContainerRecreateRequestExpansion interface{}
+
 type DaemonSetExpansion interface{}
 
+type ImagePullJobExpansion interface{}
+
+type NodeImageExpansion interface{}
+
 type SidecarSetExpansion interface{}
 
 type StatefulSetExpansion interface{}
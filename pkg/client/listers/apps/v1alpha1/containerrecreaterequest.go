@@ -0,0 +1,94 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ContainerRecreateRequestLister helps list ContainerRecreateRequests.
+type ContainerRecreateRequestLister interface {
+	// List lists all ContainerRecreateRequests in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.ContainerRecreateRequest, err error)
+	// ContainerRecreateRequests returns an object that can list and get ContainerRecreateRequests.
+	ContainerRecreateRequests(namespace string) ContainerRecreateRequestNamespaceLister
+	ContainerRecreateRequestListerExpansion
+}
+
+// containerRecreateRequestLister implements the ContainerRecreateRequestLister interface.
+type containerRecreateRequestLister struct {
+	indexer cache.Indexer
+}
+
+// NewContainerRecreateRequestLister returns a new ContainerRecreateRequestLister.
+func NewContainerRecreateRequestLister(indexer cache.Indexer) ContainerRecreateRequestLister {
+	return &containerRecreateRequestLister{indexer: indexer}
+}
+
+// List lists all ContainerRecreateRequests in the indexer.
+func (s *containerRecreateRequestLister) List(selector labels.Selector) (ret []*v1alpha1.ContainerRecreateRequest, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ContainerRecreateRequest))
+	})
+	return ret, err
+}
+
+// ContainerRecreateRequests returns an object that can list and get ContainerRecreateRequests.
+func (s *containerRecreateRequestLister) ContainerRecreateRequests(namespace string) ContainerRecreateRequestNamespaceLister {
+	return containerRecreateRequestNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ContainerRecreateRequestNamespaceLister helps list and get ContainerRecreateRequests.
+type ContainerRecreateRequestNamespaceLister interface {
+	// List lists all ContainerRecreateRequests in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.ContainerRecreateRequest, err error)
+	// Get retrieves the ContainerRecreateRequest from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.ContainerRecreateRequest, error)
+	ContainerRecreateRequestNamespaceListerExpansion
+}
+
+// containerRecreateRequestNamespaceLister implements the ContainerRecreateRequestNamespaceLister
+// interface.
+type containerRecreateRequestNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ContainerRecreateRequests in the indexer for a given namespace.
+func (s containerRecreateRequestNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.ContainerRecreateRequest, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ContainerRecreateRequest))
+	})
+	return ret, err
+}
+
+// Get retrieves the ContainerRecreateRequest from the indexer for a given namespace and name.
+func (s containerRecreateRequestNamespaceLister) Get(name string) (*v1alpha1.ContainerRecreateRequest, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("containerrecreaterequest"), name)
+	}
+	return obj.(*v1alpha1.ContainerRecreateRequest), nil
+}
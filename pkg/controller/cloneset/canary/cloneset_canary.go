@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"strconv"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	clonesetcore "github.com/openkruise/kruise/pkg/controller/cloneset/core"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/integer"
+)
+
+var timeNow = time.Now
+
+// Calculate returns the canary status of the update revision in this reconcile, and the duration after which
+// the pause of the current step ends. It moves to the next step once the updated pods of the current step are ready
+// and the pause of it has ended.
+func Calculate(cs *appsv1alpha1.CloneSet, updateRevision string, pods []*v1.Pod) (*appsv1alpha1.CloneSetCanaryStatus, time.Duration) {
+	steps := cs.Spec.UpdateStrategy.CanarySteps
+	if len(steps) == 0 || cs.Spec.Replicas == nil {
+		return nil, 0
+	}
+
+	status := &appsv1alpha1.CloneSetCanaryStatus{Revision: updateRevision, State: appsv1alpha1.CanaryStepStateUpgrading}
+	if oldStatus := cs.Status.CanaryStatus; oldStatus != nil && oldStatus.Revision == updateRevision {
+		status = oldStatus.DeepCopy()
+	}
+	// steps might have been removed by user
+	if int(status.CurrentStep) >= len(steps) {
+		status.CurrentStep = int32(len(steps) - 1)
+	}
+
+	coreControl := clonesetcore.New(cs)
+	var updatedReadyReplicas int
+	for _, pod := range clonesetutils.FilterOutPreparingDeletePods(pods) {
+		if clonesetutils.GetPodRevision(pod) == updateRevision && coreControl.IsPodUpdateReady(pod, cs.Spec.MinReadySeconds) {
+			updatedReadyReplicas++
+		}
+	}
+
+	for {
+		if updatedReadyReplicas < int(*cs.Spec.Replicas)-int(*GetPartition(cs, status)) {
+			status.State = appsv1alpha1.CanaryStepStateUpgrading
+			status.PauseStartTime = nil
+			return status, 0
+		}
+
+		if int(status.CurrentStep) == len(steps)-1 {
+			status.State = appsv1alpha1.CanaryStepStateCompleted
+			status.PauseStartTime = nil
+			return status, 0
+		}
+
+		if pause := steps[status.CurrentStep].Pause; pause != nil {
+			if status.State != appsv1alpha1.CanaryStepStatePaused || status.PauseStartTime == nil {
+				status.State = appsv1alpha1.CanaryStepStatePaused
+				status.PauseStartTime = &metav1.Time{Time: timeNow()}
+			}
+
+			if pause.Duration != nil {
+				if leftDuration := status.PauseStartTime.Add(pause.Duration.Duration).Sub(timeNow()); leftDuration > 0 {
+					return status, leftDuration
+				}
+			} else if cs.Annotations[appsv1alpha1.CloneSetCanaryResumeStepAnnotationKey] != ResumeStepValue(updateRevision, status.CurrentStep) {
+				return status, 0
+			}
+		}
+
+		status.CurrentStep++
+		status.State = appsv1alpha1.CanaryStepStateUpgrading
+		status.PauseStartTime = nil
+	}
+}
+
+// ResumeStepValue returns the value of apps.kruise.io/canary-resume-step annotation to resume the step of revision.
+func ResumeStepValue(revision string, step int32) string {
+	return revision + "/" + strconv.Itoa(int(step))
+}
+
+// GetPartition returns the partition in the current canary step, which is the larger one of the partition
+// in updateStrategy and the number of pods that should not be updated in this step.
+func GetPartition(cs *appsv1alpha1.CloneSet, status *appsv1alpha1.CloneSetCanaryStatus) *int32 {
+	var partition int32
+	if cs.Spec.UpdateStrategy.Partition != nil {
+		partition = *cs.Spec.UpdateStrategy.Partition
+	}
+
+	steps := cs.Spec.UpdateStrategy.CanarySteps
+	if status == nil || int(status.CurrentStep) >= len(steps) {
+		return &partition
+	}
+
+	replicas := int(*cs.Spec.Replicas)
+	stepReplicas, _ := intstrutil.GetValueFromIntOrPercent(&steps[status.CurrentStep].Replicas, replicas, true)
+	stepReplicas = integer.IntMax(integer.IntMin(stepReplicas, replicas), 0)
+	if stepPartition := int32(replicas - stepReplicas); stepPartition > partition {
+		partition = stepPartition
+	}
+	return &partition
+}
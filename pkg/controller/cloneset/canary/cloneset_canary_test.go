@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilpointer "k8s.io/utils/pointer"
+)
+
+func TestCalculate(t *testing.T) {
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	steps := []appsv1alpha1.CloneSetCanaryStep{
+		{Replicas: intstr.FromString("20%"), Pause: &appsv1alpha1.CloneSetCanaryPause{Duration: &metav1.Duration{Duration: time.Minute}}},
+		{Replicas: intstr.FromInt(5), Pause: &appsv1alpha1.CloneSetCanaryPause{}},
+		{Replicas: intstr.FromString("100%")},
+	}
+
+	newPods := func(updatedReady, updatedNotReady, notUpdated int) []*v1.Pod {
+		var pods []*v1.Pod
+		newPod := func(revision string, ready bool) *v1.Pod {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   fmt.Sprintf("pod-%d", len(pods)),
+					Labels: map[string]string{apps.ControllerRevisionHashLabelKey: revision},
+				},
+				Status: v1.PodStatus{Phase: v1.PodRunning},
+			}
+			if ready {
+				pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+			}
+			return pod
+		}
+		for i := 0; i < updatedReady; i++ {
+			pods = append(pods, newPod("new", true))
+		}
+		for i := 0; i < updatedNotReady; i++ {
+			pods = append(pods, newPod("new", false))
+		}
+		for i := 0; i < notUpdated; i++ {
+			pods = append(pods, newPod("old", true))
+		}
+		return pods
+	}
+
+	cases := []struct {
+		name              string
+		oldStatus         *appsv1alpha1.CloneSetCanaryStatus
+		annotations       map[string]string
+		pods              []*v1.Pod
+		expectedStatus    *appsv1alpha1.CloneSetCanaryStatus
+		expectedDuration  time.Duration
+		expectedPartition int32
+	}{
+		{
+			name:              "start the first step of new revision",
+			oldStatus:         &appsv1alpha1.CloneSetCanaryStatus{Revision: "old", CurrentStep: 2, State: appsv1alpha1.CanaryStepStateCompleted},
+			pods:              newPods(0, 0, 10),
+			expectedStatus:    &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 0, State: appsv1alpha1.CanaryStepStateUpgrading},
+			expectedPartition: 8,
+		},
+		{
+			name:              "wait for updated pods to be ready",
+			oldStatus:         &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 0, State: appsv1alpha1.CanaryStepStateUpgrading},
+			pods:              newPods(1, 1, 8),
+			expectedStatus:    &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 0, State: appsv1alpha1.CanaryStepStateUpgrading},
+			expectedPartition: 8,
+		},
+		{
+			name:              "begin to pause for duration",
+			oldStatus:         &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 0, State: appsv1alpha1.CanaryStepStateUpgrading},
+			pods:              newPods(2, 0, 8),
+			expectedStatus:    &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 0, State: appsv1alpha1.CanaryStepStatePaused, PauseStartTime: &metav1.Time{Time: now}},
+			expectedDuration:  time.Minute,
+			expectedPartition: 8,
+		},
+		{
+			name:              "pause ended and go to next step",
+			oldStatus:         &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 0, State: appsv1alpha1.CanaryStepStatePaused, PauseStartTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+			pods:              newPods(2, 0, 8),
+			expectedStatus:    &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 1, State: appsv1alpha1.CanaryStepStateUpgrading},
+			expectedPartition: 5,
+		},
+		{
+			name:              "pause until resumed",
+			oldStatus:         &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 1, State: appsv1alpha1.CanaryStepStatePaused, PauseStartTime: &metav1.Time{Time: now.Add(-time.Hour)}},
+			annotations:       map[string]string{appsv1alpha1.CloneSetCanaryResumeStepAnnotationKey: "new/0"},
+			pods:              newPods(5, 0, 5),
+			expectedStatus:    &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 1, State: appsv1alpha1.CanaryStepStatePaused, PauseStartTime: &metav1.Time{Time: now.Add(-time.Hour)}},
+			expectedPartition: 5,
+		},
+		{
+			name:              "pause for resume step of old revision",
+			oldStatus:         &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 1, State: appsv1alpha1.CanaryStepStatePaused, PauseStartTime: &metav1.Time{Time: now.Add(-time.Hour)}},
+			annotations:       map[string]string{appsv1alpha1.CloneSetCanaryResumeStepAnnotationKey: "old/1"},
+			pods:              newPods(5, 0, 5),
+			expectedStatus:    &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 1, State: appsv1alpha1.CanaryStepStatePaused, PauseStartTime: &metav1.Time{Time: now.Add(-time.Hour)}},
+			expectedPartition: 5,
+		},
+		{
+			name:              "resumed and go to last step",
+			oldStatus:         &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 1, State: appsv1alpha1.CanaryStepStatePaused, PauseStartTime: &metav1.Time{Time: now.Add(-time.Hour)}},
+			annotations:       map[string]string{appsv1alpha1.CloneSetCanaryResumeStepAnnotationKey: "new/1"},
+			pods:              newPods(5, 0, 5),
+			expectedStatus:    &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 2, State: appsv1alpha1.CanaryStepStateUpgrading},
+			expectedPartition: 0,
+		},
+		{
+			name:              "all steps completed",
+			oldStatus:         &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 2, State: appsv1alpha1.CanaryStepStateUpgrading},
+			pods:              newPods(10, 0, 0),
+			expectedStatus:    &appsv1alpha1.CloneSetCanaryStatus{Revision: "new", CurrentStep: 2, State: appsv1alpha1.CanaryStepStateCompleted},
+			expectedPartition: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := &appsv1alpha1.CloneSet{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+				Spec: appsv1alpha1.CloneSetSpec{
+					Replicas:       utilpointer.Int32Ptr(10),
+					UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{Partition: utilpointer.Int32Ptr(0), CanarySteps: steps},
+				},
+				Status: appsv1alpha1.CloneSetStatus{CanaryStatus: tc.oldStatus},
+			}
+
+			gotStatus, gotDuration := Calculate(cs, "new", tc.pods)
+			if !apiequality.Semantic.DeepEqual(gotStatus, tc.expectedStatus) {
+				t.Fatalf("expected status %+v, got %+v", tc.expectedStatus, gotStatus)
+			}
+			if gotDuration != tc.expectedDuration {
+				t.Fatalf("expected duration %v, got %v", tc.expectedDuration, gotDuration)
+			}
+			if gotPartition := GetPartition(cs, gotStatus); *gotPartition != tc.expectedPartition {
+				t.Fatalf("expected partition %v, got %v", tc.expectedPartition, *gotPartition)
+			}
+		})
+	}
+}
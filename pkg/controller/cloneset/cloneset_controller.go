@@ -24,11 +24,15 @@ import (
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
 	kruiseclient "github.com/openkruise/kruise/pkg/client"
+	"github.com/openkruise/kruise/pkg/controller/cloneset/canary"
 	clonesetcore "github.com/openkruise/kruise/pkg/controller/cloneset/core"
+	predownloadcontrol "github.com/openkruise/kruise/pkg/controller/cloneset/predownload"
+	"github.com/openkruise/kruise/pkg/controller/cloneset/progress"
 	revisioncontrol "github.com/openkruise/kruise/pkg/controller/cloneset/revision"
 	scalecontrol "github.com/openkruise/kruise/pkg/controller/cloneset/scale"
 	updatecontrol "github.com/openkruise/kruise/pkg/controller/cloneset/update"
 	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	volumeclaimcontrol "github.com/openkruise/kruise/pkg/controller/cloneset/volumeclaim"
 	"github.com/openkruise/kruise/pkg/util/expectations"
 	"github.com/openkruise/kruise/pkg/util/fieldindex"
 	"github.com/openkruise/kruise/pkg/util/gate"
@@ -38,9 +42,11 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog"
 	"k8s.io/kubernetes/pkg/controller/history"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -93,6 +99,8 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 	}
 	reconciler.scaleControl = scalecontrol.New(mgr.GetClient(), reconciler.recorder, scaleExpectations)
 	reconciler.updateControl = updatecontrol.New(mgr.GetClient(), reconciler.recorder, scaleExpectations, updateExpectations)
+	reconciler.preDownloadControl = predownloadcontrol.New(mgr.GetClient(), reconciler.recorder)
+	reconciler.volumeClaimControl = volumeclaimcontrol.New(mgr.GetClient(), reconciler.recorder, scaleExpectations)
 	reconciler.reconcileFunc = reconciler.doReconcile
 	return reconciler
 }
@@ -133,6 +141,16 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to ImagePullJob owned by CloneSet, to refresh the progress of image pre-download
+	if gate.ResourceEnabled(&appsv1alpha1.ImagePullJob{}) {
+		err = c.Watch(&source.Kind{Type: &appsv1alpha1.ImagePullJob{}}, &handler.EnqueueRequestForOwner{
+			IsController: true, OwnerType: &appsv1alpha1.CloneSet{},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -144,12 +162,14 @@ type ReconcileCloneSet struct {
 	scheme        *runtime.Scheme
 	reconcileFunc func(request reconcile.Request) (reconcile.Result, error)
 
-	recorder          record.EventRecorder
-	controllerHistory history.Interface
-	statusUpdater     StatusUpdater
-	revisionControl   revisioncontrol.Interface
-	scaleControl      scalecontrol.Interface
-	updateControl     updatecontrol.Interface
+	recorder           record.EventRecorder
+	controllerHistory  history.Interface
+	statusUpdater      StatusUpdater
+	revisionControl    revisioncontrol.Interface
+	scaleControl       scalecontrol.Interface
+	updateControl      updatecontrol.Interface
+	preDownloadControl predownloadcontrol.Interface
+	volumeClaimControl volumeclaimcontrol.Interface
 }
 
 // Reconcile reads that state of the cluster for a CloneSet object and makes changes based on the state read
@@ -158,9 +178,12 @@ type ReconcileCloneSet struct {
 // +kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=clonesets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=clonesets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=imagepulljobs,verbs=get;list;watch;create;update;patch;delete
 func (r *ReconcileCloneSet) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	return r.reconcileFunc(request)
 }
@@ -242,13 +265,29 @@ func (r *ReconcileCloneSet) doReconcile(request reconcile.Request) (res reconcil
 		return reconcile.Result{}, nil
 	}
 
+	// roll back the template if the update revision has failed to progress before the deadline
+	if rolledBack, err := r.rollbackIfProgressDeadlineExceeded(instance, revisions, updateRevision); err != nil || rolledBack {
+		return reconcile.Result{}, err
+	}
+
 	newStatus := appsv1alpha1.CloneSetStatus{
-		ObservedGeneration: instance.Generation,
-		UpdateRevision:     updateRevision.Name,
-		CollisionCount:     new(int32),
-		LabelSelector:      selector.String(),
+		ObservedGeneration:         instance.Generation,
+		CurrentRevision:            instance.Status.CurrentRevision,
+		UpdateRevision:             updateRevision.Name,
+		LastRolledBackRevision:     instance.Status.LastRolledBackRevision,
+		CollisionCount:             new(int32),
+		LabelSelector:              selector.String(),
+		InPlaceUpdateFallbackCount: instance.Status.InPlaceUpdateFallbackCount,
+		VolumeClaimUpdatedReplicas: instance.Status.VolumeClaimUpdatedReplicas,
 	}
 	*newStatus.CollisionCount = collisionCount
+	if newStatus.CurrentRevision == "" {
+		newStatus.CurrentRevision = currentRevision.Name
+	}
+
+	// calculate the current step of canary update, which decides the partition to scale and update pods
+	var canaryDelayDuration time.Duration
+	newStatus.CanaryStatus, canaryDelayDuration = canary.Calculate(instance, updateRevision.Name, filteredPods)
 
 	// scale and update pods
 	delayDuration, syncErr := r.syncCloneSet(instance, &newStatus, currentRevision, updateRevision, revisions, filteredPods, filteredPVCs)
@@ -266,6 +305,13 @@ func (r *ReconcileCloneSet) doReconcile(request reconcile.Request) (res reconcil
 		klog.Errorf("Failed to truncate history for %s: %v", request, err)
 	}
 
+	if canaryDelayDuration > 0 && (delayDuration == 0 || canaryDelayDuration < delayDuration) {
+		delayDuration = canaryDelayDuration
+	}
+	if deadlineDuration := progress.GetProgressDeadlineDuration(instance, &newStatus); deadlineDuration > 0 &&
+		(delayDuration == 0 || deadlineDuration < delayDuration) {
+		delayDuration = deadlineDuration
+	}
 	if syncErr == nil && instance.Spec.MinReadySeconds > 0 && newStatus.AvailableReplicas != newStatus.ReadyReplicas {
 		minReadyDuration := time.Second * time.Duration(instance.Spec.MinReadySeconds)
 		if delayDuration == 0 || minReadyDuration < delayDuration {
@@ -299,6 +345,34 @@ func (r *ReconcileCloneSet) syncCloneSet(
 	var podsScaleErr error
 	var podsUpdateErr error
 
+	// pre-download images of the update revision, which should not block scaling and updating
+	if preDownloadCondition, err := r.preDownloadControl.Manage(updateSet, updateRevision, filteredPods); err != nil {
+		klog.Errorf("CloneSet %s/%s failed to manage image pre-download: %v", instance.Namespace, instance.Name, err)
+		if oldCondition := clonesetutils.GetCondition(instance.Status, appsv1alpha1.CloneSetConditionImagePreDownload); oldCondition != nil {
+			newStatus.Conditions = append(newStatus.Conditions, *oldCondition)
+		}
+	} else if preDownloadCondition != nil {
+		newStatus.Conditions = append(newStatus.Conditions, *preDownloadCondition)
+	}
+
+	// expand the pvcs if the storage in volumeClaimTemplates has been increased
+	volumeClaimUpdatedReplicas, recreating, err := r.volumeClaimControl.Manage(instance, filteredPods, filteredPVCs)
+	if err != nil {
+		klog.Errorf("CloneSet %s/%s failed to manage volume claims: %v", instance.Namespace, instance.Name, err)
+	} else {
+		newStatus.VolumeClaimUpdatedReplicas = volumeClaimUpdatedReplicas
+	}
+	if recreating {
+		return delayDuration, nil
+	}
+
+	// scale and update pods with the partition of the current canary step
+	if newStatus.CanaryStatus != nil {
+		partition := canary.GetPartition(updateSet, newStatus.CanaryStatus)
+		currentSet.Spec.UpdateStrategy.Partition = partition
+		updateSet.Spec.UpdateStrategy.Partition = partition
+	}
+
 	scaling, podsScaleErr = r.scaleControl.Manage(currentSet, updateSet, currentRevision.Name, updateRevision.Name, filteredPods, filteredPVCs)
 	if podsScaleErr != nil {
 		newStatus.Conditions = append(newStatus.Conditions, appsv1alpha1.CloneSetCondition{
@@ -312,7 +386,7 @@ func (r *ReconcileCloneSet) syncCloneSet(
 		return delayDuration, podsScaleErr
 	}
 
-	delayDuration, podsUpdateErr = r.updateControl.Manage(updateSet, updateRevision, revisions, filteredPods, filteredPVCs)
+	delayDuration, podsUpdateErr = r.updateControl.Manage(updateSet, updateRevision, revisions, filteredPods, filteredPVCs, newStatus)
 	if podsUpdateErr != nil {
 		newStatus.Conditions = append(newStatus.Conditions, appsv1alpha1.CloneSetCondition{
 			Type:               appsv1alpha1.CloneSetConditionFailedUpdate,
@@ -324,6 +398,58 @@ func (r *ReconcileCloneSet) syncCloneSet(
 	return delayDuration, podsUpdateErr
 }
 
+// rollbackIfProgressDeadlineExceeded re-applies the current revision to the template of CloneSet, if autoRollback
+// is enabled and the update revision has failed to progress before the deadline.
+func (r *ReconcileCloneSet) rollbackIfProgressDeadlineExceeded(cs *appsv1alpha1.CloneSet,
+	revisions []*apps.ControllerRevision, updateRevision *apps.ControllerRevision) (bool, error) {
+
+	if !cs.Spec.UpdateStrategy.AutoRollback || cs.Status.UpdateRevision != updateRevision.Name ||
+		!progress.IsProgressDeadlineExceeded(&cs.Status) {
+		return false, nil
+	}
+
+	var stableRevision *apps.ControllerRevision
+	for i := range revisions {
+		if revisions[i].Name == cs.Status.CurrentRevision && revisions[i].Name != updateRevision.Name {
+			stableRevision = revisions[i]
+			break
+		}
+	}
+	if stableRevision == nil {
+		return false, nil
+	}
+
+	restoredSet, err := r.revisionControl.ApplyRevision(cs, stableRevision)
+	if err != nil {
+		return false, err
+	}
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		clone := &appsv1alpha1.CloneSet{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Namespace: cs.Namespace, Name: cs.Name}, clone); err != nil {
+			return err
+		}
+		clone.Spec.Template = restoredSet.Spec.Template
+		return r.Update(context.TODO(), clone)
+	})
+	if err != nil {
+		r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedRollback", "failed to roll back from revision %s to %s: %v",
+			updateRevision.Name, stableRevision.Name, err)
+		return false, err
+	}
+	r.recorder.Eventf(cs, v1.EventTypeWarning, "RolledBack",
+		"revision %s failed to progress before the deadline, rolled back to revision %s", updateRevision.Name, stableRevision.Name)
+
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		clone := &appsv1alpha1.CloneSet{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Namespace: cs.Namespace, Name: cs.Name}, clone); err != nil {
+			return err
+		}
+		clone.Status.LastRolledBackRevision = updateRevision.Name
+		return r.Status().Update(context.TODO(), clone)
+	})
+	return true, err
+}
+
 func (r *ReconcileCloneSet) getActiveRevisions(cs *appsv1alpha1.CloneSet, revisions []*apps.ControllerRevision, podsRevisions sets.String) (
 	*apps.ControllerRevision, *apps.ControllerRevision, int32, error,
 ) {
@@ -20,8 +20,13 @@ import (
 	"context"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/controller/cloneset/canary"
+	"github.com/openkruise/kruise/pkg/controller/cloneset/progress"
 	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	"github.com/openkruise/kruise/pkg/util/condition"
+	"github.com/openkruise/kruise/pkg/util/lifecycle"
 	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog"
@@ -47,8 +52,8 @@ func (r *realStatusUpdater) UpdateCloneSetStatus(cs *appsv1alpha1.CloneSet, newS
 		return nil
 	}
 
-	klog.Infof("To update CloneSet status for  %s/%s, replicas=%d ready=%d available=%d updated=%d updatedReady=%d, revisions update=%s",
-		cs.Namespace, cs.Name, newStatus.Replicas, newStatus.ReadyReplicas, newStatus.AvailableReplicas, newStatus.UpdatedReplicas, newStatus.UpdatedReadyReplicas, newStatus.UpdateRevision)
+	klog.Infof("To update CloneSet status for  %s/%s, replicas=%d ready=%d available=%d updated=%d updatedReady=%d, revisions current=%s update=%s",
+		cs.Namespace, cs.Name, newStatus.Replicas, newStatus.ReadyReplicas, newStatus.AvailableReplicas, newStatus.UpdatedReplicas, newStatus.UpdatedReadyReplicas, newStatus.CurrentRevision, newStatus.UpdateRevision)
 	return r.updateStatus(cs, newStatus)
 }
 
@@ -71,8 +76,15 @@ func (r *realStatusUpdater) inconsistentStatus(cs *appsv1alpha1.CloneSet, newSta
 		newStatus.AvailableReplicas != oldStatus.AvailableReplicas ||
 		newStatus.UpdatedReadyReplicas != oldStatus.UpdatedReadyReplicas ||
 		newStatus.UpdatedReplicas != oldStatus.UpdatedReplicas ||
+		newStatus.CurrentRevision != oldStatus.CurrentRevision ||
 		newStatus.UpdateRevision != oldStatus.UpdateRevision ||
-		newStatus.LabelSelector != oldStatus.LabelSelector
+		newStatus.LastRolledBackRevision != oldStatus.LastRolledBackRevision ||
+		newStatus.LabelSelector != oldStatus.LabelSelector ||
+		newStatus.InPlaceUpdateFallbackCount != oldStatus.InPlaceUpdateFallbackCount ||
+		newStatus.VolumeClaimUpdatedReplicas != oldStatus.VolumeClaimUpdatedReplicas ||
+		!apiequality.Semantic.DeepEqual(newStatus.LifecycleStateReplicas, oldStatus.LifecycleStateReplicas) ||
+		!apiequality.Semantic.DeepEqual(newStatus.CanaryStatus, oldStatus.CanaryStatus) ||
+		condition.Inconsistent(progress.ToConditions(oldStatus.Conditions), progress.ToConditions(newStatus.Conditions))
 }
 
 func (r *realStatusUpdater) calculateStatus(cs *appsv1alpha1.CloneSet, newStatus *appsv1alpha1.CloneSetStatus, pods []*v1.Pod) {
@@ -81,14 +93,32 @@ func (r *realStatusUpdater) calculateStatus(cs *appsv1alpha1.CloneSet, newStatus
 		if clonesetutils.IsRunningAndReady(pod) {
 			newStatus.ReadyReplicas++
 		}
-		if clonesetutils.IsRunningAndAvailable(pod, cs.Spec.MinReadySeconds) {
+		// pods out of Normal lifecycle state are waiting for hooks, which should not be considered as available
+		state := lifecycle.GetPodLifecycleState(pod)
+		inNormalState := state == appsv1alpha1.LifecycleStateNormal
+		if !inNormalState {
+			if newStatus.LifecycleStateReplicas == nil {
+				newStatus.LifecycleStateReplicas = make(map[appsv1alpha1.LifecycleStateType]int32)
+			}
+			newStatus.LifecycleStateReplicas[state]++
+		}
+		if clonesetutils.IsRunningAndAvailable(pod, cs.Spec.MinReadySeconds) && inNormalState {
 			newStatus.AvailableReplicas++
 		}
 		if clonesetutils.GetPodRevision(pod) == newStatus.UpdateRevision {
 			newStatus.UpdatedReplicas++
 		}
-		if clonesetutils.IsRunningAndReady(pod) && clonesetutils.GetPodRevision(pod) == newStatus.UpdateRevision {
+		if clonesetutils.IsRunningAndReady(pod) && clonesetutils.GetPodRevision(pod) == newStatus.UpdateRevision && inNormalState {
 			newStatus.UpdatedReadyReplicas++
 		}
 	}
+
+	if cs.Spec.Replicas == nil {
+		return
+	}
+	// the update revision becomes the stable one once all pods have been updated and been ready
+	if progress.IsRolloutCompleted(newStatus, *cs.Spec.Replicas) {
+		newStatus.CurrentRevision = newStatus.UpdateRevision
+	}
+	progress.UpdateConditions(cs, newStatus, *canary.GetPartition(cs, newStatus.CanaryStatus))
 }
@@ -25,6 +25,7 @@ import (
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
 	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
+	"github.com/openkruise/kruise/pkg/util/lifecycle"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -99,6 +100,9 @@ func (c *commonControl) newVersionedPods(cs *appsv1alpha1.CloneSet, revision str
 
 		inplaceupdate.InjectReadinessGate(pod)
 		clonesetutils.UpdateStorage(cs, pod)
+		if cs.Spec.Lifecycle != nil {
+			lifecycle.SetPodLifecycle(appsv1alpha1.LifecycleStateNormal)(pod)
+		}
 
 		newPods = append(newPods, pod)
 	}
@@ -117,7 +121,7 @@ func (c *commonControl) IsPodUpdateReady(pod *v1.Pod, minReadySeconds int32) boo
 	if condition != nil && condition.Status != v1.ConditionTrue {
 		return false
 	}
-	return true
+	return lifecycle.GetPodLifecycleState(pod) == appsv1alpha1.LifecycleStateNormal
 }
 
 func (c *commonControl) GetPodsSortFunc(pods []*v1.Pod, waitUpdateIndexes []int) func(i, j int) bool {
@@ -131,6 +135,7 @@ func (c *commonControl) GetUpdateOptions() *inplaceupdate.UpdateOptions {
 	opts := &inplaceupdate.UpdateOptions{}
 	if c.Spec.UpdateStrategy.InPlaceUpdateStrategy != nil {
 		opts.GracePeriodSeconds = c.Spec.UpdateStrategy.InPlaceUpdateStrategy.GracePeriodSeconds
+		opts.TimeoutSeconds = c.Spec.UpdateStrategy.InPlaceUpdateStrategy.TimeoutSeconds
 	}
 	return opts
 }
@@ -0,0 +1,313 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predownload
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	updatecontrol "github.com/openkruise/kruise/pkg/controller/cloneset/update"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	"github.com/openkruise/kruise/pkg/util"
+	"github.com/openkruise/kruise/pkg/util/gate"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var controllerKind = appsv1alpha1.SchemeGroupVersion.WithKind("CloneSet")
+
+// Interface for managing the ImagePullJobs that pre-download images of the update revision.
+type Interface interface {
+	// Manage creates, updates or deletes the ImagePullJobs owned by the CloneSet,
+	// and returns the condition that shows the progress of pre-downloading, nil if there is no job.
+	Manage(cs *appsv1alpha1.CloneSet, updateRevision *apps.ControllerRevision, pods []*v1.Pod) (*appsv1alpha1.CloneSetCondition, error)
+}
+
+// New returns a pre-download control.
+func New(c client.Client, recorder record.EventRecorder) Interface {
+	return &realControl{Client: c, recorder: recorder, jobEnabled: gate.ResourceEnabled(&appsv1alpha1.ImagePullJob{})}
+}
+
+type realControl struct {
+	client.Client
+	recorder record.EventRecorder
+	// jobEnabled indicates whether ImagePullJob resource is enabled in the cluster
+	jobEnabled bool
+}
+
+func (c *realControl) Manage(cs *appsv1alpha1.CloneSet, updateRevision *apps.ControllerRevision, pods []*v1.Pod) (*appsv1alpha1.CloneSetCondition, error) {
+	if !c.jobEnabled {
+		return nil, nil
+	}
+
+	jobs, err := c.getOwnedJobs(cs)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isPreDownloadEnabled(cs) {
+		return nil, c.deleteJobs(cs, jobs)
+	}
+
+	// jobs of old revisions are useless
+	var activeJobs []*appsv1alpha1.ImagePullJob
+	var staleJobs []*appsv1alpha1.ImagePullJob
+	for _, job := range jobs {
+		if job.Labels[apps.ControllerRevisionHashLabelKey] == updateRevision.Name {
+			activeJobs = append(activeJobs, job)
+		} else {
+			staleJobs = append(staleJobs, job)
+		}
+	}
+	if err := c.deleteJobs(cs, staleJobs); err != nil {
+		return nil, err
+	}
+
+	// just pause the pulling of the active jobs
+	if cs.Spec.UpdateStrategy.Paused {
+		zero := intstr.FromInt(0)
+		for _, job := range activeJobs {
+			if err := c.updateJobParallelism(job, &zero); err != nil {
+				return nil, err
+			}
+		}
+		return calculateCondition(cs, activeJobs), nil
+	}
+
+	podsToUpdate := updatecontrol.GetPodsToUpdate(cs, updateRevision, pods)
+	if len(podsToUpdate) == 0 {
+		// all pods that should be updated have been updated, so the rollout completes
+		return nil, c.deleteJobs(cs, activeJobs)
+	}
+
+	activeJobs, err = c.syncJobs(cs, updateRevision, podsToUpdate, activeJobs)
+	if err != nil {
+		return nil, err
+	}
+	return calculateCondition(cs, activeJobs), nil
+}
+
+// syncJobs makes sure each image of the update revision is pre-downloaded on the nodes of pods waiting to be updated.
+func (c *realControl) syncJobs(cs *appsv1alpha1.CloneSet, updateRevision *apps.ControllerRevision,
+	podsToUpdate []*v1.Pod, activeJobs []*appsv1alpha1.ImagePullJob) ([]*appsv1alpha1.ImagePullJob, error) {
+
+	existingJobs := make(map[string]*appsv1alpha1.ImagePullJob, len(activeJobs))
+	for _, job := range activeJobs {
+		existingJobs[job.Name] = job
+	}
+
+	for _, container := range cs.Spec.Template.Spec.Containers {
+		if _, _, err := util.ParseRepositoryTag(container.Image); err != nil {
+			klog.V(4).Infof("CloneSet %s/%s skip pre-downloading image %s: %v", cs.Namespace, cs.Name, container.Image, err)
+			continue
+		}
+
+		nodeNames := getNodesToPreDownload(container, podsToUpdate)
+		if nodeNames.Len() == 0 {
+			continue
+		}
+
+		name := fmt.Sprintf("%s-%s", updateRevision.Name, container.Name)
+		if job, ok := existingJobs[name]; ok {
+			// never remove nodes from the job, so that the progress of pulling will not go back
+			if job.Spec.Selector != nil {
+				nodeNames.Insert(job.Spec.Selector.Names...)
+			}
+			if err := c.updateJob(job, nodeNames.List(), cs.Spec.UpdateStrategy.ImagePreDownload.Parallelism); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		job := newImagePullJob(cs, updateRevision, name, container.Image, nodeNames.List())
+		if err := c.Create(context.TODO(), job); err != nil {
+			if errors.IsAlreadyExists(err) {
+				continue
+			}
+			c.recorder.Eventf(cs, v1.EventTypeWarning, "FailedCreateImagePullJob",
+				"failed to create ImagePullJob %s for image %s: %v", name, container.Image, err)
+			return nil, err
+		}
+		c.recorder.Eventf(cs, v1.EventTypeNormal, "SuccessfulCreateImagePullJob",
+			"create ImagePullJob %s to pre-download image %s on %d nodes", name, container.Image, nodeNames.Len())
+		activeJobs = append(activeJobs, job)
+	}
+	return activeJobs, nil
+}
+
+func (c *realControl) getOwnedJobs(cs *appsv1alpha1.CloneSet) ([]*appsv1alpha1.ImagePullJob, error) {
+	jobList := &appsv1alpha1.ImagePullJobList{}
+	if err := c.List(context.TODO(), client.InNamespace(cs.Namespace), jobList); err != nil {
+		return nil, err
+	}
+	var jobs []*appsv1alpha1.ImagePullJob
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if owner := metav1.GetControllerOf(job); owner != nil && owner.UID == cs.UID && job.DeletionTimestamp == nil {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs, nil
+}
+
+func (c *realControl) deleteJobs(cs *appsv1alpha1.CloneSet, jobs []*appsv1alpha1.ImagePullJob) error {
+	for _, job := range jobs {
+		if err := c.Delete(context.TODO(), job); err != nil && !errors.IsNotFound(err) {
+			c.recorder.Eventf(cs, v1.EventTypeWarning, "FailedDeleteImagePullJob", "failed to delete ImagePullJob %s: %v", job.Name, err)
+			return err
+		}
+		klog.V(3).Infof("CloneSet %s/%s deleted ImagePullJob %s", cs.Namespace, cs.Name, job.Name)
+	}
+	return nil
+}
+
+func (c *realControl) updateJob(job *appsv1alpha1.ImagePullJob, nodeNames []string, parallelism *intstr.IntOrString) error {
+	if job.Spec.Selector != nil && sets.NewString(job.Spec.Selector.Names...).Equal(sets.NewString(nodeNames...)) &&
+		isParallelismEqual(job.Spec.Parallelism, parallelism) {
+		return nil
+	}
+	return c.modifyJob(job, func(obj *appsv1alpha1.ImagePullJob) {
+		obj.Spec.Selector = &appsv1alpha1.NodeSelector{Names: nodeNames}
+		obj.Spec.Parallelism = parallelism
+	})
+}
+
+func (c *realControl) updateJobParallelism(job *appsv1alpha1.ImagePullJob, parallelism *intstr.IntOrString) error {
+	if isParallelismEqual(job.Spec.Parallelism, parallelism) {
+		return nil
+	}
+	return c.modifyJob(job, func(obj *appsv1alpha1.ImagePullJob) {
+		obj.Spec.Parallelism = parallelism
+	})
+}
+
+func (c *realControl) modifyJob(job *appsv1alpha1.ImagePullJob, modifyFunc func(*appsv1alpha1.ImagePullJob)) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		clone := &appsv1alpha1.ImagePullJob{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Namespace: job.Namespace, Name: job.Name}, clone); err != nil {
+			return err
+		}
+		modifyFunc(clone)
+		if err := c.Update(context.TODO(), clone); err != nil {
+			return err
+		}
+		*job = *clone
+		return nil
+	})
+}
+
+func isPreDownloadEnabled(cs *appsv1alpha1.CloneSet) bool {
+	if cs.Spec.UpdateStrategy.ImagePreDownload == nil {
+		return false
+	}
+	// images are only useful to be pre-downloaded when pods are updated in-place
+	return cs.Spec.UpdateStrategy.Type == appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType ||
+		cs.Spec.UpdateStrategy.Type == appsv1alpha1.InPlaceOnlyCloneSetUpdateStrategyType
+}
+
+// getNodesToPreDownload returns the names of nodes hosting pods whose container image differs from the new one.
+func getNodesToPreDownload(container v1.Container, pods []*v1.Pod) sets.String {
+	nodeNames := sets.NewString()
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		var oldImage string
+		for i := range pod.Spec.Containers {
+			if pod.Spec.Containers[i].Name == container.Name {
+				oldImage = pod.Spec.Containers[i].Image
+				break
+			}
+		}
+		if oldImage != container.Image {
+			nodeNames.Insert(pod.Spec.NodeName)
+		}
+	}
+	return nodeNames
+}
+
+func newImagePullJob(cs *appsv1alpha1.CloneSet, updateRevision *apps.ControllerRevision, name, image string, nodeNames []string) *appsv1alpha1.ImagePullJob {
+	job := &appsv1alpha1.ImagePullJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       cs.Namespace,
+			Name:            name,
+			Labels:          map[string]string{apps.ControllerRevisionHashLabelKey: updateRevision.Name},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cs, controllerKind)},
+		},
+		Spec: appsv1alpha1.ImagePullJobSpec{
+			Image:       image,
+			Selector:    &appsv1alpha1.NodeSelector{Names: nodeNames},
+			Parallelism: cs.Spec.UpdateStrategy.ImagePreDownload.Parallelism,
+			// the job will be deleted by CloneSet when the rollout completes
+			CompletionPolicy: appsv1alpha1.CompletionPolicy{Type: appsv1alpha1.Never},
+		},
+	}
+	for _, secret := range cs.Spec.Template.Spec.ImagePullSecrets {
+		job.Spec.PullSecrets = append(job.Spec.PullSecrets, secret.Name)
+	}
+	return job
+}
+
+// calculateCondition returns the condition of pre-downloading summarized from all active jobs.
+func calculateCondition(cs *appsv1alpha1.CloneSet, jobs []*appsv1alpha1.ImagePullJob) *appsv1alpha1.CloneSetCondition {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	var desired, succeeded, failed int32
+	for _, job := range jobs {
+		desired += job.Status.Desired
+		succeeded += job.Status.Succeeded
+		failed += job.Status.Failed
+	}
+
+	condition := &appsv1alpha1.CloneSetCondition{
+		Type:    appsv1alpha1.CloneSetConditionImagePreDownload,
+		Status:  v1.ConditionFalse,
+		Reason:  "ImagePreDownloading",
+		Message: fmt.Sprintf("%d/%d image pulling tasks succeeded, %d failed", succeeded, desired, failed),
+	}
+	if desired > 0 && succeeded+failed >= desired {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = "ImagePreDownloaded"
+	}
+
+	condition.LastTransitionTime = metav1.Now()
+	if oldCondition := clonesetutils.GetCondition(cs.Status, condition.Type); oldCondition != nil && oldCondition.Status == condition.Status {
+		condition.LastTransitionTime = oldCondition.LastTransitionTime
+	}
+	return condition
+}
+
+func isParallelismEqual(a, b *intstr.IntOrString) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
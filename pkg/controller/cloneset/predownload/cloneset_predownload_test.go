@@ -0,0 +1,240 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predownload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openkruise/kruise/pkg/apis"
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	_ = apis.AddToScheme(scheme.Scheme)
+}
+
+func newCloneSet(partition int32, paused bool) *appsv1alpha1.CloneSet {
+	parallelism := intstr.FromInt(2)
+	return &appsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cs", UID: "cs-uid"},
+		Spec: appsv1alpha1.CloneSetSpec{
+			Replicas: &[]int32{3}[0],
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "demo"}},
+				Spec: v1.PodSpec{
+					Containers:       []v1.Container{{Name: "main", Image: "nginx:1.19"}, {Name: "sidecar", Image: "busybox:1.31"}},
+					ImagePullSecrets: []v1.LocalObjectReference{{Name: "secret"}},
+				},
+			},
+			UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+				Type:             appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+				Partition:        &partition,
+				Paused:           paused,
+				ImagePreDownload: &appsv1alpha1.ImagePreDownloadStrategy{Parallelism: &parallelism},
+			},
+		},
+	}
+}
+
+func newPod(name, nodeName, revision string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{"app": "demo", apps.ControllerRevisionHashLabelKey: revision},
+		},
+		Spec: v1.PodSpec{
+			NodeName:   nodeName,
+			Containers: []v1.Container{{Name: "main", Image: "nginx:1.18"}, {Name: "sidecar", Image: "busybox:1.31"}},
+		},
+	}
+}
+
+func newJob(cs *appsv1alpha1.CloneSet, name, revision string, nodeNames ...string) *appsv1alpha1.ImagePullJob {
+	return &appsv1alpha1.ImagePullJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       cs.Namespace,
+			Name:            name,
+			Labels:          map[string]string{apps.ControllerRevisionHashLabelKey: revision},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cs, controllerKind)},
+		},
+		Spec: appsv1alpha1.ImagePullJobSpec{
+			Image:            "nginx:1.19",
+			Selector:         &appsv1alpha1.NodeSelector{Names: nodeNames},
+			Parallelism:      cs.Spec.UpdateStrategy.ImagePreDownload.Parallelism,
+			CompletionPolicy: appsv1alpha1.CompletionPolicy{Type: appsv1alpha1.Never},
+		},
+		Status: appsv1alpha1.ImagePullJobStatus{Desired: int32(len(nodeNames)), Succeeded: 1},
+	}
+}
+
+func TestManage(t *testing.T) {
+	updateRevision := &apps.ControllerRevision{ObjectMeta: metav1.ObjectMeta{Name: "cs-new"}}
+
+	cases := []struct {
+		name            string
+		cs              *appsv1alpha1.CloneSet
+		pods            []*v1.Pod
+		jobs            func(cs *appsv1alpha1.CloneSet) []*appsv1alpha1.ImagePullJob
+		expectNodes     map[string]int
+		expectPaused    bool
+		expectCondition *appsv1alpha1.CloneSetCondition
+	}{
+		{
+			name: "create job for pods waiting to update",
+			cs:   newCloneSet(0, false),
+			pods: []*v1.Pod{
+				newPod("pod-0", "node-0", "cs-old"),
+				newPod("pod-1", "node-1", "cs-old"),
+				newPod("pod-2", "node-1", "cs-old"),
+				newPod("pod-3", "", "cs-old"),
+			},
+			expectNodes:     map[string]int{"cs-new-main": 2},
+			expectCondition: &appsv1alpha1.CloneSetCondition{Status: v1.ConditionFalse, Reason: "ImagePreDownloading"},
+		},
+		{
+			name: "honor partition",
+			cs:   newCloneSet(2, false),
+			pods: []*v1.Pod{
+				newPod("pod-0", "node-0", "cs-old"),
+				newPod("pod-1", "node-1", "cs-old"),
+				newPod("pod-2", "node-2", "cs-old"),
+			},
+			expectNodes:     map[string]int{"cs-new-main": 1},
+			expectCondition: &appsv1alpha1.CloneSetCondition{Status: v1.ConditionFalse, Reason: "ImagePreDownloading"},
+		},
+		{
+			name: "add nodes to existing job and delete stale job",
+			cs:   newCloneSet(0, false),
+			pods: []*v1.Pod{
+				newPod("pod-0", "node-0", "cs-new"),
+				newPod("pod-1", "node-1", "cs-old"),
+				newPod("pod-2", "node-2", "cs-old"),
+			},
+			jobs: func(cs *appsv1alpha1.CloneSet) []*appsv1alpha1.ImagePullJob {
+				return []*appsv1alpha1.ImagePullJob{
+					newJob(cs, "cs-new-main", "cs-new", "node-0", "node-1"),
+					newJob(cs, "cs-old-main", "cs-old", "node-0"),
+				}
+			},
+			expectNodes:     map[string]int{"cs-new-main": 3},
+			expectCondition: &appsv1alpha1.CloneSetCondition{Status: v1.ConditionFalse, Reason: "ImagePreDownloading"},
+		},
+		{
+			name: "pause job when cloneset paused",
+			cs:   newCloneSet(0, true),
+			pods: []*v1.Pod{
+				newPod("pod-0", "node-0", "cs-old"),
+				newPod("pod-1", "node-1", "cs-old"),
+			},
+			jobs: func(cs *appsv1alpha1.CloneSet) []*appsv1alpha1.ImagePullJob {
+				return []*appsv1alpha1.ImagePullJob{newJob(cs, "cs-new-main", "cs-new", "node-0")}
+			},
+			expectNodes:     map[string]int{"cs-new-main": 1},
+			expectPaused:    true,
+			expectCondition: &appsv1alpha1.CloneSetCondition{Status: v1.ConditionTrue, Reason: "ImagePreDownloaded"},
+		},
+		{
+			name: "delete job when rollout completes",
+			cs:   newCloneSet(0, false),
+			pods: []*v1.Pod{
+				newPod("pod-0", "node-0", "cs-new"),
+				newPod("pod-1", "node-1", "cs-new"),
+			},
+			jobs: func(cs *appsv1alpha1.CloneSet) []*appsv1alpha1.ImagePullJob {
+				return []*appsv1alpha1.ImagePullJob{newJob(cs, "cs-new-main", "cs-new", "node-0", "node-1")}
+			},
+			expectNodes: map[string]int{},
+		},
+		{
+			name: "delete job when pre-download disabled",
+			cs: func() *appsv1alpha1.CloneSet {
+				cs := newCloneSet(0, false)
+				cs.Spec.UpdateStrategy.Type = appsv1alpha1.RecreateCloneSetUpdateStrategyType
+				return cs
+			}(),
+			pods: []*v1.Pod{newPod("pod-0", "node-0", "cs-old")},
+			jobs: func(cs *appsv1alpha1.CloneSet) []*appsv1alpha1.ImagePullJob {
+				return []*appsv1alpha1.ImagePullJob{newJob(cs, "cs-new-main", "cs-new", "node-0")}
+			},
+			expectNodes: map[string]int{},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			objects := []runtime.Object{testCase.cs}
+			if testCase.jobs != nil {
+				for _, job := range testCase.jobs(testCase.cs) {
+					objects = append(objects, job)
+				}
+			}
+			fakeClient := fake.NewFakeClient(objects...)
+			ctrl := &realControl{Client: fakeClient, recorder: record.NewFakeRecorder(10), jobEnabled: true}
+
+			condition, err := ctrl.Manage(testCase.cs, updateRevision, testCase.pods)
+			if err != nil {
+				t.Fatalf("failed to manage: %v", err)
+			}
+
+			jobList := &appsv1alpha1.ImagePullJobList{}
+			if err := fakeClient.List(context.TODO(), &client.ListOptions{}, jobList); err != nil {
+				t.Fatalf("failed to list jobs: %v", err)
+			}
+			if len(jobList.Items) != len(testCase.expectNodes) {
+				t.Fatalf("expected %d jobs, got %d", len(testCase.expectNodes), len(jobList.Items))
+			}
+			for _, job := range jobList.Items {
+				expectNodes, ok := testCase.expectNodes[job.Name]
+				if !ok {
+					t.Fatalf("unexpected job %s", job.Name)
+				}
+				if len(job.Spec.Selector.Names) != expectNodes {
+					t.Fatalf("expected job %s with %d nodes, got %v", job.Name, expectNodes, job.Spec.Selector.Names)
+				}
+				if paused := job.Spec.Parallelism.IntValue() == 0; paused != testCase.expectPaused {
+					t.Fatalf("expected job %s paused %v, got parallelism %v", job.Name, testCase.expectPaused, job.Spec.Parallelism)
+				}
+				if job.Spec.Image != "nginx:1.19" || metav1.GetControllerOf(&job).UID != testCase.cs.UID {
+					t.Fatalf("unexpected job %+v", job)
+				}
+			}
+
+			if testCase.expectCondition == nil {
+				if condition != nil {
+					t.Fatalf("expected no condition, got %+v", condition)
+				}
+				return
+			}
+			if condition == nil || condition.Type != appsv1alpha1.CloneSetConditionImagePreDownload ||
+				condition.Status != testCase.expectCondition.Status || condition.Reason != testCase.expectCondition.Reason {
+				t.Fatalf("expected condition %+v, got %+v", testCase.expectCondition, condition)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	"github.com/openkruise/kruise/pkg/util/condition"
+	v1 "k8s.io/api/core/v1"
+	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var timeNow = time.Now
+
+// UpdateConditions sets the Progressing, Available and ReplicaFailure conditions into the new status, by comparing
+// it with the old status of CloneSet. The partition should be the one in effect in this reconcile.
+func UpdateConditions(cs *appsv1alpha1.CloneSet, newStatus *appsv1alpha1.CloneSetStatus, partition int32) {
+	if cs.Spec.Replicas == nil {
+		return
+	}
+	oldProgress := getProgress(cs, &cs.Status, partition)
+	newProgress := getProgress(cs, newStatus, partition)
+	if failedCondition := clonesetutils.GetCondition(*newStatus, appsv1alpha1.CloneSetConditionFailedScale); failedCondition != nil && failedCondition.Status == v1.ConditionTrue {
+		newProgress.FailureReason, newProgress.FailureMessage = appsv1alpha1.WorkloadReasonFailedScale, failedCondition.Message
+	} else if failedCondition := clonesetutils.GetCondition(*newStatus, appsv1alpha1.CloneSetConditionFailedUpdate); failedCondition != nil && failedCondition.Status == v1.ConditionTrue {
+		newProgress.FailureReason, newProgress.FailureMessage = appsv1alpha1.WorkloadReasonFailedUpdate, failedCondition.Message
+	}
+
+	conditions := condition.Compute(ToConditions(cs.Status.Conditions), oldProgress, newProgress, timeNow())
+
+	var newConditions []appsv1alpha1.CloneSetCondition
+	for _, c := range newStatus.Conditions {
+		if !condition.IsWorkloadConditionType(string(c.Type)) {
+			newConditions = append(newConditions, c)
+		}
+	}
+	newStatus.Conditions = append(newConditions, fromConditions(conditions)...)
+}
+
+// GetProgressDeadlineDuration returns the duration left before the progress deadline is exceeded,
+// or 0 if there is no deadline or it has already been exceeded.
+func GetProgressDeadlineDuration(cs *appsv1alpha1.CloneSet, status *appsv1alpha1.CloneSetStatus) time.Duration {
+	return condition.GetProgressDeadlineDuration(ToConditions(status.Conditions), cs.Spec.ProgressDeadlineSeconds, timeNow())
+}
+
+// IsProgressDeadlineExceeded returns whether the update revision has failed to progress before the deadline.
+func IsProgressDeadlineExceeded(status *appsv1alpha1.CloneSetStatus) bool {
+	return condition.IsProgressDeadlineExceeded(ToConditions(status.Conditions))
+}
+
+// IsRolloutCompleted returns whether all pods have been updated to the update revision and been ready.
+func IsRolloutCompleted(status *appsv1alpha1.CloneSetStatus, replicas int32) bool {
+	return condition.IsRolloutCompleted(&condition.Progress{
+		Replicas:             replicas,
+		ActualReplicas:       status.Replicas,
+		UpdatedReplicas:      status.UpdatedReplicas,
+		UpdatedReadyReplicas: status.UpdatedReadyReplicas,
+	})
+}
+
+func getProgress(cs *appsv1alpha1.CloneSet, status *appsv1alpha1.CloneSetStatus, partition int32) *condition.Progress {
+	replicas := *cs.Spec.Replicas
+	maxUnavailable, _ := intstrutil.GetValueFromIntOrPercent(
+		intstrutil.ValueOrDefault(cs.Spec.UpdateStrategy.MaxUnavailable, intstrutil.FromString(appsv1alpha1.DefaultCloneSetMaxUnavailable)), int(replicas), true)
+	return &condition.Progress{
+		Kind:                    "CloneSet",
+		Replicas:                replicas,
+		ActualReplicas:          status.Replicas,
+		AvailableReplicas:       status.AvailableReplicas,
+		MinAvailableReplicas:    replicas - int32(maxUnavailable),
+		UpdatedReplicas:         status.UpdatedReplicas,
+		UpdatedReadyReplicas:    status.UpdatedReadyReplicas,
+		Partition:               partition,
+		UpdateRevision:          status.UpdateRevision,
+		Paused:                  cs.Spec.UpdateStrategy.Paused,
+		ProgressDeadlineSeconds: cs.Spec.ProgressDeadlineSeconds,
+	}
+}
+
+// ToConditions converts the conditions of CloneSet into the common form of workload conditions.
+func ToConditions(cloneSetConditions []appsv1alpha1.CloneSetCondition) []condition.Condition {
+	var conditions []condition.Condition
+	for _, c := range cloneSetConditions {
+		conditions = append(conditions, condition.Condition{
+			Type:               string(c.Type),
+			Status:             c.Status,
+			LastUpdateTime:     c.LastUpdateTime,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	return conditions
+}
+
+func fromConditions(conditions []condition.Condition) []appsv1alpha1.CloneSetCondition {
+	var cloneSetConditions []appsv1alpha1.CloneSetCondition
+	for _, c := range conditions {
+		cloneSetConditions = append(cloneSetConditions, appsv1alpha1.CloneSetCondition{
+			Type:               appsv1alpha1.CloneSetConditionType(c.Type),
+			Status:             c.Status,
+			LastUpdateTime:     c.LastUpdateTime,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	return cloneSetConditions
+}
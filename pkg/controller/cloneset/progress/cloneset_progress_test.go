@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilpointer "k8s.io/utils/pointer"
+)
+
+func TestUpdateConditions(t *testing.T) {
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	progressingCondition := func(status v1.ConditionStatus, reason string, lastUpdateTime time.Time) appsv1alpha1.CloneSetCondition {
+		return appsv1alpha1.CloneSetCondition{
+			Type:           appsv1alpha1.CloneSetConditionProgressing,
+			Status:         status,
+			Reason:         reason,
+			LastUpdateTime: metav1.NewTime(lastUpdateTime),
+		}
+	}
+
+	cases := []struct {
+		name                     string
+		paused                   bool
+		partition                int32
+		oldStatus                appsv1alpha1.CloneSetStatus
+		newStatus                appsv1alpha1.CloneSetStatus
+		expectedAvailable        v1.ConditionStatus
+		expectedProgressing      appsv1alpha1.CloneSetCondition
+		expectedDeadlineDuration time.Duration
+	}{
+		{
+			name:                     "begin to progress",
+			oldStatus:                appsv1alpha1.CloneSetStatus{Replicas: 10, AvailableReplicas: 10, UpdateRevision: "v1"},
+			newStatus:                appsv1alpha1.CloneSetStatus{Replicas: 10, AvailableReplicas: 10, UpdateRevision: "v2"},
+			expectedAvailable:        v1.ConditionTrue,
+			expectedProgressing:      progressingCondition(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, now),
+			expectedDeadlineDuration: time.Minute,
+		},
+		{
+			name: "no progress before deadline",
+			oldStatus: appsv1alpha1.CloneSetStatus{Replicas: 10, AvailableReplicas: 7, UpdatedReplicas: 3, UpdateRevision: "v2",
+				Conditions: []appsv1alpha1.CloneSetCondition{progressingCondition(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, now.Add(-time.Second*20))}},
+			newStatus:                appsv1alpha1.CloneSetStatus{Replicas: 10, AvailableReplicas: 7, UpdatedReplicas: 3, UpdateRevision: "v2"},
+			expectedAvailable:        v1.ConditionFalse,
+			expectedProgressing:      progressingCondition(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, now.Add(-time.Second*20)),
+			expectedDeadlineDuration: time.Second * 40,
+		},
+		{
+			name: "no progress after deadline",
+			oldStatus: appsv1alpha1.CloneSetStatus{Replicas: 10, AvailableReplicas: 8, UpdatedReplicas: 3, UpdateRevision: "v2",
+				Conditions: []appsv1alpha1.CloneSetCondition{progressingCondition(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, now.Add(-time.Minute*2))}},
+			newStatus:           appsv1alpha1.CloneSetStatus{Replicas: 10, AvailableReplicas: 8, UpdatedReplicas: 3, UpdateRevision: "v2"},
+			expectedAvailable:   v1.ConditionTrue,
+			expectedProgressing: progressingCondition(v1.ConditionFalse, appsv1alpha1.WorkloadReasonProgressDeadlineExceeded, now),
+		},
+		{
+			name: "progress again after deadline exceeded",
+			oldStatus: appsv1alpha1.CloneSetStatus{Replicas: 10, AvailableReplicas: 8, UpdatedReplicas: 3, UpdateRevision: "v2",
+				Conditions: []appsv1alpha1.CloneSetCondition{progressingCondition(v1.ConditionFalse, appsv1alpha1.WorkloadReasonProgressDeadlineExceeded, now.Add(-time.Minute))}},
+			newStatus:                appsv1alpha1.CloneSetStatus{Replicas: 10, AvailableReplicas: 8, UpdatedReplicas: 4, UpdateRevision: "v2"},
+			expectedAvailable:        v1.ConditionTrue,
+			expectedProgressing:      progressingCondition(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, now),
+			expectedDeadlineDuration: time.Minute,
+		},
+		{
+			name:      "completed with partition",
+			partition: 8,
+			oldStatus: appsv1alpha1.CloneSetStatus{Replicas: 10, AvailableReplicas: 10, UpdatedReplicas: 2, UpdateRevision: "v2",
+				Conditions: []appsv1alpha1.CloneSetCondition{progressingCondition(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, now.Add(-time.Minute*2))}},
+			newStatus:           appsv1alpha1.CloneSetStatus{Replicas: 10, AvailableReplicas: 10, UpdatedReplicas: 2, UpdatedReadyReplicas: 2, UpdateRevision: "v2"},
+			expectedAvailable:   v1.ConditionTrue,
+			expectedProgressing: progressingCondition(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionAvailable, now),
+		},
+		{
+			name:   "paused",
+			paused: true,
+			oldStatus: appsv1alpha1.CloneSetStatus{Replicas: 10, AvailableReplicas: 10, UpdatedReplicas: 2, UpdateRevision: "v2",
+				Conditions: []appsv1alpha1.CloneSetCondition{progressingCondition(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, now.Add(-time.Minute*2))}},
+			newStatus:           appsv1alpha1.CloneSetStatus{Replicas: 10, AvailableReplicas: 10, UpdatedReplicas: 2, UpdateRevision: "v2"},
+			expectedAvailable:   v1.ConditionTrue,
+			expectedProgressing: progressingCondition(v1.ConditionUnknown, appsv1alpha1.WorkloadReasonPaused, now),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := &appsv1alpha1.CloneSet{
+				Spec: appsv1alpha1.CloneSetSpec{
+					Replicas:                utilpointer.Int32Ptr(10),
+					ProgressDeadlineSeconds: utilpointer.Int32Ptr(60),
+					UpdateStrategy:          appsv1alpha1.CloneSetUpdateStrategy{Paused: tc.paused},
+				},
+				Status: tc.oldStatus,
+			}
+			newStatus := tc.newStatus.DeepCopy()
+			UpdateConditions(cs, newStatus, tc.partition)
+
+			if cond := clonesetutils.GetCondition(*newStatus, appsv1alpha1.CloneSetConditionAvailable); cond == nil || cond.Status != tc.expectedAvailable {
+				t.Fatalf("expected Available %v, got %+v", tc.expectedAvailable, cond)
+			}
+			cond := clonesetutils.GetCondition(*newStatus, appsv1alpha1.CloneSetConditionProgressing)
+			if cond == nil || cond.Status != tc.expectedProgressing.Status || cond.Reason != tc.expectedProgressing.Reason ||
+				!cond.LastUpdateTime.Equal(&tc.expectedProgressing.LastUpdateTime) {
+				t.Fatalf("expected Progressing %+v, got %+v", tc.expectedProgressing, cond)
+			}
+			if d := GetProgressDeadlineDuration(cs, newStatus); d != tc.expectedDeadlineDuration {
+				t.Fatalf("expected deadline duration %v, got %v", tc.expectedDeadlineDuration, d)
+			}
+		})
+	}
+}
+
+func TestUpdateConditionsKeepsOtherConditions(t *testing.T) {
+	cs := &appsv1alpha1.CloneSet{
+		Spec: appsv1alpha1.CloneSetSpec{Replicas: utilpointer.Int32Ptr(2)},
+		Status: appsv1alpha1.CloneSetStatus{Replicas: 2, AvailableReplicas: 2, UpdateRevision: "v1",
+			Conditions: []appsv1alpha1.CloneSetCondition{{Type: appsv1alpha1.CloneSetConditionAvailable, Status: v1.ConditionFalse}}},
+	}
+	failedScale := appsv1alpha1.CloneSetCondition{Type: appsv1alpha1.CloneSetConditionFailedScale, Status: v1.ConditionTrue, Message: "quota exceeded"}
+	newStatus := &appsv1alpha1.CloneSetStatus{Replicas: 1, AvailableReplicas: 1, UpdateRevision: "v1",
+		Conditions: []appsv1alpha1.CloneSetCondition{failedScale}}
+	UpdateConditions(cs, newStatus, 0)
+
+	if cond := clonesetutils.GetCondition(*newStatus, appsv1alpha1.CloneSetConditionFailedScale); cond == nil || *cond != failedScale {
+		t.Fatalf("expected FailedScale kept, got %+v", cond)
+	}
+	if cond := clonesetutils.GetCondition(*newStatus, appsv1alpha1.CloneSetConditionAvailable); cond == nil || cond.Status != v1.ConditionTrue {
+		t.Fatalf("expected Available True, got %+v", cond)
+	}
+	if cond := clonesetutils.GetCondition(*newStatus, appsv1alpha1.CloneSetConditionReplicaFailure); cond == nil ||
+		cond.Reason != appsv1alpha1.WorkloadReasonFailedScale || cond.Message != failedScale.Message {
+		t.Fatalf("expected ReplicaFailure from FailedScale, got %+v", cond)
+	}
+	if len(newStatus.Conditions) != 4 {
+		t.Fatalf("expected 4 conditions, got %+v", newStatus.Conditions)
+	}
+}
@@ -10,8 +10,11 @@ import (
 	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
 	"github.com/openkruise/kruise/pkg/util"
 	"github.com/openkruise/kruise/pkg/util/expectations"
+	"github.com/openkruise/kruise/pkg/util/lifecycle"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
@@ -62,11 +65,18 @@ func (r *realControl) Manage(
 		return false, nil
 	}
 
-	if podsToDelete := getPodsToDelete(updateCS, pods); len(podsToDelete) > 0 {
-		klog.V(3).Infof("CloneSet %s begin to delete pods in podsToDelete: %v", controllerKey, podsToDelete)
-		return true, r.deletePods(updateCS, podsToDelete, pvcs)
+	podsSpecifiedToDelete, podsInPreDelete := getPlannedDeletedPods(updateCS, pods)
+	if podsToDelete := append(podsSpecifiedToDelete, podsInPreDelete...); len(podsToDelete) > 0 {
+		klog.V(3).Infof("CloneSet %s find pods %v specified to delete and pods %v in preDelete",
+			controllerKey, getPodNames(podsSpecifiedToDelete), getPodNames(podsInPreDelete))
+		if modified, err := r.deletePods(updateCS, podsToDelete, pvcs); err != nil || modified {
+			return modified, err
+		}
 	}
 
+	// Pods in PreparingDelete state are waiting for their preDelete hook to be removed,
+	// so they should not be counted as replicas any more.
+	pods = clonesetutils.FilterOutPreparingDeletePods(pods)
 	updatedPods, notUpdatedPods := clonesetutils.SplitPodsByRevision(pods, updateRevision)
 
 	var err error
@@ -100,9 +110,13 @@ func (r *realControl) Manage(
 		klog.V(3).Infof("CloneSet %s begin to scale in %d pods including %d (current rev)",
 			controllerKey, diff, currentRevDiff)
 
-		podsToDelete := choosePodsToDelete(diff, currentRevDiff, notUpdatedPods, updatedPods)
+		nodes, err := r.getPodNodes(updateCS, pods)
+		if err != nil {
+			return false, err
+		}
+		podsToDelete := choosePodsToDelete(updateCS, nodes, diff, currentRevDiff, notUpdatedPods, updatedPods)
 
-		err = r.deletePods(updateCS, podsToDelete, pvcs)
+		_, err = r.deletePods(updateCS, podsToDelete, pvcs)
 		return true, err
 	}
 
@@ -179,14 +193,29 @@ func (r *realControl) createOnePod(cs *appsv1alpha1.CloneSet, pod *v1.Pod, exist
 	return nil
 }
 
-func (r *realControl) deletePods(cs *appsv1alpha1.CloneSet, podsToDelete []*v1.Pod, pvcs []*v1.PersistentVolumeClaim) error {
+func (r *realControl) deletePods(cs *appsv1alpha1.CloneSet, podsToDelete []*v1.Pod, pvcs []*v1.PersistentVolumeClaim) (bool, error) {
+	var modified bool
 	for _, pod := range podsToDelete {
+		if cs.Spec.Lifecycle != nil && lifecycle.IsPodHooked(cs.Spec.Lifecycle.PreDelete, pod) {
+			if updated, err := lifecycle.UpdatePodLifecycle(r, pod, appsv1alpha1.LifecycleStatePreparingDelete); err != nil {
+				r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedPreDelete", "failed to set pod %s to %s: %v",
+					pod.Name, appsv1alpha1.LifecycleStatePreparingDelete, err)
+				return modified, err
+			} else if updated {
+				modified = true
+				klog.V(3).Infof("CloneSet %s patch pod %s lifecycle to %s",
+					clonesetutils.GetControllerKey(cs), pod.Name, appsv1alpha1.LifecycleStatePreparingDelete)
+			}
+			continue
+		}
+
 		r.exp.ExpectScale(clonesetutils.GetControllerKey(cs), expectations.Delete, pod.Name)
 		if err := r.Delete(context.TODO(), pod); err != nil {
 			r.exp.ObserveScale(clonesetutils.GetControllerKey(cs), expectations.Delete, pod.Name)
 			r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedDelete", "failed to delete pod %s: %v", pod.Name, err)
-			return err
+			return modified, err
 		}
+		modified = true
 		r.recorder.Event(cs, v1.EventTypeNormal, "SuccessfulDelete", fmt.Sprintf("succeed to delete pod %s", pod.Name))
 
 		// delete pvcs which have the same instance-id
@@ -199,10 +228,35 @@ func (r *realControl) deletePods(cs *appsv1alpha1.CloneSet, podsToDelete []*v1.P
 			if err := r.Delete(context.TODO(), pvc); err != nil {
 				r.exp.ObserveScale(clonesetutils.GetControllerKey(cs), expectations.Delete, pvc.Name)
 				r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedDelete", "failed to delete pvc %s: %v", pvc.Name, err)
-				return err
+				return modified, err
 			}
 		}
 	}
 
-	return nil
+	return modified, nil
+}
+
+// getPodNodes returns the nodes of pods, only if topologyKeys set in scaleStrategy.
+func (r *realControl) getPodNodes(cs *appsv1alpha1.CloneSet, pods []*v1.Pod) (map[string]*v1.Node, error) {
+	nodes := make(map[string]*v1.Node)
+	if len(cs.Spec.ScaleStrategy.TopologyKeys) == 0 {
+		return nodes, nil
+	}
+
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			continue
+		} else if _, ok := nodes[pod.Spec.NodeName]; ok {
+			continue
+		}
+		node := &v1.Node{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		nodes[pod.Spec.NodeName] = node
+	}
+	return nodes, nil
 }
@@ -0,0 +1,147 @@
+package scale
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	kubecontroller "k8s.io/kubernetes/pkg/controller"
+)
+
+// deletionRanker chooses pods to delete when scaling in. Pods are chosen in the order of:
+// 1. unscheduled < scheduled, pending < unknown < running, not-ready < ready
+// 2. the larger sum of deletionPriority weights < the smaller one
+// 3. the lower deletion-cost < the higher one
+// 4. pods in the topology domain which has more pods left < pods in domain with less pods
+// 5. the rules in ActivePods, such as ready for a shorter time < ready for a longer time
+type deletionRanker struct {
+	strategy appsv1alpha1.CloneSetScaleStrategy
+	// nodes is the nodes of pods, only used when topologyKeys set
+	nodes map[string]*v1.Node
+	// domainCounts is the number of pods left in each topology domain
+	domainCounts map[string]int
+}
+
+func newDeletionRanker(strategy appsv1alpha1.CloneSetScaleStrategy, pods []*v1.Pod, nodes map[string]*v1.Node) *deletionRanker {
+	r := &deletionRanker{strategy: strategy, nodes: nodes, domainCounts: make(map[string]int)}
+	for _, pod := range pods {
+		r.domainCounts[r.getTopologyDomain(pod)]++
+	}
+	return r
+}
+
+// choose returns diff number of pods to delete from the given pods.
+func (r *deletionRanker) choose(pods []*v1.Pod, diff int) []*v1.Pod {
+	if diff > len(pods) {
+		klog.Warningf("Diff > len(pods) in choosePodsToDelete func which is not expected.")
+		diff = len(pods)
+	}
+
+	candidates := make([]*v1.Pod, len(pods))
+	copy(candidates, pods)
+	// No need to sort pods if we are about to delete all of them.
+	if diff < len(candidates) {
+		sort.SliceStable(candidates, func(i, j int) bool { return r.less(candidates[i], candidates[j]) })
+	}
+
+	chosen := make([]*v1.Pod, 0, diff)
+	for len(chosen) < diff {
+		// Among the candidates with the same priority as the first one, choose the one in the largest topology domain.
+		best := 0
+		for i := 1; i < len(candidates) && r.equalPriority(candidates[0], candidates[i]); i++ {
+			if r.domainCounts[r.getTopologyDomain(candidates[i])] > r.domainCounts[r.getTopologyDomain(candidates[best])] {
+				best = i
+			}
+		}
+		chosen = append(chosen, candidates[best])
+		r.domainCounts[r.getTopologyDomain(candidates[best])]--
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+	return chosen
+}
+
+func (r *deletionRanker) less(podI, podJ *v1.Pod) bool {
+	if stageI, stageJ := getPodStage(podI), getPodStage(podJ); stageI != stageJ {
+		return stageI < stageJ
+	}
+	if weightI, weightJ := r.getDeletionWeight(podI), r.getDeletionWeight(podJ); weightI != weightJ {
+		return weightI > weightJ
+	}
+	if costI, costJ := getDeletionCost(podI), getDeletionCost(podJ); costI != costJ {
+		return costI < costJ
+	}
+	return kubecontroller.ActivePods([]*v1.Pod{podI, podJ}).Less(0, 1)
+}
+
+func (r *deletionRanker) equalPriority(podI, podJ *v1.Pod) bool {
+	return getPodStage(podI) == getPodStage(podJ) &&
+		r.getDeletionWeight(podI) == r.getDeletionWeight(podJ) &&
+		getDeletionCost(podI) == getDeletionCost(podJ)
+}
+
+func (r *deletionRanker) getDeletionWeight(pod *v1.Pod) int64 {
+	var weight int64
+	for _, term := range r.strategy.DeletionPriority {
+		selector, err := metav1.LabelSelectorAsSelector(&term.MatchSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			weight += int64(term.Weight)
+		}
+	}
+	return weight
+}
+
+func (r *deletionRanker) getTopologyDomain(pod *v1.Pod) string {
+	if len(r.strategy.TopologyKeys) == 0 {
+		return ""
+	}
+	node, ok := r.nodes[pod.Spec.NodeName]
+	if !ok {
+		return ""
+	}
+	values := make([]string, 0, len(r.strategy.TopologyKeys))
+	for _, key := range r.strategy.TopologyKeys {
+		values = append(values, node.Labels[key])
+	}
+	return strings.Join(values, "/")
+}
+
+// getPodStage returns the stage of pod, which is the same order as ActivePods:
+// unscheduled < scheduled, pending < unknown < running, not-ready < ready.
+func getPodStage(pod *v1.Pod) int {
+	if pod.Spec.NodeName == "" {
+		return 0
+	}
+	stage := 1
+	switch pod.Status.Phase {
+	case v1.PodUnknown:
+		stage = 3
+	case v1.PodRunning:
+		stage = 5
+	}
+	if podutil.IsPodReady(pod) {
+		stage++
+	}
+	return stage
+}
+
+// getDeletionCost returns the cost in annotation of pod, or 0 if it is not set or invalid.
+func getDeletionCost(pod *v1.Pod) int64 {
+	v, ok := pod.Annotations[appsv1alpha1.PodDeletionCostAnnotationKey]
+	if !ok {
+		return 0
+	}
+	cost, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return cost
+}
@@ -0,0 +1,128 @@
+package scale
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestChoosePodsToDelete(t *testing.T) {
+	newPod := func(name, nodeName string, ready bool, labels, annotations map[string]string) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations},
+			Spec:       v1.PodSpec{NodeName: nodeName},
+			Status:     v1.PodStatus{Phase: v1.PodRunning},
+		}
+		if ready {
+			pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+		}
+		return pod
+	}
+	newNode := func(name, zone string) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"zone": zone}}}
+	}
+
+	cases := []struct {
+		name           string
+		strategy       appsv1alpha1.CloneSetScaleStrategy
+		nodes          []*v1.Node
+		totalDiff      int
+		currentRevDiff int
+		notUpdatedPods []*v1.Pod
+		updatedPods    []*v1.Pod
+		expected       []string
+	}{
+		{
+			name:      "unscheduled and not-ready first",
+			totalDiff: 2,
+			updatedPods: []*v1.Pod{
+				newPod("p0", "n0", true, nil, nil),
+				newPod("p1", "n0", false, nil, nil),
+				newPod("p2", "", false, nil, nil),
+			},
+			expected: []string{"p2", "p1"},
+		},
+		{
+			name:           "not updated first",
+			totalDiff:      2,
+			currentRevDiff: 1,
+			notUpdatedPods: []*v1.Pod{
+				newPod("p0", "n0", true, nil, nil),
+			},
+			updatedPods: []*v1.Pod{
+				newPod("p1", "n0", true, nil, nil),
+				newPod("p2", "n0", false, nil, nil),
+			},
+			expected: []string{"p0", "p2"},
+		},
+		{
+			name: "deletion priority before deletion cost",
+			strategy: appsv1alpha1.CloneSetScaleStrategy{
+				DeletionPriority: []appsv1alpha1.UpdatePriorityWeightTerm{
+					{Weight: 50, MatchSelector: metav1.LabelSelector{MatchLabels: map[string]string{"cheap": "true"}}},
+				},
+			},
+			totalDiff: 2,
+			updatedPods: []*v1.Pod{
+				newPod("p0", "n0", true, nil, map[string]string{appsv1alpha1.PodDeletionCostAnnotationKey: "-10"}),
+				newPod("p1", "n0", true, map[string]string{"cheap": "true"}, map[string]string{appsv1alpha1.PodDeletionCostAnnotationKey: "10"}),
+				newPod("p2", "n0", true, nil, map[string]string{appsv1alpha1.PodDeletionCostAnnotationKey: "5"}),
+			},
+			expected: []string{"p1", "p0"},
+		},
+		{
+			name:      "invalid deletion cost",
+			totalDiff: 1,
+			updatedPods: []*v1.Pod{
+				newPod("p0", "n0", true, nil, map[string]string{appsv1alpha1.PodDeletionCostAnnotationKey: "1"}),
+				newPod("p1", "n0", true, nil, map[string]string{appsv1alpha1.PodDeletionCostAnnotationKey: "xxx"}),
+			},
+			expected: []string{"p1"},
+		},
+		{
+			name:      "spread across zones",
+			strategy:  appsv1alpha1.CloneSetScaleStrategy{TopologyKeys: []string{"zone"}},
+			nodes:     []*v1.Node{newNode("n0", "z0"), newNode("n1", "z0"), newNode("n2", "z1")},
+			totalDiff: 3,
+			updatedPods: []*v1.Pod{
+				newPod("p0", "n2", true, nil, nil),
+				newPod("p1", "n2", true, nil, nil),
+				newPod("p2", "n0", true, nil, nil),
+				newPod("p3", "n1", true, nil, nil),
+				newPod("p4", "n1", true, nil, nil),
+				newPod("p5", "n0", true, nil, nil),
+			},
+			expected: []string{"p2", "p3", "p0"},
+		},
+		{
+			name:      "deletion cost before spread",
+			strategy:  appsv1alpha1.CloneSetScaleStrategy{TopologyKeys: []string{"zone"}},
+			nodes:     []*v1.Node{newNode("n0", "z0"), newNode("n1", "z1")},
+			totalDiff: 1,
+			updatedPods: []*v1.Pod{
+				newPod("p0", "n0", true, nil, nil),
+				newPod("p1", "n0", true, nil, nil),
+				newPod("p2", "n1", true, nil, map[string]string{appsv1alpha1.PodDeletionCostAnnotationKey: "-1"}),
+			},
+			expected: []string{"p2"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := &appsv1alpha1.CloneSet{Spec: appsv1alpha1.CloneSetSpec{ScaleStrategy: tc.strategy}}
+			nodes := make(map[string]*v1.Node)
+			for _, n := range tc.nodes {
+				nodes[n.Name] = n
+			}
+
+			got := getPodNames(choosePodsToDelete(cs, nodes, tc.totalDiff, tc.currentRevDiff, tc.notUpdatedPods, tc.updatedPods))
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -417,7 +417,7 @@ func TestDeletePods(t *testing.T) {
 		_ = ctrl.Create(context.TODO(), p)
 	}
 
-	err := ctrl.deletePods(cs, podsToDelete, pvcs)
+	_, err := ctrl.deletePods(cs, podsToDelete, pvcs)
 	if err != nil {
 		t.Fatalf("failed to delete got pods: %v", err)
 	}
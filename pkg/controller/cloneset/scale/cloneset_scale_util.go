@@ -1,27 +1,35 @@
 package scale
 
 import (
-	"sort"
-
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/lifecycle"
 	v1 "k8s.io/api/core/v1"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog"
-	kubecontroller "k8s.io/kubernetes/pkg/controller"
 	"k8s.io/utils/integer"
 )
 
-func getPodsToDelete(cs *appsv1alpha1.CloneSet, pods []*v1.Pod) []*v1.Pod {
-	var podsToDelete []*v1.Pod
+// getPlannedDeletedPods returns the pods specified in podsToDelete, and the pods in PreparingDelete state.
+func getPlannedDeletedPods(cs *appsv1alpha1.CloneSet, pods []*v1.Pod) (podsSpecifiedToDelete, podsInPreDelete []*v1.Pod) {
 	s := sets.NewString(cs.Spec.ScaleStrategy.PodsToDelete...)
 	for _, p := range pods {
 		if s.Has(p.Name) {
-			podsToDelete = append(podsToDelete, p)
+			podsSpecifiedToDelete = append(podsSpecifiedToDelete, p)
+		} else if lifecycle.GetPodLifecycleState(p) == appsv1alpha1.LifecycleStatePreparingDelete {
+			podsInPreDelete = append(podsInPreDelete, p)
 		}
 	}
-	return podsToDelete
+	return
+}
+
+func getPodNames(pods []*v1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, p := range pods {
+		names = append(names, p.Name)
+	}
+	return names
 }
 
 // Get available IDs, if the a PVC exists but the corresponding pod does not exist, then reusing the ID, i.e., reuse the pvc.
@@ -91,29 +99,18 @@ func calculateDiffs(cs *appsv1alpha1.CloneSet, revConsistent bool, totalPods int
 	return
 }
 
-func choosePodsToDelete(totalDiff int, currentRevDiff int, notUpdatedPods, updatedPods []*v1.Pod) []*v1.Pod {
-	choose := func(pods []*v1.Pod, diff int) []*v1.Pod {
-		// No need to sort pods if we are about to delete all of them.
-		if diff < len(pods) {
-			// Sort the pods in the order such that not-ready < ready, unscheduled
-			// < scheduled, and pending < running. This ensures that we delete pods
-			// in the earlier stages whenever possible.
-			sort.Sort(kubecontroller.ActivePods(pods))
-		} else if diff > len(pods) {
-			klog.Warningf("Diff > len(pods) in choosePodsToDelete func which is not expected.")
-			return pods
-		}
-		return pods[:diff]
-	}
+// choosePodsToDelete chooses totalDiff pods to delete, including currentRevDiff pods not updated at most.
+func choosePodsToDelete(cs *appsv1alpha1.CloneSet, nodes map[string]*v1.Node, totalDiff int, currentRevDiff int, notUpdatedPods, updatedPods []*v1.Pod) []*v1.Pod {
+	ranker := newDeletionRanker(cs.Spec.ScaleStrategy, append(append([]*v1.Pod{}, notUpdatedPods...), updatedPods...), nodes)
 
 	var podsToDelete []*v1.Pod
 	if currentRevDiff >= totalDiff {
-		podsToDelete = choose(notUpdatedPods, totalDiff)
+		podsToDelete = ranker.choose(notUpdatedPods, totalDiff)
 	} else if currentRevDiff > 0 {
-		podsToDelete = choose(notUpdatedPods, currentRevDiff)
-		podsToDelete = append(podsToDelete, choose(updatedPods, totalDiff-currentRevDiff)...)
+		podsToDelete = ranker.choose(notUpdatedPods, currentRevDiff)
+		podsToDelete = append(podsToDelete, ranker.choose(updatedPods, totalDiff-currentRevDiff)...)
 	} else {
-		podsToDelete = choose(updatedPods, totalDiff)
+		podsToDelete = ranker.choose(updatedPods, totalDiff)
 	}
 
 	return podsToDelete
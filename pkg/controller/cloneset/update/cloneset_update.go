@@ -27,11 +27,13 @@ import (
 	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
 	"github.com/openkruise/kruise/pkg/util/expectations"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
+	"github.com/openkruise/kruise/pkg/util/lifecycle"
 	"github.com/openkruise/kruise/pkg/util/requeueduration"
 	"github.com/openkruise/kruise/pkg/util/updatesort"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -42,6 +44,7 @@ type Interface interface {
 	Manage(cs *appsv1alpha1.CloneSet,
 		updateRevision *apps.ControllerRevision, revisions []*apps.ControllerRevision,
 		pods []*v1.Pod, pvcs []*v1.PersistentVolumeClaim,
+		newStatus *appsv1alpha1.CloneSetStatus,
 	) (time.Duration, error)
 }
 
@@ -66,6 +69,7 @@ type realControl struct {
 func (c *realControl) Manage(cs *appsv1alpha1.CloneSet,
 	updateRevision *apps.ControllerRevision, revisions []*apps.ControllerRevision,
 	pods []*v1.Pod, pvcs []*v1.PersistentVolumeClaim,
+	newStatus *appsv1alpha1.CloneSetStatus,
 ) (time.Duration, error) {
 
 	requeueDuration := requeueduration.Duration{}
@@ -75,6 +79,9 @@ func (c *realControl) Manage(cs *appsv1alpha1.CloneSet,
 		return requeueDuration.Get(), nil
 	}
 
+	// Pods in PreparingDelete state will be deleted by scale control, so just ignore them.
+	pods = clonesetutils.FilterOutPreparingDeletePods(pods)
+
 	// 1. find currently updated and not-ready count and all pods waiting to update
 	var waitUpdateIndexes []int
 	for i := range pods {
@@ -90,8 +97,27 @@ func (c *realControl) Manage(cs *appsv1alpha1.CloneSet,
 			requeueDuration.Update(res.DelayDuration)
 		}
 
+		if err := c.refreshPodLifecycle(cs, coreControl, pods[i]); err != nil {
+			klog.Errorf("CloneSet %s/%s failed to refresh lifecycle state of pod %s: %v",
+				cs.Namespace, cs.Name, pods[i].Name, err)
+			return requeueDuration.Get(), err
+		}
+
 		if clonesetutils.GetPodRevision(pods[i]) != updateRevision.Name {
 			waitUpdateIndexes = append(waitUpdateIndexes, i)
+			continue
+		}
+
+		// recreate the pod whose in-place update has not completed before the deadline
+		if timeout, leftDuration := c.inplaceControl.IsUpdateTimeout(pods[i], coreControl.GetUpdateOptions()); timeout {
+			c.recorder.Eventf(cs, v1.EventTypeWarning, "InPlaceUpdateTimeout",
+				"in-place update of pod %s has not completed before the deadline, so it will be recreated", pods[i].Name)
+			if err := c.recreatePod(cs, updateRevision, pods[i], pvcs); err != nil {
+				return requeueDuration.Get(), err
+			}
+			newStatus.InPlaceUpdateFallbackCount++
+		} else if leftDuration > 0 {
+			requeueDuration.Update(leftDuration)
 		}
 	}
 
@@ -104,7 +130,24 @@ func (c *realControl) Manage(cs *appsv1alpha1.CloneSet,
 		waitUpdateIndexes = waitUpdateIndexes[:needToUpdateCount]
 	}
 
-	// 4. update pods
+	// 4. reset pods in PreparingUpdate state which are not chosen to update any more,
+	// e.g., the update revision has been rolled back or the partition has been increased
+	chosenIndexes := sets.NewInt(waitUpdateIndexes...)
+	for i := range pods {
+		if chosenIndexes.Has(i) || lifecycle.GetPodLifecycleState(pods[i]) != appsv1alpha1.LifecycleStatePreparingUpdate {
+			continue
+		}
+		if updated, err := lifecycle.UpdatePodLifecycle(c, pods[i], appsv1alpha1.LifecycleStateNormal); err != nil {
+			klog.Errorf("CloneSet %s/%s failed to reset lifecycle state of pod %s: %v",
+				cs.Namespace, cs.Name, pods[i].Name, err)
+			return requeueDuration.Get(), err
+		} else if updated {
+			klog.V(3).Infof("CloneSet %s/%s patch pod %s lifecycle from %s to %s",
+				cs.Namespace, cs.Name, pods[i].Name, appsv1alpha1.LifecycleStatePreparingUpdate, appsv1alpha1.LifecycleStateNormal)
+		}
+	}
+
+	// 5. update pods
 	for _, idx := range waitUpdateIndexes {
 		pod := pods[idx]
 		if duration, err := c.updatePod(cs, coreControl, updateRevision, revisions, pod, pvcs); err != nil {
@@ -117,6 +160,79 @@ func (c *realControl) Manage(cs *appsv1alpha1.CloneSet,
 	return requeueDuration.Get(), nil
 }
 
+// refreshPodLifecycle moves the pod to the next lifecycle state if the current state has finished.
+func (c *realControl) refreshPodLifecycle(cs *appsv1alpha1.CloneSet, coreControl clonesetcore.Control, pod *v1.Pod) error {
+
+	var hook *appsv1alpha1.LifecycleHook
+	if cs.Spec.Lifecycle != nil {
+		hook = cs.Spec.Lifecycle.InPlaceUpdate
+	}
+
+	var state appsv1alpha1.LifecycleStateType
+	switch lifecycle.GetPodLifecycleState(pod) {
+	case appsv1alpha1.LifecycleStateUpdating:
+		checkFunc := inplaceupdate.CheckInPlaceUpdateCompleted
+		if opts := coreControl.GetUpdateOptions(); opts.CustomizeCheckUpdateCompleted != nil {
+			checkFunc = opts.CustomizeCheckUpdateCompleted
+		}
+		if pod.Annotations[appsv1alpha1.InPlaceUpdateGraceKey] == "" && checkFunc(pod) == nil {
+			if hook != nil {
+				state = appsv1alpha1.LifecycleStateUpdated
+			} else {
+				state = appsv1alpha1.LifecycleStateNormal
+			}
+		}
+	case appsv1alpha1.LifecycleStateUpdated:
+		// wait for the hook to be added back to the pod
+		if hook == nil || lifecycle.IsPodHooked(hook, pod) {
+			state = appsv1alpha1.LifecycleStateNormal
+		}
+	}
+
+	if state == "" {
+		return nil
+	}
+	if updated, err := lifecycle.UpdatePodLifecycle(c, pod, state); err != nil {
+		return err
+	} else if updated {
+		klog.V(3).Infof("CloneSet %s/%s patch pod %s lifecycle from %s to %s",
+			cs.Namespace, cs.Name, pod.Name, lifecycle.GetPodLifecycleState(pod), state)
+	}
+	return nil
+}
+
+// GetPodsToUpdate returns the pods that are waiting to be updated to the update revision in the sequence
+// they will be updated, excluding the ones that should be kept in old revisions by partition.
+func GetPodsToUpdate(cs *appsv1alpha1.CloneSet, updateRevision *apps.ControllerRevision, pods []*v1.Pod) []*v1.Pod {
+	coreControl := clonesetcore.New(cs)
+	pods = clonesetutils.FilterOutPreparingDeletePods(pods)
+
+	var waitUpdateIndexes []int
+	for i := range pods {
+		if coreControl.IsPodUpdatePaused(pods[i]) {
+			continue
+		}
+		if clonesetutils.GetPodRevision(pods[i]) != updateRevision.Name {
+			waitUpdateIndexes = append(waitUpdateIndexes, i)
+		}
+	}
+	waitUpdateIndexes = sortUpdateIndexes(coreControl, cs.Spec.UpdateStrategy, pods, waitUpdateIndexes)
+
+	partition := 0
+	if cs.Spec.UpdateStrategy.Partition != nil {
+		partition = int(*cs.Spec.UpdateStrategy.Partition)
+	}
+	if len(waitUpdateIndexes)-partition <= 0 {
+		return nil
+	}
+
+	var podsToUpdate []*v1.Pod
+	for _, idx := range waitUpdateIndexes[:len(waitUpdateIndexes)-partition] {
+		podsToUpdate = append(podsToUpdate, pods[idx])
+	}
+	return podsToUpdate
+}
+
 func sortUpdateIndexes(coreControl clonesetcore.Control, strategy appsv1alpha1.CloneSetUpdateStrategy, pods []*v1.Pod, waitUpdateIndexes []int) []int {
 	// Sort Pods with default sequence
 	sort.Slice(waitUpdateIndexes, coreControl.GetPodsSortFunc(pods, waitUpdateIndexes))
@@ -185,7 +301,22 @@ func (c *realControl) updatePod(cs *appsv1alpha1.CloneSet, coreControl clonesetc
 			}
 		}
 
-		res := c.inplaceControl.Update(pod, oldRevision, updateRevision, coreControl.GetUpdateOptions())
+		opts := coreControl.GetUpdateOptions()
+		if c.inplaceControl.CanUpdateInPlace(oldRevision, updateRevision, opts) && cs.Spec.Lifecycle != nil {
+			// If pod has been hooked, set it to PreparingUpdate and wait for the hook to be removed
+			if lifecycle.IsPodHooked(cs.Spec.Lifecycle.InPlaceUpdate, pod) {
+				if updated, err := lifecycle.UpdatePodLifecycle(c, pod, appsv1alpha1.LifecycleStatePreparingUpdate); err != nil {
+					return 0, err
+				} else if updated {
+					klog.V(3).Infof("CloneSet %s/%s patch pod %s lifecycle to %s",
+						cs.Namespace, cs.Name, pod.Name, appsv1alpha1.LifecycleStatePreparingUpdate)
+				}
+				return 0, nil
+			}
+			opts.AdditionalFuncs = append(opts.AdditionalFuncs, lifecycle.SetPodLifecycle(appsv1alpha1.LifecycleStateUpdating))
+		}
+
+		res := c.inplaceControl.Update(pod, oldRevision, updateRevision, opts)
 
 		if res.InPlaceUpdate {
 			if res.UpdateErr == nil {
@@ -205,6 +336,23 @@ func (c *realControl) updatePod(cs *appsv1alpha1.CloneSet, coreControl clonesetc
 		klog.Warningf("CloneSet %s/%s can not update Pod %s in-place, so it will back off to ReCreate", cs.Namespace, cs.Name, pod.Name)
 	}
 
+	return 0, c.recreatePod(cs, updateRevision, pod, pvcs)
+}
+
+// recreatePod deletes the pod and its pvcs, or sets it to PreparingDelete if it has been hooked.
+func (c *realControl) recreatePod(cs *appsv1alpha1.CloneSet, updateRevision *apps.ControllerRevision,
+	pod *v1.Pod, pvcs []*v1.PersistentVolumeClaim) error {
+
+	if cs.Spec.Lifecycle != nil && lifecycle.IsPodHooked(cs.Spec.Lifecycle.PreDelete, pod) {
+		if updated, err := lifecycle.UpdatePodLifecycle(c, pod, appsv1alpha1.LifecycleStatePreparingDelete); err != nil {
+			return err
+		} else if updated {
+			klog.V(3).Infof("CloneSet %s/%s patch pod %s lifecycle to %s for update %s",
+				cs.Namespace, cs.Name, pod.Name, appsv1alpha1.LifecycleStatePreparingDelete, updateRevision.Name)
+		}
+		return nil
+	}
+
 	klog.V(2).Infof("CloneSet %s/%s deleting Pod %s for update %s", cs.Namespace, cs.Name, pod.Name, updateRevision.Name)
 
 	c.scaleExp.ExpectScale(clonesetutils.GetControllerKey(cs), expectations.Delete, pod.Name)
@@ -212,7 +360,7 @@ func (c *realControl) updatePod(cs *appsv1alpha1.CloneSet, coreControl clonesetc
 		c.scaleExp.ObserveScale(clonesetutils.GetControllerKey(cs), expectations.Delete, pod.Name)
 		c.recorder.Eventf(cs, v1.EventTypeWarning, "FailedUpdatePodReCreate",
 			"failed to delete pod %s for update: %v", pod.Name, err)
-		return 0, err
+		return err
 	}
 
 	// TODO(FillZpp): add a strategy controlling if the PVCs of this pod should be deleted
@@ -225,11 +373,11 @@ func (c *realControl) updatePod(cs *appsv1alpha1.CloneSet, coreControl clonesetc
 		if err := c.Delete(context.TODO(), pvc); err != nil {
 			c.scaleExp.ObserveScale(clonesetutils.GetControllerKey(cs), expectations.Delete, pvc.Name)
 			c.recorder.Eventf(cs, v1.EventTypeWarning, "FailedDelete", "failed to delete pvc %s: %v", pvc.Name, err)
-			return 0, err
+			return err
 		}
 	}
 
 	c.recorder.Eventf(cs, v1.EventTypeNormal, "SuccessfulUpdatePodReCreate",
 		"successfully delete pod %s for update", pod.Name)
-	return 0, nil
+	return nil
 }
@@ -519,7 +519,7 @@ func TestMange(t *testing.T) {
 			expectations.NewScaleExpectations(),
 			expectations.NewUpdateExpectations(clonesetutils.GetPodRevision),
 		}
-		if _, err := ctrl.Manage(mc.cs, mc.updateRevision, mc.revisions, mc.pods, mc.pvcs); err != nil {
+		if _, err := ctrl.Manage(mc.cs, mc.updateRevision, mc.revisions, mc.pods, mc.pvcs, &appsv1alpha1.CloneSetStatus{}); err != nil {
 			t.Fatalf("Failed to test %s, manage error: %v", mc.name, err)
 		}
 		podList := v1.PodList{}
@@ -541,6 +541,258 @@ func TestMange(t *testing.T) {
 	}
 }
 
+func TestManageWithLifecycle(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	now := metav1.NewTime(time.Unix(time.Now().Add(-time.Hour).Unix(), 0))
+	lifecycleHooks := &appsv1alpha1.Lifecycle{
+		PreDelete:     &appsv1alpha1.LifecycleHook{LabelsHandler: map[string]string{"in-service": "true"}},
+		InPlaceUpdate: &appsv1alpha1.LifecycleHook{LabelsHandler: map[string]string{"in-service": "true"}},
+	}
+	oldRevision := &apps.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "rev-old"},
+		Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"$patch":"replace","spec":{"containers":[{"name":"c1","image":"foo1"}]}}}}`)},
+	}
+	newRevision := &apps.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "rev-new"},
+		Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"$patch":"replace","spec":{"containers":[{"name":"c1","image":"foo2"}]}}}}`)},
+	}
+	newPod := func(revision string, state appsv1alpha1.LifecycleStateType, hooked bool, image, imageID string) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-0", Labels: map[string]string{
+				apps.ControllerRevisionHashLabelKey: revision,
+				appsv1alpha1.CloneSetInstanceID:     "id-0",
+				appsv1alpha1.LifecycleStateKey:      string(state),
+			}},
+			Spec: v1.PodSpec{
+				ReadinessGates: []v1.PodReadinessGate{{ConditionType: appsv1alpha1.InPlaceUpdateReady}},
+				Containers:     []v1.Container{{Name: "c1", Image: image}},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				Conditions: []v1.PodCondition{
+					{Type: v1.PodReady, Status: v1.ConditionTrue},
+					{Type: appsv1alpha1.InPlaceUpdateReady, Status: v1.ConditionTrue},
+				},
+				ContainerStatuses: []v1.ContainerStatus{{Name: "c1", ImageID: imageID}},
+			},
+		}
+		if hooked {
+			pod.Labels["in-service"] = "true"
+		}
+		return pod
+	}
+
+	cases := []struct {
+		name          string
+		updateType    appsv1alpha1.CloneSetUpdateStrategyType
+		partition     *int32
+		pod           *v1.Pod
+		expectedState appsv1alpha1.LifecycleStateType
+		expectedRev   string
+		expectDeleted bool
+	}{
+		{
+			name:          "hooked pod should wait in PreparingUpdate",
+			updateType:    appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			pod:           newPod("rev-old", appsv1alpha1.LifecycleStateNormal, true, "foo1", "image-id-xyz"),
+			expectedState: appsv1alpha1.LifecycleStatePreparingUpdate,
+			expectedRev:   "rev-old",
+		},
+		{
+			name:          "unhooked pod in PreparingUpdate should be updated in-place",
+			updateType:    appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			pod:           newPod("rev-old", appsv1alpha1.LifecycleStatePreparingUpdate, false, "foo1", "image-id-xyz"),
+			expectedState: appsv1alpha1.LifecycleStateUpdating,
+			expectedRev:   "rev-new",
+		},
+		{
+			name:          "pod in PreparingUpdate should be Normal when partition increased",
+			updateType:    appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			partition:     getInt32Pointer(1),
+			pod:           newPod("rev-old", appsv1alpha1.LifecycleStatePreparingUpdate, true, "foo1", "image-id-xyz"),
+			expectedState: appsv1alpha1.LifecycleStateNormal,
+			expectedRev:   "rev-old",
+		},
+		{
+			name:          "pod in PreparingUpdate should be Normal when update revision rolled back",
+			updateType:    appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			pod:           newPod("rev-new", appsv1alpha1.LifecycleStatePreparingUpdate, true, "foo2", "image-id-abc"),
+			expectedState: appsv1alpha1.LifecycleStateNormal,
+			expectedRev:   "rev-new",
+		},
+		{
+			name:       "pod in Updating should be Updated after in-place update completed",
+			updateType: appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			pod: func() *v1.Pod {
+				pod := newPod("rev-new", appsv1alpha1.LifecycleStateUpdating, false, "foo2", "image-id-abc")
+				pod.Annotations = map[string]string{appsv1alpha1.InPlaceUpdateStateKey: util.DumpJSON(appsv1alpha1.InPlaceUpdateState{
+					Revision:              "rev-new",
+					UpdateTimestamp:       now,
+					LastContainerStatuses: map[string]appsv1alpha1.InPlaceUpdateContainerStatus{"c1": {ImageID: "image-id-xyz"}},
+				})}
+				return pod
+			}(),
+			expectedState: appsv1alpha1.LifecycleStateUpdated,
+			expectedRev:   "rev-new",
+		},
+		{
+			name:          "pod in Updated should wait for hook added back",
+			updateType:    appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			pod:           newPod("rev-new", appsv1alpha1.LifecycleStateUpdated, false, "foo2", "image-id-abc"),
+			expectedState: appsv1alpha1.LifecycleStateUpdated,
+			expectedRev:   "rev-new",
+		},
+		{
+			name:          "hooked pod in Updated should be Normal",
+			updateType:    appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+			pod:           newPod("rev-new", appsv1alpha1.LifecycleStateUpdated, true, "foo2", "image-id-abc"),
+			expectedState: appsv1alpha1.LifecycleStateNormal,
+			expectedRev:   "rev-new",
+		},
+		{
+			name:          "hooked pod should be PreparingDelete when recreate update",
+			updateType:    appsv1alpha1.RecreateCloneSetUpdateStrategyType,
+			pod:           newPod("rev-old", appsv1alpha1.LifecycleStateNormal, true, "foo1", "image-id-xyz"),
+			expectedState: appsv1alpha1.LifecycleStatePreparingDelete,
+			expectedRev:   "rev-old",
+		},
+		{
+			name:          "unhooked pod should be deleted when recreate update",
+			updateType:    appsv1alpha1.RecreateCloneSetUpdateStrategyType,
+			pod:           newPod("rev-old", appsv1alpha1.LifecycleStateNormal, false, "foo1", "image-id-xyz"),
+			expectDeleted: true,
+		},
+	}
+
+	for _, tc := range cases {
+		cs := &appsv1alpha1.CloneSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "clone-test"},
+			Spec: appsv1alpha1.CloneSetSpec{
+				Replicas:       getInt32Pointer(1),
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{Type: tc.updateType, Partition: tc.partition},
+				Lifecycle:      lifecycleHooks,
+			},
+		}
+		fakeClient := fake.NewFakeClient(cs, tc.pod)
+		ctrl := &realControl{
+			fakeClient,
+			inplaceupdate.NewForTest(fakeClient, apps.ControllerRevisionHashLabelKey, func() metav1.Time { return now }),
+			record.NewFakeRecorder(10),
+			expectations.NewScaleExpectations(),
+			expectations.NewUpdateExpectations(clonesetutils.GetPodRevision),
+		}
+		if _, err := ctrl.Manage(cs, newRevision, []*apps.ControllerRevision{oldRevision, newRevision}, []*v1.Pod{tc.pod}, nil, &appsv1alpha1.CloneSetStatus{}); err != nil {
+			t.Fatalf("Failed to test %s, manage error: %v", tc.name, err)
+		}
+
+		gotPod := &v1.Pod{}
+		err := ctrl.Client.Get(context.TODO(), types.NamespacedName{Namespace: tc.pod.Namespace, Name: tc.pod.Name}, gotPod)
+		if tc.expectDeleted {
+			if err == nil {
+				t.Fatalf("Failed to test %s, expected pod deleted", tc.name)
+			}
+			continue
+		} else if err != nil {
+			t.Fatalf("Failed to test %s, get pod error: %v", tc.name, err)
+		}
+		if state := gotPod.Labels[appsv1alpha1.LifecycleStateKey]; state != string(tc.expectedState) {
+			t.Fatalf("Failed to test %s, expected state %s, got %s", tc.name, tc.expectedState, state)
+		}
+		if rev := clonesetutils.GetPodRevision(gotPod); rev != tc.expectedRev {
+			t.Fatalf("Failed to test %s, expected revision %s, got %s", tc.name, tc.expectedRev, rev)
+		}
+	}
+}
+
+func TestManageInPlaceUpdateTimeout(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	now := metav1.NewTime(time.Unix(time.Now().Unix(), 0))
+	newRevision := &apps.ControllerRevision{ObjectMeta: metav1.ObjectMeta{Name: "rev-new"}}
+
+	newPod := func(updateAgo time.Duration, imageID string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "pod-0",
+				Labels: map[string]string{apps.ControllerRevisionHashLabelKey: "rev-new", appsv1alpha1.CloneSetInstanceID: "id-0"},
+				Annotations: map[string]string{appsv1alpha1.InPlaceUpdateStateKey: util.DumpJSON(appsv1alpha1.InPlaceUpdateState{
+					Revision:              "rev-new",
+					UpdateTimestamp:       metav1.NewTime(now.Add(-updateAgo)),
+					LastContainerStatuses: map[string]appsv1alpha1.InPlaceUpdateContainerStatus{"c1": {ImageID: "image-id-xyz"}},
+				})},
+			},
+			Spec: v1.PodSpec{Containers: []v1.Container{{Name: "c1", Image: "foo2"}}},
+			Status: v1.PodStatus{
+				Phase:             v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{{Name: "c1", ImageID: imageID}},
+			},
+		}
+	}
+
+	cases := []struct {
+		name                  string
+		pod                   *v1.Pod
+		expectDeleted         bool
+		expectedFallbackCount int32
+		expectedRequeue       time.Duration
+	}{
+		{
+			name:                  "in-place update not completed before deadline",
+			pod:                   newPod(20*time.Second, "image-id-xyz"),
+			expectedFallbackCount: 1,
+			expectedRequeue:       40 * time.Second,
+		},
+		{
+			name:                  "in-place update completed",
+			pod:                   newPod(2*time.Minute, "image-id-abc"),
+			expectedFallbackCount: 1,
+		},
+		{
+			name:                  "in-place update timeout",
+			pod:                   newPod(2*time.Minute, "image-id-xyz"),
+			expectDeleted:         true,
+			expectedFallbackCount: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		cs := &appsv1alpha1.CloneSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "clone-test"},
+			Spec: appsv1alpha1.CloneSetSpec{
+				Replicas: getInt32Pointer(1),
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:                  appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					InPlaceUpdateStrategy: &appsv1alpha1.InPlaceUpdateStrategy{TimeoutSeconds: 60},
+				},
+			},
+		}
+		fakeClient := fake.NewFakeClient(cs, tc.pod)
+		ctrl := &realControl{
+			fakeClient,
+			inplaceupdate.NewForTest(fakeClient, apps.ControllerRevisionHashLabelKey, func() metav1.Time { return now }),
+			record.NewFakeRecorder(10),
+			expectations.NewScaleExpectations(),
+			expectations.NewUpdateExpectations(clonesetutils.GetPodRevision),
+		}
+		newStatus := &appsv1alpha1.CloneSetStatus{InPlaceUpdateFallbackCount: 1}
+		requeue, err := ctrl.Manage(cs, newRevision, []*apps.ControllerRevision{newRevision}, []*v1.Pod{tc.pod}, nil, newStatus)
+		if err != nil {
+			t.Fatalf("Failed to test %s, manage error: %v", tc.name, err)
+		}
+		if requeue != tc.expectedRequeue {
+			t.Fatalf("Failed to test %s, expected requeue %v, got %v", tc.name, tc.expectedRequeue, requeue)
+		}
+
+		if newStatus.InPlaceUpdateFallbackCount != tc.expectedFallbackCount {
+			t.Fatalf("Failed to test %s, expected fallback count %d, got %d", tc.name, tc.expectedFallbackCount, newStatus.InPlaceUpdateFallbackCount)
+		}
+
+		err = ctrl.Client.Get(context.TODO(), types.NamespacedName{Namespace: tc.pod.Namespace, Name: tc.pod.Name}, &v1.Pod{})
+		if deleted := err != nil; deleted != tc.expectDeleted {
+			t.Fatalf("Failed to test %s, expected deleted %v, got err %v", tc.name, tc.expectDeleted, err)
+		}
+	}
+}
+
 func TestSortUpdateIndexes(t *testing.T) {
 	cases := []struct {
 		strategy          appsv1alpha1.CloneSetUpdateStrategy
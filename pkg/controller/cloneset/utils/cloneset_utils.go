@@ -22,6 +22,7 @@ import (
 	"sync"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/lifecycle"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -84,14 +85,18 @@ func NextRevision(revisions []*apps.ControllerRevision) int64 {
 	return revisions[count-1].Revision + 1
 }
 
-// IsRunningAndReady returns true if pod is in the PodRunning Phase, if it is ready.
+// IsRunningAndReady returns true if pod is in the PodRunning Phase, if it is ready and has no container being recreated.
 func IsRunningAndReady(pod *v1.Pod) bool {
-	return pod.Status.Phase == v1.PodRunning && podutil.IsPodReady(pod)
+	return pod.Status.Phase == v1.PodRunning && podutil.IsPodReady(pod) && !isContainerRecreating(pod)
 }
 
-// IsRunningAndAvailable returns true if pod is in the PodRunning Phase, if it is available.
+// IsRunningAndAvailable returns true if pod is in the PodRunning Phase, if it is available and has no container being recreated.
 func IsRunningAndAvailable(pod *v1.Pod, minReadySeconds int32) bool {
-	return pod.Status.Phase == v1.PodRunning && podutil.IsPodAvailable(pod, minReadySeconds, metav1.Now())
+	return pod.Status.Phase == v1.PodRunning && podutil.IsPodAvailable(pod, minReadySeconds, metav1.Now()) && !isContainerRecreating(pod)
+}
+
+func isContainerRecreating(pod *v1.Pod) bool {
+	return pod.Annotations[appsv1alpha1.ContainerRecreatingKey] != ""
 }
 
 // SplitPodsByRevision returns Pods matched and unmatched the given revision
@@ -106,6 +111,27 @@ func SplitPodsByRevision(pods []*v1.Pod, rev string) (matched, unmatched []*v1.P
 	return
 }
 
+// FilterOutPreparingDeletePods returns Pods that are not in PreparingDelete lifecycle state.
+func FilterOutPreparingDeletePods(pods []*v1.Pod) []*v1.Pod {
+	var filteredPods []*v1.Pod
+	for _, p := range pods {
+		if lifecycle.GetPodLifecycleState(p) != appsv1alpha1.LifecycleStatePreparingDelete {
+			filteredPods = append(filteredPods, p)
+		}
+	}
+	return filteredPods
+}
+
+// GetCondition returns the condition with the given type from the CloneSet status, nil if not found.
+func GetCondition(status appsv1alpha1.CloneSetStatus, condType appsv1alpha1.CloneSetConditionType) *appsv1alpha1.CloneSetCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == condType {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
 // UpdateStorage insert volumes generated by cs.Spec.VolumeClaimTemplates into Pod.
 func UpdateStorage(cs *appsv1alpha1.CloneSet, pod *v1.Pod) {
 	currentVolumes := pod.Spec.Volumes
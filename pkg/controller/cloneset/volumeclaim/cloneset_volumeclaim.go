@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeclaim
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	"github.com/openkruise/kruise/pkg/util/expectations"
+	volumeclaimutil "github.com/openkruise/kruise/pkg/util/volumeclaim"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/types"
+	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Interface for managing the expansion of PVCs that created from volumeClaimTemplates.
+type Interface interface {
+	// Manage expands the PVCs of pods to the storage requested in volumeClaimTemplates, recreates pods whose PVCs
+	// are waiting for filesystem resize if required, and returns the number of pods whose PVCs have been expanded.
+	Manage(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, pvcs []*v1.PersistentVolumeClaim) (updatedReplicas int32, recreating bool, err error)
+}
+
+// New returns a volume claim control.
+func New(c client.Client, recorder record.EventRecorder, scaleExp expectations.ScaleExpectations) Interface {
+	return &realControl{Client: c, recorder: recorder, scaleExp: scaleExp}
+}
+
+type realControl struct {
+	client.Client
+	recorder record.EventRecorder
+	scaleExp expectations.ScaleExpectations
+}
+
+func (c *realControl) Manage(cs *appsv1alpha1.CloneSet, pods []*v1.Pod, pvcs []*v1.PersistentVolumeClaim) (int32, bool, error) {
+	existingClaims := make(map[string]*v1.PersistentVolumeClaim, len(pvcs))
+	for _, pvc := range pvcs {
+		existingClaims[pvc.Name] = pvc
+	}
+	storageClasses := make(map[string]*storagev1.StorageClass)
+
+	var updatedReplicas int32
+	var resizePendingPods []*v1.Pod
+	for _, pod := range pods {
+		completed, resizePending := true, false
+		for _, expected := range clonesetutils.GetPersistentVolumeClaims(cs, pod) {
+			template := expected
+			existing, ok := existingClaims[template.Name]
+			if !ok {
+				completed = false
+				continue
+			}
+			if volumeclaimutil.IsExpansionRequired(existing, &template) {
+				completed = false
+				if err := c.expandClaim(cs, existing, &template, storageClasses); err != nil {
+					return updatedReplicas, false, err
+				}
+				continue
+			}
+			if !volumeclaimutil.IsExpansionCompleted(existing, &template) {
+				completed = false
+			}
+			if volumeclaimutil.IsFileSystemResizePending(existing) {
+				resizePending = true
+			}
+		}
+		if completed {
+			updatedReplicas++
+		}
+		if resizePending {
+			resizePendingPods = append(resizePendingPods, pod)
+		}
+	}
+
+	if cs.Spec.VolumeClaimUpdateStrategy == nil || !cs.Spec.VolumeClaimUpdateStrategy.RecreatePodOnFileSystemResizePending ||
+		len(resizePendingPods) == 0 {
+		return updatedReplicas, false, nil
+	}
+	recreating, err := c.recreatePods(cs, pods, resizePendingPods)
+	return updatedReplicas, recreating, err
+}
+
+// expandClaim updates the storage request of claim to the one in template, if its StorageClass allows volume expansion.
+func (c *realControl) expandClaim(cs *appsv1alpha1.CloneSet, claim, template *v1.PersistentVolumeClaim,
+	storageClasses map[string]*storagev1.StorageClass) error {
+
+	scName := volumeclaimutil.GetStorageClassName(claim)
+	if scName == "" {
+		klog.V(4).Infof("CloneSet %s skip expanding pvc %s with no StorageClass", clonesetutils.GetControllerKey(cs), claim.Name)
+		return nil
+	}
+	sc, ok := storageClasses[scName]
+	if !ok {
+		sc = &storagev1.StorageClass{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: scName}, sc); err != nil {
+			return err
+		}
+		storageClasses[scName] = sc
+	}
+	if !volumeclaimutil.AllowsExpansion(sc) {
+		klog.V(4).Infof("CloneSet %s skip expanding pvc %s, StorageClass %s does not allow volume expansion",
+			clonesetutils.GetControllerKey(cs), claim.Name, scName)
+		return nil
+	}
+
+	newClaim := volumeclaimutil.NewExpandedClaim(claim, template)
+	if err := c.Update(context.TODO(), newClaim); err != nil {
+		c.recorder.Eventf(cs, v1.EventTypeWarning, "FailedExpandPVC", "failed to expand pvc %s: %v", claim.Name, err)
+		return err
+	}
+	storage := volumeclaimutil.GetStorageRequest(template)
+	c.recorder.Eventf(cs, v1.EventTypeNormal, "SuccessfulExpandPVC", "succeed to expand pvc %s to %s", claim.Name, storage.String())
+	return nil
+}
+
+// recreatePods deletes the available pods whose PVCs are waiting for filesystem resize, without exceeding
+// maxUnavailable of the update strategy. The PVCs are kept, so that they will be mounted by the new pods.
+func (c *realControl) recreatePods(cs *appsv1alpha1.CloneSet, pods, resizePendingPods []*v1.Pod) (bool, error) {
+	replicas := len(pods)
+	if cs.Spec.Replicas != nil {
+		replicas = int(*cs.Spec.Replicas)
+	}
+	maxUnavailable, err := intstrutil.GetValueFromIntOrPercent(
+		intstrutil.ValueOrDefault(cs.Spec.UpdateStrategy.MaxUnavailable, intstrutil.FromString(appsv1alpha1.DefaultCloneSetMaxUnavailable)), replicas, true)
+	if err != nil {
+		return false, err
+	}
+
+	var unavailable int
+	for _, pod := range pods {
+		if !clonesetutils.IsRunningAndAvailable(pod, cs.Spec.MinReadySeconds) {
+			unavailable++
+		}
+	}
+
+	var recreating bool
+	for _, pod := range resizePendingPods {
+		if unavailable >= maxUnavailable {
+			break
+		}
+		if !clonesetutils.IsRunningAndAvailable(pod, cs.Spec.MinReadySeconds) {
+			continue
+		}
+
+		klog.V(2).Infof("CloneSet %s deleting Pod %s for filesystem resize", clonesetutils.GetControllerKey(cs), pod.Name)
+		c.scaleExp.ExpectScale(clonesetutils.GetControllerKey(cs), expectations.Delete, pod.Name)
+		if err := c.Delete(context.TODO(), pod); err != nil {
+			c.scaleExp.ObserveScale(clonesetutils.GetControllerKey(cs), expectations.Delete, pod.Name)
+			c.recorder.Eventf(cs, v1.EventTypeWarning, "FailedRecreatePodForFileSystemResize",
+				"failed to delete pod %s for filesystem resize: %v", pod.Name, err)
+			return recreating, err
+		}
+		c.recorder.Eventf(cs, v1.EventTypeNormal, "SuccessfulRecreatePodForFileSystemResize",
+			"succeed to delete pod %s for filesystem resize", pod.Name)
+		unavailable++
+		recreating = true
+	}
+	return recreating, nil
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeclaim
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openkruise/kruise/pkg/apis"
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/expectations"
+	volumeclaimutil "github.com/openkruise/kruise/pkg/util/volumeclaim"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	_ = apis.AddToScheme(scheme.Scheme)
+}
+
+func newCloneSet(storage string, recreate bool) *appsv1alpha1.CloneSet {
+	scName := "standard"
+	maxUnavailable := intstr.FromInt(1)
+	return &appsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cs", UID: "cs-uid"},
+		Spec: appsv1alpha1.CloneSetSpec{
+			Replicas: &[]int32{3}[0],
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}},
+			VolumeClaimTemplates: []v1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "data"},
+				Spec: v1.PersistentVolumeClaimSpec{
+					StorageClassName: &scName,
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse(storage)},
+					},
+				},
+			}},
+			VolumeClaimUpdateStrategy: &appsv1alpha1.VolumeClaimUpdateStrategy{RecreatePodOnFileSystemResizePending: recreate},
+			UpdateStrategy:            appsv1alpha1.CloneSetUpdateStrategy{MaxUnavailable: &maxUnavailable},
+		},
+	}
+}
+
+func newPod(id string, ready bool) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "cs-" + id,
+			Labels:    map[string]string{"app": "demo", appsv1alpha1.CloneSetInstanceID: id},
+		},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+	if ready {
+		pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	}
+	return pod
+}
+
+func newPVC(id, storage, capacity string, resizePending bool) *v1.PersistentVolumeClaim {
+	scName := "standard"
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "data-cs-" + id,
+			Labels:    map[string]string{"app": "demo", appsv1alpha1.CloneSetInstanceID: id},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			StorageClassName: &scName,
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse(storage)},
+			},
+		},
+		Status: v1.PersistentVolumeClaimStatus{
+			Capacity: v1.ResourceList{v1.ResourceStorage: resource.MustParse(capacity)},
+		},
+	}
+	if resizePending {
+		pvc.Status.Conditions = []v1.PersistentVolumeClaimCondition{
+			{Type: v1.PersistentVolumeClaimFileSystemResizePending, Status: v1.ConditionTrue},
+		}
+	}
+	return pvc
+}
+
+func TestManage(t *testing.T) {
+	cases := []struct {
+		name                  string
+		cs                    *appsv1alpha1.CloneSet
+		allowExpansion        bool
+		pods                  []*v1.Pod
+		pvcs                  []*v1.PersistentVolumeClaim
+		expectStorage         map[string]string
+		expectUpdatedReplicas int32
+		expectRecreating      bool
+		expectDeletedPods     []string
+	}{
+		{
+			name:           "expand pvcs",
+			cs:             newCloneSet("2Gi", false),
+			allowExpansion: true,
+			pods:           []*v1.Pod{newPod("a", true), newPod("b", true)},
+			pvcs:           []*v1.PersistentVolumeClaim{newPVC("a", "1Gi", "1Gi", false), newPVC("b", "2Gi", "2Gi", false)},
+			expectStorage:  map[string]string{"data-cs-a": "2Gi", "data-cs-b": "2Gi"},
+
+			expectUpdatedReplicas: 1,
+		},
+		{
+			name:           "skip storage class not allowing expansion",
+			cs:             newCloneSet("2Gi", false),
+			allowExpansion: false,
+			pods:           []*v1.Pod{newPod("a", true)},
+			pvcs:           []*v1.PersistentVolumeClaim{newPVC("a", "1Gi", "1Gi", false)},
+			expectStorage:  map[string]string{"data-cs-a": "1Gi"},
+		},
+		{
+			name:           "keep pods when recreation disabled",
+			cs:             newCloneSet("2Gi", false),
+			allowExpansion: true,
+			pods:           []*v1.Pod{newPod("a", true)},
+			pvcs:           []*v1.PersistentVolumeClaim{newPVC("a", "2Gi", "1Gi", true)},
+			expectStorage:  map[string]string{"data-cs-a": "2Gi"},
+		},
+		{
+			name:           "recreate pods within maxUnavailable",
+			cs:             newCloneSet("2Gi", true),
+			allowExpansion: true,
+			pods:           []*v1.Pod{newPod("a", true), newPod("b", true), newPod("c", true)},
+			pvcs: []*v1.PersistentVolumeClaim{
+				newPVC("a", "2Gi", "1Gi", true), newPVC("b", "2Gi", "1Gi", true), newPVC("c", "2Gi", "2Gi", false),
+			},
+			expectStorage:         map[string]string{"data-cs-a": "2Gi", "data-cs-b": "2Gi", "data-cs-c": "2Gi"},
+			expectUpdatedReplicas: 1,
+			expectRecreating:      true,
+			expectDeletedPods:     []string{"cs-a"},
+		},
+		{
+			name:           "wait for unavailable pods before recreating",
+			cs:             newCloneSet("2Gi", true),
+			allowExpansion: true,
+			pods:           []*v1.Pod{newPod("a", true), newPod("b", false)},
+			pvcs:           []*v1.PersistentVolumeClaim{newPVC("a", "2Gi", "1Gi", true), newPVC("b", "2Gi", "2Gi", false)},
+			expectStorage:  map[string]string{"data-cs-a": "2Gi", "data-cs-b": "2Gi"},
+
+			expectUpdatedReplicas: 1,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sc := &storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: "standard"},
+				AllowVolumeExpansion: &testCase.allowExpansion,
+			}
+			objects := []runtime.Object{testCase.cs, sc}
+			for _, pod := range testCase.pods {
+				objects = append(objects, pod)
+			}
+			for _, pvc := range testCase.pvcs {
+				objects = append(objects, pvc)
+			}
+			fakeClient := fake.NewFakeClient(objects...)
+			ctrl := &realControl{Client: fakeClient, recorder: record.NewFakeRecorder(10), scaleExp: expectations.NewScaleExpectations()}
+
+			updatedReplicas, recreating, err := ctrl.Manage(testCase.cs, testCase.pods, testCase.pvcs)
+			if err != nil {
+				t.Fatalf("Failed to manage: %v", err)
+			}
+			if updatedReplicas != testCase.expectUpdatedReplicas {
+				t.Fatalf("Expected updatedReplicas %d, got %d", testCase.expectUpdatedReplicas, updatedReplicas)
+			}
+			if recreating != testCase.expectRecreating {
+				t.Fatalf("Expected recreating %v, got %v", testCase.expectRecreating, recreating)
+			}
+
+			for name, storage := range testCase.expectStorage {
+				pvc := &v1.PersistentVolumeClaim{}
+				if err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: name}, pvc); err != nil {
+					t.Fatalf("Failed to get pvc %s: %v", name, err)
+				}
+				expected := resource.MustParse(storage)
+				if got := volumeclaimutil.GetStorageRequest(pvc); got.Cmp(expected) != 0 {
+					t.Fatalf("Expected pvc %s requesting %s, got %s", name, expected.String(), got.String())
+				}
+			}
+
+			deleted := map[string]bool{}
+			for _, name := range testCase.expectDeletedPods {
+				deleted[name] = true
+			}
+			for _, pod := range testCase.pods {
+				err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}, &v1.Pod{})
+				if deleted[pod.Name] != errors.IsNotFound(err) {
+					t.Fatalf("Expected pod %s deleted %v, got error %v", pod.Name, deleted[pod.Name], err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreaterequest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/gate"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Add creates a new ContainerRecreateRequest Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	if !gate.ResourceEnabled(&appsv1alpha1.ContainerRecreateRequest{}) {
+		return nil
+	}
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileContainerRecreateRequest{
+		Client:   mgr.GetClient(),
+		recorder: mgr.GetRecorder("containerrecreaterequest-controller"),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("containerrecreaterequest-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to ContainerRecreateRequest
+	err = c.Watch(&source.Kind{Type: &appsv1alpha1.ContainerRecreateRequest{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to Pod
+	err = c.Watch(&source.Kind{Type: &v1.Pod{}}, &podEventHandler{client: mgr.GetClient()})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileContainerRecreateRequest{}
+
+// ReconcileContainerRecreateRequest reconciles a ContainerRecreateRequest object
+type ReconcileContainerRecreateRequest struct {
+	client.Client
+	recorder record.EventRecorder
+}
+
+// Reconcile reads that state of the cluster for a ContainerRecreateRequest object and makes changes based on the state read
+// and what is in the ContainerRecreateRequest.Spec. The containers are recreated by kruise-daemon on the node of Pod,
+// this controller only accepts the request, marks the Pod as recreating and finishes the request when it is timeout.
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=containerrecreaterequests,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=containerrecreaterequests/status,verbs=get;update;patch
+func (r *ReconcileContainerRecreateRequest) Reconcile(request reconcile.Request) (res reconcile.Result, retErr error) {
+	startTime := time.Now()
+	defer func() {
+		if retErr == nil {
+			if res.Requeue || res.RequeueAfter > 0 {
+				klog.Infof("Finished syncing ContainerRecreateRequest %s, cost %v, result: %v", request, time.Since(startTime), res)
+			} else {
+				klog.Infof("Finished syncing ContainerRecreateRequest %s, cost %v", request, time.Since(startTime))
+			}
+		} else {
+			klog.Errorf("Failed syncing ContainerRecreateRequest %s: %v", request, retErr)
+		}
+	}()
+
+	// Fetch the ContainerRecreateRequest instance
+	crr := &appsv1alpha1.ContainerRecreateRequest{}
+	err := r.Get(context.TODO(), request.NamespacedName, crr)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Object not found, release the Pod that may still be marked as recreating by it.
+			return reconcile.Result{}, r.releasePodsOfDeletedRequest(request.Namespace, request.Name)
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	pod := &v1.Pod{}
+	err = r.Get(context.TODO(), types.NamespacedName{Namespace: crr.Namespace, Name: crr.Spec.PodName}, pod)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		pod = nil
+	}
+
+	if crr.DeletionTimestamp != nil {
+		if pod != nil {
+			return reconcile.Result{}, r.updatePodRecreatingAnnotation(pod, crr)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if appsv1alpha1.IsContainerRecreateRequestCompleted(crr) {
+		if pod != nil {
+			if err = r.updatePodRecreatingAnnotation(pod, crr); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		if isPast, leftTime := pastTTLDeadline(crr); isPast {
+			klog.Infof("Deleting ContainerRecreateRequest %s for ttlSecondsAfterFinished", request)
+			if err = r.Delete(context.TODO(), crr); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+		} else if leftTime > 0 {
+			return reconcile.Result{RequeueAfter: leftTime}, nil
+		}
+		return reconcile.Result{}, nil
+	}
+
+	switch {
+	case pod == nil || pod.DeletionTimestamp != nil:
+		return r.failRequest(crr, nil, "pod not found or has been deleted")
+	case crr.Labels[appsv1alpha1.ContainerRecreateRequestPodUIDKey] != "" && crr.Labels[appsv1alpha1.ContainerRecreateRequestPodUIDKey] != string(pod.UID):
+		return r.failRequest(crr, nil, "pod has been recreated")
+	case pod.Spec.NodeName == "":
+		return r.failRequest(crr, pod, "pod has not been scheduled")
+	}
+
+	var requeueAfter time.Duration
+	if isPast, leftTime := pastActiveDeadline(crr); isPast {
+		r.recorder.Eventf(crr, v1.EventTypeWarning, "DeadlineExceeded", "ContainerRecreateRequest was active longer than specified deadline")
+		return r.failRequest(crr, pod, "recreating has exceeded the activeDeadlineSeconds")
+	} else if leftTime > 0 {
+		requeueAfter = leftTime
+	}
+
+	if crr.Labels[appsv1alpha1.ContainerRecreateRequestPodUIDKey] == "" {
+		if msg := validateContainers(crr, pod); msg != "" {
+			return r.failRequest(crr, pod, msg)
+		}
+		if crr, err = r.acceptRequest(crr, pod); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// mark the Pod as recreating before the request can be handled by kruise-daemon
+	if err = r.updatePodRecreatingAnnotation(pod, crr); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if crr.Status.Phase == "" {
+		err = r.updateStatus(crr, func(status *appsv1alpha1.ContainerRecreateRequestStatus) {
+			status.Phase = appsv1alpha1.ContainerRecreateRequestPending
+		})
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, err
+}
+
+// acceptRequest sets the labels of node and Pod, and records the current status of containers into the request.
+func (r *ReconcileContainerRecreateRequest) acceptRequest(crr *appsv1alpha1.ContainerRecreateRequest, pod *v1.Pod) (*appsv1alpha1.ContainerRecreateRequest, error) {
+	clone := &appsv1alpha1.ContainerRecreateRequest{}
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := r.Get(context.TODO(), types.NamespacedName{Namespace: crr.Namespace, Name: crr.Name}, clone); err != nil {
+			return err
+		}
+		if clone.Labels == nil {
+			clone.Labels = make(map[string]string)
+		}
+		clone.Labels[appsv1alpha1.ContainerRecreateRequestNodeNameKey] = pod.Spec.NodeName
+		clone.Labels[appsv1alpha1.ContainerRecreateRequestPodUIDKey] = string(pod.UID)
+		for i := range clone.Spec.Containers {
+			c := &clone.Spec.Containers[i]
+			if cs := getContainerStatus(pod, c.Name); cs != nil {
+				c.StatusContext = &appsv1alpha1.ContainerRecreateRequestContainerContext{
+					ContainerID:  cs.ContainerID,
+					RestartCount: cs.RestartCount,
+				}
+			}
+		}
+		return r.Update(context.TODO(), clone)
+	})
+	if err != nil {
+		return nil, err
+	}
+	klog.V(3).Infof("Accepted ContainerRecreateRequest %s/%s for Pod %s on node %s", crr.Namespace, crr.Name, pod.Name, pod.Spec.NodeName)
+	return clone, nil
+}
+
+func (r *ReconcileContainerRecreateRequest) failRequest(crr *appsv1alpha1.ContainerRecreateRequest, pod *v1.Pod, msg string) (reconcile.Result, error) {
+	r.recorder.Eventf(crr, v1.EventTypeWarning, "Failed", "ContainerRecreateRequest failed: %s", msg)
+	err := r.updateStatus(crr, func(status *appsv1alpha1.ContainerRecreateRequestStatus) {
+		now := metav1.Now()
+		status.Phase = appsv1alpha1.ContainerRecreateRequestFailed
+		status.CompletionTime = &now
+		status.Message = msg
+	})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	// the Pod will be released in the next reconcile when the request is completed
+	return reconcile.Result{}, nil
+}
+
+// updatePodRecreatingAnnotation sets the recreating annotation of Pod to one of its active requests,
+// or removes the annotation if there is no active request.
+func (r *ReconcileContainerRecreateRequest) updatePodRecreatingAnnotation(pod *v1.Pod, crr *appsv1alpha1.ContainerRecreateRequest) error {
+	crrList := &appsv1alpha1.ContainerRecreateRequestList{}
+	opts := client.InNamespace(pod.Namespace).MatchingLabels(map[string]string{appsv1alpha1.ContainerRecreateRequestPodUIDKey: string(pod.UID)})
+	if err := r.List(context.TODO(), opts, crrList); err != nil {
+		return err
+	}
+
+	activeNames := make(map[string]struct{})
+	for i := range crrList.Items {
+		item := &crrList.Items[i]
+		if item.Labels[appsv1alpha1.ContainerRecreateRequestPodUIDKey] == string(pod.UID) && isActive(item) {
+			activeNames[item.Name] = struct{}{}
+		}
+	}
+	// the cache of requests may be out of date, so trust the current one
+	if isActive(crr) {
+		activeNames[crr.Name] = struct{}{}
+	} else {
+		delete(activeNames, crr.Name)
+	}
+	return r.updatePod(pod.Namespace, pod.Name, getDesiredRecreatingAnnotation(activeNames))
+}
+
+func (r *ReconcileContainerRecreateRequest) releasePodsOfDeletedRequest(namespace, name string) error {
+	podList := &v1.PodList{}
+	if err := r.List(context.TODO(), client.InNamespace(namespace), podList); err != nil {
+		return err
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Annotations[appsv1alpha1.ContainerRecreatingKey] != name {
+			continue
+		}
+		crr := &appsv1alpha1.ContainerRecreateRequest{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		crr.Status.Phase = appsv1alpha1.ContainerRecreateRequestFailed
+		if err := r.updatePodRecreatingAnnotation(pod, crr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReconcileContainerRecreateRequest) updatePod(namespace, name, recreating string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		pod := &v1.Pod{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, pod); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if pod.Annotations[appsv1alpha1.ContainerRecreatingKey] == recreating {
+			return nil
+		}
+		if recreating == "" {
+			delete(pod.Annotations, appsv1alpha1.ContainerRecreatingKey)
+		} else {
+			if pod.Annotations == nil {
+				pod.Annotations = make(map[string]string)
+			}
+			pod.Annotations[appsv1alpha1.ContainerRecreatingKey] = recreating
+		}
+		return r.Update(context.TODO(), pod)
+	})
+}
+
+func (r *ReconcileContainerRecreateRequest) updateStatus(crr *appsv1alpha1.ContainerRecreateRequest, modifyFunc func(*appsv1alpha1.ContainerRecreateRequestStatus)) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		clone := &appsv1alpha1.ContainerRecreateRequest{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Namespace: crr.Namespace, Name: crr.Name}, clone); err != nil {
+			return err
+		}
+		if appsv1alpha1.IsContainerRecreateRequestCompleted(clone) {
+			return nil
+		}
+		modifyFunc(&clone.Status)
+		return r.Status().Update(context.TODO(), clone)
+	})
+}
+
+// validateContainers returns the message why the request is invalid for the Pod, or empty if it is valid.
+func validateContainers(crr *appsv1alpha1.ContainerRecreateRequest, pod *v1.Pod) string {
+	if len(crr.Spec.Containers) == 0 {
+		return "no container to recreate"
+	}
+	names := make(map[string]struct{}, len(crr.Spec.Containers))
+	for _, c := range crr.Spec.Containers {
+		if _, ok := names[c.Name]; ok {
+			return fmt.Sprintf("duplicated container %s", c.Name)
+		}
+		names[c.Name] = struct{}{}
+		cs := getContainerStatus(pod, c.Name)
+		if cs == nil || cs.ContainerID == "" {
+			return fmt.Sprintf("container %s not found or not started in pod", c.Name)
+		}
+		if c.PreStop != nil && c.PreStop.TCPSocket != nil {
+			return fmt.Sprintf("tcpSocket preStop of container %s is not supported", c.Name)
+		}
+	}
+	return ""
+}
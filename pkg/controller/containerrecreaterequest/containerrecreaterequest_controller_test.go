@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreaterequest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openkruise/kruise/pkg/apis"
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	_ = apis.AddToScheme(scheme.Scheme)
+}
+
+func newPod() *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", UID: "pod-uid"},
+		Spec: v1.PodSpec{
+			NodeName:   "node1",
+			Containers: []v1.Container{{Name: "main"}, {Name: "sidecar"}},
+		},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "main", ContainerID: "docker://main-id", RestartCount: 1},
+				{Name: "sidecar", ContainerID: "docker://sidecar-id"},
+			},
+		},
+	}
+}
+
+func newCRR() *appsv1alpha1.ContainerRecreateRequest {
+	return &appsv1alpha1.ContainerRecreateRequest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "crr", CreationTimestamp: metav1.Now()},
+		Spec: appsv1alpha1.ContainerRecreateRequestSpec{
+			PodName:    "pod",
+			Containers: []appsv1alpha1.ContainerRecreateRequestContainer{{Name: "main"}},
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	longAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	cases := []struct {
+		name               string
+		getPod             func() *v1.Pod
+		getCRR             func() *appsv1alpha1.ContainerRecreateRequest
+		expectedPhase      appsv1alpha1.ContainerRecreateRequestPhase
+		expectedAccepted   bool
+		expectedRecreating string
+	}{
+		{
+			name:               "accept request",
+			getPod:             newPod,
+			getCRR:             newCRR,
+			expectedPhase:      appsv1alpha1.ContainerRecreateRequestPending,
+			expectedAccepted:   true,
+			expectedRecreating: "crr",
+		},
+		{
+			name:          "pod not found",
+			getPod:        func() *v1.Pod { return nil },
+			getCRR:        newCRR,
+			expectedPhase: appsv1alpha1.ContainerRecreateRequestFailed,
+		},
+		{
+			name: "pod not scheduled",
+			getPod: func() *v1.Pod {
+				pod := newPod()
+				pod.Spec.NodeName = ""
+				return pod
+			},
+			getCRR:        newCRR,
+			expectedPhase: appsv1alpha1.ContainerRecreateRequestFailed,
+		},
+		{
+			name:   "container not found",
+			getPod: newPod,
+			getCRR: func() *appsv1alpha1.ContainerRecreateRequest {
+				crr := newCRR()
+				crr.Spec.Containers = append(crr.Spec.Containers, appsv1alpha1.ContainerRecreateRequestContainer{Name: "none"})
+				return crr
+			},
+			expectedPhase: appsv1alpha1.ContainerRecreateRequestFailed,
+		},
+		{
+			name: "pod has been recreated",
+			getPod: func() *v1.Pod {
+				pod := newPod()
+				pod.UID = "new-uid"
+				return pod
+			},
+			getCRR: func() *appsv1alpha1.ContainerRecreateRequest {
+				crr := newCRR()
+				crr.Labels = map[string]string{appsv1alpha1.ContainerRecreateRequestPodUIDKey: "pod-uid"}
+				crr.Status.Phase = appsv1alpha1.ContainerRecreateRequestRecreating
+				return crr
+			},
+			expectedPhase:    appsv1alpha1.ContainerRecreateRequestFailed,
+			expectedAccepted: true,
+		},
+		{
+			name: "active deadline exceeded",
+			getPod: func() *v1.Pod {
+				pod := newPod()
+				pod.Annotations = map[string]string{appsv1alpha1.ContainerRecreatingKey: "crr"}
+				return pod
+			},
+			getCRR: func() *appsv1alpha1.ContainerRecreateRequest {
+				crr := newCRR()
+				crr.CreationTimestamp = longAgo
+				crr.Spec.ActiveDeadlineSeconds = func() *int64 { v := int64(60); return &v }()
+				crr.Status.Phase = appsv1alpha1.ContainerRecreateRequestRecreating
+				return crr
+			},
+			expectedPhase:      appsv1alpha1.ContainerRecreateRequestFailed,
+			expectedRecreating: "crr",
+		},
+		{
+			name: "release pod after completed",
+			getPod: func() *v1.Pod {
+				pod := newPod()
+				pod.Annotations = map[string]string{appsv1alpha1.ContainerRecreatingKey: "crr"}
+				return pod
+			},
+			getCRR: func() *appsv1alpha1.ContainerRecreateRequest {
+				crr := newCRR()
+				crr.Labels = map[string]string{appsv1alpha1.ContainerRecreateRequestPodUIDKey: "pod-uid"}
+				crr.Status.Phase = appsv1alpha1.ContainerRecreateRequestSucceeded
+				return crr
+			},
+			expectedPhase:    appsv1alpha1.ContainerRecreateRequestSucceeded,
+			expectedAccepted: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			crr := tc.getCRR()
+			objects := []runtime.Object{crr}
+			if pod := tc.getPod(); pod != nil {
+				objects = append(objects, pod)
+			}
+			fakeClient := fake.NewFakeClient(objects...)
+			r := &ReconcileContainerRecreateRequest{Client: fakeClient, recorder: record.NewFakeRecorder(10)}
+
+			if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: crr.Namespace, Name: crr.Name}}); err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+
+			gotCRR := &appsv1alpha1.ContainerRecreateRequest{}
+			if err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: crr.Namespace, Name: crr.Name}, gotCRR); err != nil {
+				t.Fatalf("failed to get crr: %v", err)
+			}
+			if gotCRR.Status.Phase != tc.expectedPhase {
+				t.Fatalf("expected phase %s, got %s", tc.expectedPhase, gotCRR.Status.Phase)
+			}
+			if accepted := gotCRR.Labels[appsv1alpha1.ContainerRecreateRequestPodUIDKey] != ""; accepted != tc.expectedAccepted {
+				t.Fatalf("expected accepted %v, got labels %v", tc.expectedAccepted, gotCRR.Labels)
+			}
+			if tc.name == "accept request" {
+				if gotCRR.Labels[appsv1alpha1.ContainerRecreateRequestNodeNameKey] != "node1" {
+					t.Fatalf("expected node name label, got %v", gotCRR.Labels)
+				}
+				if ctx := gotCRR.Spec.Containers[0].StatusContext; ctx == nil || ctx.ContainerID != "docker://main-id" || ctx.RestartCount != 1 {
+					t.Fatalf("unexpected status context %+v", ctx)
+				}
+			}
+
+			gotPod := &v1.Pod{}
+			if err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "pod"}, gotPod); err != nil {
+				return
+			}
+			if v := gotPod.Annotations[appsv1alpha1.ContainerRecreatingKey]; v != tc.expectedRecreating {
+				t.Fatalf("expected recreating annotation %q, got %q", tc.expectedRecreating, v)
+			}
+		})
+	}
+}
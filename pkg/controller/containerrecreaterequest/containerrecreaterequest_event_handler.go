@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreaterequest
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ handler.EventHandler = &podEventHandler{}
+
+type podEventHandler struct {
+	client client.Client
+}
+
+func (e *podEventHandler) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (e *podEventHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	oldPod, oldOK := evt.ObjectOld.(*v1.Pod)
+	newPod, newOK := evt.ObjectNew.(*v1.Pod)
+	if !oldOK || !newOK {
+		return
+	}
+	if oldPod.DeletionTimestamp.Equal(newPod.DeletionTimestamp) &&
+		oldPod.Annotations[appsv1alpha1.ContainerRecreatingKey] == newPod.Annotations[appsv1alpha1.ContainerRecreatingKey] {
+		return
+	}
+	e.enqueueRequestsOfPod(newPod, q)
+}
+
+func (e *podEventHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	if pod, ok := evt.Object.(*v1.Pod); ok {
+		e.enqueueRequestsOfPod(pod, q)
+	}
+}
+
+func (e *podEventHandler) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (e *podEventHandler) enqueueRequestsOfPod(pod *v1.Pod, q workqueue.RateLimitingInterface) {
+	crrList := &appsv1alpha1.ContainerRecreateRequestList{}
+	opts := client.InNamespace(pod.Namespace).MatchingLabels(map[string]string{appsv1alpha1.ContainerRecreateRequestPodUIDKey: string(pod.UID)})
+	if err := e.client.List(context.TODO(), opts, crrList); err != nil {
+		klog.Errorf("Failed to list ContainerRecreateRequests for Pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return
+	}
+	for i := range crrList.Items {
+		crr := &crrList.Items[i]
+		if crr.Labels[appsv1alpha1.ContainerRecreateRequestPodUIDKey] != string(pod.UID) || !isActive(crr) {
+			continue
+		}
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: crr.Namespace, Name: crr.Name}})
+	}
+}
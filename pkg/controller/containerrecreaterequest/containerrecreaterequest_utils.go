@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreaterequest
+
+import (
+	"sort"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// isActive returns whether the request is still recreating containers or waiting to.
+func isActive(crr *appsv1alpha1.ContainerRecreateRequest) bool {
+	return crr.DeletionTimestamp == nil && !appsv1alpha1.IsContainerRecreateRequestCompleted(crr)
+}
+
+// pastActiveDeadline checks if request has ActiveDeadlineSeconds field set and if it is exceeded.
+func pastActiveDeadline(crr *appsv1alpha1.ContainerRecreateRequest) (bool, time.Duration) {
+	if crr.Spec.ActiveDeadlineSeconds == nil {
+		return false, -1
+	}
+	duration := time.Since(crr.CreationTimestamp.Time)
+	allowedDuration := time.Duration(*crr.Spec.ActiveDeadlineSeconds) * time.Second
+	return duration >= allowedDuration, allowedDuration - duration
+}
+
+// pastTTLDeadline checks if request has TTLSecondsAfterFinished field set and if it is exceeded.
+func pastTTLDeadline(crr *appsv1alpha1.ContainerRecreateRequest) (bool, time.Duration) {
+	if crr.Spec.TTLSecondsAfterFinished == nil || crr.Status.CompletionTime == nil {
+		return false, -1
+	}
+	duration := time.Since(crr.Status.CompletionTime.Time)
+	allowedDuration := time.Duration(*crr.Spec.TTLSecondsAfterFinished) * time.Second
+	return duration >= allowedDuration, allowedDuration - duration
+}
+
+func getContainerStatus(pod *v1.Pod, name string) *v1.ContainerStatus {
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == name {
+			return &pod.Status.ContainerStatuses[i]
+		}
+	}
+	return nil
+}
+
+// getDesiredRecreatingAnnotation returns the first name of the active requests, so that
+// the annotation is stable when there are multiple requests for the same Pod.
+func getDesiredRecreatingAnnotation(activeNames map[string]struct{}) string {
+	if len(activeNames) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(activeNames))
+	for name := range activeNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names[0]
+}
@@ -43,7 +43,6 @@ import (
 	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/client-go/util/integer"
 	"k8s.io/klog"
-	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
 	"k8s.io/kubernetes/pkg/controller/daemon/util"
 	daemonsetutil "k8s.io/kubernetes/pkg/controller/daemon/util"
@@ -63,13 +62,15 @@ import (
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
 	"github.com/openkruise/kruise/pkg/client"
 	"github.com/openkruise/kruise/pkg/client/clientset/versioned/scheme"
+	"github.com/openkruise/kruise/pkg/util/condition"
 	kruiseExpectations "github.com/openkruise/kruise/pkg/util/expectations"
 	"github.com/openkruise/kruise/pkg/util/gate"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
 )
 
 func init() {
-	flag.BoolVar(&scheduleDaemonSetPods, "assign-pods-by-scheduler", true, "Use scheduler to assign pod to node.")
+	flag.BoolVar(&scheduleDaemonSetPods, "assign-pods-by-scheduler", true, "Use kube-scheduler to bind daemon pods to the target nodes by nodeAffinity on metadata.name, "+
+		"instead of placing them by the in-tree predicates.")
 }
 
 var (
@@ -355,14 +356,14 @@ func (dsc *ReconcileDaemonSet) syncDaemonSet(request reconcile.Request) (reconci
 	}
 
 	if !expectations.SatisfiedExpectations(dsKey) {
-		return dsc.updateDaemonSetStatus(ds, nodeList, hash, true)
+		return dsc.updateDaemonSetStatus(ds, nodeList, hash, 0, true)
 	}
 
 	if ds.Spec.UpdateStrategy.RollingUpdate != nil &&
 		ds.Spec.UpdateStrategy.RollingUpdate.Paused != nil &&
 		*ds.Spec.UpdateStrategy.RollingUpdate.Paused {
 		klog.V(4).Infof("DaemonSet %s deployment and update is paused.", ds.Name)
-		return dsc.updateDaemonSetStatus(ds, nodeList, hash, true)
+		return dsc.updateDaemonSetStatus(ds, nodeList, hash, 0, true)
 	}
 
 	result, err := dsc.manage(ds, hash)
@@ -394,7 +395,7 @@ func (dsc *ReconcileDaemonSet) syncDaemonSet(request reconcile.Request) (reconci
 		return reconcile.Result{}, fmt.Errorf("failed to clean up revisions of DaemonSet: %v", err)
 	}
 
-	return dsc.updateDaemonSetStatus(ds, nodeList, hash, true)
+	return dsc.updateDaemonSetStatus(ds, nodeList, hash, 0, true)
 }
 
 func (dsc *ReconcileDaemonSet) getDaemonSetsForPod(pod *corev1.Pod) []*appsv1alpha1.DaemonSet {
@@ -510,13 +511,18 @@ func NewPod(ds *appsv1alpha1.DaemonSet, nodeName string) *corev1.Pod {
 	return newPod
 }
 
-func (dsc *ReconcileDaemonSet) updateDaemonSetStatus(ds *appsv1alpha1.DaemonSet, nodeList []*corev1.Node, hash string, updateObservedGen bool) (reconcile.Result, error) {
+// updateDaemonSetStatus calculates and stores the status of ds, inPlaceUpdateFallbacks is the number of pods
+// recreated for in-place update timeout in this round, which will be added to status.
+func (dsc *ReconcileDaemonSet) updateDaemonSetStatus(ds *appsv1alpha1.DaemonSet, nodeList []*corev1.Node, hash string, inPlaceUpdateFallbacks int32, updateObservedGen bool) (reconcile.Result, error) {
 	nodeToDaemonPods, err := dsc.getNodesToDaemonPods(ds)
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("couldn't get node to daemon pod mapping for daemon set %q: %v", ds.Name, err)
 	}
 
-	var desiredNumberScheduled, currentNumberScheduled, numberMisscheduled, numberReady, updatedNumberScheduled, numberAvailable = 0, 0, 0, 0, 0, 0
+	var desiredNumberScheduled, currentNumberScheduled, numberMisscheduled, numberReady, updatedNumberScheduled, numberAvailable, numberUnschedulable = 0, 0, 0, 0, 0, 0, 0
+	// the numbers of nodes with updated and available daemon pods, and nodes not selected to update,
+	// which are used to compute the conditions
+	var updatedNumberAvailable, numberNotToUpdate int32
 	for _, node := range nodeList {
 		if !CanNodeBeDeployed(node, ds) {
 			continue
@@ -532,15 +538,19 @@ func (dsc *ReconcileDaemonSet) updateDaemonSetStatus(ds *appsv1alpha1.DaemonSet,
 			if CanNodeBeDeployed(node, ds) {
 				desiredNumberScheduled++
 			}
+			if ds.Spec.UpdateStrategy.RollingUpdate != nil && ds.Spec.UpdateStrategy.RollingUpdate.Selector != nil &&
+				!NodeShouldUpdateBySelector(node, ds) {
+				numberNotToUpdate++
+			}
 			if scheduled {
 				currentNumberScheduled++
 				// Sort the daemon pods by creation time, so that the oldest is first.
 				daemonPods, _ := nodeToDaemonPods[node.Name]
 				sort.Sort(podByCreationTimestampAndPhase(daemonPods))
 				pod := daemonPods[0]
-				if podutil.IsPodReady(pod) {
+				if isDaemonPodReady(pod) {
 					numberReady++
-					if podutil.IsPodAvailable(pod, ds.Spec.MinReadySeconds, metav1.Now()) {
+					if isDaemonPodAvailable(pod, ds.Spec.MinReadySeconds) {
 						numberAvailable++
 					}
 				}
@@ -552,6 +562,15 @@ func (dsc *ReconcileDaemonSet) updateDaemonSetStatus(ds *appsv1alpha1.DaemonSet,
 				}
 				if util.IsPodUpdated(pod, hash, generation) {
 					updatedNumberScheduled++
+					if isDaemonPodAvailable(pod, ds.Spec.MinReadySeconds) {
+						updatedNumberAvailable++
+					}
+				}
+				for _, daemonPod := range daemonPods {
+					if isDaemonPodUnschedulable(daemonPod) {
+						numberUnschedulable++
+						break
+					}
 				}
 			}
 		} else {
@@ -562,7 +581,22 @@ func (dsc *ReconcileDaemonSet) updateDaemonSetStatus(ds *appsv1alpha1.DaemonSet,
 	}
 	numberUnavailable := desiredNumberScheduled - numberAvailable
 
-	err = storeDaemonSetStatus(dsc.client, ds, desiredNumberScheduled, currentNumberScheduled, numberMisscheduled, numberReady, updatedNumberScheduled, numberAvailable, numberUnavailable, updateObservedGen, hash)
+	updatingTopologyDomain, _, err := dsc.getUpdatingTopologyDomain(ds, nodeToDaemonPods, hash)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	conditions := calculateDaemonSetConditions(ds, &condition.Progress{
+		Replicas:             int32(desiredNumberScheduled),
+		ActualReplicas:       int32(currentNumberScheduled),
+		AvailableReplicas:    int32(numberAvailable),
+		UpdatedReplicas:      int32(updatedNumberScheduled),
+		UpdatedReadyReplicas: updatedNumberAvailable,
+		Partition:            numberNotToUpdate,
+		UpdateRevision:       hash,
+	}, numberUnschedulable)
+
+	err = storeDaemonSetStatus(dsc.client, ds, desiredNumberScheduled, currentNumberScheduled, numberMisscheduled, numberReady, updatedNumberScheduled, numberAvailable, numberUnavailable, numberUnschedulable, updatingTopologyDomain, conditions, inPlaceUpdateFallbacks, updateObservedGen, hash)
 	if err != nil {
 		return reconcile.Result{
 			Requeue: true,
@@ -618,7 +652,7 @@ func (dsc *ReconcileDaemonSet) manage(ds *appsv1alpha1.DaemonSet, hash string) (
 		if ds.Spec.UpdateStrategy.RollingUpdate.Partition != nil && *ds.Spec.UpdateStrategy.RollingUpdate.Partition != 0 {
 			partition := *ds.Spec.UpdateStrategy.RollingUpdate.Partition
 			if ds.Status.DesiredNumberScheduled == 0 && ds.Status.CurrentNumberScheduled == 0 {
-				_, err := dsc.updateDaemonSetStatus(ds, nodeList, hash, true)
+				_, err := dsc.updateDaemonSetStatus(ds, nodeList, hash, 0, true)
 				if err != nil {
 					klog.Errorf("updateDaemonSetStatus failed in first deploy process")
 				}
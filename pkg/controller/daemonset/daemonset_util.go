@@ -19,13 +19,16 @@ package daemonset
 import (
 	"context"
 	"fmt"
+	"time"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/condition"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	kubeClient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -247,7 +250,7 @@ func (dsc *ReconcileDaemonSet) GetPodDaemonSets(pod *corev1.Pod) ([]*appsv1alpha
 	return daemonSets, nil
 }
 
-func storeDaemonSetStatus(dsClient kubeClient.Client, ds *appsv1alpha1.DaemonSet, desiredNumberScheduled, currentNumberScheduled, numberMisscheduled, numberReady, updatedNumberScheduled, numberAvailable, numberUnavailable int, updateObservedGen bool, hash string) error {
+func storeDaemonSetStatus(dsClient kubeClient.Client, ds *appsv1alpha1.DaemonSet, desiredNumberScheduled, currentNumberScheduled, numberMisscheduled, numberReady, updatedNumberScheduled, numberAvailable, numberUnavailable, numberUnschedulable int, updatingTopologyDomain string, conditions []appsv1alpha1.DaemonSetCondition, inPlaceUpdateFallbacks int32, updateObservedGen bool, hash string) error {
 	key := types.NamespacedName{
 		Namespace: ds.Namespace,
 		Name:      ds.Name,
@@ -260,6 +263,10 @@ func storeDaemonSetStatus(dsClient kubeClient.Client, ds *appsv1alpha1.DaemonSet
 		int(ds.Status.UpdatedNumberScheduled) == updatedNumberScheduled &&
 		int(ds.Status.NumberAvailable) == numberAvailable &&
 		int(ds.Status.NumberUnavailable) == numberUnavailable &&
+		int(ds.Status.NumberUnschedulable) == numberUnschedulable &&
+		ds.Status.UpdatingTopologyDomain == updatingTopologyDomain &&
+		!condition.Inconsistent(toConditions(ds.Status.Conditions), toConditions(conditions)) &&
+		inPlaceUpdateFallbacks == 0 &&
 		ds.Status.ObservedGeneration >= ds.Generation && ds.Status.DaemonSetHash == hash {
 		klog.V(6).Info("storeDaemonSetStatus has no changes and return nil.")
 		return nil
@@ -279,7 +286,11 @@ func storeDaemonSetStatus(dsClient kubeClient.Client, ds *appsv1alpha1.DaemonSet
 		ds.Status.UpdatedNumberScheduled = int32(updatedNumberScheduled)
 		ds.Status.NumberAvailable = int32(numberAvailable)
 		ds.Status.NumberUnavailable = int32(numberUnavailable)
+		ds.Status.NumberUnschedulable = int32(numberUnschedulable)
+		ds.Status.UpdatingTopologyDomain = updatingTopologyDomain
+		ds.Status.Conditions = conditions
 		ds.Status.DaemonSetHash = hash
+		ds.Status.InPlaceUpdateFallbackCount += inPlaceUpdateFallbacks
 
 		if updateErr = dsClient.Status().Update(context.TODO(), ds); updateErr == nil {
 			klog.V(6).Infof("update DaemonSet status succeed. new status is %v", ds.Status)
@@ -296,6 +307,7 @@ func storeDaemonSetStatus(dsClient kubeClient.Client, ds *appsv1alpha1.DaemonSet
 			klog.Errorf("get DaemonSet %v failed: %v", ds.Name, getErr)
 			return getErr
 		}
+		ds = newDs
 	}
 	return updateErr
 }
@@ -330,3 +342,111 @@ func NodeShouldUpdateBySelector(node *corev1.Node, ds *appsv1alpha1.DaemonSet) b
 		return false
 	}
 }
+
+// isDaemonPodReady returns true if the pod is ready and has no container being recreated.
+func isDaemonPodReady(pod *corev1.Pod) bool {
+	return podutil.IsPodReady(pod) && pod.Annotations[appsv1alpha1.ContainerRecreatingKey] == ""
+}
+
+// isDaemonPodAvailable returns true if the pod has been available for minReadySeconds, has no container
+// being recreated, and its containers have been restarted with new images if it is updated in-place.
+func isDaemonPodAvailable(pod *corev1.Pod, minReadySeconds int32) bool {
+	return podutil.IsPodAvailable(pod, minReadySeconds, metav1.Now()) && pod.Annotations[appsv1alpha1.ContainerRecreatingKey] == "" &&
+		checkInPlaceUpdateCompleted(pod) == nil
+}
+
+// splitByAvailablePods splits provided daemon set pods by availability.
+func splitByAvailablePods(minReadySeconds int32, pods []*corev1.Pod) ([]*corev1.Pod, []*corev1.Pod) {
+	var unavailablePods []*corev1.Pod
+	var availablePods []*corev1.Pod
+	for _, pod := range pods {
+		if isDaemonPodAvailable(pod, minReadySeconds) {
+			availablePods = append(availablePods, pod)
+		} else {
+			unavailablePods = append(unavailablePods, pod)
+		}
+	}
+	return availablePods, unavailablePods
+}
+
+// isDaemonPodUnschedulable returns true if the daemon pod has not been bound to a node
+// because kube-scheduler found it unschedulable.
+func isDaemonPodUnschedulable(pod *corev1.Pod) bool {
+	if pod.Spec.NodeName != "" {
+		return false
+	}
+	_, condition := podutil.GetPodCondition(&pod.Status, corev1.PodScheduled)
+	return condition != nil && condition.Status == corev1.ConditionFalse && condition.Reason == corev1.PodReasonUnschedulable
+}
+
+// calculateDaemonSetConditions returns the conditions of DaemonSet, with the Progressing, Available and ReplicaFailure
+// conditions computed from the new progress and the old status.
+func calculateDaemonSetConditions(ds *appsv1alpha1.DaemonSet, newProgress *condition.Progress, numberUnschedulable int) []appsv1alpha1.DaemonSetCondition {
+	newProgress.Kind = "DaemonSet"
+	newProgress.MinAvailableReplicas = newProgress.Replicas
+	if ds.Spec.UpdateStrategy.Type == appsv1alpha1.RollingUpdateDaemonSetStrategyType && ds.Spec.UpdateStrategy.RollingUpdate != nil {
+		rollingUpdate := ds.Spec.UpdateStrategy.RollingUpdate
+		if maxUnavailable, err := intstrutil.GetValueFromIntOrPercent(rollingUpdate.MaxUnavailable, int(newProgress.Replicas), true); err == nil {
+			newProgress.MinAvailableReplicas = newProgress.Replicas - int32(maxUnavailable)
+		}
+		if rollingUpdate.Selector == nil && rollingUpdate.Partition != nil {
+			newProgress.Partition = *rollingUpdate.Partition
+		}
+		newProgress.Paused = rollingUpdate.Paused != nil && *rollingUpdate.Paused
+	}
+	if numberUnschedulable > 0 {
+		newProgress.FailureReason = appsv1alpha1.WorkloadReasonUnschedulable
+		newProgress.FailureMessage = fmt.Sprintf("%d daemon pods are unschedulable.", numberUnschedulable)
+	}
+	oldProgress := &condition.Progress{
+		Replicas:          ds.Status.DesiredNumberScheduled,
+		ActualReplicas:    ds.Status.CurrentNumberScheduled,
+		AvailableReplicas: ds.Status.NumberAvailable,
+		UpdatedReplicas:   ds.Status.UpdatedNumberScheduled,
+		// the number of updated and available pods is not recorded in status
+		UpdatedReadyReplicas: newProgress.UpdatedReadyReplicas,
+		UpdateRevision:       ds.Status.DaemonSetHash,
+	}
+
+	conditions := condition.Compute(toConditions(ds.Status.Conditions), oldProgress, newProgress, time.Now())
+	return setWorkloadConditions(ds.Status.Conditions, conditions)
+}
+
+// toConditions converts the conditions of DaemonSet into the common form of workload conditions.
+func toConditions(list []appsv1alpha1.DaemonSetCondition) []condition.Condition {
+	var conditions []condition.Condition
+	for _, c := range list {
+		conditions = append(conditions, condition.Condition{
+			Type:               string(c.Type),
+			Status:             c.Status,
+			LastUpdateTime:     c.LastUpdateTime,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	return conditions
+}
+
+// setWorkloadConditions returns the conditions of DaemonSet in list whose types are not computed by package condition,
+// followed by the given workload conditions.
+func setWorkloadConditions(list []appsv1alpha1.DaemonSetCondition, conditions []condition.Condition) []appsv1alpha1.DaemonSetCondition {
+	var newList []appsv1alpha1.DaemonSetCondition
+	for _, c := range list {
+		if !condition.IsWorkloadConditionType(string(c.Type)) {
+			newList = append(newList, c)
+		}
+	}
+	for _, c := range conditions {
+		newList = append(newList, appsv1alpha1.DaemonSetCondition{
+			Type:               appsv1alpha1.DaemonSetConditionType(c.Type),
+			Status:             c.Status,
+			LastUpdateTime:     c.LastUpdateTime,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	return newList
+}
+
@@ -17,14 +17,20 @@ limitations under the License.
 package daemonset
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/condition"
 )
 
 func Test_nodeInSameCondition(t *testing.T) {
@@ -264,6 +270,121 @@ func TestGetPodRevision(t *testing.T) {
 	}
 }
 
+func TestIsDaemonPodAvailable(t *testing.T) {
+	newPod := func(revision string, state *appsv1alpha1.InPlaceUpdateState, imageID string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{apps.ControllerRevisionHashLabelKey: revision},
+				Annotations: map[string]string{},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{
+					Type:               corev1.PodReady,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Minute)),
+				}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "c1", ImageID: imageID}},
+			},
+		}
+		if state != nil {
+			stateJSON, _ := json.Marshal(state)
+			pod.Annotations[appsv1alpha1.InPlaceUpdateStateKey] = string(stateJSON)
+		}
+		return pod
+	}
+	state := &appsv1alpha1.InPlaceUpdateState{
+		Revision:              "new",
+		LastContainerStatuses: map[string]appsv1alpha1.InPlaceUpdateContainerStatus{"c1": {ImageID: "img-old"}},
+	}
+
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want bool
+	}{
+		{
+			name: "not updated in-place",
+			pod:  newPod("new", nil, "img-old"),
+			want: true,
+		},
+		{
+			name: "in-place update not completed",
+			pod:  newPod("new", state, "img-old"),
+			want: false,
+		},
+		{
+			name: "in-place update completed",
+			pod:  newPod("new", state, "img-new"),
+			want: true,
+		},
+		{
+			name: "in-place update state left by another revision",
+			pod:  newPod("newer", state, "img-old"),
+			want: true,
+		},
+		{
+			name: "container being recreated",
+			pod: func() *corev1.Pod {
+				pod := newPod("new", nil, "img-old")
+				pod.Annotations[appsv1alpha1.ContainerRecreatingKey] = "crr"
+				return pod
+			}(),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDaemonPodAvailable(tt.pod, 0); got != tt.want {
+				t.Errorf("isDaemonPodAvailable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDaemonPodUnschedulable(t *testing.T) {
+	newPod := func(nodeName string, condition *corev1.PodCondition) *corev1.Pod {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{NodeName: nodeName}}
+		if condition != nil {
+			pod.Status.Conditions = []corev1.PodCondition{*condition}
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want bool
+	}{
+		{
+			name: "not scheduled yet",
+			pod:  newPod("", nil),
+			want: false,
+		},
+		{
+			name: "unschedulable",
+			pod:  newPod("", &corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable}),
+			want: true,
+		},
+		{
+			name: "failed to schedule for other reasons",
+			pod:  newPod("", &corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "SchedulerError"}),
+			want: false,
+		},
+		{
+			name: "bound to node",
+			pod:  newPod("node1", &corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDaemonPodUnschedulable(tt.pod); got != tt.want {
+				t.Errorf("isDaemonPodUnschedulable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func newNode(name string, label map[string]string) *corev1.Node {
 	return &corev1.Node{
 		TypeMeta: metav1.TypeMeta{APIVersion: "v1"},
@@ -339,3 +460,73 @@ func TestNodeShouldUpdateBySelector(t *testing.T) {
 		}
 	}
 }
+
+func TestStoreDaemonSetStatusInPlaceUpdateFallbacks(t *testing.T) {
+	ds := newDaemonSet("ds1")
+	ds.Status.InPlaceUpdateFallbackCount = 1
+	fakeClient := fake.NewFakeClient(ds)
+	key := types.NamespacedName{Namespace: ds.Namespace, Name: ds.Name}
+
+	for _, fallbacks := range []int32{0, 2} {
+		ds := &appsv1alpha1.DaemonSet{}
+		if err := fakeClient.Get(context.TODO(), key, ds); err != nil {
+			t.Fatalf("failed to get DaemonSet: %v", err)
+		}
+		if err := storeDaemonSetStatus(fakeClient, ds, 0, 0, 0, 0, 0, 0, 0, 0, "", nil, fallbacks, false, ""); err != nil {
+			t.Fatalf("failed to store status: %v", err)
+		}
+	}
+
+	got := &appsv1alpha1.DaemonSet{}
+	if err := fakeClient.Get(context.TODO(), key, got); err != nil {
+		t.Fatalf("failed to get DaemonSet: %v", err)
+	}
+	if got.Status.InPlaceUpdateFallbackCount != 3 {
+		t.Fatalf("expected InPlaceUpdateFallbackCount 3, got %d", got.Status.InPlaceUpdateFallbackCount)
+	}
+}
+
+func TestCalculateDaemonSetConditions(t *testing.T) {
+	ds := &appsv1alpha1.DaemonSet{
+		Status: appsv1alpha1.DaemonSetStatus{
+			DesiredNumberScheduled: 3,
+			CurrentNumberScheduled: 3,
+			NumberAvailable:        3,
+			UpdatedNumberScheduled: 3,
+			DaemonSetHash:          "v1",
+			Conditions: []appsv1alpha1.DaemonSetCondition{
+				{Type: appsv1alpha1.DaemonSetConditionReplicaFailure, Status: corev1.ConditionTrue, Reason: appsv1alpha1.WorkloadReasonUnschedulable},
+			},
+		},
+	}
+	conditions := calculateDaemonSetConditions(ds, &condition.Progress{
+		Replicas:             3,
+		ActualReplicas:       3,
+		AvailableReplicas:    3,
+		UpdatedReplicas:      3,
+		UpdatedReadyReplicas: 3,
+		UpdateRevision:       "v1",
+	}, 0)
+
+	status := appsv1alpha1.DaemonSetStatus{Conditions: conditions}
+	getCondition := func(condType appsv1alpha1.DaemonSetConditionType) *appsv1alpha1.DaemonSetCondition {
+		for i := range status.Conditions {
+			if status.Conditions[i].Type == condType {
+				return &status.Conditions[i]
+			}
+		}
+		return nil
+	}
+	if cond := getCondition(appsv1alpha1.DaemonSetConditionAvailable); cond == nil || cond.Status != corev1.ConditionTrue {
+		t.Fatalf("expected Available True, got %+v", cond)
+	}
+	if cond := getCondition(appsv1alpha1.DaemonSetConditionProgressing); cond == nil || cond.Reason != appsv1alpha1.WorkloadReasonNewRevisionAvailable {
+		t.Fatalf("expected Progressing completed, got %+v", cond)
+	}
+	if cond := getCondition(appsv1alpha1.DaemonSetConditionReplicaFailure); cond != nil {
+		t.Fatalf("expected ReplicaFailure removed, got %+v", cond)
+	}
+	if len(ds.Status.Conditions) != 1 || ds.Status.Conditions[0].Type != appsv1alpha1.DaemonSetConditionReplicaFailure {
+		t.Fatalf("expected conditions of old status unchanged, got %+v", ds.Status.Conditions)
+	}
+}
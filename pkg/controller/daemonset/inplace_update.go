@@ -17,20 +17,22 @@ limitations under the License.
 package daemonset
 
 import (
-	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/klog"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
-	"k8s.io/kubernetes/pkg/controller/daemon/util"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
 )
 
 func (dsc *ReconcileDaemonSet) inplaceRollingUpdate(ds *appsv1alpha1.DaemonSet, hash string) (reconcile.Result, error) {
@@ -39,26 +41,41 @@ func (dsc *ReconcileDaemonSet) inplaceRollingUpdate(ds *appsv1alpha1.DaemonSet,
 		return reconcile.Result{}, fmt.Errorf("couldn't get node to daemon pod mapping for daemon set %q: %v", ds.Name, err)
 	}
 
-	maxUnavailable, numUnavailable, err := dsc.getUnavailableNumbers(ds, nodeToDaemonPods)
+	// refresh the in-place update condition and grace period of pods
+	requeueAfter, err := dsc.refreshInPlaceUpdatePods(ds, nodeToDaemonPods)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// recreate the updated pods whose in-place update has not completed before the deadline
+	newPods, _ := dsc.getAllDaemonSetPods(ds, nodeToDaemonPods, hash)
+	timeoutPods, timeoutRequeueAfter := dsc.getInPlaceUpdateTimeoutPods(ds, newPods)
+	if len(timeoutPods) > 0 {
+		return dsc.recreateInPlaceUpdateTimeoutPods(ds, timeoutPods, hash)
+	}
+	if timeoutRequeueAfter > 0 && (requeueAfter == 0 || timeoutRequeueAfter < requeueAfter) {
+		requeueAfter = timeoutRequeueAfter
+	}
+
+	// only update the daemon pods in the failure domain being updated.
+	_, topologyNodes, err := dsc.getUpdatingTopologyDomain(ds, nodeToDaemonPods, hash)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("Couldn't get updating topology domain: %v", err)
+	}
+	nodeToDaemonPodsToUpdate := dsc.getNodeToDaemonPodsToUpdateInDomain(ds, nodeToDaemonPods, topologyNodes)
+	nodeToDaemonPods = filterNodeToDaemonPodsByNodes(nodeToDaemonPods, topologyNodes)
+
+	maxUnavailable, numUnavailable, err := dsc.getUnavailableNumbers(ds, nodeToDaemonPods, topologyNodes)
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("Couldn't get unavailable numbers: %v", err)
 	}
 
 	// respect gray update choice.
-	if ds.Spec.UpdateStrategy.RollingUpdate.Selector == nil {
-		if ds.Spec.UpdateStrategy.RollingUpdate.Partition != nil &&
-			*ds.Spec.UpdateStrategy.RollingUpdate.Partition != 0 {
-			// respect partitioned nodes to keep old versions.
-			nodeToDaemonPods = dsc.getNodeToDaemonPodsByPartition(ds, nodeToDaemonPods)
-		}
-	} else {
-		// respect selected nodes to update.
-		nodeToDaemonPods = dsc.getNodeToDaemonPodsBySelector(ds, nodeToDaemonPods)
-	}
+	nodeToDaemonPods = nodeToDaemonPodsToUpdate
 
 	_, oldPods := dsc.getAllDaemonSetPods(ds, nodeToDaemonPods, hash)
 
-	oldAvailablePods, oldUnavailablePods := util.SplitByAvailablePods(ds.Spec.MinReadySeconds, oldPods)
+	oldAvailablePods, oldUnavailablePods := splitByAvailablePods(ds.Spec.MinReadySeconds, oldPods)
 
 	// for oldPods delete all not running pods
 	var oldPodsToInplaceUpdate []*corev1.Pod
@@ -85,7 +102,7 @@ func (dsc *ReconcileDaemonSet) inplaceRollingUpdate(ds *appsv1alpha1.DaemonSet,
 		klog.Errorf("failed to construct revisions of DaemonSet: %v", err)
 	}
 	if len(old) == 0 {
-		return reconcile.Result{}, nil
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
 	}
 
 	// Refresh update expectations
@@ -101,10 +118,119 @@ func (dsc *ReconcileDaemonSet) inplaceRollingUpdate(ds *appsv1alpha1.DaemonSet,
 		return reconcile.Result{}, nil
 	}
 
-	return dsc.syncNodesWhenInplaceUpdate(ds, oldPodsToInplaceUpdate, hash, old[len(old)-1], cur)
+	res, err := dsc.syncNodesWhenInplaceUpdate(ds, oldPodsToInplaceUpdate, hash, old, cur)
+	if requeueAfter > 0 && (res.RequeueAfter == 0 || requeueAfter < res.RequeueAfter) {
+		res.RequeueAfter = requeueAfter
+	}
+	return res, err
+}
+
+// getInPlaceUpdateTimeoutPods returns the names of pods whose in-place update has not completed before the deadline,
+// and the shortest duration left before the deadline of other pods.
+func (dsc *ReconcileDaemonSet) getInPlaceUpdateTimeoutPods(ds *appsv1alpha1.DaemonSet, newPods []*corev1.Pod) ([]string, time.Duration) {
+	var timeoutPods []string
+	var requeueAfter time.Duration
+	opts := getInPlaceUpdateOptions(ds)
+	for _, pod := range newPods {
+		// Skip terminating pods. We won't delete them again
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if timeout, leftDuration := dsc.inplaceControl.IsUpdateTimeout(pod, opts); timeout {
+			timeoutPods = append(timeoutPods, pod.Name)
+		} else if leftDuration > 0 && (requeueAfter == 0 || leftDuration < requeueAfter) {
+			requeueAfter = leftDuration
+		}
+	}
+	return timeoutPods, requeueAfter
+}
+
+// recreateInPlaceUpdateTimeoutPods deletes the pods whose in-place update timed out, then they will be
+// created again by the daemon set controller, and adds them to the fallback count in status.
+func (dsc *ReconcileDaemonSet) recreateInPlaceUpdateTimeoutPods(ds *appsv1alpha1.DaemonSet, timeoutPods []string, hash string) (reconcile.Result, error) {
+	if burstReplicas := getBurstReplicas(ds); len(timeoutPods) > burstReplicas {
+		timeoutPods = timeoutPods[:burstReplicas]
+	}
+	for _, name := range timeoutPods {
+		dsc.eventRecorder.Eventf(ds, corev1.EventTypeWarning, "InPlaceUpdateTimeout",
+			"in-place update of pod %s has not completed before the deadline, so it will be recreated", name)
+	}
+
+	res, err := dsc.syncNodes(ds, timeoutPods, []string{}, hash)
+	if err != nil {
+		return res, err
+	}
+
+	nodeList, err := dsc.nodeLister.List(labels.Everything())
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("couldn't get nodeList %v", err)
+	}
+	if _, err = dsc.updateDaemonSetStatus(ds, nodeList, hash, int32(len(timeoutPods)), false); err != nil {
+		return reconcile.Result{}, err
+	}
+	return res, nil
+}
+
+// refreshInPlaceUpdatePods refreshes the InPlaceUpdateReady condition of pods and finishes their grace period,
+// and returns the shortest duration to wait for grace period.
+func (dsc *ReconcileDaemonSet) refreshInPlaceUpdatePods(ds *appsv1alpha1.DaemonSet, nodeToDaemonPods map[string][]*corev1.Pod) (time.Duration, error) {
+	var requeueAfter time.Duration
+	opts := getInPlaceUpdateOptions(ds)
+	for _, pods := range nodeToDaemonPods {
+		for _, pod := range pods {
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+			res := dsc.inplaceControl.Refresh(pod, opts)
+			if res.RefreshErr != nil {
+				klog.Errorf("DaemonSet %s/%s failed to update pod %s condition for inplace: %v", ds.Namespace, ds.Name, pod.Name, res.RefreshErr)
+				return 0, res.RefreshErr
+			}
+			if res.DelayDuration > 0 && (requeueAfter == 0 || res.DelayDuration < requeueAfter) {
+				requeueAfter = res.DelayDuration
+			}
+		}
+	}
+	return requeueAfter, nil
+}
+
+// checkInPlaceUpdateCompleted checks whether the containers of pod have been restarted with new images.
+// The in-place update state left by another revision is ignored.
+func checkInPlaceUpdateCompleted(pod *corev1.Pod) error {
+	inPlaceUpdateState := appsv1alpha1.InPlaceUpdateState{}
+	if stateStr, ok := pod.Annotations[appsv1alpha1.InPlaceUpdateStateKey]; !ok {
+		return nil
+	} else if err := json.Unmarshal([]byte(stateStr), &inPlaceUpdateState); err != nil {
+		return err
+	}
+	if inPlaceUpdateState.Revision != GetPodRevision(pod) {
+		return nil
+	}
+	return inplaceupdate.CheckInPlaceUpdateCompleted(pod)
+}
+
+func getInPlaceUpdateOptions(ds *appsv1alpha1.DaemonSet) *inplaceupdate.UpdateOptions {
+	opts := &inplaceupdate.UpdateOptions{
+		// pods of DaemonSet are labeled with the hash of revision instead of its name
+		GetRevision: func(revision *apps.ControllerRevision) string {
+			return revision.Labels[apps.DefaultDaemonSetUniqueLabelKey]
+		},
+		CustomizeCheckUpdateCompleted: checkInPlaceUpdateCompleted,
+	}
+	if ds.Spec.UpdateStrategy.RollingUpdate != nil && ds.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy != nil {
+		opts.GracePeriodSeconds = ds.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy.GracePeriodSeconds
+		opts.TimeoutSeconds = ds.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy.TimeoutSeconds
+	}
+	return opts
 }
 
-func (dsc *ReconcileDaemonSet) syncNodesWhenInplaceUpdate(ds *appsv1alpha1.DaemonSet, oldPodsToInplaceUpdate []*corev1.Pod, hash string, last, cur *apps.ControllerRevision) (reconcile.Result, error) {
+// syncNodesWhenInplaceUpdate updates the given pods in-place, and recreates the pods that can not be updated in-place.
+func (dsc *ReconcileDaemonSet) syncNodesWhenInplaceUpdate(ds *appsv1alpha1.DaemonSet, oldPodsToInplaceUpdate []*corev1.Pod, hash string, old []*apps.ControllerRevision, cur *apps.ControllerRevision) (reconcile.Result, error) {
+	dsKey, err := kubecontroller.KeyFunc(ds)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("couldn't get key for object %#v: %v", ds, err)
+	}
+
 	updateDiff := len(oldPodsToInplaceUpdate)
 
 	burstReplicas := getBurstReplicas(ds)
@@ -112,42 +238,72 @@ func (dsc *ReconcileDaemonSet) syncNodesWhenInplaceUpdate(ds *appsv1alpha1.Daemo
 		updateDiff = burstReplicas
 	}
 
+	revisions := make(map[string]*apps.ControllerRevision, len(old))
+	for _, revision := range old {
+		revisions[revision.Labels[apps.DefaultDaemonSetUniqueLabelKey]] = revision
+	}
+	opts := getInPlaceUpdateOptions(ds)
+
 	// error channel to communicate back failures.  make the buffer big enough to avoid any blocking
-	errCh := make(chan error, updateDiff)
+	errCh := make(chan error, updateDiff+1)
+	// pods that can not be updated in-place, which will be recreated
+	recreateCh := make(chan string, updateDiff)
+	delayCh := make(chan time.Duration, updateDiff)
 
-	klog.V(4).Infof("Pods to delete for daemon set %s: %+v, updating %d", ds.Name, oldPodsToInplaceUpdate, updateDiff)
+	klog.V(4).Infof("Pods to update in-place for daemon set %s: %+v, updating %d", ds.Name, oldPodsToInplaceUpdate, updateDiff)
 	updateWait := sync.WaitGroup{}
 	updateWait.Add(updateDiff)
 	for i := 0; i < updateDiff; i++ {
 		go func(ix int, ds *appsv1alpha1.DaemonSet, pod *corev1.Pod) {
 			defer updateWait.Done()
 
-			res := dsc.inplaceControl.Update(pod, last, cur, nil)
-			if res.InPlaceUpdate && res.UpdateErr == nil {
-				dsc.eventRecorder.Eventf(ds, corev1.EventTypeNormal, "SuccessfulUpdatePodInPlace", "successfully update pod %s in-place", pod.Name)
-				dsKey, err := kubecontroller.KeyFunc(ds)
-				if err != nil {
-					klog.Errorf("couldn't get key for object %#v: %v", ds, err)
-					return
-				}
-				dsc.updateExp.ExpectUpdated(dsKey, cur.Name, pod)
-				refreshRes := dsc.inplaceControl.Refresh(pod, nil)
-				if refreshRes.RefreshErr != nil {
-					klog.Errorf("failed to update pod condition: %v", err)
-				}
-				pod.Labels[apps.DefaultDaemonSetUniqueLabelKey] = hash
-				dsc.client.Update(context.TODO(), pod)
+			oldRevision, ok := revisions[GetPodRevision(pod)]
+			if !ok {
+				klog.V(4).Infof("DaemonSet %s/%s could not find revision of Pod %s, so it will be recreated", ds.Namespace, ds.Name, pod.Name)
+				recreateCh <- pod.Name
 				return
 			}
 
+			res := dsc.inplaceControl.Update(pod, oldRevision, cur, opts)
+			if !res.InPlaceUpdate {
+				klog.V(4).Infof("DaemonSet %s/%s could not in-place update Pod %s, so it will be recreated", ds.Namespace, ds.Name, pod.Name)
+				recreateCh <- pod.Name
+				return
+			}
 			if res.UpdateErr != nil {
-				klog.Warningf("DaemonSet %s/%s failed to in-place update Pod %s, so it will back off to ReCreate", ds.Namespace, ds.Name, pod.Name)
+				klog.Warningf("DaemonSet %s/%s failed to in-place update Pod %s: %v", ds.Namespace, ds.Name, pod.Name, res.UpdateErr)
 				errCh <- res.UpdateErr
 				utilruntime.HandleError(res.UpdateErr)
+				return
+			}
+
+			dsc.eventRecorder.Eventf(ds, corev1.EventTypeNormal, "SuccessfulUpdatePodInPlace", "successfully update pod %s in-place", pod.Name)
+			dsc.updateExp.ExpectUpdated(dsKey, cur.Name, pod)
+			if res.DelayDuration > 0 {
+				delayCh <- res.DelayDuration
 			}
 		}(i, ds, oldPodsToInplaceUpdate[i])
 	}
 	updateWait.Wait()
+	close(recreateCh)
+	close(delayCh)
+
+	var res reconcile.Result
+	for d := range delayCh {
+		if res.RequeueAfter == 0 || d < res.RequeueAfter {
+			res.RequeueAfter = d
+		}
+	}
+
+	var podsToRecreate []string
+	for name := range recreateCh {
+		podsToRecreate = append(podsToRecreate, name)
+	}
+	if len(podsToRecreate) > 0 {
+		if _, err := dsc.syncNodes(ds, podsToRecreate, []string{}, hash); err != nil {
+			errCh <- err
+		}
+	}
 
 	// collect errors if any for proper reporting/retry logic in the controller
 	errors := []error{}
@@ -155,5 +311,5 @@ func (dsc *ReconcileDaemonSet) syncNodesWhenInplaceUpdate(ds *appsv1alpha1.Daemo
 	for err := range errCh {
 		errors = append(errors, err)
 	}
-	return reconcile.Result{}, utilerrors.NewAggregate(errors)
+	return res, utilerrors.NewAggregate(errors)
 }
@@ -36,7 +36,6 @@ import (
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog"
-	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
 	"k8s.io/kubernetes/pkg/controller/daemon/util"
 	labelsutil "k8s.io/kubernetes/pkg/util/labels"
@@ -124,7 +123,15 @@ func (dsc *ReconcileDaemonSet) standardRollingUpdate(ds *appsv1alpha1.DaemonSet,
 		return reconcile.Result{}, fmt.Errorf("couldn't get node to daemon pod mapping for daemon set %q: %v", ds.Name, err)
 	}
 
-	maxUnavailable, numUnavailable, err := dsc.getUnavailableNumbers(ds, nodeToDaemonPods)
+	// only update the daemon pods in the failure domain being updated.
+	_, topologyNodes, err := dsc.getUpdatingTopologyDomain(ds, nodeToDaemonPods, hash)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("Couldn't get updating topology domain: %v", err)
+	}
+	nodeToDaemonPodsToUpdate := dsc.getNodeToDaemonPodsToUpdateInDomain(ds, nodeToDaemonPods, topologyNodes)
+	nodeToDaemonPods = filterNodeToDaemonPodsByNodes(nodeToDaemonPods, topologyNodes)
+
+	maxUnavailable, numUnavailable, err := dsc.getUnavailableNumbers(ds, nodeToDaemonPods, topologyNodes)
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("Couldn't get unavailable numbers: %v", err)
 	}
@@ -132,7 +139,7 @@ func (dsc *ReconcileDaemonSet) standardRollingUpdate(ds *appsv1alpha1.DaemonSet,
 	// calculate the cluster scope numUnavailable.
 	if numUnavailable >= maxUnavailable {
 		_, oldPods := dsc.getAllDaemonSetPods(ds, nodeToDaemonPods, hash)
-		_, oldUnavailablePods := util.SplitByAvailablePods(ds.Spec.MinReadySeconds, oldPods)
+		_, oldUnavailablePods := splitByAvailablePods(ds.Spec.MinReadySeconds, oldPods)
 
 		// for oldPods delete all not running pods
 		var oldPodsToDelete []string
@@ -147,20 +154,11 @@ func (dsc *ReconcileDaemonSet) standardRollingUpdate(ds *appsv1alpha1.DaemonSet,
 	}
 
 	// if the rollingUpdate continue, respect gray update choice.
-	if ds.Spec.UpdateStrategy.RollingUpdate.Selector == nil {
-		if ds.Spec.UpdateStrategy.RollingUpdate.Partition != nil &&
-			*ds.Spec.UpdateStrategy.RollingUpdate.Partition != 0 {
-			// respect partitioned nodes to keep old versions.
-			nodeToDaemonPods = dsc.getNodeToDaemonPodsByPartition(ds, nodeToDaemonPods)
-		}
-	} else {
-		// respect selected nodes to update.
-		nodeToDaemonPods = dsc.getNodeToDaemonPodsBySelector(ds, nodeToDaemonPods)
-	}
+	nodeToDaemonPods = nodeToDaemonPodsToUpdate
 
 	_, oldPods := dsc.getAllDaemonSetPods(ds, nodeToDaemonPods, hash)
 
-	oldAvailablePods, oldUnavailablePods := util.SplitByAvailablePods(ds.Spec.MinReadySeconds, oldPods)
+	oldAvailablePods, oldUnavailablePods := splitByAvailablePods(ds.Spec.MinReadySeconds, oldPods)
 
 	// for oldPods delete all not running pods
 	var oldPodsToDelete []string
@@ -230,7 +228,9 @@ func GetTemplateGeneration(ds *appsv1alpha1.DaemonSet) (*int64, error) {
 	return &generation, nil
 }
 
-func (dsc *ReconcileDaemonSet) getUnavailableNumbers(ds *appsv1alpha1.DaemonSet, nodeToDaemonPods map[string][]*corev1.Pod) (int, int, error) {
+// getUnavailableNumbers returns the max allowable number of unavailable pods and the current number of
+// unavailable pods. If nodesInScope is not nil, only the nodes in it are counted.
+func (dsc *ReconcileDaemonSet) getUnavailableNumbers(ds *appsv1alpha1.DaemonSet, nodeToDaemonPods map[string][]*corev1.Pod, nodesInScope sets.String) (int, int, error) {
 	klog.V(6).Infof("Getting unavailable numbers")
 	nodeList, err := dsc.nodeLister.List(labels.Everything())
 	if err != nil {
@@ -240,6 +240,9 @@ func (dsc *ReconcileDaemonSet) getUnavailableNumbers(ds *appsv1alpha1.DaemonSet,
 	var numUnavailable, desiredNumberScheduled int
 	for i := range nodeList {
 		node := nodeList[i]
+		if nodesInScope != nil && !nodesInScope.Has(node.Name) {
+			continue
+		}
 		if !CanNodeBeDeployed(node, ds) {
 			continue
 		}
@@ -259,7 +262,7 @@ func (dsc *ReconcileDaemonSet) getUnavailableNumbers(ds *appsv1alpha1.DaemonSet,
 		available := false
 		for _, pod := range daemonPods {
 			//for the purposes of update we ensure that the Pod is both available and not terminating
-			if podutil.IsPodAvailable(pod, ds.Spec.MinReadySeconds, metav1.Now()) && pod.DeletionTimestamp == nil {
+			if isDaemonPodAvailable(pod, ds.Spec.MinReadySeconds) && pod.DeletionTimestamp == nil {
 				available = true
 				break
 			}
@@ -518,10 +521,123 @@ func (dsc *ReconcileDaemonSet) getNodeToDaemonPodsBySelector(ds *appsv1alpha1.Da
 	return nodeToDaemonPods
 }
 
+// getNodeToDaemonPodsToUpdate returns a copy of nodeToDaemonPods that only contains the nodes
+// selected to update by the gray update choice, which is either partition or selector.
+func (dsc *ReconcileDaemonSet) getNodeToDaemonPodsToUpdate(ds *appsv1alpha1.DaemonSet, nodeToDaemonPods map[string][]*corev1.Pod) map[string][]*corev1.Pod {
+	nodeToDaemonPodsToUpdate := make(map[string][]*corev1.Pod, len(nodeToDaemonPods))
+	for nodeName, pods := range nodeToDaemonPods {
+		nodeToDaemonPodsToUpdate[nodeName] = pods
+	}
+
+	if ds.Spec.UpdateStrategy.RollingUpdate.Selector == nil {
+		if ds.Spec.UpdateStrategy.RollingUpdate.Partition != nil &&
+			*ds.Spec.UpdateStrategy.RollingUpdate.Partition != 0 {
+			// respect partitioned nodes to keep old versions.
+			nodeToDaemonPodsToUpdate = dsc.getNodeToDaemonPodsByPartition(ds, nodeToDaemonPodsToUpdate)
+		}
+	} else {
+		// respect selected nodes to update.
+		nodeToDaemonPodsToUpdate = dsc.getNodeToDaemonPodsBySelector(ds, nodeToDaemonPodsToUpdate)
+	}
+	return nodeToDaemonPodsToUpdate
+}
+
+// getNodeToDaemonPodsToUpdateInDomain returns the nodes selected to update by the gray update choice
+// that are in the failure domain being updated. The gray update choice is applied to all the nodes
+// before filtering by the domain, so that partition and selector keep the same nodes on the old version
+// no matter which domain is being updated.
+func (dsc *ReconcileDaemonSet) getNodeToDaemonPodsToUpdateInDomain(ds *appsv1alpha1.DaemonSet, nodeToDaemonPods map[string][]*corev1.Pod, topologyNodes sets.String) map[string][]*corev1.Pod {
+	return filterNodeToDaemonPodsByNodes(dsc.getNodeToDaemonPodsToUpdate(ds, nodeToDaemonPods), topologyNodes)
+}
+
+// getUpdatingTopologyDomain returns the failure domain being updated and the names of all nodes in it,
+// when rollingUpdate.topologyKey is set. The domains are updated one at a time in the order given by
+// sortTopologyDomains, and a domain is finished once all the daemon pods to update in it are updated,
+// available and not terminating.
+// The returned node set is nil if topologyKey is not set or all the domains are finished.
+func (dsc *ReconcileDaemonSet) getUpdatingTopologyDomain(ds *appsv1alpha1.DaemonSet, nodeToDaemonPods map[string][]*corev1.Pod, hash string) (string, sets.String, error) {
+	if ds.Spec.UpdateStrategy.Type != appsv1alpha1.RollingUpdateDaemonSetStrategyType ||
+		ds.Spec.UpdateStrategy.RollingUpdate == nil || ds.Spec.UpdateStrategy.RollingUpdate.TopologyKey == "" {
+		return "", nil, nil
+	}
+
+	nodeList, err := dsc.nodeLister.List(labels.Everything())
+	if err != nil {
+		return "", nil, fmt.Errorf("couldn't get list of nodes during rolling update of daemon set %#v: %v", ds, err)
+	}
+	domainToNodes := make(map[string]sets.String)
+	for _, node := range nodeList {
+		domain := node.Labels[ds.Spec.UpdateStrategy.RollingUpdate.TopologyKey]
+		if _, ok := domainToNodes[domain]; !ok {
+			domainToNodes[domain] = sets.NewString()
+		}
+		domainToNodes[domain].Insert(node.Name)
+	}
+
+	generation, err := GetTemplateGeneration(ds)
+	if err != nil {
+		generation = nil
+	}
+	nodeToDaemonPodsToUpdate := dsc.getNodeToDaemonPodsToUpdate(ds, nodeToDaemonPods)
+	for _, domain := range sortTopologyDomains(domainToNodes, ds.Spec.UpdateStrategy.RollingUpdate.TopologyDomainOrder) {
+		for nodeName := range domainToNodes[domain] {
+			for _, pod := range nodeToDaemonPodsToUpdate[nodeName] {
+				if !util.IsPodUpdated(pod, hash, generation) || pod.DeletionTimestamp != nil ||
+					!isDaemonPodAvailable(pod, ds.Spec.MinReadySeconds) {
+					return domain, domainToNodes[domain], nil
+				}
+			}
+		}
+	}
+	return "", nil, nil
+}
+
+// sortTopologyDomains returns the domains in the order to update. The domains in order come first,
+// then the other domains in alphabetical order, and the nodes without topology label are at last.
+func sortTopologyDomains(domainToNodes map[string]sets.String, order []string) []string {
+	var domains []string
+	sorted := sets.NewString()
+	for _, domain := range order {
+		if _, ok := domainToNodes[domain]; ok && domain != "" && !sorted.Has(domain) {
+			domains = append(domains, domain)
+			sorted.Insert(domain)
+		}
+	}
+
+	var others []string
+	for domain := range domainToNodes {
+		if domain != "" && !sorted.Has(domain) {
+			others = append(others, domain)
+		}
+	}
+	sort.Strings(others)
+	domains = append(domains, others...)
+
+	if _, ok := domainToNodes[""]; ok {
+		domains = append(domains, "")
+	}
+	return domains
+}
+
+// filterNodeToDaemonPodsByNodes returns the part of nodeToDaemonPods whose nodes are in the given set.
+// If nodes is nil, nodeToDaemonPods is returned as it is.
+func filterNodeToDaemonPodsByNodes(nodeToDaemonPods map[string][]*corev1.Pod, nodes sets.String) map[string][]*corev1.Pod {
+	if nodes == nil {
+		return nodeToDaemonPods
+	}
+	filtered := make(map[string][]*corev1.Pod)
+	for nodeName, pods := range nodeToDaemonPods {
+		if nodes.Has(nodeName) {
+			filtered[nodeName] = pods
+		}
+	}
+	return filtered
+}
+
 // getSurgeNumbers returns the max allowable number of surging pods and the current number of
 // surging pods. The number of surging pods is computed as the total number pods above the first
-// on each node.
-func (dsc *ReconcileDaemonSet) getSurgeNumbers(ds *appsv1alpha1.DaemonSet, nodeToDaemonPods map[string][]*corev1.Pod, hash string) (int, int, error) {
+// on each node. If nodesInScope is not nil, only the nodes in it are counted.
+func (dsc *ReconcileDaemonSet) getSurgeNumbers(ds *appsv1alpha1.DaemonSet, nodeToDaemonPods map[string][]*corev1.Pod, nodesInScope sets.String, hash string) (int, int, error) {
 	nodeList, err := dsc.nodeLister.List(labels.Everything())
 	if err != nil {
 		return -1, -1, fmt.Errorf("couldn't get list of nodes during surging rolling update of daemon set %#v: %v", ds, err)
@@ -534,6 +650,9 @@ func (dsc *ReconcileDaemonSet) getSurgeNumbers(ds *appsv1alpha1.DaemonSet, nodeT
 	var desiredNumberScheduled, numSurge int
 	for i := range nodeList {
 		node := nodeList[i]
+		if nodesInScope != nil && !nodesInScope.Has(node.Name) {
+			continue
+		}
 		if !CanNodeBeDeployed(node, ds) {
 			continue
 		}
@@ -629,18 +748,16 @@ func (dsc *ReconcileDaemonSet) surgingRollingUpdate(ds *appsv1alpha1.DaemonSet,
 		return reconcile.Result{}, fmt.Errorf("couldn't get node to daemon pod mapping for daemon set %q: %v", ds.Name, err)
 	}
 
-	// respect gray update choice.
-	if ds.Spec.UpdateStrategy.RollingUpdate.Selector == nil {
-		if ds.Spec.UpdateStrategy.RollingUpdate.Partition != nil && *ds.Spec.UpdateStrategy.RollingUpdate.Partition != 0 {
-			// respect partitioned nodes to keep old versions.
-			nodeToDaemonPods = dsc.getNodeToDaemonPodsByPartition(ds, nodeToDaemonPods)
-		}
-	} else {
-		// respect selected nodes to update.
-		nodeToDaemonPods = dsc.getNodeToDaemonPodsBySelector(ds, nodeToDaemonPods)
+	// only update the daemon pods in the failure domain being updated.
+	_, topologyNodes, err := dsc.getUpdatingTopologyDomain(ds, nodeToDaemonPods, hash)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("Couldn't get updating topology domain: %v", err)
 	}
 
-	maxSurge, numSurge, err := dsc.getSurgeNumbers(ds, nodeToDaemonPods, hash)
+	// respect gray update choice.
+	nodeToDaemonPods = dsc.getNodeToDaemonPodsToUpdateInDomain(ds, nodeToDaemonPods, topologyNodes)
+
+	maxSurge, numSurge, err := dsc.getSurgeNumbers(ds, nodeToDaemonPods, topologyNodes, hash)
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("Couldn't get surge numbers: %v", err)
 	}
@@ -683,13 +800,13 @@ func (dsc *ReconcileDaemonSet) surgingRollingUpdate(ds *appsv1alpha1.DaemonSet,
 				}
 				oldPod = pod
 			}
-			if !foundAvailable && podutil.IsPodAvailable(pod, ds.Spec.MinReadySeconds, metav1.Now()) {
+			if !foundAvailable && isDaemonPodAvailable(pod, ds.Spec.MinReadySeconds) {
 				foundAvailable = true
 			}
 		}
 
 		if newPod != nil {
-			klog.Infof("newPod IsPodAvailable is %v", podutil.IsPodAvailable(newPod, ds.Spec.MinReadySeconds, metav1.Now()))
+			klog.Infof("newPod IsPodAvailable is %v", isDaemonPodAvailable(newPod, ds.Spec.MinReadySeconds))
 		}
 		if newPod == nil && numSurge < maxSurge && wantToRun {
 			if !foundAvailable && len(pods) >= 2 {
@@ -699,13 +816,13 @@ func (dsc *ReconcileDaemonSet) surgingRollingUpdate(ds *appsv1alpha1.DaemonSet,
 				numSurge++
 				nodesToSurge = append(nodesToSurge, node)
 			}
-		} else if newPod != nil && podutil.IsPodAvailable(newPod, ds.Spec.MinReadySeconds, metav1.Now()) && oldPod != nil && oldPod.DeletionTimestamp == nil {
+		} else if newPod != nil && isDaemonPodAvailable(newPod, ds.Spec.MinReadySeconds) && oldPod != nil && oldPod.DeletionTimestamp == nil {
 			klog.V(6).Infof("Marking pod %s/%s for deletion", ds.Name, oldPod.Name)
 			oldPodsToDelete = append(oldPodsToDelete, oldPod.Name)
 		} else {
 			if ds.Spec.MinReadySeconds > 0 {
 				if newPod != nil {
-					if podutil.IsPodAvailable(newPod, ds.Spec.MinReadySeconds, metav1.Now()) {
+					if isDaemonPodAvailable(newPod, ds.Spec.MinReadySeconds) {
 						if oldPod != nil && oldPod.DeletionTimestamp == nil {
 							klog.V(6).Infof("Marking pod %s/%s for deletion", ds.Name, oldPod.Name)
 							oldPodsToDelete = append(oldPodsToDelete, oldPod.Name)
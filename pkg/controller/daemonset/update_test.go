@@ -17,11 +17,16 @@ limitations under the License.
 package daemonset
 
 import (
+	"reflect"
 	"testing"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 func Test_maxRevision(t *testing.T) {
@@ -103,3 +108,244 @@ func TestGetTemplateGeneration(t *testing.T) {
 		})
 	}
 }
+
+func TestSortTopologyDomains(t *testing.T) {
+	domainToNodes := map[string]sets.String{
+		"zone-a": sets.NewString("node-1"),
+		"zone-b": sets.NewString("node-2"),
+		"zone-c": sets.NewString("node-3"),
+		"":       sets.NewString("node-4"),
+	}
+	tests := []struct {
+		name  string
+		order []string
+		want  []string
+	}{
+		{
+			name: "no order",
+			want: []string{"zone-a", "zone-b", "zone-c", ""},
+		},
+		{
+			name:  "partial order",
+			order: []string{"zone-c"},
+			want:  []string{"zone-c", "zone-a", "zone-b", ""},
+		},
+		{
+			name:  "order with unknown and duplicated domains",
+			order: []string{"zone-b", "zone-x", "zone-b", "", "zone-a"},
+			want:  []string{"zone-b", "zone-a", "zone-c", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sortTopologyDomains(domainToNodes, tt.order); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortTopologyDomains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetUpdatingTopologyDomain(t *testing.T) {
+	const hash = "new"
+	const zoneLabel = "topology.kubernetes.io/zone"
+	newNode := func(name, zone string) *corev1.Node {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+		if zone != "" {
+			node.Labels[zoneLabel] = zone
+		}
+		return node
+	}
+	newPod := func(nodeName, revision string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   nodeName + "-" + revision,
+				Labels: map[string]string{apps.DefaultDaemonSetUniqueLabelKey: revision},
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range []*corev1.Node{
+		newNode("node-1", "zone-a"),
+		newNode("node-2", "zone-a"),
+		newNode("node-3", "zone-b"),
+		newNode("node-4", ""),
+	} {
+		_ = indexer.Add(node)
+	}
+	dsc := &ReconcileDaemonSet{nodeLister: corelisters.NewNodeLister(indexer)}
+
+	newDaemonSet := func(topologyKey string, order []string) *appsv1alpha1.DaemonSet {
+		return &appsv1alpha1.DaemonSet{
+			Spec: appsv1alpha1.DaemonSetSpec{
+				UpdateStrategy: appsv1alpha1.DaemonSetUpdateStrategy{
+					Type: appsv1alpha1.RollingUpdateDaemonSetStrategyType,
+					RollingUpdate: &appsv1alpha1.RollingUpdateDaemonSet{
+						TopologyKey:         topologyKey,
+						TopologyDomainOrder: order,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		ds               *appsv1alpha1.DaemonSet
+		nodeToDaemonPods map[string][]*corev1.Pod
+		wantDomain       string
+		wantNodes        sets.String
+	}{
+		{
+			name: "no topology key",
+			ds:   newDaemonSet("", nil),
+			nodeToDaemonPods: map[string][]*corev1.Pod{
+				"node-1": {newPod("node-1", "old", true)},
+			},
+		},
+		{
+			name: "first domain not updated",
+			ds:   newDaemonSet(zoneLabel, nil),
+			nodeToDaemonPods: map[string][]*corev1.Pod{
+				"node-1": {newPod("node-1", hash, true)},
+				"node-2": {newPod("node-2", "old", true)},
+				"node-3": {newPod("node-3", "old", true)},
+				"node-4": {newPod("node-4", "old", true)},
+			},
+			wantDomain: "zone-a",
+			wantNodes:  sets.NewString("node-1", "node-2"),
+		},
+		{
+			name: "first domain updated but unavailable",
+			ds:   newDaemonSet(zoneLabel, nil),
+			nodeToDaemonPods: map[string][]*corev1.Pod{
+				"node-1": {newPod("node-1", hash, true)},
+				"node-2": {newPod("node-2", hash, false)},
+				"node-3": {newPod("node-3", "old", true)},
+			},
+			wantDomain: "zone-a",
+			wantNodes:  sets.NewString("node-1", "node-2"),
+		},
+		{
+			name: "first domain finished",
+			ds:   newDaemonSet(zoneLabel, nil),
+			nodeToDaemonPods: map[string][]*corev1.Pod{
+				"node-1": {newPod("node-1", hash, true)},
+				"node-2": {newPod("node-2", hash, true)},
+				"node-3": {newPod("node-3", "old", true)},
+				"node-4": {newPod("node-4", "old", true)},
+			},
+			wantDomain: "zone-b",
+			wantNodes:  sets.NewString("node-3"),
+		},
+		{
+			name: "domain order",
+			ds:   newDaemonSet(zoneLabel, []string{"zone-b"}),
+			nodeToDaemonPods: map[string][]*corev1.Pod{
+				"node-1": {newPod("node-1", "old", true)},
+				"node-3": {newPod("node-3", "old", true)},
+			},
+			wantDomain: "zone-b",
+			wantNodes:  sets.NewString("node-3"),
+		},
+		{
+			name: "nodes without topology label at last",
+			ds:   newDaemonSet(zoneLabel, nil),
+			nodeToDaemonPods: map[string][]*corev1.Pod{
+				"node-1": {newPod("node-1", hash, true)},
+				"node-3": {newPod("node-3", hash, true)},
+				"node-4": {newPod("node-4", "old", true)},
+			},
+			wantDomain: "",
+			wantNodes:  sets.NewString("node-4"),
+		},
+		{
+			name: "all domains finished",
+			ds:   newDaemonSet(zoneLabel, nil),
+			nodeToDaemonPods: map[string][]*corev1.Pod{
+				"node-1": {newPod("node-1", hash, true)},
+				"node-3": {newPod("node-3", hash, true)},
+				"node-4": {newPod("node-4", hash, true)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domain, nodes, err := dsc.getUpdatingTopologyDomain(tt.ds, tt.nodeToDaemonPods, hash)
+			if err != nil {
+				t.Fatalf("getUpdatingTopologyDomain() error = %v", err)
+			}
+			if domain != tt.wantDomain || !reflect.DeepEqual(nodes, tt.wantNodes) {
+				t.Errorf("getUpdatingTopologyDomain() = %q, %v, want %q, %v", domain, nodes, tt.wantDomain, tt.wantNodes)
+			}
+		})
+	}
+}
+
+func TestGetNodeToDaemonPodsToUpdateInDomainWithPartition(t *testing.T) {
+	const hash = "new"
+	const zoneLabel = "topology.kubernetes.io/zone"
+	newPod := func(nodeName, revision string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   nodeName + "-" + revision,
+				Labels: map[string]string{apps.DefaultDaemonSetUniqueLabelKey: revision},
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for name, zone := range map[string]string{"node-1": "zone-a", "node-2": "zone-a", "node-3": "zone-b", "node-4": "zone-b"} {
+		_ = indexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{zoneLabel: zone}}})
+	}
+	dsc := &ReconcileDaemonSet{nodeLister: corelisters.NewNodeLister(indexer)}
+
+	// partition keeps node-1 on the old version, so zone-a is finished once node-2 is updated.
+	partition := int32(1)
+	ds := &appsv1alpha1.DaemonSet{
+		Spec: appsv1alpha1.DaemonSetSpec{
+			UpdateStrategy: appsv1alpha1.DaemonSetUpdateStrategy{
+				Type: appsv1alpha1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1alpha1.RollingUpdateDaemonSet{
+					TopologyKey: zoneLabel,
+					Partition:   &partition,
+				},
+			},
+		},
+	}
+	nodeToDaemonPods := map[string][]*corev1.Pod{
+		"node-1": {newPod("node-1", "old")},
+		"node-2": {newPod("node-2", hash)},
+		"node-3": {newPod("node-3", "old")},
+		"node-4": {newPod("node-4", "old")},
+	}
+
+	domain, topologyNodes, err := dsc.getUpdatingTopologyDomain(ds, nodeToDaemonPods, hash)
+	if err != nil {
+		t.Fatalf("getUpdatingTopologyDomain() error = %v", err)
+	}
+	if domain != "zone-b" {
+		t.Fatalf("getUpdatingTopologyDomain() = %q, want %q", domain, "zone-b")
+	}
+
+	got := sets.NewString()
+	for nodeName := range dsc.getNodeToDaemonPodsToUpdateInDomain(ds, nodeToDaemonPods, topologyNodes) {
+		got.Insert(nodeName)
+	}
+	// the partition is counted on all the nodes rather than the nodes in zone-b.
+	if want := sets.NewString("node-3", "node-4"); !got.Equal(want) {
+		t.Errorf("getNodeToDaemonPodsToUpdateInDomain() = %v, want %v", got.List(), want.List())
+	}
+}
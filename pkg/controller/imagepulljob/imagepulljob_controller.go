@@ -0,0 +1,327 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepulljob
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util"
+	"github.com/openkruise/kruise/pkg/util/gate"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+	kubecontroller "k8s.io/kubernetes/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var controllerKind = appsv1alpha1.SchemeGroupVersion.WithKind("ImagePullJob")
+
+// Add creates a new ImagePullJob Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	if !gate.ResourceEnabled(&appsv1alpha1.ImagePullJob{}) || !gate.ResourceEnabled(&appsv1alpha1.NodeImage{}) {
+		return nil
+	}
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileImagePullJob{
+		Client:   mgr.GetClient(),
+		recorder: mgr.GetRecorder("imagepulljob-controller"),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("imagepulljob-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to ImagePullJob
+	err = c.Watch(&source.Kind{Type: &appsv1alpha1.ImagePullJob{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to NodeImage
+	err = c.Watch(&source.Kind{Type: &appsv1alpha1.NodeImage{}}, &nodeImageEventHandler{client: mgr.GetClient()})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to Node labels
+	err = c.Watch(&source.Kind{Type: &v1.Node{}}, &nodeEventHandler{client: mgr.GetClient()})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileImagePullJob{}
+
+// ReconcileImagePullJob reconciles a ImagePullJob object
+type ReconcileImagePullJob struct {
+	client.Client
+	recorder record.EventRecorder
+}
+
+// Reconcile reads that state of the cluster for a ImagePullJob object and makes changes based on the state read
+// and what is in the ImagePullJob.Spec
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=nodeimages,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=nodeimages/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=imagepulljobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=imagepulljobs/status,verbs=get;update;patch
+func (r *ReconcileImagePullJob) Reconcile(request reconcile.Request) (res reconcile.Result, retErr error) {
+	startTime := time.Now()
+	defer func() {
+		if retErr == nil {
+			if res.Requeue || res.RequeueAfter > 0 {
+				klog.Infof("Finished syncing ImagePullJob %s, cost %v, result: %v", request, time.Since(startTime), res)
+			} else {
+				klog.Infof("Finished syncing ImagePullJob %s, cost %v", request, time.Since(startTime))
+			}
+		} else {
+			klog.Errorf("Failed syncing ImagePullJob %s: %v", request, retErr)
+		}
+	}()
+
+	// Fetch the ImagePullJob instance
+	job := &appsv1alpha1.ImagePullJob{}
+	err := r.Get(context.TODO(), request.NamespacedName, job)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Object not found, return. The tags owned by it will be cleaned up by nodeimage controller.
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if job.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	if IsJobFinished(job) {
+		if isPast, leftTime := pastTTLDeadline(job); isPast {
+			klog.Infof("Deleting ImagePullJob %s for ttlSecondsAfterFinished", request)
+			if err = r.Delete(context.TODO(), job); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+		} else if leftTime > 0 {
+			return reconcile.Result{RequeueAfter: leftTime}, nil
+		}
+		return reconcile.Result{}, nil
+	}
+
+	newStatus := job.Status.DeepCopy()
+	if newStatus.StartTime == nil {
+		now := metav1.Now()
+		newStatus.StartTime = &now
+	}
+
+	image, tag, err := util.ParseRepositoryTag(job.Spec.Image)
+	if err != nil {
+		r.recorder.Eventf(job, v1.EventTypeWarning, "InvalidImage", "Failed to parse image %s: %v", job.Spec.Image, err)
+		newStatus.Message = fmt.Sprintf("invalid image %s: %v", job.Spec.Image, err)
+		return reconcile.Result{}, r.updateStatus(job, newStatus)
+	}
+
+	nodeImages, err := r.getNodeImagesForJob(job)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	var requeueAfter time.Duration
+	var toPull, active []*appsv1alpha1.NodeImage
+	newStatus.Desired, newStatus.Active, newStatus.Succeeded, newStatus.Failed = int32(len(nodeImages.desired)), 0, 0, 0
+	for _, nodeImage := range nodeImages.desired {
+		tagSpec := getImageTagSpec(nodeImage, image, tag)
+		if tagSpec == nil || !containsOwnerRef(tagSpec.OwnerReferences, job.UID) {
+			toPull = append(toPull, nodeImage)
+			continue
+		}
+
+		tagStatus := getImageTagStatus(nodeImage, image, tag)
+		switch {
+		case tagStatus != nil && tagStatus.Phase == appsv1alpha1.ImagePhaseSucceeded:
+			newStatus.Succeeded++
+		case tagStatus != nil && tagStatus.Phase == appsv1alpha1.ImagePhaseFailed:
+			newStatus.Failed++
+		default:
+			active = append(active, nodeImage)
+		}
+	}
+	newStatus.Active = int32(len(active))
+
+	// release the nodes that no longer match this job
+	if err = r.removeTagFromNodes(job, nodeImages.notMatched, image, tag); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if isPast, leftTime := pastActiveDeadline(job.Spec.CompletionPolicy, newStatus); isPast {
+		if err = r.removeTagFromNodes(job, active, image, tag); err != nil {
+			return reconcile.Result{}, err
+		}
+		now := metav1.Now()
+		newStatus.CompletionTime = &now
+		newStatus.Active = 0
+		newStatus.Message = fmt.Sprintf("job exceeded activeDeadlineSeconds, %d nodes succeeded, %d nodes failed, %d nodes not finished",
+			newStatus.Succeeded, newStatus.Failed, newStatus.Desired-newStatus.Succeeded-newStatus.Failed)
+		r.recorder.Eventf(job, v1.EventTypeWarning, "DeadlineExceeded", "Job was active longer than specified deadline")
+		return r.finishJob(job, newStatus)
+	} else if leftTime > 0 {
+		requeueAfter = leftTime
+	}
+
+	// start pulling on more nodes within the parallelism
+	if diff := getParallelism(job, len(nodeImages.desired)) - len(active); diff > 0 && len(toPull) > 0 {
+		if diff > len(toPull) {
+			diff = len(toPull)
+		}
+		toPull = toPull[:diff]
+		successes, err := util.SlowStartBatch(len(toPull), kubecontroller.SlowStartInitialBatchSize, func(idx int) error {
+			return r.updateNodeImage(toPull[idx].Name, func(nodeImage *appsv1alpha1.NodeImage) bool {
+				return addImageTagSpec(nodeImage, job, image, tag)
+			})
+		})
+		newStatus.Active += int32(successes)
+		if err != nil {
+			r.recorder.Eventf(job, v1.EventTypeWarning, "FailedUpdateNodeImage", "Failed to update NodeImage for pulling: %v", err)
+			return reconcile.Result{}, err
+		}
+	}
+
+	finished := newStatus.Succeeded + newStatus.Failed
+	newStatus.Message = fmt.Sprintf("job is running, %d/%d nodes finished", finished, newStatus.Desired)
+	if job.Spec.CompletionPolicy.Type != appsv1alpha1.Never && newStatus.Desired > 0 && finished == newStatus.Desired {
+		now := metav1.Now()
+		newStatus.CompletionTime = &now
+		newStatus.Message = fmt.Sprintf("job has completed, %d nodes succeeded, %d nodes failed", newStatus.Succeeded, newStatus.Failed)
+		r.recorder.Eventf(job, v1.EventTypeNormal, "Completed", "Job has completed, %d nodes succeeded, %d nodes failed", newStatus.Succeeded, newStatus.Failed)
+		return r.finishJob(job, newStatus)
+	}
+
+	return reconcile.Result{RequeueAfter: requeueAfter}, r.updateStatus(job, newStatus)
+}
+
+func (r *ReconcileImagePullJob) finishJob(job *appsv1alpha1.ImagePullJob, newStatus *appsv1alpha1.ImagePullJobStatus) (reconcile.Result, error) {
+	if err := r.updateStatus(job, newStatus); err != nil {
+		return reconcile.Result{}, err
+	}
+	if job.Spec.CompletionPolicy.TTLSecondsAfterFinished != nil {
+		// a bit more than the TTLSecondsAfterFinished to ensure it exceeds the TTLSecondsAfterFinished when being reconciled
+		return reconcile.Result{RequeueAfter: time.Duration(*job.Spec.CompletionPolicy.TTLSecondsAfterFinished+1) * time.Second}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+type jobNodeImages struct {
+	// desired contains NodeImages of the nodes selected by job
+	desired []*appsv1alpha1.NodeImage
+	// notMatched contains NodeImages that are owned by job but no longer selected
+	notMatched []*appsv1alpha1.NodeImage
+}
+
+func (r *ReconcileImagePullJob) getNodeImagesForJob(job *appsv1alpha1.ImagePullJob) (*jobNodeImages, error) {
+	nodeList := &v1.NodeList{}
+	if err := r.List(context.TODO(), &client.ListOptions{}, nodeList); err != nil {
+		return nil, err
+	}
+	nodeImageList := &appsv1alpha1.NodeImageList{}
+	if err := r.List(context.TODO(), &client.ListOptions{}, nodeImageList); err != nil {
+		return nil, err
+	}
+
+	matchedNodes := make(map[string]struct{}, len(nodeList.Items))
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		matched, err := matchNode(job, node)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			matchedNodes[node.Name] = struct{}{}
+		}
+	}
+
+	result := &jobNodeImages{}
+	sort.Slice(nodeImageList.Items, func(i, j int) bool { return nodeImageList.Items[i].Name < nodeImageList.Items[j].Name })
+	for i := range nodeImageList.Items {
+		nodeImage := &nodeImageList.Items[i]
+		if _, ok := matchedNodes[nodeImage.Name]; ok {
+			result.desired = append(result.desired, nodeImage)
+			continue
+		}
+		if isOwnedBy(nodeImage, job.UID) {
+			result.notMatched = append(result.notMatched, nodeImage)
+		}
+	}
+	return result, nil
+}
+
+func (r *ReconcileImagePullJob) removeTagFromNodes(job *appsv1alpha1.ImagePullJob, nodeImages []*appsv1alpha1.NodeImage, image, tag string) error {
+	for _, nodeImage := range nodeImages {
+		if err := r.updateNodeImage(nodeImage.Name, func(nodeImage *appsv1alpha1.NodeImage) bool {
+			return removeImageTagSpec(nodeImage, job, image, tag)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReconcileImagePullJob) updateNodeImage(name string, modifyFunc func(*appsv1alpha1.NodeImage) bool) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		nodeImage := &appsv1alpha1.NodeImage{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: name}, nodeImage); err != nil {
+			return err
+		}
+		if !modifyFunc(nodeImage) {
+			return nil
+		}
+		return r.Update(context.TODO(), nodeImage)
+	})
+}
+
+func (r *ReconcileImagePullJob) updateStatus(job *appsv1alpha1.ImagePullJob, newStatus *appsv1alpha1.ImagePullJobStatus) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		clone := &appsv1alpha1.ImagePullJob{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Namespace: job.Namespace, Name: job.Name}, clone); err != nil {
+			return err
+		}
+		clone.Status = *newStatus
+		return r.Status().Update(context.TODO(), clone)
+	})
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepulljob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openkruise/kruise/pkg/apis"
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	_ = apis.AddToScheme(scheme.Scheme)
+}
+
+func newNode(name string, labels map[string]string) *v1.Node {
+	return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newNodeImage(name string, job *appsv1alpha1.ImagePullJob, phase appsv1alpha1.ImagePullPhase) *appsv1alpha1.NodeImage {
+	nodeImage := &appsv1alpha1.NodeImage{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if job == nil {
+		return nodeImage
+	}
+	nodeImage.Spec.Images = map[string]appsv1alpha1.ImageSpec{
+		"nginx": {Tags: []appsv1alpha1.ImageTagSpec{{Tag: "1.18", OwnerReferences: []v1.ObjectReference{getOwnerRef(job)}}}},
+	}
+	if phase != "" {
+		nodeImage.Status.ImageStatuses = map[string]appsv1alpha1.ImageStatus{
+			"nginx": {Tags: []appsv1alpha1.ImageTagStatus{{Tag: "1.18", Phase: phase}}},
+		}
+	}
+	return nodeImage
+}
+
+func newJob() *appsv1alpha1.ImagePullJob {
+	return &appsv1alpha1.ImagePullJob{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "job", UID: "job-uid"},
+		Spec: appsv1alpha1.ImagePullJobSpec{
+			Image:       "nginx:1.18",
+			PullSecrets: []string{"secret"},
+			Selector: &appsv1alpha1.NodeSelector{
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}},
+			},
+			CompletionPolicy: appsv1alpha1.CompletionPolicy{Type: appsv1alpha1.Always},
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	longAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+	nodes := []runtime.Object{
+		newNode("node1", map[string]string{"pool": "a"}),
+		newNode("node2", map[string]string{"pool": "a"}),
+		newNode("node3", map[string]string{"pool": "b"}),
+	}
+
+	cases := []struct {
+		name               string
+		getJob             func() *appsv1alpha1.ImagePullJob
+		getNodeImages      func(job *appsv1alpha1.ImagePullJob) []runtime.Object
+		expectedStatus     appsv1alpha1.ImagePullJobStatus
+		expectedCompleted  bool
+		expectedOwnedNodes []string
+	}{
+		{
+			name:   "start pulling within parallelism",
+			getJob: newJob,
+			getNodeImages: func(job *appsv1alpha1.ImagePullJob) []runtime.Object {
+				return []runtime.Object{newNodeImage("node1", nil, ""), newNodeImage("node2", nil, ""), newNodeImage("node3", nil, "")}
+			},
+			expectedStatus:     appsv1alpha1.ImagePullJobStatus{Desired: 2, Active: 1},
+			expectedOwnedNodes: []string{"node1"},
+		},
+		{
+			name: "start pulling with percent parallelism",
+			getJob: func() *appsv1alpha1.ImagePullJob {
+				job := newJob()
+				p := intstr.FromString("100%")
+				job.Spec.Parallelism = &p
+				return job
+			},
+			getNodeImages: func(job *appsv1alpha1.ImagePullJob) []runtime.Object {
+				return []runtime.Object{newNodeImage("node1", job, appsv1alpha1.ImagePhaseSucceeded), newNodeImage("node2", nil, ""), newNodeImage("node3", nil, "")}
+			},
+			expectedStatus:     appsv1alpha1.ImagePullJobStatus{Desired: 2, Active: 1, Succeeded: 1},
+			expectedOwnedNodes: []string{"node1", "node2"},
+		},
+		{
+			name:   "release node no longer selected",
+			getJob: newJob,
+			getNodeImages: func(job *appsv1alpha1.ImagePullJob) []runtime.Object {
+				return []runtime.Object{newNodeImage("node1", job, appsv1alpha1.ImagePhasePulling), newNodeImage("node2", nil, ""), newNodeImage("node3", job, appsv1alpha1.ImagePhasePulling)}
+			},
+			expectedStatus:     appsv1alpha1.ImagePullJobStatus{Desired: 2, Active: 1},
+			expectedOwnedNodes: []string{"node1"},
+		},
+		{
+			name:   "complete when all nodes finished",
+			getJob: newJob,
+			getNodeImages: func(job *appsv1alpha1.ImagePullJob) []runtime.Object {
+				return []runtime.Object{newNodeImage("node1", job, appsv1alpha1.ImagePhaseSucceeded), newNodeImage("node2", job, appsv1alpha1.ImagePhaseFailed), newNodeImage("node3", nil, "")}
+			},
+			expectedStatus:     appsv1alpha1.ImagePullJobStatus{Desired: 2, Succeeded: 1, Failed: 1},
+			expectedCompleted:  true,
+			expectedOwnedNodes: []string{"node1", "node2"},
+		},
+		{
+			name: "never complete",
+			getJob: func() *appsv1alpha1.ImagePullJob {
+				job := newJob()
+				job.Spec.CompletionPolicy.Type = appsv1alpha1.Never
+				return job
+			},
+			getNodeImages: func(job *appsv1alpha1.ImagePullJob) []runtime.Object {
+				return []runtime.Object{newNodeImage("node1", job, appsv1alpha1.ImagePhaseSucceeded), newNodeImage("node2", job, appsv1alpha1.ImagePhaseSucceeded), newNodeImage("node3", nil, "")}
+			},
+			expectedStatus:     appsv1alpha1.ImagePullJobStatus{Desired: 2, Succeeded: 2},
+			expectedOwnedNodes: []string{"node1", "node2"},
+		},
+		{
+			name: "complete when active deadline exceeded",
+			getJob: func() *appsv1alpha1.ImagePullJob {
+				job := newJob()
+				job.Spec.CompletionPolicy.ActiveDeadlineSeconds = func() *int64 { v := int64(60); return &v }()
+				job.Status.StartTime = &longAgo
+				return job
+			},
+			getNodeImages: func(job *appsv1alpha1.ImagePullJob) []runtime.Object {
+				return []runtime.Object{newNodeImage("node1", job, appsv1alpha1.ImagePhaseSucceeded), newNodeImage("node2", job, appsv1alpha1.ImagePhasePulling), newNodeImage("node3", nil, "")}
+			},
+			expectedStatus:     appsv1alpha1.ImagePullJobStatus{Desired: 2, Succeeded: 1},
+			expectedCompleted:  true,
+			expectedOwnedNodes: []string{"node1"},
+		},
+	}
+
+	for _, tc := range cases {
+		job := tc.getJob()
+		objects := append([]runtime.Object{job}, nodes...)
+		objects = append(objects, tc.getNodeImages(job)...)
+		fakeClient := fake.NewFakeClient(objects...)
+		r := &ReconcileImagePullJob{Client: fakeClient, recorder: record.NewFakeRecorder(10)}
+
+		if _, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: job.Namespace, Name: job.Name}}); err != nil {
+			t.Fatalf("%s: failed to reconcile: %v", tc.name, err)
+		}
+
+		gotJob := &appsv1alpha1.ImagePullJob{}
+		if err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: job.Namespace, Name: job.Name}, gotJob); err != nil {
+			t.Fatalf("%s: failed to get job: %v", tc.name, err)
+		}
+		if gotJob.Status.StartTime == nil {
+			t.Fatalf("%s: expected startTime set", tc.name)
+		}
+		if (gotJob.Status.CompletionTime != nil) != tc.expectedCompleted {
+			t.Fatalf("%s: expected completed %v, got completionTime %v", tc.name, tc.expectedCompleted, gotJob.Status.CompletionTime)
+		}
+		if gotJob.Status.Desired != tc.expectedStatus.Desired || gotJob.Status.Active != tc.expectedStatus.Active ||
+			gotJob.Status.Succeeded != tc.expectedStatus.Succeeded || gotJob.Status.Failed != tc.expectedStatus.Failed {
+			t.Fatalf("%s: expected status %+v, got %+v", tc.name, tc.expectedStatus, gotJob.Status)
+		}
+
+		nodeImageList := &appsv1alpha1.NodeImageList{}
+		if err := fakeClient.List(context.TODO(), &client.ListOptions{}, nodeImageList); err != nil {
+			t.Fatalf("%s: failed to list NodeImages: %v", tc.name, err)
+		}
+		var ownedNodes []string
+		for i := range nodeImageList.Items {
+			nodeImage := &nodeImageList.Items[i]
+			if !isOwnedBy(nodeImage, job.UID) {
+				continue
+			}
+			ownedNodes = append(ownedNodes, nodeImage.Name)
+			if tagSpec := getImageTagSpec(nodeImage, "nginx", "1.18"); tagSpec == nil {
+				t.Fatalf("%s: expected tag in NodeImage %s", tc.name, nodeImage.Name)
+			}
+		}
+		if len(ownedNodes) != len(tc.expectedOwnedNodes) {
+			t.Fatalf("%s: expected owned nodes %v, got %v", tc.name, tc.expectedOwnedNodes, ownedNodes)
+		}
+		for i := range ownedNodes {
+			if ownedNodes[i] != tc.expectedOwnedNodes[i] {
+				t.Fatalf("%s: expected owned nodes %v, got %v", tc.name, tc.expectedOwnedNodes, ownedNodes)
+			}
+		}
+	}
+}
+
+func TestImageTagSpec(t *testing.T) {
+	job := newJob()
+	otherJob := newJob()
+	otherJob.UID = "other-uid"
+	nodeImage := newNodeImage("node1", nil, "")
+
+	if !addImageTagSpec(nodeImage, job, "nginx", "1.18") {
+		t.Fatalf("expected tag added")
+	}
+	if addImageTagSpec(nodeImage, job, "nginx", "1.18") {
+		t.Fatalf("expected nothing changed for existing owner")
+	}
+	if !addImageTagSpec(nodeImage, otherJob, "nginx", "1.18") {
+		t.Fatalf("expected owner added")
+	}
+	imageSpec := nodeImage.Spec.Images["nginx"]
+	if len(imageSpec.Tags) != 1 || len(imageSpec.Tags[0].OwnerReferences) != 2 {
+		t.Fatalf("unexpected image spec %+v", imageSpec)
+	}
+	if len(imageSpec.PullSecrets) != 1 || imageSpec.PullSecrets[0].Namespace != "default" || imageSpec.PullSecrets[0].Name != "secret" {
+		t.Fatalf("unexpected pull secrets %+v", imageSpec.PullSecrets)
+	}
+
+	if !removeImageTagSpec(nodeImage, job, "nginx", "1.18") {
+		t.Fatalf("expected owner removed")
+	}
+	if tagSpec := getImageTagSpec(nodeImage, "nginx", "1.18"); tagSpec == nil || len(tagSpec.OwnerReferences) != 1 {
+		t.Fatalf("expected tag kept for other owner, got %+v", tagSpec)
+	}
+	if removeImageTagSpec(nodeImage, job, "nginx", "1.18") {
+		t.Fatalf("expected nothing changed for removed owner")
+	}
+	if !removeImageTagSpec(nodeImage, otherJob, "nginx", "1.18") {
+		t.Fatalf("expected owner removed")
+	}
+	if _, ok := nodeImage.Spec.Images["nginx"]; ok {
+		t.Fatalf("expected image removed without tags")
+	}
+}
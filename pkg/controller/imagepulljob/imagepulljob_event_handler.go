@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepulljob
+
+import (
+	"context"
+	"reflect"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ handler.EventHandler = &nodeImageEventHandler{}
+
+type nodeImageEventHandler struct {
+	client client.Client
+}
+
+func (e *nodeImageEventHandler) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	// a new node may be selected by the unfinished jobs
+	enqueueUnfinishedJobs(e.client, q)
+}
+
+func (e *nodeImageEventHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	oldObj, oldOK := evt.ObjectOld.(*appsv1alpha1.NodeImage)
+	newObj, newOK := evt.ObjectNew.(*appsv1alpha1.NodeImage)
+	if !oldOK || !newOK {
+		return
+	}
+	if reflect.DeepEqual(oldObj.Spec, newObj.Spec) && reflect.DeepEqual(oldObj.Status, newObj.Status) {
+		return
+	}
+	enqueueOwnerJobs(oldObj, q)
+	enqueueOwnerJobs(newObj, q)
+}
+
+func (e *nodeImageEventHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	if obj, ok := evt.Object.(*appsv1alpha1.NodeImage); ok {
+		enqueueOwnerJobs(obj, q)
+	}
+}
+
+func (e *nodeImageEventHandler) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+}
+
+var _ handler.EventHandler = &nodeEventHandler{}
+
+type nodeEventHandler struct {
+	client client.Client
+}
+
+func (e *nodeEventHandler) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (e *nodeEventHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	oldNode, oldOK := evt.ObjectOld.(*v1.Node)
+	newNode, newOK := evt.ObjectNew.(*v1.Node)
+	if !oldOK || !newOK || reflect.DeepEqual(oldNode.Labels, newNode.Labels) {
+		return
+	}
+	enqueueUnfinishedJobs(e.client, q)
+}
+
+func (e *nodeEventHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (e *nodeEventHandler) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+}
+
+func enqueueOwnerJobs(nodeImage *appsv1alpha1.NodeImage, q workqueue.RateLimitingInterface) {
+	for _, imageSpec := range nodeImage.Spec.Images {
+		for _, tagSpec := range imageSpec.Tags {
+			for _, ref := range tagSpec.OwnerReferences {
+				if ref.Kind != controllerKind.Kind {
+					continue
+				}
+				q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}})
+			}
+		}
+	}
+}
+
+func enqueueUnfinishedJobs(c client.Client, q workqueue.RateLimitingInterface) {
+	jobList := &appsv1alpha1.ImagePullJobList{}
+	if err := c.List(context.TODO(), &client.ListOptions{}, jobList); err != nil {
+		klog.Errorf("Failed to list ImagePullJobs: %v", err)
+		return
+	}
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if IsJobFinished(job) {
+			continue
+		}
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: job.Namespace, Name: job.Name}})
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepulljob
+
+import (
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// IsJobFinished returns true if the job has been completed.
+func IsJobFinished(job *appsv1alpha1.ImagePullJob) bool {
+	if job.Spec.CompletionPolicy.Type == appsv1alpha1.Never {
+		return false
+	}
+	return job.Status.CompletionTime != nil
+}
+
+// pastActiveDeadline checks if job has ActiveDeadlineSeconds field set and if it is exceeded.
+func pastActiveDeadline(policy appsv1alpha1.CompletionPolicy, status *appsv1alpha1.ImagePullJobStatus) (bool, time.Duration) {
+	if policy.Type == appsv1alpha1.Never || policy.ActiveDeadlineSeconds == nil || status.StartTime == nil {
+		return false, -1
+	}
+	duration := time.Since(status.StartTime.Time)
+	allowedDuration := time.Duration(*policy.ActiveDeadlineSeconds) * time.Second
+	return duration >= allowedDuration, allowedDuration - duration
+}
+
+// pastTTLDeadline checks if job has past the TTLSecondsAfterFinished deadline
+func pastTTLDeadline(job *appsv1alpha1.ImagePullJob) (bool, time.Duration) {
+	if job.Spec.CompletionPolicy.TTLSecondsAfterFinished == nil || job.Status.CompletionTime == nil {
+		return false, -1
+	}
+	duration := time.Since(job.Status.CompletionTime.Time)
+	allowedDuration := time.Duration(*job.Spec.CompletionPolicy.TTLSecondsAfterFinished) * time.Second
+	return duration >= allowedDuration, allowedDuration - duration
+}
+
+// getParallelism returns the max number of nodes that can be pulling the image at the same time.
+func getParallelism(job *appsv1alpha1.ImagePullJob, desired int) int {
+	if job.Spec.Parallelism == nil {
+		return 1
+	}
+	parallelism, err := intstr.GetValueFromIntOrPercent(job.Spec.Parallelism, desired, true)
+	if err != nil || parallelism < 0 {
+		return 1
+	}
+	return parallelism
+}
+
+// matchNode returns true if the node is selected by the job.
+func matchNode(job *appsv1alpha1.ImagePullJob, node *v1.Node) (bool, error) {
+	if job.Spec.Selector == nil {
+		return true, nil
+	}
+	if len(job.Spec.Selector.Names) > 0 && !sets.NewString(job.Spec.Selector.Names...).Has(node.Name) {
+		return false, nil
+	}
+	if len(job.Spec.Selector.MatchLabels) == 0 && len(job.Spec.Selector.MatchExpressions) == 0 {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&job.Spec.Selector.LabelSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(node.Labels)), nil
+}
+
+func getOwnerRef(job *appsv1alpha1.ImagePullJob) v1.ObjectReference {
+	return v1.ObjectReference{
+		APIVersion: controllerKind.GroupVersion().String(),
+		Kind:       controllerKind.Kind,
+		Namespace:  job.Namespace,
+		Name:       job.Name,
+		UID:        job.UID,
+	}
+}
+
+func containsOwnerRef(refs []v1.ObjectReference, uid types.UID) bool {
+	for _, ref := range refs {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// getImageTagSpec returns the tag spec in NodeImage, or nil if it does not exist.
+func getImageTagSpec(nodeImage *appsv1alpha1.NodeImage, image, tag string) *appsv1alpha1.ImageTagSpec {
+	imageSpec, ok := nodeImage.Spec.Images[image]
+	if !ok {
+		return nil
+	}
+	for i := range imageSpec.Tags {
+		if imageSpec.Tags[i].Tag == tag {
+			return &imageSpec.Tags[i]
+		}
+	}
+	return nil
+}
+
+// getImageTagStatus returns the tag status in NodeImage, or nil if it does not exist.
+func getImageTagStatus(nodeImage *appsv1alpha1.NodeImage, image, tag string) *appsv1alpha1.ImageTagStatus {
+	imageStatus, ok := nodeImage.Status.ImageStatuses[image]
+	if !ok {
+		return nil
+	}
+	for i := range imageStatus.Tags {
+		if imageStatus.Tags[i].Tag == tag {
+			return &imageStatus.Tags[i]
+		}
+	}
+	return nil
+}
+
+// addImageTagSpec adds the job as an owner of the tag in NodeImage, and returns false if nothing changed.
+func addImageTagSpec(nodeImage *appsv1alpha1.NodeImage, job *appsv1alpha1.ImagePullJob, image, tag string) bool {
+	if tagSpec := getImageTagSpec(nodeImage, image, tag); tagSpec != nil {
+		if containsOwnerRef(tagSpec.OwnerReferences, job.UID) {
+			return false
+		}
+		tagSpec.OwnerReferences = append(tagSpec.OwnerReferences, getOwnerRef(job))
+		return true
+	}
+
+	if nodeImage.Spec.Images == nil {
+		nodeImage.Spec.Images = make(map[string]appsv1alpha1.ImageSpec)
+	}
+	imageSpec := nodeImage.Spec.Images[image]
+	for _, secret := range job.Spec.PullSecrets {
+		ref := appsv1alpha1.ReferenceObject{Namespace: job.Namespace, Name: secret}
+		if !containsReferenceObject(imageSpec.PullSecrets, ref) {
+			imageSpec.PullSecrets = append(imageSpec.PullSecrets, ref)
+		}
+	}
+
+	now := metav1.Now()
+	tagSpec := appsv1alpha1.ImageTagSpec{
+		Tag:             tag,
+		CreatedAt:       &now,
+		OwnerReferences: []v1.ObjectReference{getOwnerRef(job)},
+		PullPolicy: &appsv1alpha1.ImageTagPullPolicy{
+			ActiveDeadlineSeconds:   job.Spec.CompletionPolicy.ActiveDeadlineSeconds,
+			TTLSecondsAfterFinished: job.Spec.CompletionPolicy.TTLSecondsAfterFinished,
+		},
+	}
+	if job.Spec.PullPolicy != nil {
+		tagSpec.PullPolicy.TimeoutSeconds = job.Spec.PullPolicy.TimeoutSeconds
+		tagSpec.PullPolicy.BackoffLimit = job.Spec.PullPolicy.BackoffLimit
+	}
+	imageSpec.Tags = append(imageSpec.Tags, tagSpec)
+	nodeImage.Spec.Images[image] = imageSpec
+	return true
+}
+
+// removeImageTagSpec removes the job from the owners of the tag in NodeImage,
+// the tag will also be removed if it has no owner left. It returns false if nothing changed.
+func removeImageTagSpec(nodeImage *appsv1alpha1.NodeImage, job *appsv1alpha1.ImagePullJob, image, tag string) bool {
+	imageSpec, ok := nodeImage.Spec.Images[image]
+	if !ok {
+		return false
+	}
+
+	var changed bool
+	var tags []appsv1alpha1.ImageTagSpec
+	for _, tagSpec := range imageSpec.Tags {
+		if tagSpec.Tag != tag || !containsOwnerRef(tagSpec.OwnerReferences, job.UID) {
+			tags = append(tags, tagSpec)
+			continue
+		}
+		changed = true
+		var refs []v1.ObjectReference
+		for _, ref := range tagSpec.OwnerReferences {
+			if ref.UID != job.UID {
+				refs = append(refs, ref)
+			}
+		}
+		if len(refs) > 0 {
+			tagSpec.OwnerReferences = refs
+			tags = append(tags, tagSpec)
+		}
+	}
+	if !changed {
+		return false
+	}
+
+	if len(tags) == 0 {
+		delete(nodeImage.Spec.Images, image)
+	} else {
+		imageSpec.Tags = tags
+		nodeImage.Spec.Images[image] = imageSpec
+	}
+	return true
+}
+
+// isOwnedBy returns true if any tag in NodeImage is owned by the job.
+func isOwnedBy(nodeImage *appsv1alpha1.NodeImage, uid types.UID) bool {
+	for _, imageSpec := range nodeImage.Spec.Images {
+		for _, tagSpec := range imageSpec.Tags {
+			if containsOwnerRef(tagSpec.OwnerReferences, uid) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsReferenceObject(refs []appsv1alpha1.ReferenceObject, ref appsv1alpha1.ReferenceObject) bool {
+	for _, r := range refs {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeimage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/gate"
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Add creates a new NodeImage Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	if !gate.ResourceEnabled(&appsv1alpha1.NodeImage{}) {
+		return nil
+	}
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileNodeImage{
+		Client:   mgr.GetClient(),
+		recorder: mgr.GetRecorder("nodeimage-controller"),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("nodeimage-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to NodeImage
+	err = c.Watch(&source.Kind{Type: &appsv1alpha1.NodeImage{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to Node, the NodeImage has the same name as Node
+	err = c.Watch(&source.Kind{Type: &v1.Node{}}, &nodeEventHandler{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for deletion of ImagePullJob to prune the tags owned by it
+	err = c.Watch(&source.Kind{Type: &appsv1alpha1.ImagePullJob{}}, &imagePullJobEventHandler{client: mgr.GetClient()})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileNodeImage{}
+
+// ReconcileNodeImage reconciles a NodeImage object
+type ReconcileNodeImage struct {
+	client.Client
+	recorder record.EventRecorder
+}
+
+// Reconcile reads that state of the cluster for a NodeImage object and makes changes based on the state read
+// and what is in the NodeImage.Spec
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=nodeimages,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=nodeimages/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=imagepulljobs,verbs=get;list;watch
+func (r *ReconcileNodeImage) Reconcile(request reconcile.Request) (res reconcile.Result, retErr error) {
+	startTime := time.Now()
+	defer func() {
+		if retErr == nil {
+			if res.Requeue || res.RequeueAfter > 0 {
+				klog.Infof("Finished syncing NodeImage %s, cost %v, result: %v", request.Name, time.Since(startTime), res)
+			} else {
+				klog.Infof("Finished syncing NodeImage %s, cost %v", request.Name, time.Since(startTime))
+			}
+		} else {
+			klog.Errorf("Failed syncing NodeImage %s: %v", request.Name, retErr)
+		}
+	}()
+
+	node := &v1.Node{}
+	err := r.Get(context.TODO(), types.NamespacedName{Name: request.Name}, node)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		node = nil
+	}
+
+	nodeImage := &appsv1alpha1.NodeImage{}
+	err = r.Get(context.TODO(), types.NamespacedName{Name: request.Name}, nodeImage)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		nodeImage = nil
+	}
+
+	if node == nil || node.DeletionTimestamp != nil {
+		if nodeImage != nil && nodeImage.DeletionTimestamp == nil {
+			klog.Infof("Deleting NodeImage %s for node has been removed", request.Name)
+			if err = r.Delete(context.TODO(), nodeImage); err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if nodeImage == nil {
+		klog.Infof("Creating NodeImage %s for node", request.Name)
+		nodeImage = &appsv1alpha1.NodeImage{ObjectMeta: metav1.ObjectMeta{Name: node.Name}}
+		if err = r.Create(context.TODO(), nodeImage); err != nil && !errors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+	if nodeImage.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	requeueAfter, err := r.updateNodeImageSpec(nodeImage.Name)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update spec: %v", err)
+	}
+	if err = r.updateNodeImageStatus(nodeImage.Name); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update status: %v", err)
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// pruneSpec returns the new spec without the tags whose owners are gone or whose ttl has expired,
+// and the duration after which the next tag will expire.
+func (r *ReconcileNodeImage) pruneSpec(nodeImage *appsv1alpha1.NodeImage) (*appsv1alpha1.NodeImageSpec, time.Duration, error) {
+	newSpec := nodeImage.Spec.DeepCopy()
+	var requeueAfter time.Duration
+	for name, imageSpec := range newSpec.Images {
+		var tags []appsv1alpha1.ImageTagSpec
+		for _, tagSpec := range imageSpec.Tags {
+			if left := ttlLeft(&tagSpec, getTagStatus(&nodeImage.Status, name, tagSpec.Tag)); left != nil {
+				if *left <= 0 {
+					klog.V(3).Infof("NodeImage %s prune tag %s:%s for ttl expired", nodeImage.Name, name, tagSpec.Tag)
+					continue
+				} else if requeueAfter == 0 || *left < requeueAfter {
+					requeueAfter = *left
+				}
+			}
+
+			if len(tagSpec.OwnerReferences) > 0 {
+				var refs []v1.ObjectReference
+				for _, ref := range tagSpec.OwnerReferences {
+					exists, err := r.ownerExists(ref)
+					if err != nil {
+						return nil, 0, err
+					}
+					if exists {
+						refs = append(refs, ref)
+					}
+				}
+				if len(refs) == 0 {
+					klog.V(3).Infof("NodeImage %s prune tag %s:%s for all owners gone", nodeImage.Name, name, tagSpec.Tag)
+					continue
+				}
+				tagSpec.OwnerReferences = refs
+			}
+			tags = append(tags, tagSpec)
+		}
+
+		if len(tags) == 0 {
+			delete(newSpec.Images, name)
+			continue
+		}
+		imageSpec.Tags = tags
+		newSpec.Images[name] = imageSpec
+	}
+	return newSpec, requeueAfter, nil
+}
+
+func (r *ReconcileNodeImage) ownerExists(ref v1.ObjectReference) (bool, error) {
+	if ref.Kind != "ImagePullJob" {
+		// unknown owner kind
+		return true, nil
+	}
+	job := &appsv1alpha1.ImagePullJob{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, job); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return ref.UID == "" || job.UID == ref.UID, nil
+}
+
+// updateNodeImageSpec prunes the spec of the latest NodeImage and updates it if changed,
+// it returns the duration after which the next tag will expire.
+func (r *ReconcileNodeImage) updateNodeImageSpec(name string) (time.Duration, error) {
+	var requeueAfter time.Duration
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		nodeImage := &appsv1alpha1.NodeImage{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: name}, nodeImage); err != nil {
+			return err
+		}
+		newSpec, left, err := r.pruneSpec(nodeImage)
+		if err != nil {
+			return err
+		}
+		requeueAfter = left
+		if apiequality.Semantic.DeepEqual(newSpec, &nodeImage.Spec) {
+			return nil
+		}
+		nodeImage.Spec = *newSpec
+		return r.Update(context.TODO(), nodeImage)
+	})
+	return requeueAfter, err
+}
+
+// updateNodeImageStatus calculates the status from the latest NodeImage and updates it if changed.
+func (r *ReconcileNodeImage) updateNodeImageStatus(name string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		nodeImage := &appsv1alpha1.NodeImage{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: name}, nodeImage); err != nil {
+			return err
+		}
+		newStatus := calculateStatus(&nodeImage.Spec, &nodeImage.Status)
+		if apiequality.Semantic.DeepEqual(newStatus, &nodeImage.Status) {
+			return nil
+		}
+		nodeImage.Status = *newStatus
+		return r.Status().Update(context.TODO(), nodeImage)
+	})
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeimage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openkruise/kruise/pkg/apis"
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	_ = apis.AddToScheme(scheme.Scheme)
+}
+
+func newJob(name string, uid types.UID) *appsv1alpha1.ImagePullJob {
+	return &appsv1alpha1.ImagePullJob{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: uid}}
+}
+
+func newOwnerRef(name string, uid types.UID) v1.ObjectReference {
+	return v1.ObjectReference{APIVersion: "apps.kruise.io/v1alpha1", Kind: "ImagePullJob", Namespace: "default", Name: name, UID: uid}
+}
+
+func TestReconcile(t *testing.T) {
+	ttl := int32(60)
+	longAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+	recently := metav1.NewTime(time.Now().Add(-time.Second))
+
+	cases := []struct {
+		name           string
+		objects        []runtime.Object
+		expectExists   bool
+		expectSpec     appsv1alpha1.NodeImageSpec
+		expectStatus   appsv1alpha1.NodeImageStatus
+		expectRequeued bool
+	}{
+		{
+			name:         "create NodeImage for new node",
+			objects:      []runtime.Object{&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}},
+			expectExists: true,
+		},
+		{
+			name:    "delete NodeImage for removed node",
+			objects: []runtime.Object{&appsv1alpha1.NodeImage{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}},
+		},
+		{
+			name: "prune tags whose owners are gone",
+			objects: []runtime.Object{
+				&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+				newJob("job1", "uid1"),
+				newJob("job2", "uid2-new"),
+				&appsv1alpha1.NodeImage{
+					ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+					Spec: appsv1alpha1.NodeImageSpec{Images: map[string]appsv1alpha1.ImageSpec{
+						"nginx": {Tags: []appsv1alpha1.ImageTagSpec{
+							{Tag: "1.18", OwnerReferences: []v1.ObjectReference{newOwnerRef("job1", "uid1"), newOwnerRef("job3", "uid3")}},
+							{Tag: "1.19", OwnerReferences: []v1.ObjectReference{newOwnerRef("job2", "uid2")}},
+							{Tag: "1.20"},
+						}},
+						"redis": {Tags: []appsv1alpha1.ImageTagSpec{
+							{Tag: "5", OwnerReferences: []v1.ObjectReference{newOwnerRef("job3", "uid3")}},
+						}},
+					}},
+					Status: appsv1alpha1.NodeImageStatus{
+						Desired: 4, Succeeded: 2, Pulling: 2,
+						ImageStatuses: map[string]appsv1alpha1.ImageStatus{
+							"nginx": {Tags: []appsv1alpha1.ImageTagStatus{
+								{Tag: "1.18", Phase: appsv1alpha1.ImagePhaseSucceeded},
+								{Tag: "1.19", Phase: appsv1alpha1.ImagePhaseSucceeded},
+							}},
+						},
+					},
+				},
+			},
+			expectExists: true,
+			expectSpec: appsv1alpha1.NodeImageSpec{Images: map[string]appsv1alpha1.ImageSpec{
+				"nginx": {Tags: []appsv1alpha1.ImageTagSpec{
+					{Tag: "1.18", OwnerReferences: []v1.ObjectReference{newOwnerRef("job1", "uid1")}},
+					{Tag: "1.20"},
+				}},
+			}},
+			expectStatus: appsv1alpha1.NodeImageStatus{
+				Desired: 2, Succeeded: 1, Pulling: 1,
+				ImageStatuses: map[string]appsv1alpha1.ImageStatus{
+					"nginx": {Tags: []appsv1alpha1.ImageTagStatus{{Tag: "1.18", Phase: appsv1alpha1.ImagePhaseSucceeded}}},
+				},
+			},
+		},
+		{
+			name: "prune tags whose ttl expired",
+			objects: []runtime.Object{
+				&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+				&appsv1alpha1.NodeImage{
+					ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+					Spec: appsv1alpha1.NodeImageSpec{Images: map[string]appsv1alpha1.ImageSpec{
+						"nginx": {Tags: []appsv1alpha1.ImageTagSpec{
+							{Tag: "1.18", PullPolicy: &appsv1alpha1.ImageTagPullPolicy{TTLSecondsAfterFinished: &ttl}},
+							{Tag: "1.19", PullPolicy: &appsv1alpha1.ImageTagPullPolicy{TTLSecondsAfterFinished: &ttl}},
+						}},
+					}},
+					Status: appsv1alpha1.NodeImageStatus{
+						Desired: 2, Succeeded: 1, Failed: 1,
+						ImageStatuses: map[string]appsv1alpha1.ImageStatus{
+							"nginx": {Tags: []appsv1alpha1.ImageTagStatus{
+								{Tag: "1.18", Phase: appsv1alpha1.ImagePhaseFailed, CompletionTime: &longAgo},
+								{Tag: "1.19", Phase: appsv1alpha1.ImagePhaseSucceeded, CompletionTime: &recently},
+							}},
+						},
+					},
+				},
+			},
+			expectExists: true,
+			expectSpec: appsv1alpha1.NodeImageSpec{Images: map[string]appsv1alpha1.ImageSpec{
+				"nginx": {Tags: []appsv1alpha1.ImageTagSpec{
+					{Tag: "1.19", PullPolicy: &appsv1alpha1.ImageTagPullPolicy{TTLSecondsAfterFinished: &ttl}},
+				}},
+			}},
+			expectStatus: appsv1alpha1.NodeImageStatus{
+				Desired: 1, Succeeded: 1,
+				ImageStatuses: map[string]appsv1alpha1.ImageStatus{
+					"nginx": {Tags: []appsv1alpha1.ImageTagStatus{{Tag: "1.19", Phase: appsv1alpha1.ImagePhaseSucceeded, CompletionTime: &recently}}},
+				},
+			},
+			expectRequeued: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(testCase.objects...)
+			r := &ReconcileNodeImage{Client: fakeClient, recorder: record.NewFakeRecorder(10)}
+
+			res, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "node1"}})
+			if err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+			if requeued := res.RequeueAfter > 0; requeued != testCase.expectRequeued {
+				t.Fatalf("expected requeued %v, got %v", testCase.expectRequeued, res)
+			}
+
+			nodeImage := &appsv1alpha1.NodeImage{}
+			err = fakeClient.Get(context.TODO(), types.NamespacedName{Name: "node1"}, nodeImage)
+			if exists := !errors.IsNotFound(err); exists != testCase.expectExists {
+				t.Fatalf("expected NodeImage exists %v, got err %v", testCase.expectExists, err)
+			}
+			if !testCase.expectExists {
+				return
+			}
+			if !equalSpec(&nodeImage.Spec, &testCase.expectSpec) {
+				t.Fatalf("expected spec %+v, got %+v", testCase.expectSpec, nodeImage.Spec)
+			}
+			if !equalStatus(&nodeImage.Status, &testCase.expectStatus) {
+				t.Fatalf("expected status %+v, got %+v", testCase.expectStatus, nodeImage.Status)
+			}
+		})
+	}
+}
+
+func equalSpec(a, b *appsv1alpha1.NodeImageSpec) bool {
+	if len(a.Images) == 0 && len(b.Images) == 0 {
+		return true
+	}
+	return apiequality.Semantic.DeepEqual(a, b)
+}
+
+// equalStatus ignores the CompletionTime which loses precision when round-tripped through the client.
+func equalStatus(a, b *appsv1alpha1.NodeImageStatus) bool {
+	a, b = a.DeepCopy(), b.DeepCopy()
+	for _, s := range []*appsv1alpha1.NodeImageStatus{a, b} {
+		for name, imageStatus := range s.ImageStatuses {
+			for i := range imageStatus.Tags {
+				imageStatus.Tags[i].CompletionTime = nil
+			}
+			s.ImageStatuses[name] = imageStatus
+		}
+		if len(s.ImageStatuses) == 0 {
+			s.ImageStatuses = nil
+		}
+	}
+	return apiequality.Semantic.DeepEqual(a, b)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeimage
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ handler.EventHandler = &nodeEventHandler{}
+
+type nodeEventHandler struct{}
+
+func (e *nodeEventHandler) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	if node, ok := evt.Object.(*v1.Node); ok {
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}})
+	}
+}
+
+func (e *nodeEventHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	oldNode, oldOK := evt.ObjectOld.(*v1.Node)
+	newNode, newOK := evt.ObjectNew.(*v1.Node)
+	if !oldOK || !newOK {
+		return
+	}
+	if oldNode.DeletionTimestamp == nil && newNode.DeletionTimestamp != nil {
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: newNode.Name}})
+	}
+}
+
+func (e *nodeEventHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	if node, ok := evt.Object.(*v1.Node); ok {
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}})
+	}
+}
+
+func (e *nodeEventHandler) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+}
+
+var _ handler.EventHandler = &imagePullJobEventHandler{}
+
+type imagePullJobEventHandler struct {
+	client client.Client
+}
+
+func (e *imagePullJobEventHandler) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (e *imagePullJobEventHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+}
+
+func (e *imagePullJobEventHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	job, ok := evt.Object.(*appsv1alpha1.ImagePullJob)
+	if !ok {
+		return
+	}
+
+	nodeImageList := &appsv1alpha1.NodeImageList{}
+	if err := e.client.List(context.TODO(), &client.ListOptions{}, nodeImageList); err != nil {
+		klog.Errorf("Failed to list NodeImages: %v", err)
+		return
+	}
+	for i := range nodeImageList.Items {
+		nodeImage := &nodeImageList.Items[i]
+		if isOwnedByJob(nodeImage, job) {
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: nodeImage.Name}})
+		}
+	}
+}
+
+func (e *imagePullJobEventHandler) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+}
+
+func isOwnedByJob(nodeImage *appsv1alpha1.NodeImage, job *appsv1alpha1.ImagePullJob) bool {
+	for _, imageSpec := range nodeImage.Spec.Images {
+		for _, tagSpec := range imageSpec.Tags {
+			for _, ref := range tagSpec.OwnerReferences {
+				if ref.Kind == "ImagePullJob" && ref.Namespace == job.Namespace && ref.Name == job.Name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
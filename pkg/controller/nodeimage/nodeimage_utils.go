@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeimage
+
+import (
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+)
+
+// calculateStatus returns the status that only contains the tags in spec, and with the numbers recalculated.
+func calculateStatus(spec *appsv1alpha1.NodeImageSpec, status *appsv1alpha1.NodeImageStatus) *appsv1alpha1.NodeImageStatus {
+	newStatus := &appsv1alpha1.NodeImageStatus{ObservedGeneration: status.ObservedGeneration}
+	for name, imageSpec := range spec.Images {
+		for _, tagSpec := range imageSpec.Tags {
+			newStatus.Desired++
+			tagStatus := getTagStatus(status, name, tagSpec.Tag)
+			if tagStatus == nil {
+				continue
+			}
+
+			switch tagStatus.Phase {
+			case appsv1alpha1.ImagePhaseSucceeded:
+				newStatus.Succeeded++
+			case appsv1alpha1.ImagePhaseFailed:
+				newStatus.Failed++
+			}
+			if newStatus.ImageStatuses == nil {
+				newStatus.ImageStatuses = make(map[string]appsv1alpha1.ImageStatus)
+			}
+			imageStatus := newStatus.ImageStatuses[name]
+			imageStatus.Tags = append(imageStatus.Tags, *tagStatus)
+			newStatus.ImageStatuses[name] = imageStatus
+		}
+	}
+	newStatus.Pulling = newStatus.Desired - newStatus.Succeeded - newStatus.Failed
+
+	// keep the original order of tags to avoid unnecessary updates
+	for name, imageStatus := range newStatus.ImageStatuses {
+		var tags []appsv1alpha1.ImageTagStatus
+		for _, oldTag := range status.ImageStatuses[name].Tags {
+			for _, tag := range imageStatus.Tags {
+				if tag.Tag == oldTag.Tag {
+					tags = append(tags, tag)
+					break
+				}
+			}
+		}
+		imageStatus.Tags = tags
+		newStatus.ImageStatuses[name] = imageStatus
+	}
+	return newStatus
+}
+
+func getTagStatus(status *appsv1alpha1.NodeImageStatus, name, tag string) *appsv1alpha1.ImageTagStatus {
+	imageStatus, ok := status.ImageStatuses[name]
+	if !ok {
+		return nil
+	}
+	for i := range imageStatus.Tags {
+		if imageStatus.Tags[i].Tag == tag {
+			return &imageStatus.Tags[i]
+		}
+	}
+	return nil
+}
+
+// ttlLeft returns the time left before the finished tag expires, or nil if it will never expire.
+func ttlLeft(tagSpec *appsv1alpha1.ImageTagSpec, tagStatus *appsv1alpha1.ImageTagStatus) *time.Duration {
+	if tagSpec.PullPolicy == nil || tagSpec.PullPolicy.TTLSecondsAfterFinished == nil ||
+		tagStatus == nil || tagStatus.CompletionTime == nil {
+		return nil
+	}
+	if tagStatus.Phase != appsv1alpha1.ImagePhaseSucceeded && tagStatus.Phase != appsv1alpha1.ImagePhaseFailed {
+		return nil
+	}
+	left := time.Duration(*tagSpec.PullPolicy.TTLSecondsAfterFinished)*time.Second - time.Since(tagStatus.CompletionTime.Time)
+	return &left
+}
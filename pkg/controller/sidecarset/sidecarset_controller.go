@@ -18,6 +18,7 @@ package sidecarset
 
 import (
 	"context"
+	"sort"
 
 	"github.com/openkruise/kruise/pkg/util/gate"
 	corev1 "k8s.io/api/core/v1"
@@ -35,6 +36,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	podmutating "github.com/openkruise/kruise/pkg/webhook/default_server/pod/mutating"
 )
 
 /**
@@ -93,6 +95,7 @@ type ReconcileSidecarSet struct {
 // Automatically generate RBAC rules to allow the Controller to read and write Deployments
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=sidecarsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=sidecarsets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 func (r *ReconcileSidecarSet) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	// Fetch the SidecarSet instance
 	sidecarSet := &appsv1alpha1.SidecarSet{}
@@ -114,19 +117,26 @@ func (r *ReconcileSidecarSet) Reconcile(request reconcile.Request) (reconcile.Re
 		return reconcile.Result{}, err
 	}
 	matchedPods := &corev1.PodList{}
-	if err := r.List(context.TODO(), &client.ListOptions{LabelSelector: selector}, matchedPods); err != nil {
+	if err := r.List(context.TODO(), &client.ListOptions{LabelSelector: selector, Namespace: sidecarSet.Spec.Namespace}, matchedPods); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	// ignore inactive pods and pods are created before sidecarset creates
+	// ignore inactive pods, pods out of the scope of sidecarset and pods are created before sidecarset creates
 	var filteredPods []*corev1.Pod
 	for i := range matchedPods.Items {
 		pod := &matchedPods.Items[i]
+		if !controllerutil.IsPodActive(pod) {
+			continue
+		}
+		matched, err := podmutating.PodMatchSidecarSet(r.Client, pod, *sidecarSet)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 		podCreateBeforeSidecarSet, err := isPodCreatedBeforeSidecarSet(sidecarSet, pod)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
-		if controllerutil.IsPodActive(pod) && !isIgnoredPod(pod) && !podCreateBeforeSidecarSet {
+		if matched && !podCreateBeforeSidecarSet {
 			filteredPods = append(filteredPods, pod)
 		}
 	}
@@ -184,6 +194,16 @@ func (r *ReconcileSidecarSet) Reconcile(request reconcile.Request) (reconcile.Re
 			podsNeedUpdate = append(podsNeedUpdate, pod)
 		}
 	}
+	// the pods in the middle of HotUpgrade come first, so that they can be completed
+	sort.SliceStable(podsNeedUpdate, func(i, j int) bool {
+		return isPodHotUpgrading(sidecarSet, podsNeedUpdate[i]) && !isPodHotUpgrading(sidecarSet, podsNeedUpdate[j])
+	})
 	updateNum := maxUnavailableNum - unavailableNum
-	return reconcile.Result{}, r.updateSidecarImageAndHash(sidecarSet, podsNeedUpdate, updateNum)
+	updateErr := r.updateSidecarImageAndHash(sidecarSet, podsNeedUpdate, updateNum)
+	if setReplicaFailureCondition(status, updateErr) {
+		if err := r.updateSidecarSetStatus(sidecarSet, status); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, updateErr
 }
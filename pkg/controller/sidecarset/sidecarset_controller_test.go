@@ -214,3 +214,176 @@ func TestUpdateWhenExceedsMaxUnavailable(t *testing.T) {
 		t.Errorf("shouldn't update sidecar because exceeds unavailable number")
 	}
 }
+
+func TestHotUpgrade(t *testing.T) {
+	sidecarSetInput := sidecarSetDemo.DeepCopy()
+	sidecarSetInput.Spec.Containers[0].UpgradeStrategy = &appsv1alpha1.SidecarContainerUpgradeStrategy{
+		UpgradeType:          appsv1alpha1.SidecarContainerHotUpgrade,
+		HotUpgradeEmptyImage: "empty:v1",
+	}
+	podInput := podDemo.DeepCopy()
+	podInput.Annotations[mutating.SidecarSetWorkingHotUpgradeContainerAnnotation] = `{"test-sidecar":"test-sidecar-1"}`
+	podInput.Spec.Containers = []corev1.Container{
+		{Name: "nginx", Image: "nginx:1.15.1"},
+		{Name: "test-sidecar-1", Image: "test-image:v1"},
+		{Name: "test-sidecar-2", Image: "empty:v1"},
+	}
+	// the container runtime reports the normalized image names
+	for _, c := range podInput.Spec.Containers {
+		podInput.Status.ContainerStatuses = append(podInput.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:    c.Name,
+			Image:   "docker.io/library/" + c.Image,
+			ImageID: "docker.io/library/" + c.Image + "@sha256:" + c.Name,
+			Ready:   true,
+			State:   corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		})
+	}
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: sidecarSetInput.Namespace,
+			Name:      sidecarSetInput.Name,
+		},
+	}
+
+	fakeClient := fake.NewFakeClientWithScheme(scheme, sidecarSetInput, podInput)
+	reconciler := ReconcileSidecarSet{Client: fakeClient}
+	if _, err := reconciler.Reconcile(request); err != nil {
+		t.Fatalf("reconcile failed, err: %v", err)
+	}
+
+	// the new image is started in the idle container
+	podOutput, err := getLatestPod(fakeClient, podInput)
+	if err != nil {
+		t.Fatalf("get latest pod failed, err: %v", err)
+	}
+	if !isSidecarImageUpdated(podOutput, "test-sidecar-1", "test-image:v1") || !isSidecarImageUpdated(podOutput, "test-sidecar-2", "test-image:v2") {
+		t.Fatalf("should start new image in the idle container, got %+v", podOutput.Spec.Containers)
+	}
+	if podOutput.Annotations[mutating.SidecarSetHashAnnotation] != `{"test-sidecarset":"aaa"}` {
+		t.Fatalf("should not update hash before the idle container is ready")
+	}
+	if podOutput.Annotations[mutating.SidecarSetHotUpgradeMigratingAnnotation] != "test-sidecar-2" {
+		t.Fatalf("should run migration hook in the idle container, got %v", podOutput.Annotations)
+	}
+	if podOutput.Annotations[mutating.SidecarSetHotUpgradeLastImageIDsAnnotation] != `{"test-sidecar-2":"docker.io/library/empty:v1@sha256:test-sidecar-2"}` {
+		t.Fatalf("should record the image ID of the empty image, got %v", podOutput.Annotations)
+	}
+
+	// the idle container is still running the empty image
+	if _, err := reconciler.Reconcile(request); err != nil {
+		t.Fatalf("reconcile failed, err: %v", err)
+	}
+	podOutput, err = getLatestPod(fakeClient, podInput)
+	if err != nil {
+		t.Fatalf("get latest pod failed, err: %v", err)
+	}
+	if podOutput.Annotations[mutating.SidecarSetWorkingHotUpgradeContainerAnnotation] != `{"test-sidecar":"test-sidecar-1"}` {
+		t.Fatalf("should not switch working container before the new image started, got %v", podOutput.Annotations)
+	}
+
+	// the idle container is running the new image
+	podOutput.Status.ContainerStatuses[2].Image = "docker.io/library/test-image:v2"
+	podOutput.Status.ContainerStatuses[2].ImageID = "docker.io/library/test-image:v2@sha256:test-sidecar-2"
+	if err := fakeClient.Update(context.TODO(), podOutput); err != nil {
+		t.Fatalf("update pod failed, err: %v", err)
+	}
+	if _, err := reconciler.Reconcile(request); err != nil {
+		t.Fatalf("reconcile failed, err: %v", err)
+	}
+
+	podOutput, err = getLatestPod(fakeClient, podInput)
+	if err != nil {
+		t.Fatalf("get latest pod failed, err: %v", err)
+	}
+	if !isSidecarImageUpdated(podOutput, "test-sidecar-1", "empty:v1") || !isSidecarImageUpdated(podOutput, "test-sidecar-2", "test-image:v2") {
+		t.Fatalf("should run empty image in the old container, got %+v", podOutput.Spec.Containers)
+	}
+	if podOutput.Annotations[mutating.SidecarSetWorkingHotUpgradeContainerAnnotation] != `{"test-sidecar":"test-sidecar-2"}` {
+		t.Fatalf("should switch working container, got %v", podOutput.Annotations[mutating.SidecarSetWorkingHotUpgradeContainerAnnotation])
+	}
+	if podOutput.Annotations[mutating.SidecarSetHashAnnotation] != `{"test-sidecarset":"ccc"}` {
+		t.Fatalf("should update hash after upgrade completed, got %v", podOutput.Annotations[mutating.SidecarSetHashAnnotation])
+	}
+	if _, ok := podOutput.Annotations[mutating.SidecarSetHotUpgradeMigratingAnnotation]; ok {
+		t.Fatalf("should not run migration hook after upgrade completed, got %v", podOutput.Annotations)
+	}
+	if _, ok := podOutput.Annotations[mutating.SidecarSetHotUpgradeLastImageIDsAnnotation]; ok {
+		t.Fatalf("should remove the image ID of the empty image after upgrade completed, got %v", podOutput.Annotations)
+	}
+}
+
+func TestUpdatePodSidecarWaitsForNewImageID(t *testing.T) {
+	sidecarSet := sidecarSetDemo.DeepCopy()
+	sidecarSet.Spec.Containers[0].UpgradeStrategy = &appsv1alpha1.SidecarContainerUpgradeStrategy{
+		UpgradeType:          appsv1alpha1.SidecarContainerHotUpgrade,
+		HotUpgradeEmptyImage: "empty:v1",
+	}
+	pod := podDemo.DeepCopy()
+	pod.Annotations[mutating.SidecarSetWorkingHotUpgradeContainerAnnotation] = `{"test-sidecar":"test-sidecar-1"}`
+	pod.Annotations[mutating.SidecarSetHotUpgradeLastImageIDsAnnotation] = `{"test-sidecar-2":"empty@sha256:1"}`
+	pod.Spec.Containers = []corev1.Container{
+		{Name: "test-sidecar-1", Image: "test-image:v1"},
+		{Name: "test-sidecar-2", Image: "test-image:v2"},
+	}
+	// the image is reported with the normalized name, and the image ID is still the one of the empty image
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{Name: "test-sidecar-1", Image: "docker.io/library/test-image:v1", ImageID: "test-image@sha256:1", Ready: true,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+		{Name: "test-sidecar-2", Image: "docker.io/library/test-image:v2", ImageID: "empty@sha256:1", Ready: true,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+	}
+
+	if completed, err := updatePodSidecar(sidecarSet, pod); err != nil || completed {
+		t.Fatalf("expected not completed before the image ID changed, got %v, %v", completed, err)
+	}
+	if pod.Spec.Containers[0].Image != "test-image:v1" {
+		t.Fatalf("should not switch working container before the image ID changed, got %+v", pod.Spec.Containers)
+	}
+
+	pod.Status.ContainerStatuses[1].ImageID = "test-image@sha256:2"
+	if completed, err := updatePodSidecar(sidecarSet, pod); err != nil || !completed {
+		t.Fatalf("expected completed after the image ID changed, got %v, %v", completed, err)
+	}
+	if pod.Spec.Containers[0].Image != "empty:v1" ||
+		pod.Annotations[mutating.SidecarSetWorkingHotUpgradeContainerAnnotation] != `{"test-sidecar":"test-sidecar-2"}` {
+		t.Fatalf("should switch working container, got %+v, %v", pod.Spec.Containers, pod.Annotations)
+	}
+	if _, ok := pod.Annotations[mutating.SidecarSetHotUpgradeLastImageIDsAnnotation]; ok {
+		t.Fatalf("should remove the recorded image ID, got %v", pod.Annotations)
+	}
+}
+
+func TestCalculateConditions(t *testing.T) {
+	sidecarSet := sidecarSetDemo.DeepCopy()
+	replicaFailure := appsv1alpha1.SidecarSetCondition{
+		Type:    appsv1alpha1.SidecarSetConditionReplicaFailure,
+		Status:  corev1.ConditionTrue,
+		Reason:  appsv1alpha1.WorkloadReasonFailedUpdate,
+		Message: "conflict",
+	}
+	sidecarSet.Status = appsv1alpha1.SidecarSetStatus{
+		MatchedPods: 2,
+		UpdatedPods: 1,
+		ReadyPods:   2,
+		Conditions:  []appsv1alpha1.SidecarSetCondition{replicaFailure},
+	}
+	status := &appsv1alpha1.SidecarSetStatus{MatchedPods: 2, UpdatedPods: 2, ReadyPods: 2}
+	conditions := calculateConditions(sidecarSet, status, 2)
+
+	expectedReasons := map[appsv1alpha1.SidecarSetConditionType]string{
+		appsv1alpha1.SidecarSetConditionAvailable:      appsv1alpha1.WorkloadReasonMinimumReplicasAvailable,
+		appsv1alpha1.SidecarSetConditionProgressing:    appsv1alpha1.WorkloadReasonNewRevisionAvailable,
+		appsv1alpha1.SidecarSetConditionReplicaFailure: appsv1alpha1.WorkloadReasonFailedUpdate,
+	}
+	if len(conditions) != len(expectedReasons) {
+		t.Fatalf("expected %d conditions, got %+v", len(expectedReasons), conditions)
+	}
+	for _, c := range conditions {
+		if c.Reason != expectedReasons[c.Type] {
+			t.Errorf("expected %s condition with reason %s, got %+v", c.Type, expectedReasons[c.Type], c)
+		}
+	}
+	if sidecarSet.Status.Conditions[0] != replicaFailure {
+		t.Errorf("expected conditions of old status unchanged, got %+v", sidecarSet.Status.Conditions)
+	}
+}
@@ -134,7 +134,7 @@ func (p *enqueueRequestForPod) getPodSidecarSets(pod *corev1.Pod) ([]appsv1alpha
 
 	var matchedSidecarSets []appsv1alpha1.SidecarSet
 	for _, sidecarSet := range sidecarSets.Items {
-		matched, err := mutating.PodMatchSidecarSet(pod, sidecarSet)
+		matched, err := mutating.PodMatchSidecarSet(p.client, pod, sidecarSet)
 		if err != nil {
 			return nil, err
 		}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/util/retry"
@@ -15,7 +17,8 @@ import (
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
-	podmutating "github.com/openkruise/kruise/pkg/webhook/default_server/pod/mutating"
+	"github.com/openkruise/kruise/pkg/util"
+	"github.com/openkruise/kruise/pkg/util/condition"
 	sidecarsetmutating "github.com/openkruise/kruise/pkg/webhook/default_server/sidecarset/mutating"
 )
 
@@ -25,17 +28,8 @@ var (
 	}
 )
 
-func isIgnoredPod(pod *corev1.Pod) bool {
-	for _, namespace := range podmutating.SidecarIgnoredNamespaces {
-		if pod.Namespace == namespace {
-			return true
-		}
-	}
-	return false
-}
-
 func calculateStatus(sidecarSet *appsv1alpha1.SidecarSet, pods []*corev1.Pod) (*appsv1alpha1.SidecarSetStatus, error) {
-	var matchedPods, updatedPods, readyPods int32
+	var matchedPods, updatedPods, readyPods, updatedReadyPods int32
 	matchedPods = int32(len(pods))
 	for _, pod := range pods {
 		updated, err := isPodSidecarUpdated(sidecarSet, pod)
@@ -48,15 +42,131 @@ func calculateStatus(sidecarSet *appsv1alpha1.SidecarSet, pods []*corev1.Pod) (*
 
 		if isRunningAndReady(pod) {
 			readyPods++
+			if updated {
+				updatedReadyPods++
+			}
 		}
 	}
 
-	return &appsv1alpha1.SidecarSetStatus{
+	status := &appsv1alpha1.SidecarSetStatus{
 		ObservedGeneration: sidecarSet.Generation,
 		MatchedPods:        matchedPods,
 		UpdatedPods:        updatedPods,
 		ReadyPods:          readyPods,
-	}, nil
+	}
+	status.Conditions = calculateConditions(sidecarSet, status, updatedReadyPods)
+	return status, nil
+}
+
+// calculateConditions computes the Progressing, Available and ReplicaFailure conditions of SidecarSet.
+// The ReplicaFailure condition is kept until the next update of pod sidecars succeeds.
+func calculateConditions(sidecarSet *appsv1alpha1.SidecarSet, status *appsv1alpha1.SidecarSetStatus, updatedReadyPods int32) []appsv1alpha1.SidecarSetCondition {
+	var maxUnavailable int
+	if sidecarSet.Spec.Strategy.RollingUpdate != nil {
+		maxUnavailable, _ = intstrutil.GetValueFromIntOrPercent(
+			intstrutil.ValueOrDefault(sidecarSet.Spec.Strategy.RollingUpdate.MaxUnavailable, intstrutil.FromInt(0)),
+			int(status.MatchedPods),
+			false)
+	}
+	hash := sidecarSet.Annotations[sidecarsetmutating.SidecarSetHashAnnotation]
+	newProgress := &condition.Progress{
+		Kind:                 "SidecarSet",
+		Replicas:             status.MatchedPods,
+		ActualReplicas:       status.MatchedPods,
+		AvailableReplicas:    status.ReadyPods,
+		MinAvailableReplicas: status.MatchedPods - int32(maxUnavailable),
+		UpdatedReplicas:      status.UpdatedPods,
+		UpdatedReadyReplicas: updatedReadyPods,
+		UpdateRevision:       hash,
+		Paused:               sidecarSet.Spec.Paused,
+	}
+	// the hash and the number of updated and ready pods are not recorded in status
+	oldProgress := &condition.Progress{
+		Replicas:             sidecarSet.Status.MatchedPods,
+		ActualReplicas:       sidecarSet.Status.MatchedPods,
+		AvailableReplicas:    sidecarSet.Status.ReadyPods,
+		UpdatedReplicas:      sidecarSet.Status.UpdatedPods,
+		UpdatedReadyReplicas: updatedReadyPods,
+		UpdateRevision:       hash,
+	}
+
+	oldConditions := toConditions(sidecarSet.Status.Conditions)
+	if c := condition.GetCondition(oldConditions, appsv1alpha1.WorkloadConditionReplicaFailure); c != nil && c.Status == corev1.ConditionTrue {
+		newProgress.FailureReason = c.Reason
+		newProgress.FailureMessage = c.Message
+	}
+	conditions := condition.Compute(oldConditions, oldProgress, newProgress, time.Now())
+	return setWorkloadConditions(sidecarSet.Status.Conditions, conditions)
+}
+
+// toConditions converts the conditions of SidecarSet into the common form of workload conditions.
+func toConditions(list []appsv1alpha1.SidecarSetCondition) []condition.Condition {
+	var conditions []condition.Condition
+	for _, c := range list {
+		conditions = append(conditions, condition.Condition{
+			Type:               string(c.Type),
+			Status:             c.Status,
+			LastUpdateTime:     c.LastUpdateTime,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	return conditions
+}
+
+// setWorkloadConditions returns the conditions of SidecarSet in list whose types are not computed by package condition,
+// followed by the given workload conditions.
+func setWorkloadConditions(list []appsv1alpha1.SidecarSetCondition, conditions []condition.Condition) []appsv1alpha1.SidecarSetCondition {
+	var newList []appsv1alpha1.SidecarSetCondition
+	for _, c := range list {
+		if !condition.IsWorkloadConditionType(string(c.Type)) {
+			newList = append(newList, c)
+		}
+	}
+	for _, c := range conditions {
+		newList = append(newList, appsv1alpha1.SidecarSetCondition{
+			Type:               appsv1alpha1.SidecarSetConditionType(c.Type),
+			Status:             c.Status,
+			LastUpdateTime:     c.LastUpdateTime,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	return newList
+}
+
+// setReplicaFailureCondition sets the ReplicaFailure condition in status if the update of pod sidecars failed,
+// or removes it otherwise. It returns whether the conditions have been changed.
+func setReplicaFailureCondition(status *appsv1alpha1.SidecarSetStatus, updateErr error) bool {
+	var conditions []appsv1alpha1.SidecarSetCondition
+	var oldCondition *appsv1alpha1.SidecarSetCondition
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == appsv1alpha1.SidecarSetConditionReplicaFailure {
+			oldCondition = &status.Conditions[i]
+			continue
+		}
+		conditions = append(conditions, status.Conditions[i])
+	}
+
+	if updateErr == nil {
+		status.Conditions = conditions
+		return oldCondition != nil
+	}
+	if oldCondition != nil && oldCondition.Reason == appsv1alpha1.WorkloadReasonFailedUpdate && oldCondition.Message == updateErr.Error() {
+		return false
+	}
+	now := metav1.Now()
+	status.Conditions = append(conditions, appsv1alpha1.SidecarSetCondition{
+		Type:               appsv1alpha1.SidecarSetConditionReplicaFailure,
+		Status:             corev1.ConditionTrue,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+		Reason:             appsv1alpha1.WorkloadReasonFailedUpdate,
+		Message:            updateErr.Error(),
+	})
+	return true
 }
 
 func isPodSidecarUpdated(sidecarSet *appsv1alpha1.SidecarSet, pod *corev1.Pod) (bool, error) {
@@ -109,7 +219,8 @@ func inconsistentStatus(sidecarSet *appsv1alpha1.SidecarSet, status *appsv1alpha
 	return status.ObservedGeneration > sidecarSet.Status.ObservedGeneration ||
 		status.MatchedPods != sidecarSet.Status.MatchedPods ||
 		status.UpdatedPods != sidecarSet.Status.UpdatedPods ||
-		status.ReadyPods != sidecarSet.Status.ReadyPods
+		status.ReadyPods != sidecarSet.Status.ReadyPods ||
+		condition.Inconsistent(toConditions(sidecarSet.Status.Conditions), toConditions(status.Conditions))
 }
 
 // add this cache to avoid be influenced by informer cache latency when controller try to count maxUnavailable
@@ -161,7 +272,7 @@ func (u *updatedPodCache) reset(sidecarSet *appsv1alpha1.SidecarSet) {
 }
 
 // available definition:
-// 1. image in pod.spec and pod.status is exactly the same
+// 1. image in pod.spec and pod.status is the same after being normalized
 // 2. pod is ready
 func getUnavailableNumber(sidecarSet *appsv1alpha1.SidecarSet, pods []*corev1.Pod) (int, error) {
 	var unavailableNum int
@@ -231,7 +342,7 @@ func isPodImageConsistent(pod *corev1.Pod) bool {
 	}
 
 	for _, containerStatus := range pod.Status.ContainerStatuses {
-		if containerSpecImage[containerStatus.Name] != containerStatus.Image {
+		if !util.IsContainerImageEqual(containerSpecImage[containerStatus.Name], containerStatus.Image) {
 			return false
 		}
 	}
@@ -245,9 +356,14 @@ func (r *ReconcileSidecarSet) updateSidecarImageAndHash(sidecarSet *appsv1alpha1
 
 	for i := 0; i < updateNum; i++ {
 		klog.V(3).Infof("try to update sidecar of %v/%v", pods[i].Namespace, pods[i].Name)
-		if err := r.updatePodSidecarAndHash(sidecarSet, pods[i]); err != nil {
+		completed, err := r.updatePodSidecarAndHash(sidecarSet, pods[i])
+		if err != nil {
 			return err
 		}
+		// the pod with HotUpgrade sidecars in progress will be updated again when the new containers are ready
+		if !completed {
+			continue
+		}
 		updateCache.set(
 			fmt.Sprintf("%v/%v/%v", sidecarSet.Name, pods[i].Namespace, pods[i].Name),
 			sidecarSet.Annotations[sidecarsetmutating.SidecarSetHashAnnotation])
@@ -255,24 +371,32 @@ func (r *ReconcileSidecarSet) updateSidecarImageAndHash(sidecarSet *appsv1alpha1
 	return nil
 }
 
-func (r *ReconcileSidecarSet) updatePodSidecarAndHash(sidecarSet *appsv1alpha1.SidecarSet, pod *corev1.Pod) error {
+// updatePodSidecarAndHash updates the sidecar containers of pod, and returns whether the update is completed,
+// in which case the sidecarset hash of pod is updated.
+func (r *ReconcileSidecarSet) updatePodSidecarAndHash(sidecarSet *appsv1alpha1.SidecarSet, pod *corev1.Pod) (bool, error) {
 	podClone := pod.DeepCopy()
+	var completed bool
 	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		// update sidecar image
-		updatePodSidecar(sidecarSet, podClone)
-
-		// update hash
-		hashKey := sidecarsetmutating.SidecarSetHashAnnotation
-		sidecarSetHash := make(map[string]string)
-		if err := json.Unmarshal([]byte(podClone.Annotations[hashKey]), &sidecarSetHash); err != nil {
+		var err error
+		if completed, err = updatePodSidecar(sidecarSet, podClone); err != nil {
 			return err
 		}
-		sidecarSetHash[sidecarSet.Name] = sidecarSet.Annotations[hashKey]
-		newHash, err := json.Marshal(sidecarSetHash)
-		if err != nil {
-			return err
+
+		// update hash
+		if completed {
+			hashKey := sidecarsetmutating.SidecarSetHashAnnotation
+			sidecarSetHash := make(map[string]string)
+			if err := json.Unmarshal([]byte(podClone.Annotations[hashKey]), &sidecarSetHash); err != nil {
+				return err
+			}
+			sidecarSetHash[sidecarSet.Name] = sidecarSet.Annotations[hashKey]
+			newHash, err := json.Marshal(sidecarSetHash)
+			if err != nil {
+				return err
+			}
+			podClone.Annotations[hashKey] = string(newHash)
 		}
-		podClone.Annotations[hashKey] = string(newHash)
 
 		updateErr := r.Update(context.TODO(), podClone)
 		if updateErr == nil {
@@ -291,13 +415,83 @@ func (r *ReconcileSidecarSet) updatePodSidecarAndHash(sidecarSet *appsv1alpha1.S
 		return updateErr
 	})
 
-	return err
+	return completed, err
 }
 
-func updatePodSidecar(sidecarSet *appsv1alpha1.SidecarSet, pod *corev1.Pod) {
+// updatePodSidecar updates the images of sidecar containers in pod, and returns whether all the sidecars have been
+// upgraded. A HotUpgrade sidecar is upgraded in two steps, which are decided by its working container recorded in
+// pod annotations and the image of its idle container, so that the upgrade can be resumed after controller restarts:
+//  1. start the new image in the idle container, whose postStart hook migrates the state from the working container,
+//     since it is recorded as migrating in pod annotations;
+//  2. after the idle container is running and ready with a new image ID, switch it to be the working container, and run the empty image
+//     in the old working container. Neither of them runs the migration hook on later restarts.
+func updatePodSidecar(sidecarSet *appsv1alpha1.SidecarSet, pod *corev1.Pod) (bool, error) {
+	workingContainers, err := sidecarsetmutating.GetPodWorkingHotUpgradeContainers(pod)
+	if err != nil {
+		return false, err
+	}
+
+	completed := true
 	sidecarImage := make(map[string]string, len(sidecarSet.Spec.Containers))
-	for _, container := range sidecarSet.Spec.Containers {
-		sidecarImage[container.Name] = container.Image
+	for i := range sidecarSet.Spec.Containers {
+		sidecarContainer := &sidecarSet.Spec.Containers[i]
+		if !sidecarsetmutating.IsHotUpgradeContainer(sidecarContainer) {
+			sidecarImage[sidecarContainer.Name] = sidecarContainer.Image
+			continue
+		}
+
+		working, idle, err := sidecarsetmutating.GetPodHotUpgradeContainers(sidecarContainer.Name, pod)
+		if err != nil {
+			return false, err
+		}
+		workingContainer, idleContainer := getPodContainer(pod, working), getPodContainer(pod, idle)
+		if workingContainer == nil || idleContainer == nil {
+			continue
+		}
+		if workingContainer.Image == sidecarContainer.Image {
+			// stop the idle container if the sidecarset has been rolled back during upgrade
+			idleContainer.Image = sidecarContainer.UpgradeStrategy.HotUpgradeEmptyImage
+			sidecarsetmutating.SetPodHotUpgradeMigratingContainer(pod, idle, false)
+			if err := sidecarsetmutating.SetPodHotUpgradeLastImageID(pod, idle, ""); err != nil {
+				return false, err
+			}
+			continue
+		}
+		if idleContainer.Image != sidecarContainer.Image {
+			completed = false
+			idleStatus := getContainerStatus(pod, idle)
+			if idleStatus == nil || idleStatus.ImageID == "" {
+				klog.V(3).Infof("wait for the image ID of idle container %s of pod %s/%s", idle, pod.Namespace, pod.Name)
+				continue
+			}
+			klog.V(3).Infof("start image %s in idle container %s of pod %s/%s", sidecarContainer.Image, idle, pod.Namespace, pod.Name)
+			idleContainer.Image = sidecarContainer.Image
+			// the idle container runs the migration hook when it starts the new image
+			sidecarsetmutating.SetPodHotUpgradeMigratingContainer(pod, idle, true)
+			// the image ID of the empty image is recorded to check whether the new image has been started
+			if err := sidecarsetmutating.SetPodHotUpgradeLastImageID(pod, idle, idleStatus.ImageID); err != nil {
+				return false, err
+			}
+			continue
+		}
+		if started, err := isNewImageRunningAndReady(pod, idle); err != nil {
+			return false, err
+		} else if !started {
+			completed = false
+			continue
+		}
+		klog.V(3).Infof("switch working container from %s to %s in pod %s/%s", working, idle, pod.Namespace, pod.Name)
+		workingContainer.Image = sidecarContainer.UpgradeStrategy.HotUpgradeEmptyImage
+		workingContainers[sidecarContainer.Name] = idle
+		sidecarsetmutating.SetPodHotUpgradeMigratingContainer(pod, idle, false)
+		if err := sidecarsetmutating.SetPodHotUpgradeLastImageID(pod, idle, ""); err != nil {
+			return false, err
+		}
+	}
+	if len(workingContainers) != 0 {
+		if err := sidecarsetmutating.SetPodWorkingHotUpgradeContainers(pod, workingContainers); err != nil {
+			return false, err
+		}
 	}
 
 	for i := range pod.Spec.Containers {
@@ -306,6 +500,61 @@ func updatePodSidecar(sidecarSet *appsv1alpha1.SidecarSet, pod *corev1.Pod) {
 			container.Image = image
 		}
 	}
+	return completed, nil
+}
+
+// isPodHotUpgrading returns true if the new image of any HotUpgrade sidecar has been started in the idle container.
+func isPodHotUpgrading(sidecarSet *appsv1alpha1.SidecarSet, pod *corev1.Pod) bool {
+	for i := range sidecarSet.Spec.Containers {
+		sidecarContainer := &sidecarSet.Spec.Containers[i]
+		if !sidecarsetmutating.IsHotUpgradeContainer(sidecarContainer) {
+			continue
+		}
+		working, idle, err := sidecarsetmutating.GetPodHotUpgradeContainers(sidecarContainer.Name, pod)
+		if err != nil {
+			continue
+		}
+		workingContainer, idleContainer := getPodContainer(pod, working), getPodContainer(pod, idle)
+		if workingContainer != nil && idleContainer != nil &&
+			workingContainer.Image != sidecarContainer.Image && idleContainer.Image == sidecarContainer.Image {
+			return true
+		}
+	}
+	return false
+}
+
+func getPodContainer(pod *corev1.Pod, name string) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+func getContainerStatus(pod *corev1.Pod, name string) *corev1.ContainerStatus {
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == name {
+			return &pod.Status.ContainerStatuses[i]
+		}
+	}
+	return nil
+}
+
+// isNewImageRunningAndReady returns true if the container is running and ready with an image ID different from the
+// one recorded before it started the new image. The image in container status is not compared with the one in spec,
+// for it may be normalized by the container runtime, e.g. nginx:1.0 is reported as docker.io/library/nginx:1.0.
+func isNewImageRunningAndReady(pod *corev1.Pod, name string) (bool, error) {
+	status := getContainerStatus(pod, name)
+	if status == nil || status.State.Running == nil || !status.Ready {
+		return false, nil
+	}
+	lastImageIDs, err := sidecarsetmutating.GetPodHotUpgradeLastImageIDs(pod)
+	if err != nil {
+		return false, err
+	}
+	// TODO: we assume that the new image does not have the same imageID as the empty image
+	return status.ImageID != lastImageIDs[name], nil
 }
 
 func getMaxUnavailable(sidecarSet *appsv1alpha1.SidecarSet) int {
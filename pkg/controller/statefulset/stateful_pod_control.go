@@ -23,14 +23,17 @@ import (
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
 	kruiseappslisters "github.com/openkruise/kruise/pkg/client/listers/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/volumeclaim"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientset "k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
 )
 
 // StatefulPodControlInterface defines the interface that StatefulSetController uses to create, update, and delete Pods,
@@ -46,9 +49,31 @@ type StatefulPodControlInterface interface {
 	// pod is an in-out parameter, and any updates made to the pod are reflected as mutations to this parameter. If
 	// the create is successful, the returned error is nil.
 	UpdateStatefulPod(set *appsv1alpha1.StatefulSet, pod *v1.Pod) error
+	// UpdateStatefulPodRevision updates the revision label of a Pod in a StatefulSet, whose template is not changed
+	// in the revision, so it needs neither recreation nor in-place update. pod is an in-out parameter, and the update
+	// is reflected as mutations to this parameter. If the update is successful, the returned error is nil.
+	UpdateStatefulPodRevision(set *appsv1alpha1.StatefulSet, pod *v1.Pod, revision string) error
 	// DeleteStatefulPod deletes a Pod in a StatefulSet. The pods PVCs are not deleted. If the delete is successful,
 	// the returned error is nil.
 	DeleteStatefulPod(set *appsv1alpha1.StatefulSet, pod *v1.Pod) error
+	// ClaimsMatchRetentionPolicy returns false if the PVCs for pod are not consistent with set's PVC retention
+	// policy. An error is returned if something is not consistent. This is expected if the pod is being otherwise
+	// updated, but a problem otherwise. condemned tells whether pod is going to be deleted by scale-in.
+	ClaimsMatchRetentionPolicy(set *appsv1alpha1.StatefulSet, pod *v1.Pod, condemned bool) (bool, error)
+	// UpdatePodClaimForRetentionPolicy updates the owner references on the PVCs of pod, so that they will be
+	// garbage collected according to set's PVC retention policy. If the returned error is nil the PVCs are consistent.
+	// condemned tells whether pod is going to be deleted by scale-in.
+	UpdatePodClaimForRetentionPolicy(set *appsv1alpha1.StatefulSet, pod *v1.Pod, condemned bool) error
+	// PodClaimIsStale returns true if any PVC of pod is owned by a previous Pod of the same ordinal, which means the
+	// PVC is going to be garbage collected and the new Pod should not be created until it is gone.
+	PodClaimIsStale(set *appsv1alpha1.StatefulSet, pod *v1.Pod) (bool, error)
+	// UpdatePodClaimsStorage expands the storage requests of the PVCs of pod to the ones in set's
+	// volumeClaimTemplates, if their StorageClasses allow volume expansion. If the returned error is nil
+	// all expandable PVCs have been updated.
+	UpdatePodClaimsStorage(set *appsv1alpha1.StatefulSet, pod *v1.Pod) error
+	// PodClaimsStorageState returns whether the expansion of all PVCs of pod has completed, and whether any
+	// of them is waiting for the filesystem to be resized, which requires pod to be recreated.
+	PodClaimsStorageState(set *appsv1alpha1.StatefulSet, pod *v1.Pod) (completed, fileSystemResizePending bool, err error)
 }
 
 // NewRealStatefulPodControl returns a new realStatefulPodControl
@@ -57,9 +82,10 @@ func NewRealStatefulPodControl(
 	setLister kruiseappslisters.StatefulSetLister,
 	podLister corelisters.PodLister,
 	pvcLister corelisters.PersistentVolumeClaimLister,
+	scLister storagelisters.StorageClassLister,
 	recorder record.EventRecorder,
 ) StatefulPodControlInterface {
-	return &realStatefulPodControl{client, setLister, podLister, pvcLister, recorder}
+	return &realStatefulPodControl{client, setLister, podLister, pvcLister, scLister, recorder}
 }
 
 // realStatefulPodControl implements StatefulPodControlInterface using a clientset.Interface to communicate with the
@@ -69,6 +95,7 @@ type realStatefulPodControl struct {
 	setLister kruiseappslisters.StatefulSetLister
 	podLister corelisters.PodLister
 	pvcLister corelisters.PersistentVolumeClaimLister
+	scLister  storagelisters.StorageClassLister
 	recorder  record.EventRecorder
 }
 
@@ -135,12 +162,146 @@ func (spc *realStatefulPodControl) UpdateStatefulPod(set *appsv1alpha1.StatefulS
 	return err
 }
 
+func (spc *realStatefulPodControl) UpdateStatefulPodRevision(set *appsv1alpha1.StatefulSet, pod *v1.Pod, revision string) error {
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		updatePodRevision(pod, revision)
+		updated, updateErr := spc.client.CoreV1().Pods(set.Namespace).Update(pod)
+		if updateErr == nil {
+			*pod = *updated
+			return nil
+		}
+
+		if updated, err := spc.podLister.Pods(set.Namespace).Get(pod.Name); err == nil {
+			// make a copy so we don't mutate the shared cache
+			*pod = *updated.DeepCopy()
+		} else {
+			utilruntime.HandleError(fmt.Errorf("error getting updated Pod %s/%s from lister: %v", set.Namespace, pod.Name, err))
+		}
+
+		return updateErr
+	})
+	spc.recordPodEvent("update", set, pod, err)
+	return err
+}
+
 func (spc *realStatefulPodControl) DeleteStatefulPod(set *appsv1alpha1.StatefulSet, pod *v1.Pod) error {
 	err := spc.client.CoreV1().Pods(set.Namespace).Delete(pod.Name, nil)
 	spc.recordPodEvent("delete", set, pod, err)
 	return err
 }
 
+func (spc *realStatefulPodControl) ClaimsMatchRetentionPolicy(set *appsv1alpha1.StatefulSet, pod *v1.Pod, condemned bool) (bool, error) {
+	for _, claim := range getPersistentVolumeClaims(set, pod) {
+		existing, err := spc.pvcLister.PersistentVolumeClaims(claim.Namespace).Get(claim.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+			// the claim will be created with the right owner references
+			continue
+		case err != nil:
+			return false, fmt.Errorf("Could not retrieve claim %s for %s when checking PVC deletion policy: %v", claim.Name, pod.Name, err)
+		}
+		if updateClaimOwnerRefForSetAndPod(existing.DeepCopy(), set, pod, condemned) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (spc *realStatefulPodControl) UpdatePodClaimForRetentionPolicy(set *appsv1alpha1.StatefulSet, pod *v1.Pod, condemned bool) error {
+	for _, claim := range getPersistentVolumeClaims(set, pod) {
+		existing, err := spc.pvcLister.PersistentVolumeClaims(claim.Namespace).Get(claim.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+			continue
+		case err != nil:
+			return fmt.Errorf("Could not retrieve claim %s for %s when checking PVC deletion policy: %v", claim.Name, pod.Name, err)
+		}
+		updated := existing.DeepCopy()
+		if !updateClaimOwnerRefForSetAndPod(updated, set, pod, condemned) {
+			continue
+		}
+		_, err = spc.client.CoreV1().PersistentVolumeClaims(updated.Namespace).Update(updated)
+		spc.recordClaimEvent("update", set, pod, updated, err)
+		if err != nil {
+			return fmt.Errorf("Could not update claim %s for delete policy ownerRefs: %v", updated.Name, err)
+		}
+	}
+	return nil
+}
+
+func (spc *realStatefulPodControl) PodClaimIsStale(set *appsv1alpha1.StatefulSet, pod *v1.Pod) (bool, error) {
+	for _, claim := range getPersistentVolumeClaims(set, pod) {
+		existing, err := spc.pvcLister.PersistentVolumeClaims(claim.Namespace).Get(claim.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+			// if the claim doesn't exist, nothing is stale
+			continue
+		case err != nil:
+			return false, err
+		}
+		if isClaimOwnedByStalePod(existing, pod) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (spc *realStatefulPodControl) UpdatePodClaimsStorage(set *appsv1alpha1.StatefulSet, pod *v1.Pod) error {
+	for _, claim := range getPersistentVolumeClaims(set, pod) {
+		existing, err := spc.pvcLister.PersistentVolumeClaims(claim.Namespace).Get(claim.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+			continue
+		case err != nil:
+			return fmt.Errorf("Could not retrieve claim %s for %s when expanding storage: %v", claim.Name, pod.Name, err)
+		}
+		if !volumeclaim.IsExpansionRequired(existing, &claim) {
+			continue
+		}
+		scName := volumeclaim.GetStorageClassName(existing)
+		if scName == "" {
+			klog.V(4).Infof("Skip expanding claim %s/%s with no StorageClass", existing.Namespace, existing.Name)
+			continue
+		}
+		sc, err := spc.scLister.Get(scName)
+		if err != nil {
+			return fmt.Errorf("Could not retrieve StorageClass %s of claim %s: %v", scName, existing.Name, err)
+		}
+		if !volumeclaim.AllowsExpansion(sc) {
+			klog.V(4).Infof("Skip expanding claim %s/%s, StorageClass %s does not allow volume expansion", existing.Namespace, existing.Name, scName)
+			continue
+		}
+		updated := volumeclaim.NewExpandedClaim(existing, &claim)
+		_, err = spc.client.CoreV1().PersistentVolumeClaims(updated.Namespace).Update(updated)
+		spc.recordClaimEvent("update", set, pod, updated, err)
+		if err != nil {
+			return fmt.Errorf("Could not expand storage of claim %s: %v", updated.Name, err)
+		}
+	}
+	return nil
+}
+
+func (spc *realStatefulPodControl) PodClaimsStorageState(set *appsv1alpha1.StatefulSet, pod *v1.Pod) (bool, bool, error) {
+	completed, fileSystemResizePending := true, false
+	for _, claim := range getPersistentVolumeClaims(set, pod) {
+		existing, err := spc.pvcLister.PersistentVolumeClaims(claim.Namespace).Get(claim.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+			completed = false
+			continue
+		case err != nil:
+			return false, false, err
+		}
+		if !volumeclaim.IsExpansionCompleted(existing, &claim) {
+			completed = false
+		}
+		if volumeclaim.IsFileSystemResizePending(existing) {
+			fileSystemResizePending = true
+		}
+	}
+	return completed, fileSystemResizePending, nil
+}
+
 // recordPodEvent records an event for verb applied to a Pod in a StatefulSet. If err is nil the generated event will
 // have a reason of v1.EventTypeNormal. If err is not nil the generated event will have a reason of v1.EventTypeWarning.
 func (spc *realStatefulPodControl) recordPodEvent(verb string, set *appsv1alpha1.StatefulSet, pod *v1.Pod, err error) {
@@ -184,6 +345,7 @@ func (spc *realStatefulPodControl) createPersistentVolumeClaims(set *appsv1alpha
 		_, err := spc.pvcLister.PersistentVolumeClaims(claim.Namespace).Get(claim.Name)
 		switch {
 		case apierrors.IsNotFound(err):
+			updateClaimOwnerRefForSetAndPod(&claim, set, pod, false)
 			_, err := spc.client.CoreV1().PersistentVolumeClaims(claim.Namespace).Create(&claim)
 			if err != nil {
 				errs = append(errs, fmt.Errorf("Failed to create PVC %s: %s", claim.Name, err))
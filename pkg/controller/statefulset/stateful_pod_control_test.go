@@ -22,7 +22,10 @@ import (
 	"strings"
 	"testing"
 
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	core "k8s.io/client-go/testing"
@@ -32,6 +35,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/fake"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
 	_ "k8s.io/kubernetes/pkg/apis/apps/install"
 	_ "k8s.io/kubernetes/pkg/apis/core/install"
 )
@@ -43,7 +47,7 @@ func TestStatefulPodControlCreatesPods(t *testing.T) {
 	fakeClient := &fake.Clientset{}
 	pvcIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
 	pvcLister := corelisters.NewPersistentVolumeClaimLister(pvcIndexer)
-	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, nil, recorder)
 	fakeClient.AddReactor("get", "persistentvolumeclaims", func(action core.Action) (bool, runtime.Object, error) {
 		return true, nil, apierrors.NewNotFound(action.GetResource().GroupResource(), action.GetResource().Resource)
 	})
@@ -81,7 +85,7 @@ func TestStatefulPodControlCreatePodExists(t *testing.T) {
 		pvcIndexer.Add(&pvc)
 	}
 	pvcLister := corelisters.NewPersistentVolumeClaimLister(pvcIndexer)
-	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, nil, recorder)
 	fakeClient.AddReactor("create", "persistentvolumeclaims", func(action core.Action) (bool, runtime.Object, error) {
 		create := action.(core.CreateAction)
 		return true, create.GetObject(), nil
@@ -108,7 +112,7 @@ func TestStatefulPodControlCreatePodPvcCreateFailure(t *testing.T) {
 	fakeClient := &fake.Clientset{}
 	pvcIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
 	pvcLister := corelisters.NewPersistentVolumeClaimLister(pvcIndexer)
-	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, nil, recorder)
 	fakeClient.AddReactor("create", "persistentvolumeclaims", func(action core.Action) (bool, runtime.Object, error) {
 		return true, nil, apierrors.NewInternalError(errors.New("API server down"))
 	})
@@ -146,7 +150,7 @@ func TestStatefulPodControlCreatePodPvcGetFailure(t *testing.T) {
 	fakeClient := &fake.Clientset{}
 	pvcIndexer := &fakeIndexer{getError: errors.New("API server down")}
 	pvcLister := corelisters.NewPersistentVolumeClaimLister(pvcIndexer)
-	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, nil, recorder)
 	fakeClient.AddReactor("create", "persistentvolumeclaims", func(action core.Action) (bool, runtime.Object, error) {
 		return true, nil, apierrors.NewInternalError(errors.New("API server down"))
 	})
@@ -175,7 +179,7 @@ func TestStatefulPodControlCreatePodFailed(t *testing.T) {
 	fakeClient := &fake.Clientset{}
 	pvcIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
 	pvcLister := corelisters.NewPersistentVolumeClaimLister(pvcIndexer)
-	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, nil, recorder)
 	fakeClient.AddReactor("create", "persistentvolumeclaims", func(action core.Action) (bool, runtime.Object, error) {
 		create := action.(core.CreateAction)
 		return true, create.GetObject(), nil
@@ -203,7 +207,7 @@ func TestStatefulPodControlNoOpUpdate(t *testing.T) {
 	set := newStatefulSet(3)
 	pod := newStatefulSetPod(set, 0)
 	fakeClient := &fake.Clientset{}
-	control := NewRealStatefulPodControl(fakeClient, nil, nil, nil, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, nil, nil, nil, recorder)
 	fakeClient.AddReactor("*", "*", func(action core.Action) (bool, runtime.Object, error) {
 		t.Error("no-op update should not make any client invocation")
 		return true, nil, apierrors.NewInternalError(errors.New("If we are here we have a problem"))
@@ -222,7 +226,7 @@ func TestStatefulPodControlUpdatesIdentity(t *testing.T) {
 	set := newStatefulSet(3)
 	pod := newStatefulSetPod(set, 0)
 	fakeClient := fake.NewSimpleClientset(pod)
-	control := NewRealStatefulPodControl(fakeClient, nil, nil, nil, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, nil, nil, nil, recorder)
 	var updated *v1.Pod
 	fakeClient.PrependReactor("update", "pods", func(action core.Action) (bool, runtime.Object, error) {
 		update := action.(core.UpdateAction)
@@ -254,7 +258,7 @@ func TestStatefulPodControlUpdateIdentityFailure(t *testing.T) {
 	gooPod.Name = "goo-0"
 	indexer.Add(gooPod)
 	podLister := corelisters.NewPodLister(indexer)
-	control := NewRealStatefulPodControl(fakeClient, nil, podLister, nil, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, podLister, nil, nil, recorder)
 	fakeClient.AddReactor("update", "pods", func(action core.Action) (bool, runtime.Object, error) {
 		pod.Name = "goo-0"
 		return true, nil, apierrors.NewInternalError(errors.New("API server down"))
@@ -281,7 +285,7 @@ func TestStatefulPodControlUpdatesPodStorage(t *testing.T) {
 	fakeClient := &fake.Clientset{}
 	pvcIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
 	pvcLister := corelisters.NewPersistentVolumeClaimLister(pvcIndexer)
-	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, nil, recorder)
 	pvcs := getPersistentVolumeClaims(set, pod)
 	volumes := make([]v1.Volume, 0, len(pod.Spec.Volumes))
 	for i := range pod.Spec.Volumes {
@@ -321,6 +325,54 @@ func TestStatefulPodControlUpdatesPodStorage(t *testing.T) {
 	}
 }
 
+func TestStatefulPodControlExpandsClaimStorage(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	set := newStatefulSetWithVolumes(3, "foo", []v1.VolumeMount{
+		{Name: "datadir", MountPath: "/data"},
+		{Name: "logdir", MountPath: "/log"},
+	}, nil)
+	pod := newStatefulSetPod(set, 0)
+	fakeClient := &fake.Clientset{}
+	pvcIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	pvcLister := corelisters.NewPersistentVolumeClaimLister(pvcIndexer)
+	scIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	scLister := storagelisters.NewStorageClassLister(scIndexer)
+	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, scLister, recorder)
+	allowExpansion := true
+	scIndexer.Add(&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "expandable"}, AllowVolumeExpansion: &allowExpansion})
+	scIndexer.Add(&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fixed"}})
+	scNames := map[string]string{"datadir": "expandable", "logdir": "fixed"}
+	for i := range set.Spec.VolumeClaimTemplates {
+		set.Spec.VolumeClaimTemplates[i].Spec.Resources.Requests[v1.ResourceStorage] = resource.MustParse("2Gi")
+	}
+	for name, claim := range getPersistentVolumeClaims(set, pod) {
+		scName := scNames[name]
+		existing := claim.DeepCopy()
+		existing.Spec.Resources.Requests[v1.ResourceStorage] = resource.MustParse("1Gi")
+		existing.Spec.StorageClassName = &scName
+		pvcIndexer.Add(existing)
+	}
+	var updated []*v1.PersistentVolumeClaim
+	fakeClient.AddReactor("update", "persistentvolumeclaims", func(action core.Action) (bool, runtime.Object, error) {
+		update := action.(core.UpdateAction)
+		updated = append(updated, update.GetObject().(*v1.PersistentVolumeClaim))
+		return true, update.GetObject(), nil
+	})
+	if err := control.UpdatePodClaimsStorage(set, pod); err != nil {
+		t.Fatalf("Successful expansion returned an error: %s", err)
+	}
+	if len(updated) != 1 || len(fakeClient.Actions()) != 1 {
+		t.Fatalf("Expected only the claim with expandable StorageClass updated, got %d updated and %d actions",
+			len(updated), len(fakeClient.Actions()))
+	}
+	if scName := *updated[0].Spec.StorageClassName; scName != "expandable" {
+		t.Errorf("Unexpected expansion of claim %s with StorageClass %s", updated[0].Name, scName)
+	}
+	if storage := updated[0].Spec.Resources.Requests[v1.ResourceStorage]; storage.String() != "2Gi" {
+		t.Errorf("Expected claim %s expanded to 2Gi, got %s", updated[0].Name, storage.String())
+	}
+}
+
 func TestStatefulPodControlUpdatePodStorageFailure(t *testing.T) {
 	recorder := record.NewFakeRecorder(10)
 	set := newStatefulSet(3)
@@ -328,7 +380,7 @@ func TestStatefulPodControlUpdatePodStorageFailure(t *testing.T) {
 	fakeClient := &fake.Clientset{}
 	pvcIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
 	pvcLister := corelisters.NewPersistentVolumeClaimLister(pvcIndexer)
-	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, nil, pvcLister, nil, recorder)
 	pvcs := getPersistentVolumeClaims(set, pod)
 	volumes := make([]v1.Volume, 0, len(pod.Spec.Volumes))
 	for i := range pod.Spec.Volumes {
@@ -368,7 +420,7 @@ func TestStatefulPodControlUpdatePodConflictSuccess(t *testing.T) {
 	gooPod.Name = "goo-0"
 	indexer.Add(gooPod)
 	podLister := corelisters.NewPodLister(indexer)
-	control := NewRealStatefulPodControl(fakeClient, nil, podLister, nil, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, podLister, nil, nil, recorder)
 	conflict := false
 	fakeClient.AddReactor("update", "pods", func(action core.Action) (bool, runtime.Object, error) {
 		update := action.(core.UpdateAction)
@@ -398,7 +450,7 @@ func TestStatefulPodControlDeletesStatefulPod(t *testing.T) {
 	set := newStatefulSet(3)
 	pod := newStatefulSetPod(set, 0)
 	fakeClient := &fake.Clientset{}
-	control := NewRealStatefulPodControl(fakeClient, nil, nil, nil, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, nil, nil, nil, recorder)
 	fakeClient.AddReactor("delete", "pods", func(action core.Action) (bool, runtime.Object, error) {
 		return true, nil, nil
 	})
@@ -418,7 +470,7 @@ func TestStatefulPodControlDeleteFailure(t *testing.T) {
 	set := newStatefulSet(3)
 	pod := newStatefulSetPod(set, 0)
 	fakeClient := &fake.Clientset{}
-	control := NewRealStatefulPodControl(fakeClient, nil, nil, nil, recorder)
+	control := NewRealStatefulPodControl(fakeClient, nil, nil, nil, nil, recorder)
 	fakeClient.AddReactor("delete", "pods", func(action core.Action) (bool, runtime.Object, error) {
 		return true, nil, apierrors.NewInternalError(errors.New("API server down"))
 	})
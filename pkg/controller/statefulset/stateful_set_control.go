@@ -18,17 +18,20 @@ limitations under the License.
 package statefulset
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"sort"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
+	statefulsetutil "github.com/openkruise/kruise/pkg/util/statefulset"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 	"k8s.io/kubernetes/pkg/controller/history"
@@ -298,11 +301,18 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 	status.UpdateRevision = updateRevision.Name
 	status.CollisionCount = utilpointer.Int32Ptr(collisionCount)
 	status.LabelSelector = selector.String()
+	status.InPlaceUpdateFallbackCount = set.Status.InPlaceUpdateFallbackCount
 
 	replicaCount := int(*set.Spec.Replicas)
-	// slice that will contain all Pods such that 0 <= getOrdinal(pod) < set.Spec.Replicas
+	// ordinals of the Pods that set should have, excluding the reserved ordinals
+	ordinals := statefulsetutil.GetOrdinals(set)
+	ordinalIndexes := make(map[int]int, len(ordinals))
+	for i, ord := range ordinals {
+		ordinalIndexes[ord] = i
+	}
+	// slice that will contain all Pods such that getOrdinal(pod) is in ordinals, at the index of its ordinal
 	replicas := make([]*v1.Pod, replicaCount)
-	// slice that will contain all Pods such that set.Spec.Replicas <= getOrdinal(pod)
+	// slice that will contain all Pods such that getOrdinal(pod) is not in ordinals
 	condemned := make([]*v1.Pod, 0, len(pods))
 	unhealthy := 0
 	firstUnhealthyOrdinal := math.MaxInt32
@@ -316,6 +326,13 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 		if isRunningAndReady(pods[i]) {
 			status.ReadyReplicas++
 		}
+		// count the number of available replicas, and requeue when the ready ones become available
+		available := isRunningAndAvailable(pods[i], set.Spec.MinReadySeconds)
+		if available {
+			status.AvailableReplicas++
+		} else if delay := getPodAvailableDelay(pods[i], set.Spec.MinReadySeconds); delay > 0 {
+			durationStore.Push(getStatefulSetKey(set), delay)
+		}
 
 		// count the number of current and update replicas
 		if isCreated(pods[i]) && !isTerminating(pods[i]) {
@@ -324,29 +341,35 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 			}
 			if getPodRevision(pods[i]) == updateRevision.Name {
 				status.UpdatedReplicas++
+				if available {
+					status.UpdatedAvailableReplicas++
+				}
 			}
 		}
 
-		if ord := getOrdinal(pods[i]); 0 <= ord && ord < replicaCount {
-			// if the ordinal of the pod is within the range of the current number of replicas,
+		if idx, ok := ordinalIndexes[getOrdinal(pods[i])]; ok {
+			// if the ordinal of the pod is one of the ordinals that set should have,
 			// insert it at the indirection of its ordinal
-			replicas[ord] = pods[i]
+			replicas[idx] = pods[i]
 
-		} else if ord >= replicaCount {
-			// if the ordinal is greater than the number of replicas add it to the condemned list
+		} else if getOrdinal(pods[i]) >= 0 {
+			// if the ordinal is out of the range or reserved, add it to the condemned list
 			condemned = append(condemned, pods[i])
 		}
 		// If the ordinal could not be parsed (ord < 0), ignore the Pod.
 	}
 
-	// for any empty indices in the sequence [0,set.Spec.Replicas) create a new Pod at the correct revision
-	for ord := 0; ord < replicaCount; ord++ {
-		if replicas[ord] == nil {
-			replicas[ord] = newVersionedStatefulSetPod(
+	// for any empty indices in the sequence of ordinals create a new Pod at the correct revision
+	for idx, ord := range ordinals {
+		if replicas[idx] == nil {
+			if replicas[idx], err = newVersionedStatefulSetPod(
 				currentSet,
 				updateSet,
 				currentRevision.Name,
-				updateRevision.Name, ord, replicas)
+				updateRevision.Name, ord, idx, replicas); err != nil {
+				ssc.recorder.Eventf(set, v1.EventTypeWarning, "FailedCreate", "create Pod of ordinal %d failed: %v", ord, err)
+				return &status, err
+			}
 		}
 	}
 
@@ -382,6 +405,23 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 			firstUnhealthyPod.Name)
 	}
 
+	// count the Pods whose PVCs have been expanded to the storage in volumeClaimTemplates,
+	// and find the ones waiting for the filesystem to be resized
+	fileSystemResizePending := make([]bool, len(replicas))
+	for i := range replicas {
+		if !isCreated(replicas[i]) {
+			continue
+		}
+		completed, resizePending, err := ssc.podControl.PodClaimsStorageState(set, replicas[i])
+		if err != nil {
+			return &status, err
+		}
+		if completed {
+			status.VolumeClaimUpdatedReplicas++
+		}
+		fileSystemResizePending[i] = resizePending
+	}
+
 	// If the StatefulSet is being deleted, don't do anything other than updating
 	// status.
 	if set.DeletionTimestamp != nil {
@@ -390,6 +430,18 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 
 	monotonic := !allowsBurst(set)
 
+	// In monotonic mode, scaleMaxUnavailable limits the number of Pods that can be in flight during scaling.
+	// The controller stops making progress in this round once it reaches the limit.
+	scaleMaxUnavailable, err := getScaleMaxUnavailable(set)
+	if err != nil {
+		return &status, err
+	}
+	var scaleUnavailable int
+	blockedByUnavailable := func() bool {
+		scaleUnavailable++
+		return scaleUnavailable >= scaleMaxUnavailable
+	}
+
 	// Examine each replica with respect to its ordinal
 	for i := range replicas {
 		// delete and recreate failed pods
@@ -409,15 +461,30 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 				status.UpdatedReplicas--
 			}
 			status.Replicas--
-			replicas[i] = newVersionedStatefulSetPod(
+			if replicas[i], err = newVersionedStatefulSetPod(
 				currentSet,
 				updateSet,
 				currentRevision.Name,
 				updateRevision.Name,
-				i, replicas)
+				ordinals[i], i, replicas); err != nil {
+				ssc.recorder.Eventf(set, v1.EventTypeWarning, "FailedCreate", "create Pod of ordinal %d failed: %v", ordinals[i], err)
+				return &status, err
+			}
 		}
 		// If we find a Pod that has not been created we create the Pod
 		if !isCreated(replicas[i]) {
+			// wait for the PVCs condemned with the previous Pod of this ordinal to be garbage collected,
+			// otherwise the new Pod would be bound to volumes that are about to be deleted
+			if isStale, err := ssc.podControl.PodClaimIsStale(set, replicas[i]); err != nil {
+				return &status, err
+			} else if isStale {
+				klog.V(4).Infof(
+					"StatefulSet %s/%s is waiting for stale PVCs of Pod %s to be deleted",
+					set.Namespace,
+					set.Name,
+					replicas[i].Name)
+				return &status, nil
+			}
 			if err := ssc.podControl.CreateStatefulPod(set, replicas[i]); err != nil {
 				msg := fmt.Sprintf("StatefulPodControl failed to create Pod error: %s", err)
 				condition := NewStatefulsetCondition(appsv1alpha1.FailedCreatePod, v1.ConditionTrue, "", msg)
@@ -432,8 +499,8 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 				status.UpdatedReplicas++
 			}
 
-			// if the set does not allow bursting, return immediately
-			if monotonic {
+			// if the set does not allow bursting, return immediately when reaching scaleMaxUnavailable
+			if monotonic && blockedByUnavailable() {
 				return &status, nil
 			}
 			// pod created, no more work possible for this round
@@ -447,7 +514,10 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 				set.Namespace,
 				set.Name,
 				replicas[i].Name)
-			return &status, nil
+			if blockedByUnavailable() {
+				return &status, nil
+			}
+			continue
 		}
 		// Update InPlaceUpdateReady condition for pod
 		if res := ssc.inplaceControl.Refresh(replicas[i], nil); res.RefreshErr != nil {
@@ -457,6 +527,29 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 		} else if res.DelayDuration > 0 {
 			durationStore.Push(getStatefulSetKey(set), res.DelayDuration)
 		}
+		// delete and recreate pods whose in-place update has not completed before the deadline
+		if timeout, leftDuration := ssc.inplaceControl.IsUpdateTimeout(replicas[i], getInPlaceUpdateOptions(set)); timeout && !isTerminating(replicas[i]) {
+			ssc.recorder.Eventf(set, v1.EventTypeWarning, "InPlaceUpdateTimeout",
+				"in-place update of Pod %s has not completed before the deadline, so it will be recreated",
+				replicas[i].Name)
+			if err := ssc.podControl.DeleteStatefulPod(set, replicas[i]); err != nil {
+				return &status, err
+			}
+			status.InPlaceUpdateFallbackCount++
+			if getPodRevision(replicas[i]) == currentRevision.Name {
+				status.CurrentReplicas--
+			}
+			if getPodRevision(replicas[i]) == updateRevision.Name {
+				status.UpdatedReplicas--
+			}
+			// wait for the Pod to be terminated before it is recreated
+			if monotonic && blockedByUnavailable() {
+				return &status, nil
+			}
+			continue
+		} else if leftDuration > 0 {
+			durationStore.Push(getStatefulSetKey(set), leftDuration)
+		}
 		// If we have a Pod that has been created but is not running and ready we can not make progress.
 		// We must ensure that all for each Pod, when we create it, all of its predecessors, with respect to its
 		// ordinal, are Running and Ready.
@@ -466,7 +559,21 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 				set.Namespace,
 				set.Name,
 				replicas[i].Name)
-			return &status, nil
+			if blockedByUnavailable() {
+				return &status, nil
+			}
+		}
+		// Enforce the PVC retention policy
+		if match, err := ssc.podControl.ClaimsMatchRetentionPolicy(set, replicas[i], false); err != nil {
+			return &status, err
+		} else if !match {
+			if err := ssc.podControl.UpdatePodClaimForRetentionPolicy(set, replicas[i], false); err != nil {
+				return &status, err
+			}
+		}
+		// Expand the PVCs if the storage in volumeClaimTemplates has been increased
+		if err := ssc.podControl.UpdatePodClaimsStorage(set, replicas[i]); err != nil {
+			return &status, err
 		}
 		// Enforce the StatefulSet invariants
 		if identityMatches(set, replicas[i]) && storageMatches(set, replicas[i]) {
@@ -482,7 +589,8 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 		}
 	}
 
-	// At this point, all of the current Replicas are Running and Ready, we can consider termination.
+	// At this point, all of the current Replicas are Running and Ready (or fewer than scaleMaxUnavailable are not),
+	// we can consider termination.
 	// We will wait for all predecessors to be Running and Ready prior to attempting a deletion.
 	// We will terminate Pods in a monotonically decreasing order over [len(pods),set.Spec.Replicas).
 	// Note that we do not resurrect Pods in this interval. Also not that scaling will take precedence over
@@ -495,14 +603,15 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 				set.Namespace,
 				set.Name,
 				condemned[target].Name)
-			// block if we are in monotonic mode
-			if monotonic {
+			// block if we are in monotonic mode and reaching scaleMaxUnavailable
+			if monotonic && blockedByUnavailable() {
 				return &status, nil
 			}
 			continue
 		}
-		// if we are in monotonic mode and the condemned target is not the first unhealthy Pod block
-		if !isRunningAndReady(condemned[target]) && monotonic && condemned[target] != firstUnhealthyPod {
+		// if we are in monotonic mode and the condemned target is not the first unhealthy Pod block,
+		// unless more than one Pod is allowed to be in flight by scaleMaxUnavailable
+		if !isRunningAndReady(condemned[target]) && monotonic && scaleMaxUnavailable <= 1 && condemned[target] != firstUnhealthyPod {
 			klog.V(4).Infof(
 				"StatefulSet %s/%s is waiting for Pod %s to be Running and Ready prior to scale down",
 				set.Namespace,
@@ -515,6 +624,10 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 			set.Name,
 			condemned[target].Name)
 
+		// hand the PVCs over to the condemned Pod if they should be deleted on scale-in
+		if err := ssc.podControl.UpdatePodClaimForRetentionPolicy(set, condemned[target], true); err != nil {
+			return &status, err
+		}
 		if err := ssc.podControl.DeleteStatefulPod(set, condemned[target]); err != nil {
 			return &status, err
 		}
@@ -524,7 +637,7 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 		if getPodRevision(condemned[target]) == updateRevision.Name {
 			status.UpdatedReplicas--
 		}
-		if monotonic {
+		if monotonic && blockedByUnavailable() {
 			return &status, nil
 		}
 	}
@@ -566,34 +679,54 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 
 		// delete the Pod if it is not already terminating and does not match the update revision.
 		if getPodRevision(replicas[target]) != updateRevision.Name && !isTerminating(replicas[target]) {
-
-			inplacing, inplaceUpdateErr := ssc.inPlaceUpdatePod(set, replicas[target], updateRevision, revisions)
-			if inplaceUpdateErr != nil {
-				if errors.IsConflict(inplaceUpdateErr) || isInPlaceOnly(set) {
-					return &status, err
-				}
-				// If it failed to in-place update && error is not conflict && podUpdatePolicy is not InPlaceOnly,
-				// then we should try to recreate this pod
-				klog.Warningf("StatefulSet %s/%s failed to in-place update Pod %s, so it will back off to ReCreate",
-					set.Namespace, set.Name, replicas[target].Name)
+			if getPodRevision(replicas[target]) == currentRevision.Name {
+				status.CurrentReplicas--
 			}
 
-			if !inplacing || inplaceUpdateErr != nil {
-				klog.V(2).Infof("StatefulSet %s/%s terminating Pod %s for update",
-					set.Namespace,
-					set.Name,
-					replicas[target].Name)
-				if err := ssc.podControl.DeleteStatefulPod(set, replicas[target]); err != nil {
+			// compare the templates of the ordinal with podOverrides applied, so that the Pods whose templates are
+			// not changed, e.g., only the podOverrides of other ordinals have been changed, are not recreated
+			oldRevision, newRevision, err := getPodRevisionsOfOrdinal(set, replicas[target], updateRevision, revisions)
+			if err != nil {
+				return &status, err
+			}
+			if oldRevision != nil && bytes.Equal(oldRevision.Data.Raw, newRevision.Data.Raw) {
+				// only the revision label needs to be updated, and the Pod keeps available
+				updated := replicas[target].DeepCopy()
+				if err := ssc.podControl.UpdateStatefulPodRevision(set, updated, updateRevision.Name); err != nil {
 					return &status, err
 				}
-			}
+				updateExpectations.ExpectUpdated(getStatefulSetKey(set), updateRevision.Name, updated)
+				replicas[target] = updated
+				status.UpdatedReplicas++
+				if isRunningAndAvailable(updated, set.Spec.MinReadySeconds) {
+					status.UpdatedAvailableReplicas++
+				}
+			} else {
+				inplacing, inplaceUpdateErr := ssc.inPlaceUpdatePod(set, replicas[target], updateRevision, oldRevision, newRevision)
+				if inplaceUpdateErr != nil {
+					if errors.IsConflict(inplaceUpdateErr) || isInPlaceOnly(set) {
+						return &status, err
+					}
+					// If it failed to in-place update && error is not conflict && podUpdatePolicy is not InPlaceOnly,
+					// then we should try to recreate this pod
+					klog.Warningf("StatefulSet %s/%s failed to in-place update Pod %s, so it will back off to ReCreate",
+						set.Namespace, set.Name, replicas[target].Name)
+				}
 
-			if getPodRevision(replicas[target]) == currentRevision.Name {
-				status.CurrentReplicas--
+				if !inplacing || inplaceUpdateErr != nil {
+					klog.V(2).Infof("StatefulSet %s/%s terminating Pod %s for update",
+						set.Namespace,
+						set.Name,
+						replicas[target].Name)
+					if err := ssc.podControl.DeleteStatefulPod(set, replicas[target]); err != nil {
+						return &status, err
+					}
+				}
 			}
 		}
 
-		if getPodRevision(replicas[target]) != updateRevision.Name || !isHealthy(replicas[target]) {
+		if getPodRevision(replicas[target]) != updateRevision.Name || !isHealthy(replicas[target]) ||
+			!isRunningAndAvailable(replicas[target], set.Spec.MinReadySeconds) {
 			unavailablePods = append(unavailablePods, replicas[target].Name)
 		} else if completedErr := inplaceupdate.CheckInPlaceUpdateCompleted(replicas[target]); completedErr != nil {
 			klog.V(4).Infof("StatefulSet %s/%s check Pod %s in-place update not-ready: %v",
@@ -615,35 +748,52 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 		}
 
 	}
+
+	// recreate Pods whose PVCs are waiting for the filesystem to be resized, which is done when the volumes
+	// are mounted again, without exceeding maxUnavailable
+	if set.Spec.VolumeClaimUpdateStrategy != nil && set.Spec.VolumeClaimUpdateStrategy.RecreatePodOnFileSystemResizePending {
+		unavailable := sets.NewString(unavailablePods...)
+		for i := range replicas {
+			if !isHealthy(replicas[i]) || !isRunningAndAvailable(replicas[i], set.Spec.MinReadySeconds) {
+				unavailable.Insert(replicas[i].Name)
+			}
+		}
+		for target := len(replicas) - 1; target >= 0 && unavailable.Len() < maxUnavailable; target-- {
+			if !fileSystemResizePending[target] || unavailable.Has(replicas[target].Name) {
+				continue
+			}
+			ssc.recorder.Eventf(set, v1.EventTypeNormal, "RecreatingFileSystemResizePendingPod",
+				"StatefulSet %s/%s is recreating Pod %s to finish filesystem resize of its PVCs",
+				set.Namespace,
+				set.Name,
+				replicas[target].Name)
+			if err := ssc.podControl.DeleteStatefulPod(set, replicas[target]); err != nil {
+				return &status, err
+			}
+			if getPodRevision(replicas[target]) == currentRevision.Name {
+				status.CurrentReplicas--
+			}
+			if getPodRevision(replicas[target]) == updateRevision.Name {
+				status.UpdatedReplicas--
+			}
+			unavailable.Insert(replicas[target].Name)
+		}
+	}
 	return &status, nil
 }
 
+// inPlaceUpdatePod updates pod in-place from oldRevision to newRevision, which are the revisions of its ordinal
+// with podOverrides applied. It returns false if pod can not be updated in-place and should be recreated.
 func (ssc *defaultStatefulSetControl) inPlaceUpdatePod(
-	set *appsv1alpha1.StatefulSet, pod *v1.Pod,
-	updateRevision *apps.ControllerRevision, revisions []*apps.ControllerRevision,
+	set *appsv1alpha1.StatefulSet, pod *v1.Pod, updateRevision, oldRevision, newRevision *apps.ControllerRevision,
 ) (bool, error) {
-	if set.Spec.UpdateStrategy.RollingUpdate == nil {
-		return false, nil
-	}
-	if set.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy != appsv1alpha1.InPlaceIfPossiblePodUpdateStrategyType &&
-		set.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy != appsv1alpha1.InPlaceOnlyPodUpdateStrategyType {
+	if set.Spec.UpdateStrategy.RollingUpdate == nil ||
+		set.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy != appsv1alpha1.InPlaceIfPossiblePodUpdateStrategyType &&
+			set.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy != appsv1alpha1.InPlaceOnlyPodUpdateStrategyType {
 		return false, nil
 	}
 
-	var oldRevision *apps.ControllerRevision
-	for _, r := range revisions {
-		if r.Name == getPodRevision(pod) {
-			oldRevision = r
-			break
-		}
-	}
-
-	opts := &inplaceupdate.UpdateOptions{}
-	if set.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy != nil {
-		opts.GracePeriodSeconds = set.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy.GracePeriodSeconds
-	}
-
-	res := ssc.inplaceControl.Update(pod, oldRevision, updateRevision, opts)
+	res := ssc.inplaceControl.Update(pod, oldRevision, newRevision, getInPlaceUpdateOptions(set))
 	if res.InPlaceUpdate && res.UpdateErr == nil {
 		ssc.recorder.Eventf(set, v1.EventTypeNormal, "SuccessfulUpdatePodInPlace", "successfully update pod %s in-place", pod.Name)
 		updateExpectations.ExpectUpdated(getStatefulSetKey(set), updateRevision.Name, pod)
@@ -667,6 +817,9 @@ func (ssc *defaultStatefulSetControl) updateStatefulSetStatus(
 	// complete any in progress rolling update if necessary
 	completeRollingUpdate(set, status)
 
+	// compute the progressing, available and replica failure conditions
+	updateStatefulSetConditions(set, status)
+
 	// if the status is not inconsistent do not perform an update
 	if !inconsistentStatus(set, status) {
 		return nil
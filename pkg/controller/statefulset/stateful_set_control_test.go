@@ -38,11 +38,14 @@ import (
 	kruiseappsinformers "github.com/openkruise/kruise/pkg/client/informers/externalversions/apps/v1alpha1"
 	kruiseappslisters "github.com/openkruise/kruise/pkg/client/listers/apps/v1alpha1"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
+	"github.com/openkruise/kruise/pkg/util/volumeclaim"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	clientset "k8s.io/client-go/kubernetes"
@@ -503,6 +506,518 @@ func TestStatefulSetControlScaleDownDeleteError(t *testing.T) {
 	}
 }
 
+func TestStatefulSetControlReserveOrdinals(t *testing.T) {
+	invariants := assertBurstInvariants
+	set := burst(newStatefulSet(3))
+	set.Spec.ReserveOrdinals = []int{1}
+	client := fake.NewSimpleClientset()
+	kruiseClient := kruisefake.NewSimpleClientset(set)
+	spc, _, ssc, stop := setupController(client, kruiseClient)
+	defer close(stop)
+
+	getOrdinals := func() []int {
+		selector, err := metav1.LabelSelectorAsSelector(set.Spec.Selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pods, err := spc.podsLister.Pods(set.Namespace).List(selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(ascendingOrdinal(pods))
+		var ordinals []int
+		for _, pod := range pods {
+			ordinals = append(ordinals, getOrdinal(pod))
+		}
+		return ordinals
+	}
+
+	if err := scaleUpStatefulSetControl(set, ssc, spc, invariants); err != nil {
+		t.Fatalf("Failed to turn up StatefulSet : %s", err)
+	}
+	if ordinals := getOrdinals(); !reflect.DeepEqual(ordinals, []int{0, 2, 3}) {
+		t.Fatalf("Expected ordinals [0 2 3], got %v", ordinals)
+	}
+
+	// the Pod with the unreserved ordinal should be brought back, and the last one should be deleted
+	var err error
+	set, err = spc.setsLister.StatefulSets(set.Namespace).Get(set.Name)
+	if err != nil {
+		t.Fatalf("Error getting updated StatefulSet: %v", err)
+	}
+	set.Spec.ReserveOrdinals = nil
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.Selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pods, err := spc.podsLister.Pods(set.Namespace).List(selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ssc.UpdateStatefulSet(set, pods); err != nil {
+		t.Fatalf("Failed to update StatefulSet: %v", err)
+	}
+	if ordinals := getOrdinals(); !reflect.DeepEqual(ordinals, []int{0, 1, 2}) {
+		t.Fatalf("Expected ordinals [0 1 2], got %v", ordinals)
+	}
+}
+
+func TestStatefulSetControlStartOrdinal(t *testing.T) {
+	invariants := assertBurstInvariants
+	set := burst(newStatefulSet(3))
+	set.Spec.Ordinals = &appsv1alpha1.StatefulSetOrdinals{Start: 5}
+	client := fake.NewSimpleClientset()
+	kruiseClient := kruisefake.NewSimpleClientset(set)
+	spc, _, ssc, stop := setupController(client, kruiseClient)
+	defer close(stop)
+
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.Selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	getOrdinals := func() []int {
+		pods, err := spc.podsLister.Pods(set.Namespace).List(selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(ascendingOrdinal(pods))
+		var ordinals []int
+		for _, pod := range pods {
+			ordinals = append(ordinals, getOrdinal(pod))
+		}
+		return ordinals
+	}
+
+	if err := scaleUpStatefulSetControl(set, ssc, spc, invariants); err != nil {
+		t.Fatalf("Failed to turn up StatefulSet : %s", err)
+	}
+	if ordinals := getOrdinals(); !reflect.DeepEqual(ordinals, []int{5, 6, 7}) {
+		t.Fatalf("Expected ordinals [5 6 7], got %v", ordinals)
+	}
+	claims, err := spc.claimsLister.PersistentVolumeClaims(set.Namespace).List(labels.Everything())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, claim := range claims {
+		if ord := getOrdinal(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: claim.Name}}); ord < 5 {
+			t.Fatalf("Unexpected claim %s out of ordinals range", claim.Name)
+		}
+	}
+
+	// move ordinal 5 out of the StatefulSet
+	set, err = spc.setsLister.StatefulSets(set.Namespace).Get(set.Name)
+	if err != nil {
+		t.Fatalf("Error getting updated StatefulSet: %v", err)
+	}
+	set.Spec.Ordinals.Start = 6
+	*set.Spec.Replicas = 2
+	pods, err := spc.podsLister.Pods(set.Namespace).List(selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ssc.UpdateStatefulSet(set, pods); err != nil {
+		t.Fatalf("Failed to update StatefulSet: %v", err)
+	}
+	if ordinals := getOrdinals(); !reflect.DeepEqual(ordinals, []int{6, 7}) {
+		t.Fatalf("Expected ordinals [6 7], got %v", ordinals)
+	}
+}
+
+func TestStatefulSetControlPVCRetentionPolicy(t *testing.T) {
+	invariants := assertBurstInvariants
+	set := burst(newStatefulSet(3))
+	set.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: appsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType,
+		WhenScaled:  appsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType,
+	}
+	client := fake.NewSimpleClientset()
+	kruiseClient := kruisefake.NewSimpleClientset(set)
+	spc, _, ssc, stop := setupController(client, kruiseClient)
+	defer close(stop)
+
+	getOwnerKinds := func(ordinal int) []string {
+		claims := getPersistentVolumeClaims(set, newStatefulSetPod(set, ordinal))
+		claim, err := spc.claimsLister.PersistentVolumeClaims(set.Namespace).Get(claims["datadir"].Name)
+		if err != nil {
+			t.Fatalf("Failed to get claim of ordinal %d: %v", ordinal, err)
+		}
+		var kinds []string
+		for _, ref := range claim.OwnerReferences {
+			kinds = append(kinds, ref.Kind)
+		}
+		return kinds
+	}
+
+	if err := scaleUpStatefulSetControl(set, ssc, spc, invariants); err != nil {
+		t.Fatalf("Failed to turn up StatefulSet : %s", err)
+	}
+	for ord := 0; ord < 3; ord++ {
+		if kinds := getOwnerKinds(ord); !reflect.DeepEqual(kinds, []string{"StatefulSet"}) {
+			t.Fatalf("Expected claims of ordinal %d owned by StatefulSet, got %v", ord, kinds)
+		}
+	}
+
+	var err error
+	set, err = spc.setsLister.StatefulSets(set.Namespace).Get(set.Name)
+	if err != nil {
+		t.Fatalf("Error getting updated StatefulSet: %v", err)
+	}
+	*set.Spec.Replicas = 1
+	if err := scaleDownStatefulSetControl(set, ssc, spc, invariants); err != nil {
+		t.Fatalf("Failed to scale down StatefulSet : %s", err)
+	}
+	if kinds := getOwnerKinds(0); !reflect.DeepEqual(kinds, []string{"StatefulSet"}) {
+		t.Fatalf("Expected claims of ordinal 0 owned by StatefulSet, got %v", kinds)
+	}
+	for ord := 1; ord < 3; ord++ {
+		if kinds := getOwnerKinds(ord); !reflect.DeepEqual(kinds, []string{"Pod"}) {
+			t.Fatalf("Expected claims of condemned ordinal %d owned by Pod, got %v", ord, kinds)
+		}
+	}
+}
+
+func TestStatefulSetControlVolumeClaimExpansion(t *testing.T) {
+	set := newStatefulSet(3)
+	client := fake.NewSimpleClientset()
+	kruiseClient := kruisefake.NewSimpleClientset(set)
+	spc, _, ssc, stop := setupController(client, kruiseClient)
+	defer close(stop)
+
+	if err := scaleUpStatefulSetControl(set, ssc, spc, assertMonotonicInvariants); err != nil {
+		t.Fatalf("Failed to turn up StatefulSet : %s", err)
+	}
+	var err error
+	if set, err = spc.setsLister.StatefulSets(set.Namespace).Get(set.Name); err != nil {
+		t.Fatalf("Error getting updated StatefulSet: %v", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.Selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sync := func() []*v1.Pod {
+		pods, err := spc.podsLister.Pods(set.Namespace).List(selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ssc.UpdateStatefulSet(set, pods); err != nil {
+			t.Fatalf("Failed to update StatefulSet: %v", err)
+		}
+		if set, err = spc.setsLister.StatefulSets(set.Namespace).Get(set.Name); err != nil {
+			t.Fatalf("Error getting updated StatefulSet: %v", err)
+		}
+		if pods, err = spc.podsLister.Pods(set.Namespace).List(selector); err != nil {
+			t.Fatal(err)
+		}
+		return pods
+	}
+	getClaim := func(ordinal int) *v1.PersistentVolumeClaim {
+		claims := getPersistentVolumeClaims(set, newStatefulSetPod(set, ordinal))
+		claim, err := spc.claimsLister.PersistentVolumeClaims(set.Namespace).Get(claims["datadir"].Name)
+		if err != nil {
+			t.Fatalf("Failed to get claim of ordinal %d: %v", ordinal, err)
+		}
+		return claim.DeepCopy()
+	}
+
+	expected := resource.MustParse("2Gi")
+	set = set.DeepCopy()
+	set.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[v1.ResourceStorage] = expected
+	set.Spec.VolumeClaimUpdateStrategy = &appsv1alpha1.VolumeClaimUpdateStrategy{RecreatePodOnFileSystemResizePending: true}
+	spc.setsIndexer.Update(set)
+	sync()
+	for ord := 0; ord < 3; ord++ {
+		if storage := volumeclaim.GetStorageRequest(getClaim(ord)); storage.Cmp(expected) != 0 {
+			t.Fatalf("Expected claim of ordinal %d requesting %s, got %s", ord, expected.String(), storage.String())
+		}
+	}
+
+	// the volumes of ordinal 0 and 1 have been expanded, while ordinal 2 is waiting for filesystem resize
+	for ord := 0; ord < 3; ord++ {
+		claim := getClaim(ord)
+		claim.Status.Capacity = v1.ResourceList{v1.ResourceStorage: expected}
+		if ord == 2 {
+			claim.Status.Conditions = []v1.PersistentVolumeClaimCondition{
+				{Type: v1.PersistentVolumeClaimFileSystemResizePending, Status: v1.ConditionTrue},
+			}
+		}
+		spc.claimsIndexer.Update(claim)
+	}
+	pods := sync()
+	if set.Status.VolumeClaimUpdatedReplicas != 2 {
+		t.Fatalf("Expected 2 volumeClaimUpdatedReplicas, got %d", set.Status.VolumeClaimUpdatedReplicas)
+	}
+	if len(pods) != 2 {
+		t.Fatalf("Expected Pod with filesystem resize pending to be recreated, got %d Pods", len(pods))
+	}
+	for _, pod := range pods {
+		if getOrdinal(pod) == 2 {
+			t.Fatalf("Expected Pod of ordinal 2 to be deleted")
+		}
+	}
+}
+
+func TestStatefulSetControlScaleMaxUnavailable(t *testing.T) {
+	set := newStatefulSet(5)
+	maxUnavailable := intstr.FromInt(2)
+	set.Spec.ScaleStrategy = &appsv1alpha1.StatefulSetScaleStrategy{MaxUnavailable: &maxUnavailable}
+	client := fake.NewSimpleClientset()
+	kruiseClient := kruisefake.NewSimpleClientset(set)
+	spc, _, ssc, stop := setupController(client, kruiseClient)
+	defer close(stop)
+
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.Selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncAndGetOrdinals := func() []int {
+		pods, err := spc.podsLister.Pods(set.Namespace).List(selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ssc.UpdateStatefulSet(set, pods); err != nil {
+			t.Fatalf("Failed to update StatefulSet: %v", err)
+		}
+		if set, err = spc.setsLister.StatefulSets(set.Namespace).Get(set.Name); err != nil {
+			t.Fatalf("Error getting updated StatefulSet: %v", err)
+		}
+		pods, err = spc.podsLister.Pods(set.Namespace).List(selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(ascendingOrdinal(pods))
+		var ordinals []int
+		for _, pod := range pods {
+			ordinals = append(ordinals, getOrdinal(pod))
+		}
+		return ordinals
+	}
+	setReady := func(ordinal int) {
+		if _, err := spc.setPodRunning(set, ordinal); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := spc.setPodReady(set, ordinal); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// two Pods are created in ordinal order without waiting for each other
+	if ordinals := syncAndGetOrdinals(); !reflect.DeepEqual(ordinals, []int{0, 1}) {
+		t.Fatalf("Expected ordinals [0 1], got %v", ordinals)
+	}
+	// no more Pods should be created until one of them is ready
+	if ordinals := syncAndGetOrdinals(); !reflect.DeepEqual(ordinals, []int{0, 1}) {
+		t.Fatalf("Expected ordinals [0 1], got %v", ordinals)
+	}
+	setReady(0)
+	if ordinals := syncAndGetOrdinals(); !reflect.DeepEqual(ordinals, []int{0, 1, 2}) {
+		t.Fatalf("Expected ordinals [0 1 2], got %v", ordinals)
+	}
+	setReady(1)
+	setReady(2)
+	if ordinals := syncAndGetOrdinals(); !reflect.DeepEqual(ordinals, []int{0, 1, 2, 3, 4}) {
+		t.Fatalf("Expected ordinals [0 1 2 3 4], got %v", ordinals)
+	}
+	setReady(3)
+	setReady(4)
+
+	// scale down from the largest ordinal, two Pods at a time
+	*set.Spec.Replicas = 1
+	if ordinals := syncAndGetOrdinals(); !reflect.DeepEqual(ordinals, []int{0, 1, 2}) {
+		t.Fatalf("Expected ordinals [0 1 2], got %v", ordinals)
+	}
+	if ordinals := syncAndGetOrdinals(); !reflect.DeepEqual(ordinals, []int{0}) {
+		t.Fatalf("Expected ordinals [0], got %v", ordinals)
+	}
+}
+
+func TestStatefulSetControlMinReadySeconds(t *testing.T) {
+	set := newStatefulSet(3)
+	set.Spec.MinReadySeconds = 30
+	client := fake.NewSimpleClientset()
+	kruiseClient := kruisefake.NewSimpleClientset(set)
+	spc, _, ssc, stop := setupController(client, kruiseClient)
+	defer close(stop)
+
+	if err := scaleUpStatefulSetControl(set, ssc, spc, assertMonotonicInvariants); err != nil {
+		t.Fatalf("Failed to turn up StatefulSet : %s", err)
+	}
+	var err error
+	if set, err = spc.setsLister.StatefulSets(set.Namespace).Get(set.Name); err != nil {
+		t.Fatalf("Error getting updated StatefulSet: %v", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.Selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sync := func() []*v1.Pod {
+		pods, err := spc.podsLister.Pods(set.Namespace).List(selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ssc.UpdateStatefulSet(set, pods); err != nil {
+			t.Fatalf("Failed to update StatefulSet: %v", err)
+		}
+		if set, err = spc.setsLister.StatefulSets(set.Namespace).Get(set.Name); err != nil {
+			t.Fatalf("Error getting updated StatefulSet: %v", err)
+		}
+		if pods, err = spc.podsLister.Pods(set.Namespace).List(selector); err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(ascendingOrdinal(pods))
+		return pods
+	}
+	setAvailable := func(pods []*v1.Pod) {
+		for _, pod := range pods {
+			pod = pod.DeepCopy()
+			for i := range pod.Status.Conditions {
+				if pod.Status.Conditions[i].Type == v1.PodReady {
+					pod.Status.Conditions[i].LastTransitionTime = metav1.NewTime(time.Now().Add(-time.Minute))
+				}
+			}
+			spc.podsIndexer.Update(pod)
+		}
+	}
+
+	// the ready Pods are not available until minReadySeconds, so the set should be requeued
+	durationStore.Pop(getStatefulSetKey(set))
+	pods := sync()
+	if set.Status.ReadyReplicas != 3 || set.Status.AvailableReplicas != 0 {
+		t.Fatalf("Expected 3 ready and 0 available replicas, got %d ready and %d available",
+			set.Status.ReadyReplicas, set.Status.AvailableReplicas)
+	}
+	if delay := durationStore.Pop(getStatefulSetKey(set)); delay <= 0 || delay > 30*time.Second {
+		t.Fatalf("Expected to requeue within minReadySeconds, got %v", delay)
+	}
+
+	setAvailable(pods)
+	sync()
+	if set.Status.AvailableReplicas != 3 || set.Status.UpdatedAvailableReplicas != 3 {
+		t.Fatalf("Expected 3 available and updated available replicas, got %d and %d",
+			set.Status.AvailableReplicas, set.Status.UpdatedAvailableReplicas)
+	}
+
+	// update the template, and the Pod of the largest ordinal is recreated first
+	set = set.DeepCopy()
+	set.Spec.Template.Spec.Containers[0].Image = "foo"
+	spc.setsIndexer.Update(set)
+	pods = sync()
+	if len(pods) != 2 {
+		t.Fatalf("Expected the Pod of ordinal 2 to be deleted, got %d Pods", len(pods))
+	}
+	oldRevision := getPodRevision(pods[1])
+	pods = sync()
+	if len(pods) != 3 {
+		t.Fatalf("Expected the Pod of ordinal 2 to be recreated, got %d Pods", len(pods))
+	}
+	if _, err := spc.setPodRunning(set, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := spc.setPodReady(set, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// the updated Pod is ready but not available, so the next Pod should not be updated
+	pods = sync()
+	if len(pods) != 3 || getPodRevision(pods[1]) != oldRevision {
+		t.Fatalf("Expected the Pod of ordinal 1 to wait for the updated Pod to be available")
+	}
+	if set.Status.UpdatedReplicas != 1 || set.Status.UpdatedAvailableReplicas != 0 {
+		t.Fatalf("Expected 1 updated and 0 updated available replicas, got %d and %d",
+			set.Status.UpdatedReplicas, set.Status.UpdatedAvailableReplicas)
+	}
+
+	setAvailable(pods[2:])
+	if pods = sync(); len(pods) != 2 {
+		t.Fatalf("Expected the Pod of ordinal 1 to be deleted after the updated Pod is available, got %d Pods", len(pods))
+	}
+}
+
+func TestStatefulSetControlPodOverrides(t *testing.T) {
+	set := newStatefulSet(3)
+	partition := int32(0)
+	set.Spec.UpdateStrategy.RollingUpdate = &appsv1alpha1.RollingUpdateStatefulSetStrategy{Partition: &partition}
+	set.Spec.PodOverrides = []appsv1alpha1.StatefulSetPodOverride{
+		{Ordinals: "0", Patch: k8sruntime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"nginx","env":[{"name":"ROLE","value":"bootstrap"}]}]}}`)}},
+	}
+	client := fake.NewSimpleClientset()
+	kruiseClient := kruisefake.NewSimpleClientset(set)
+	spc, _, ssc, stop := setupController(client, kruiseClient)
+	defer close(stop)
+
+	if err := scaleUpStatefulSetControl(set, ssc, spc, assertMonotonicInvariants); err != nil {
+		t.Fatalf("Failed to turn up StatefulSet : %s", err)
+	}
+	var err error
+	if set, err = spc.setsLister.StatefulSets(set.Namespace).Get(set.Name); err != nil {
+		t.Fatalf("Error getting updated StatefulSet: %v", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.Selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sync := func() []*v1.Pod {
+		pods, err := spc.podsLister.Pods(set.Namespace).List(selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ssc.UpdateStatefulSet(set, pods); err != nil {
+			t.Fatalf("Failed to update StatefulSet: %v", err)
+		}
+		if set, err = spc.setsLister.StatefulSets(set.Namespace).Get(set.Name); err != nil {
+			t.Fatalf("Error getting updated StatefulSet: %v", err)
+		}
+		if pods, err = spc.podsLister.Pods(set.Namespace).List(selector); err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(ascendingOrdinal(pods))
+		return pods
+	}
+	getEnv := func(pod *v1.Pod) []v1.EnvVar {
+		return pod.Spec.Containers[0].Env
+	}
+
+	pods := sync()
+	if env := getEnv(pods[0]); len(env) != 1 || env[0].Value != "bootstrap" {
+		t.Fatalf("Expected Pod of ordinal 0 overridden, got env %v", env)
+	}
+	for _, pod := range pods[1:] {
+		if env := getEnv(pod); len(env) != 0 {
+			t.Fatalf("Expected Pod %s not overridden, got env %v", pod.Name, env)
+		}
+	}
+
+	// change the override of ordinal 0, and the other Pods should not be recreated
+	set = set.DeepCopy()
+	set.Spec.PodOverrides[0].Patch.Raw = []byte(`{"spec":{"containers":[{"name":"nginx","env":[{"name":"ROLE","value":"seed"}]}]}}`)
+	spc.setsIndexer.Update(set)
+	pods = sync()
+	if len(pods) != 2 || getOrdinal(pods[0]) != 1 {
+		t.Fatalf("Expected only the Pod of ordinal 0 to be recreated, got %d Pods", len(pods))
+	}
+	for _, pod := range pods {
+		if getPodRevision(pod) != set.Status.UpdateRevision {
+			t.Fatalf("Expected Pod %s updated to revision %s without recreation, got %s", pod.Name, set.Status.UpdateRevision, getPodRevision(pod))
+		}
+		if _, ok := pod.Annotations[appsv1alpha1.InPlaceUpdateStateKey]; ok {
+			t.Fatalf("Expected only the revision label of Pod %s updated, got in-place update state", pod.Name)
+		}
+	}
+	if set.Status.UpdatedReplicas != 2 || set.Status.CurrentReplicas != 0 {
+		t.Fatalf("Expected the Pods with revision label updated counted as updated replicas, got updated %d current %d",
+			set.Status.UpdatedReplicas, set.Status.CurrentReplicas)
+	}
+	pods = sync()
+	if len(pods) != 3 || getOrdinal(pods[0]) != 0 {
+		t.Fatalf("Expected Pod of ordinal 0 to be created")
+	}
+	if env := getEnv(pods[0]); len(env) != 1 || env[0].Value != "seed" {
+		t.Fatalf("Expected Pod of ordinal 0 overridden by the new patch, got env %v", env)
+	}
+}
+
 func TestStatefulSetControl_getSetRevisions(t *testing.T) {
 	type testcase struct {
 		name            string
@@ -2169,6 +2684,10 @@ func (spc *fakeStatefulPodControl) CreateStatefulPod(set *appsv1alpha1.StatefulS
 	}
 
 	for _, claim := range getPersistentVolumeClaims(set, pod) {
+		if _, err := spc.claimsLister.PersistentVolumeClaims(claim.Namespace).Get(claim.Name); err == nil {
+			continue
+		}
+		updateClaimOwnerRefForSetAndPod(&claim, set, pod, false)
 		spc.claimsIndexer.Update(&claim)
 	}
 	spc.podsIndexer.Update(pod)
@@ -2194,6 +2713,17 @@ func (spc *fakeStatefulPodControl) UpdateStatefulPod(set *appsv1alpha1.StatefulS
 	return nil
 }
 
+func (spc *fakeStatefulPodControl) UpdateStatefulPodRevision(set *appsv1alpha1.StatefulSet, pod *v1.Pod, revision string) error {
+	defer spc.updatePodTracker.inc()
+	if spc.updatePodTracker.errorReady() {
+		defer spc.updatePodTracker.reset()
+		return spc.updatePodTracker.err
+	}
+	updatePodRevision(pod, revision)
+	spc.podsIndexer.Update(pod)
+	return nil
+}
+
 func (spc *fakeStatefulPodControl) DeleteStatefulPod(set *appsv1alpha1.StatefulSet, pod *v1.Pod) error {
 	defer spc.deletePodTracker.inc()
 	if spc.deletePodTracker.errorReady() {
@@ -2211,6 +2741,77 @@ func (spc *fakeStatefulPodControl) DeleteStatefulPod(set *appsv1alpha1.StatefulS
 	return nil
 }
 
+func (spc *fakeStatefulPodControl) ClaimsMatchRetentionPolicy(set *appsv1alpha1.StatefulSet, pod *v1.Pod, condemned bool) (bool, error) {
+	for _, claim := range getPersistentVolumeClaims(set, pod) {
+		existing, err := spc.claimsLister.PersistentVolumeClaims(claim.Namespace).Get(claim.Name)
+		if err != nil {
+			continue
+		}
+		if updateClaimOwnerRefForSetAndPod(existing.DeepCopy(), set, pod, condemned) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (spc *fakeStatefulPodControl) UpdatePodClaimForRetentionPolicy(set *appsv1alpha1.StatefulSet, pod *v1.Pod, condemned bool) error {
+	for _, claim := range getPersistentVolumeClaims(set, pod) {
+		existing, err := spc.claimsLister.PersistentVolumeClaims(claim.Namespace).Get(claim.Name)
+		if err != nil {
+			continue
+		}
+		updated := existing.DeepCopy()
+		if updateClaimOwnerRefForSetAndPod(updated, set, pod, condemned) {
+			spc.claimsIndexer.Update(updated)
+		}
+	}
+	return nil
+}
+
+func (spc *fakeStatefulPodControl) PodClaimIsStale(set *appsv1alpha1.StatefulSet, pod *v1.Pod) (bool, error) {
+	for _, claim := range getPersistentVolumeClaims(set, pod) {
+		existing, err := spc.claimsLister.PersistentVolumeClaims(claim.Namespace).Get(claim.Name)
+		if err != nil {
+			continue
+		}
+		if isClaimOwnedByStalePod(existing, pod) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (spc *fakeStatefulPodControl) UpdatePodClaimsStorage(set *appsv1alpha1.StatefulSet, pod *v1.Pod) error {
+	for _, claim := range getPersistentVolumeClaims(set, pod) {
+		existing, err := spc.claimsLister.PersistentVolumeClaims(claim.Namespace).Get(claim.Name)
+		if err != nil {
+			continue
+		}
+		if volumeclaim.IsExpansionRequired(existing, &claim) {
+			spc.claimsIndexer.Update(volumeclaim.NewExpandedClaim(existing, &claim))
+		}
+	}
+	return nil
+}
+
+func (spc *fakeStatefulPodControl) PodClaimsStorageState(set *appsv1alpha1.StatefulSet, pod *v1.Pod) (bool, bool, error) {
+	completed, fileSystemResizePending := true, false
+	for _, claim := range getPersistentVolumeClaims(set, pod) {
+		existing, err := spc.claimsLister.PersistentVolumeClaims(claim.Namespace).Get(claim.Name)
+		if err != nil {
+			completed = false
+			continue
+		}
+		if !volumeclaim.IsExpansionCompleted(existing, &claim) {
+			completed = false
+		}
+		if volumeclaim.IsFileSystemResizePending(existing) {
+			fileSystemResizePending = true
+		}
+	}
+	return completed, fileSystemResizePending, nil
+}
+
 var _ StatefulPodControlInterface = &fakeStatefulPodControl{}
 
 type fakeStatefulSetStatusUpdater struct {
@@ -23,12 +23,18 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"time"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/condition"
+	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
+	statefulsetutil "github.com/openkruise/kruise/pkg/util/statefulset"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/kubernetes/scheme"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
@@ -38,6 +44,11 @@ import (
 
 var patchCodec = scheme.Codecs.LegacyCodec(appsv1alpha1.SchemeGroupVersion)
 
+// podKind contains the schema.GroupVersionKind for Pod, which may own PersistentVolumeClaims after scale-in.
+var podKind = v1.SchemeGroupVersion.WithKind("Pod")
+
+var timeNow = time.Now
+
 // statefulPodRegex is a regular expression that extracts the parent StatefulSet and ordinal from the Name of a Pod
 var statefulPodRegex = regexp.MustCompile("(.*)-([0-9]+)$")
 
@@ -70,6 +81,23 @@ func getOrdinal(pod *v1.Pod) int {
 	return ordinal
 }
 
+// getScaleMaxUnavailable returns the maximum number of Pods that can be in flight during scaling in monotonic mode,
+// which is calculated from set.Spec.ScaleStrategy.MaxUnavailable and is not less than 1.
+func getScaleMaxUnavailable(set *appsv1alpha1.StatefulSet) (int, error) {
+	if set.Spec.ScaleStrategy == nil || set.Spec.ScaleStrategy.MaxUnavailable == nil {
+		return 1, nil
+	}
+	maxUnavailable, err := intstrutil.GetValueFromIntOrPercent(set.Spec.ScaleStrategy.MaxUnavailable, int(*set.Spec.Replicas), false)
+	if err != nil {
+		return 1, err
+	}
+	// maxUnavailable should not less than 1
+	if maxUnavailable < 1 {
+		maxUnavailable = 1
+	}
+	return maxUnavailable, nil
+}
+
 // getPodName gets the name of set's child Pod with an ordinal index of ordinal
 func getPodName(set *appsv1alpha1.StatefulSet, ordinal int) string {
 	return fmt.Sprintf("%s-%d", set.Name, ordinal)
@@ -136,6 +164,97 @@ func getPersistentVolumeClaims(set *appsv1alpha1.StatefulSet, pod *v1.Pod) map[s
 	return claims
 }
 
+// getPersistentVolumeClaimRetentionPolicy returns the PVC retention policy of set, in which unset
+// fields default to Retain.
+func getPersistentVolumeClaimRetentionPolicy(set *appsv1alpha1.StatefulSet) appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy {
+	policy := appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: appsv1alpha1.RetainPersistentVolumeClaimRetentionPolicyType,
+		WhenScaled:  appsv1alpha1.RetainPersistentVolumeClaimRetentionPolicyType,
+	}
+	if set.Spec.PersistentVolumeClaimRetentionPolicy != nil {
+		if set.Spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted != "" {
+			policy.WhenDeleted = set.Spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted
+		}
+		if set.Spec.PersistentVolumeClaimRetentionPolicy.WhenScaled != "" {
+			policy.WhenScaled = set.Spec.PersistentVolumeClaimRetentionPolicy.WhenScaled
+		}
+	}
+	return policy
+}
+
+// updateClaimOwnerRefForSetAndPod updates the owner references of claim according to the retention policy of set,
+// and returns true if claim has been modified. When WhenDeleted is Delete, claim is owned by set so that it will be
+// garbage collected with set. When WhenScaled is Delete and pod has been condemned by scale-in, which means its
+// ordinal is not one of the ordinals that set should have, claim is owned only by pod so that it will be garbage
+// collected with pod. Claims of pods that are kept or just recreated for update
+// are never owned by the pods.
+func updateClaimOwnerRefForSetAndPod(claim *v1.PersistentVolumeClaim, set *appsv1alpha1.StatefulSet, pod *v1.Pod, condemned bool) bool {
+	policy := getPersistentVolumeClaimRetentionPolicy(set)
+	setRef := newOwnerRef(set, controllerKind)
+	podRef := newOwnerRef(pod, podKind)
+
+	needsUpdate := false
+	switch {
+	case policy.WhenScaled == appsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType && condemned:
+		needsUpdate = removeOwnerRef(claim, setRef) || needsUpdate
+		needsUpdate = setOwnerRef(claim, podRef) || needsUpdate
+	case policy.WhenDeleted == appsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType:
+		needsUpdate = setOwnerRef(claim, setRef) || needsUpdate
+		needsUpdate = removeOwnerRef(claim, podRef) || needsUpdate
+	default:
+		needsUpdate = removeOwnerRef(claim, setRef) || needsUpdate
+		needsUpdate = removeOwnerRef(claim, podRef) || needsUpdate
+	}
+	return needsUpdate
+}
+
+// isClaimOwnedByStalePod returns true if claim is owned by a Pod other than pod, which means it was condemned
+// with a previous Pod of the same ordinal and is waiting to be garbage collected.
+func isClaimOwnedByStalePod(claim *v1.PersistentVolumeClaim, pod *v1.Pod) bool {
+	for _, ref := range claim.OwnerReferences {
+		if ref.Kind == podKind.Kind && ref.APIVersion == podKind.GroupVersion().String() && ref.UID != pod.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// newOwnerRef returns a non-controller OwnerReference pointing to obj.
+func newOwnerRef(obj metav1.Object, gvk schema.GroupVersionKind) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       obj.GetName(),
+		UID:        obj.GetUID(),
+	}
+}
+
+// setOwnerRef adds ref to the owner references of claim if absent, and returns true if claim has been modified.
+func setOwnerRef(claim *v1.PersistentVolumeClaim, ref metav1.OwnerReference) bool {
+	for _, r := range claim.OwnerReferences {
+		if r.UID == ref.UID {
+			return false
+		}
+	}
+	claim.OwnerReferences = append(claim.OwnerReferences, ref)
+	return true
+}
+
+// removeOwnerRef removes ref from the owner references of claim, and returns true if claim has been modified.
+func removeOwnerRef(claim *v1.PersistentVolumeClaim, ref metav1.OwnerReference) bool {
+	var refs []metav1.OwnerReference
+	for _, r := range claim.OwnerReferences {
+		if r.UID != ref.UID {
+			refs = append(refs, r)
+		}
+	}
+	if len(refs) == len(claim.OwnerReferences) {
+		return false
+	}
+	claim.OwnerReferences = refs
+	return true
+}
+
 // updateStorage updates pod's Volumes to conform with the PersistentVolumeClaim of set's templates. If pod has
 // conflicting local Volumes these are replaced with Volumes that conform to the set's templates.
 func updateStorage(set *appsv1alpha1.StatefulSet, pod *v1.Pod) {
@@ -180,9 +299,33 @@ func updateIdentity(set *appsv1alpha1.StatefulSet, pod *v1.Pod) {
 	pod.Labels[apps.StatefulSetPodNameLabel] = pod.Name
 }
 
-// isRunningAndReady returns true if pod is in the PodRunning Phase, if it has a condition of PodReady.
+// isRunningAndReady returns true if pod is in the PodRunning Phase, if it has a condition of PodReady
+// and has no container being recreated.
 func isRunningAndReady(pod *v1.Pod) bool {
-	return pod.Status.Phase == v1.PodRunning && podutil.IsPodReady(pod)
+	return pod.Status.Phase == v1.PodRunning && podutil.IsPodReady(pod) &&
+		pod.Annotations[appsv1alpha1.ContainerRecreatingKey] == ""
+}
+
+// isRunningAndAvailable returns true if pod is running and ready for at least minReadySeconds
+func isRunningAndAvailable(pod *v1.Pod, minReadySeconds int32) bool {
+	return isRunningAndReady(pod) && podutil.IsPodAvailable(pod, minReadySeconds, metav1.Now())
+}
+
+// getPodAvailableDelay returns the time to wait before pod becomes available, or 0 if pod is not ready
+// or has been available.
+func getPodAvailableDelay(pod *v1.Pod, minReadySeconds int32) time.Duration {
+	if minReadySeconds <= 0 || !isRunningAndReady(pod) {
+		return 0
+	}
+	condition := podutil.GetPodReadyCondition(pod.Status)
+	if condition == nil {
+		return 0
+	}
+	minReadyDuration := time.Duration(minReadySeconds) * time.Second
+	if delay := condition.LastTransitionTime.Add(minReadyDuration).Sub(time.Now()); delay > 0 {
+		return delay
+	}
+	return 0
 }
 
 // isCreated returns true if pod has been created and is maintained by the API server
@@ -229,45 +372,124 @@ func getPodRevision(pod *v1.Pod) string {
 
 // newStatefulSetPod returns a new Pod conforming to the set's Spec with an identity generated from ordinal.
 func newStatefulSetPod(set *appsv1alpha1.StatefulSet, ordinal int) *v1.Pod {
-	pod, _ := controller.GetPodFromTemplate(&set.Spec.Template, set, metav1.NewControllerRef(set, controllerKind))
+	return newStatefulSetPodFromTemplate(set, &set.Spec.Template, ordinal)
+}
+
+// newStatefulSetPodWithOverrides returns a new Pod like newStatefulSetPod, but the podOverrides of ordinal
+// in the set's Spec have been applied to its template. If the returned error is nil, the returned Pod is valid.
+func newStatefulSetPodWithOverrides(set *appsv1alpha1.StatefulSet, ordinal int) (*v1.Pod, error) {
+	template, err := statefulsetutil.ApplyPodOverrides(&set.Spec.Template, set.Spec.PodOverrides, ordinal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply podOverrides to ordinal %d: %v", ordinal, err)
+	}
+	return newStatefulSetPodFromTemplate(set, template, ordinal), nil
+}
+
+func newStatefulSetPodFromTemplate(set *appsv1alpha1.StatefulSet, template *v1.PodTemplateSpec, ordinal int) *v1.Pod {
+	pod, _ := controller.GetPodFromTemplate(template, set, metav1.NewControllerRef(set, controllerKind))
 	pod.Name = getPodName(set, ordinal)
 	initIdentity(set, pod)
 	updateStorage(set, pod)
 	return pod
 }
 
+// getRevisionsOfOrdinal returns the copies of revisions, whose data are replaced by the patches of the Pod template
+// with ordinal, in which the podOverrides of each revision have been applied. The returned revisions can be compared
+// to decide whether and how the Pod with ordinal should be updated. A nil revision is returned as nil.
+func getRevisionsOfOrdinal(set *appsv1alpha1.StatefulSet, ordinal int, revisions ...*apps.ControllerRevision) ([]*apps.ControllerRevision, error) {
+	results := make([]*apps.ControllerRevision, len(revisions))
+	for i, revision := range revisions {
+		if revision == nil {
+			continue
+		}
+		revisionSet, err := ApplyRevision(set, revision)
+		if err != nil {
+			return nil, err
+		}
+		template, err := statefulsetutil.ApplyPodOverrides(&revisionSet.Spec.Template, revisionSet.Spec.PodOverrides, ordinal)
+		if err != nil {
+			return nil, err
+		}
+		revisionSet.Spec.Template = *template
+		revisionSet.Spec.PodOverrides = nil
+		patch, err := getPatch(revisionSet)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = revision.DeepCopy()
+		results[i].Data.Raw = patch
+	}
+	return results, nil
+}
+
+// getPodRevisionsOfOrdinal returns the current revision of pod and updateRevision, in which the podOverrides of
+// the ordinal of pod have been applied. The returned current revision is nil if it is not found in revisions.
+func getPodRevisionsOfOrdinal(set *appsv1alpha1.StatefulSet, pod *v1.Pod,
+	updateRevision *apps.ControllerRevision, revisions []*apps.ControllerRevision,
+) (*apps.ControllerRevision, *apps.ControllerRevision, error) {
+	var oldRevision *apps.ControllerRevision
+	for _, r := range revisions {
+		if r.Name == getPodRevision(pod) {
+			oldRevision = r
+			break
+		}
+	}
+	ordinalRevisions, err := getRevisionsOfOrdinal(set, getOrdinal(pod), oldRevision, updateRevision)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ordinalRevisions[0], ordinalRevisions[1], nil
+}
+
+// updatePodRevision sets the revision label of pod to revision. If pod has the state of an in-place update to its
+// current revision, the state is moved to the new revision, so that the completion of that update is still checked.
+func updatePodRevision(pod *v1.Pod, revision string) {
+	if stateStr, ok := pod.Annotations[appsv1alpha1.InPlaceUpdateStateKey]; ok {
+		state := appsv1alpha1.InPlaceUpdateState{}
+		if err := json.Unmarshal([]byte(stateStr), &state); err == nil && state.Revision == getPodRevision(pod) {
+			state.Revision = revision
+			stateJSON, _ := json.Marshal(state)
+			pod.Annotations[appsv1alpha1.InPlaceUpdateStateKey] = string(stateJSON)
+		}
+	}
+	setPodRevision(pod, revision)
+}
+
 // newVersionedStatefulSetPod creates a new Pod for a StatefulSet. currentSet is the representation of the set at the
 // current revision. updateSet is the representation of the set at the updateRevision. currentRevision is the name of
 // the current revision. updateRevision is the name of the update revision. ordinal is the ordinal of the Pod. If the
 // returned error is nil, the returned Pod is valid.
 func newVersionedStatefulSetPod(currentSet, updateSet *appsv1alpha1.StatefulSet, currentRevision, updateRevision string,
-	ordinal int, replicas []*v1.Pod,
-) *v1.Pod {
-	if isCurrentRevisionNeeded(currentSet, updateRevision, ordinal, replicas) {
-		pod := newStatefulSetPod(currentSet, ordinal)
-		setPodRevision(pod, currentRevision)
-		return pod
-	}
-	pod := newStatefulSetPod(updateSet, ordinal)
-	setPodRevision(pod, updateRevision)
-	return pod
+	ordinal, index int, replicas []*v1.Pod,
+) (*v1.Pod, error) {
+	set, revision := updateSet, updateRevision
+	if isCurrentRevisionNeeded(currentSet, updateRevision, index, replicas) {
+		set, revision = currentSet, currentRevision
+	}
+	pod, err := newStatefulSetPodWithOverrides(set, ordinal)
+	if err != nil {
+		return nil, err
+	}
+	setPodRevision(pod, revision)
+	return pod, nil
 }
 
-// isCurrentRevisionNeeded calculate if the 'ordinal' Pod should be current revision.
-func isCurrentRevisionNeeded(set *appsv1alpha1.StatefulSet, updateRevision string, ordinal int, replicas []*v1.Pod) bool {
+// isCurrentRevisionNeeded calculate if the Pod at index of replicas should be current revision.
+// The index is the position of Pod in the actual ordinals of set, which excludes the reserved ordinals.
+func isCurrentRevisionNeeded(set *appsv1alpha1.StatefulSet, updateRevision string, index int, replicas []*v1.Pod) bool {
 	if set.Spec.UpdateStrategy.Type != apps.RollingUpdateStatefulSetStrategyType {
 		return false
 	}
 	if set.Spec.UpdateStrategy.RollingUpdate == nil {
-		return ordinal < int(set.Status.CurrentReplicas)
+		return index < int(set.Status.CurrentReplicas)
 	}
 	if set.Spec.UpdateStrategy.RollingUpdate.UnorderedUpdate == nil {
-		return ordinal < int(*set.Spec.UpdateStrategy.RollingUpdate.Partition)
+		return index < int(*set.Spec.UpdateStrategy.RollingUpdate.Partition)
 	}
 
 	var noUpdatedReplicas int
 	for i, pod := range replicas {
-		if pod == nil || i == ordinal {
+		if pod == nil || i == index {
 			continue
 		}
 		if getPodRevision(pod) != updateRevision {
@@ -303,6 +525,10 @@ func getPatch(set *appsv1alpha1.StatefulSet) ([]byte, error) {
 	template := spec["template"].(map[string]interface{})
 	specCopy["template"] = template
 	template["$patch"] = "replace"
+	// podOverrides are only recorded if specified, so that the revisions of sets without them are not changed
+	if podOverrides, ok := spec["podOverrides"]; ok {
+		specCopy["podOverrides"] = podOverrides
+	}
 	objCopy["spec"] = specCopy
 	patch, err := json.Marshal(objCopy)
 	return patch, err
@@ -339,6 +565,8 @@ func newRevision(set *appsv1alpha1.StatefulSet, revision int64, collisionCount *
 // is nil, the returned StatefulSet is valid.
 func ApplyRevision(set *appsv1alpha1.StatefulSet, revision *apps.ControllerRevision) (*appsv1alpha1.StatefulSet, error) {
 	clone := set.DeepCopy()
+	// podOverrides are absent in the revisions without them, so they should be cleared before the patch is applied
+	clone.Spec.PodOverrides = nil
 	patched, err := strategicpatch.StrategicMergePatch([]byte(runtime.EncodeOrDie(patchCodec, clone)), revision.Data.Raw, clone)
 	if err != nil {
 		return nil, err
@@ -372,7 +600,12 @@ func inconsistentStatus(set *appsv1alpha1.StatefulSet, status *appsv1alpha1.Stat
 		status.UpdatedReplicas != set.Status.UpdatedReplicas ||
 		status.CurrentRevision != set.Status.CurrentRevision ||
 		status.UpdateRevision != set.Status.UpdateRevision ||
-		status.LabelSelector != set.Status.LabelSelector
+		status.LabelSelector != set.Status.LabelSelector ||
+		status.InPlaceUpdateFallbackCount != set.Status.InPlaceUpdateFallbackCount ||
+		status.VolumeClaimUpdatedReplicas != set.Status.VolumeClaimUpdatedReplicas ||
+		status.AvailableReplicas != set.Status.AvailableReplicas ||
+		status.UpdatedAvailableReplicas != set.Status.UpdatedAvailableReplicas ||
+		condition.Inconsistent(toConditions(set.Status.Conditions), toConditions(status.Conditions))
 }
 
 // completeRollingUpdate completes a rolling update when all of set's replica Pods have been updated
@@ -452,6 +685,90 @@ func filterOutCondition(conditions []apps.StatefulSetCondition, condType apps.St
 	return newCondtitions
 }
 
+// updateStatefulSetConditions sets the Progressing, Available and ReplicaFailure conditions into the status,
+// by comparing it with the old status of set.
+func updateStatefulSetConditions(set *appsv1alpha1.StatefulSet, status *appsv1alpha1.StatefulSetStatus) {
+	oldProgress := getStatefulSetProgress(set, &set.Status)
+	newProgress := getStatefulSetProgress(set, status)
+	if failedCondition := GetStatefulsetConditition(*status, appsv1alpha1.FailedCreatePod); failedCondition != nil && failedCondition.Status == v1.ConditionTrue {
+		newProgress.FailureReason, newProgress.FailureMessage = appsv1alpha1.WorkloadReasonFailedScale, failedCondition.Message
+	} else if failedCondition := GetStatefulsetConditition(*status, appsv1alpha1.FailedUpdatePod); failedCondition != nil && failedCondition.Status == v1.ConditionTrue {
+		newProgress.FailureReason, newProgress.FailureMessage = appsv1alpha1.WorkloadReasonFailedUpdate, failedCondition.Message
+	}
+
+	conditions := condition.Compute(toConditions(set.Status.Conditions), oldProgress, newProgress, timeNow())
+
+	var newConditions []apps.StatefulSetCondition
+	for _, c := range status.Conditions {
+		if !condition.IsWorkloadConditionType(string(c.Type)) {
+			newConditions = append(newConditions, c)
+		}
+	}
+	status.Conditions = append(newConditions, fromConditions(conditions)...)
+}
+
+// toConditions converts the conditions of StatefulSet into the common form of workload conditions.
+// StatefulSetCondition has no lastUpdateTime, which is only used by the progress deadline that
+// Advanced StatefulSet does not support.
+func toConditions(statefulSetConditions []apps.StatefulSetCondition) []condition.Condition {
+	var conditions []condition.Condition
+	for _, c := range statefulSetConditions {
+		conditions = append(conditions, condition.Condition{
+			Type:               string(c.Type),
+			Status:             c.Status,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	return conditions
+}
+
+func fromConditions(conditions []condition.Condition) []apps.StatefulSetCondition {
+	var statefulSetConditions []apps.StatefulSetCondition
+	for _, c := range conditions {
+		statefulSetConditions = append(statefulSetConditions, apps.StatefulSetCondition{
+			Type:               apps.StatefulSetConditionType(c.Type),
+			Status:             c.Status,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	return statefulSetConditions
+}
+
+func getStatefulSetProgress(set *appsv1alpha1.StatefulSet, status *appsv1alpha1.StatefulSetStatus) *condition.Progress {
+	replicas := int32(1)
+	if set.Spec.Replicas != nil {
+		replicas = *set.Spec.Replicas
+	}
+	progress := &condition.Progress{
+		Kind:                 "StatefulSet",
+		Replicas:             replicas,
+		ActualReplicas:       status.Replicas,
+		AvailableReplicas:    status.AvailableReplicas,
+		MinAvailableReplicas: replicas - 1,
+		UpdatedReplicas:      status.UpdatedReplicas,
+		UpdatedReadyReplicas: status.UpdatedAvailableReplicas,
+		UpdateRevision:       status.UpdateRevision,
+	}
+	if set.Spec.UpdateStrategy.RollingUpdate != nil {
+		maxUnavailable, _ := intstrutil.GetValueFromIntOrPercent(
+			intstrutil.ValueOrDefault(set.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable, intstrutil.FromInt(1)), int(replicas), false)
+		// maxUnavailable should not less than 1
+		if maxUnavailable < 1 {
+			maxUnavailable = 1
+		}
+		progress.MinAvailableReplicas = replicas - int32(maxUnavailable)
+		if set.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+			progress.Partition = *set.Spec.UpdateStrategy.RollingUpdate.Partition
+		}
+		progress.Paused = set.Spec.UpdateStrategy.RollingUpdate.Paused
+	}
+	return progress
+}
+
 func getStatefulSetKey(o metav1.Object) string {
 	return o.GetNamespace() + "/" + o.GetName()
 }
@@ -460,3 +777,12 @@ func isInPlaceOnly(set *appsv1alpha1.StatefulSet) bool {
 	return set.Spec.UpdateStrategy.RollingUpdate != nil &&
 		set.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy == appsv1alpha1.InPlaceOnlyPodUpdateStrategyType
 }
+
+func getInPlaceUpdateOptions(set *appsv1alpha1.StatefulSet) *inplaceupdate.UpdateOptions {
+	opts := &inplaceupdate.UpdateOptions{}
+	if set.Spec.UpdateStrategy.RollingUpdate != nil && set.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy != nil {
+		opts.GracePeriodSeconds = set.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy.GracePeriodSeconds
+		opts.TimeoutSeconds = set.Spec.UpdateStrategy.RollingUpdate.InPlaceUpdateStrategy.TimeoutSeconds
+	}
+	return opts
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package statefulset
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"reflect"
@@ -27,8 +28,10 @@ import (
 	"time"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	statefulsetutil "github.com/openkruise/kruise/pkg/util/statefulset"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
 	apps "k8s.io/api/apps/v1"
@@ -68,6 +71,25 @@ func TestGetParentNameAndOrdinal(t *testing.T) {
 	}
 }
 
+func TestIsCurrentRevisionNeededWithReserveOrdinals(t *testing.T) {
+	set := newStatefulSet(3)
+	set.Spec.ReserveOrdinals = []int{1}
+	set.Spec.UpdateStrategy.RollingUpdate = &appsv1alpha1.RollingUpdateStatefulSetStrategy{Partition: func() *int32 { p := int32(1); return &p }()}
+	ordinals := statefulsetutil.GetOrdinals(set)
+	replicas := make([]*v1.Pod, len(ordinals))
+
+	var current []int
+	for idx, ord := range ordinals {
+		if isCurrentRevisionNeeded(set, "update", idx, replicas) {
+			current = append(current, ord)
+		}
+	}
+	// partition is counted on the actual ordinals, so only the first Pod is kept in current revision
+	if !reflect.DeepEqual(current, []int{0}) {
+		t.Fatalf("expected only ordinal 0 in current revision, got %v", current)
+	}
+}
+
 func TestIsMemberOf(t *testing.T) {
 	set := newStatefulSet(3)
 	set2 := newStatefulSet(3)
@@ -197,6 +219,84 @@ func TestUpdateStorage(t *testing.T) {
 	}
 }
 
+func TestUpdateClaimOwnerRefForSetAndPod(t *testing.T) {
+	const retain = appsv1alpha1.RetainPersistentVolumeClaimRetentionPolicyType
+	const del = appsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType
+	cases := []struct {
+		name         string
+		policy       *appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy
+		scaledDown   bool
+		expectSetRef bool
+		expectPodRef bool
+	}{
+		{name: "default policy", policy: nil, expectSetRef: false, expectPodRef: false},
+		{name: "retain/retain scaled down", policy: &appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy{WhenDeleted: retain, WhenScaled: retain}, scaledDown: true},
+		{name: "delete when deleted", policy: &appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy{WhenDeleted: del}, expectSetRef: true},
+		{name: "delete when deleted scaled down", policy: &appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy{WhenDeleted: del}, scaledDown: true, expectSetRef: true},
+		{name: "delete when scaled", policy: &appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy{WhenScaled: del}},
+		{name: "delete when scaled scaled down", policy: &appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy{WhenScaled: del}, scaledDown: true, expectPodRef: true},
+		{name: "delete/delete", policy: &appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy{WhenDeleted: del, WhenScaled: del}, expectSetRef: true},
+		{name: "delete/delete scaled down", policy: &appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy{WhenDeleted: del, WhenScaled: del}, scaledDown: true, expectPodRef: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			set := newStatefulSet(3)
+			set.Spec.PersistentVolumeClaimRetentionPolicy = tc.policy
+			pod := newStatefulSetPod(set, 1)
+			pod.UID = types.UID("pod-1")
+			if tc.scaledDown {
+				set.Spec.Replicas = func() *int32 { i := int32(1); return &i }()
+			}
+			claims := getPersistentVolumeClaims(set, pod)
+			claim := claims["datadir"]
+			// start from the opposite state so that both adding and removing are covered
+			if !tc.expectSetRef {
+				claim.OwnerReferences = append(claim.OwnerReferences, newOwnerRef(set, controllerKind))
+			}
+			if !tc.expectPodRef {
+				claim.OwnerReferences = append(claim.OwnerReferences, newOwnerRef(pod, podKind))
+			}
+			if !updateClaimOwnerRefForSetAndPod(&claim, set, pod, tc.scaledDown) {
+				t.Fatalf("expected claim to be updated")
+			}
+			if updateClaimOwnerRefForSetAndPod(&claim, set, pod, tc.scaledDown) {
+				t.Fatalf("expected claim to be consistent after update")
+			}
+			var hasSetRef, hasPodRef bool
+			for _, ref := range claim.OwnerReferences {
+				switch ref.UID {
+				case set.UID:
+					hasSetRef = true
+				case pod.UID:
+					hasPodRef = true
+				}
+			}
+			if hasSetRef != tc.expectSetRef || hasPodRef != tc.expectPodRef {
+				t.Fatalf("expected setRef=%v podRef=%v, got setRef=%v podRef=%v",
+					tc.expectSetRef, tc.expectPodRef, hasSetRef, hasPodRef)
+			}
+		})
+	}
+}
+
+func TestIsClaimOwnedByStalePod(t *testing.T) {
+	set := newStatefulSet(3)
+	pod := newStatefulSetPod(set, 1)
+	pod.UID = types.UID("old")
+	claim := getPersistentVolumeClaims(set, pod)["datadir"]
+	if isClaimOwnedByStalePod(&claim, pod) {
+		t.Fatalf("expected claim without owners not to be stale")
+	}
+	claim.OwnerReferences = []metav1.OwnerReference{newOwnerRef(set, controllerKind), newOwnerRef(pod, podKind)}
+	if isClaimOwnedByStalePod(&claim, pod) {
+		t.Fatalf("expected claim owned by the same Pod not to be stale")
+	}
+	recreated := newStatefulSetPod(set, 1)
+	if !isClaimOwnedByStalePod(&claim, recreated) {
+		t.Fatalf("expected claim owned by a previous Pod to be stale")
+	}
+}
+
 func TestIsRunningAndReady(t *testing.T) {
 	set := newStatefulSet(3)
 	pod := newStatefulSetPod(set, 1)
@@ -339,6 +439,41 @@ func TestRollingUpdateApplyRevision(t *testing.T) {
 	}
 }
 
+func TestPodOverridesApplyRevision(t *testing.T) {
+	set := newStatefulSet(1)
+	set.Status.CollisionCount = new(int32)
+	revisionWithoutOverrides, err := newRevision(set, 1, set.Status.CollisionCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set.Spec.PodOverrides = []appsv1alpha1.StatefulSetPodOverride{
+		{Ordinals: "0", Patch: runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"role":"bootstrap"}}}`)}},
+	}
+	revisionWithOverrides, err := newRevision(set, 2, set.Status.CollisionCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history.EqualRevision(revisionWithoutOverrides, revisionWithOverrides) {
+		t.Fatalf("expected podOverrides to change the revision")
+	}
+
+	restoredSet, err := ApplyRevision(set, revisionWithoutOverrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restoredSet.Spec.PodOverrides) != 0 {
+		t.Errorf("expected no podOverrides restored, got %v", restoredSet.Spec.PodOverrides)
+	}
+	restoredSet, err = ApplyRevision(set, revisionWithOverrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(set.Spec.PodOverrides, restoredSet.Spec.PodOverrides) {
+		t.Errorf("want %v got %v", set.Spec.PodOverrides, restoredSet.Spec.PodOverrides)
+	}
+}
+
 func newPVC(name string) v1.PersistentVolumeClaim {
 	return v1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -424,3 +559,59 @@ func newStatefulSet(replicas int) *appsv1alpha1.StatefulSet {
 	}
 	return newStatefulSetWithVolumes(replicas, "foo", petMounts, podMounts)
 }
+
+func TestUpdatePodRevision(t *testing.T) {
+	set := newStatefulSet(1)
+	pod := newStatefulSetPod(set, 0)
+	setPodRevision(pod, "rev-1")
+	pod.Annotations = map[string]string{appsv1alpha1.InPlaceUpdateStateKey: `{"revision":"rev-1","lastContainerStatuses":{"nginx":{"imageID":"img-1"}}}`}
+
+	updatePodRevision(pod, "rev-2")
+	if getPodRevision(pod) != "rev-2" {
+		t.Fatalf("expected revision rev-2, got %s", getPodRevision(pod))
+	}
+	state := appsv1alpha1.InPlaceUpdateState{}
+	if err := json.Unmarshal([]byte(pod.Annotations[appsv1alpha1.InPlaceUpdateStateKey]), &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Revision != "rev-2" || state.LastContainerStatuses["nginx"].ImageID != "img-1" {
+		t.Fatalf("expected in-place update state moved to rev-2, got %+v", state)
+	}
+}
+
+func TestNewVersionedStatefulSetPodWithInvalidOverrides(t *testing.T) {
+	set := newStatefulSet(3)
+	set.Spec.PodOverrides = []appsv1alpha1.StatefulSetPodOverride{
+		{Ordinals: "1", Patch: runtime.RawExtension{Raw: []byte(`{"spec":{"containers":"invalid"}}`)}},
+	}
+	if _, err := newVersionedStatefulSetPod(set, set, "rev", "rev", 0, 0, nil); err != nil {
+		t.Fatalf("expected Pod of ordinal 0 created without overrides, got error %v", err)
+	}
+	if pod, err := newVersionedStatefulSetPod(set, set, "rev", "rev", 1, 1, nil); err == nil {
+		t.Fatalf("expected error for invalid overrides, got Pod %v", pod)
+	}
+}
+
+func TestUpdateStatefulSetConditions(t *testing.T) {
+	set := newStatefulSet(3)
+	set.Status = appsv1alpha1.StatefulSetStatus{Replicas: 3, AvailableReplicas: 3, UpdatedReplicas: 3, UpdatedAvailableReplicas: 3, UpdateRevision: "v1"}
+	failedUpdate := apps.StatefulSetCondition{Type: appsv1alpha1.FailedUpdatePod, Status: v1.ConditionTrue, Message: "conflict"}
+	status := &appsv1alpha1.StatefulSetStatus{Replicas: 3, AvailableReplicas: 3, UpdateRevision: "v2",
+		Conditions: []apps.StatefulSetCondition{failedUpdate}}
+	updateStatefulSetConditions(set, status)
+
+	if cond := GetStatefulsetConditition(*status, appsv1alpha1.FailedUpdatePod); cond == nil || *cond != failedUpdate {
+		t.Fatalf("expected FailedUpdatePod kept, got %+v", cond)
+	}
+	if cond := GetStatefulsetConditition(*status, appsv1alpha1.StatefulSetConditionProgressing); cond == nil ||
+		cond.Status != v1.ConditionTrue || cond.Reason != appsv1alpha1.WorkloadReasonNewRevisionProgressing {
+		t.Fatalf("expected Progressing to new revision, got %+v", cond)
+	}
+	if cond := GetStatefulsetConditition(*status, appsv1alpha1.StatefulSetConditionReplicaFailure); cond == nil ||
+		cond.Reason != appsv1alpha1.WorkloadReasonFailedUpdate || cond.Message != failedUpdate.Message {
+		t.Fatalf("expected ReplicaFailure from FailedUpdatePod, got %+v", cond)
+	}
+	if !inconsistentStatus(set, status) {
+		t.Fatalf("expected status changed by conditions")
+	}
+}
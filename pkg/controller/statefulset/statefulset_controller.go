@@ -27,11 +27,13 @@ import (
 	kruiseappslisters "github.com/openkruise/kruise/pkg/client/listers/apps/v1alpha1"
 	"github.com/openkruise/kruise/pkg/util/expectations"
 	"github.com/openkruise/kruise/pkg/util/gate"
+	"github.com/openkruise/kruise/pkg/util/headlessservice"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
 	"github.com/openkruise/kruise/pkg/util/requeueduration"
 	apps "k8s.io/api/apps/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -40,10 +42,12 @@ import (
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	appslisters "k8s.io/client-go/listers/apps/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
 	"k8s.io/kubernetes/pkg/controller/history"
+	kubeClient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -97,10 +101,15 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 	if err != nil {
 		return nil, err
 	}
+	scInformer, err := cacher.GetInformerForKind(storagev1.SchemeGroupVersion.WithKind("StorageClass"))
+	if err != nil {
+		return nil, err
+	}
 
 	statefulSetLister := kruiseappslisters.NewStatefulSetLister(statefulSetInformer.GetIndexer())
 	podLister := corelisters.NewPodLister(podInformer.GetIndexer())
 	pvcLister := corelisters.NewPersistentVolumeClaimLister(pvcInformer.GetIndexer())
+	scLister := storagelisters.NewStorageClassLister(scInformer.GetIndexer())
 
 	genericClient := client.GetGenericClient()
 	//recorder := mgr.GetRecorder("statefulset-controller")
@@ -111,12 +120,14 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 
 	return &ReconcileStatefulSet{
 		kruiseClient: genericClient.KruiseClient,
+		client:       mgr.GetClient(),
 		control: NewDefaultStatefulSetControl(
 			NewRealStatefulPodControl(
 				genericClient.KubeClient,
 				statefulSetLister,
 				podLister,
 				pvcLister,
+				scLister,
 				recorder),
 			inplaceupdate.New(mgr.GetClient(), appsv1.ControllerRevisionHashLabelKey),
 			NewRealStatefulSetStatusUpdater(genericClient.KruiseClient, statefulSetLister),
@@ -126,6 +137,7 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 		podControl: kubecontroller.RealPodControl{KubeClient: genericClient.KubeClient, Recorder: recorder},
 		podLister:  podLister,
 		setLister:  statefulSetLister,
+		recorder:   recorder,
 	}, nil
 }
 
@@ -135,6 +147,8 @@ var _ reconcile.Reconciler = &ReconcileStatefulSet{}
 type ReconcileStatefulSet struct {
 	// client interface
 	kruiseClient kruiseclientset.Interface
+	// client is used to manage the headless Service
+	client kubeClient.Client
 	// control returns an interface capable of syncing a stateful set.
 	// Abstracted out for testing.
 	control ControlInterface
@@ -144,6 +158,8 @@ type ReconcileStatefulSet struct {
 	podLister corelisters.PodLister
 	// setLister is able to list/get stateful sets from a shared informer's store
 	setLister kruiseappslisters.StatefulSetLister
+	// recorder is used to record events on stateful sets
+	recorder record.EventRecorder
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -178,6 +194,15 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to headless Service managed by StatefulSet
+	err = c.Watch(&source.Kind{Type: &v1.Service{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &appsv1alpha1.StatefulSet{},
+	})
+	if err != nil {
+		return err
+	}
+
 	klog.V(4).Infof("finished to add statefulset-controller")
 
 	return nil
@@ -190,6 +215,8 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 // +kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=statefulsets/status,verbs=get;update;patch
@@ -305,6 +332,9 @@ func (ssc *ReconcileStatefulSet) getPodsForStatefulSet(set *appsv1alpha1.Statefu
 // syncStatefulSet syncs a tuple of (statefulset, []*v1.Pod).
 func (ssc *ReconcileStatefulSet) syncStatefulSet(set *appsv1alpha1.StatefulSet, pods []*v1.Pod) error {
 	klog.V(4).Infof("Syncing StatefulSet %v/%v with %d pods", set.Namespace, set.Name, len(pods))
+	if err := ssc.syncHeadlessService(set); err != nil {
+		return err
+	}
 	// TODO: investigate where we mutate the set during the update as it is not obvious.
 	if err := ssc.control.UpdateStatefulSet(set.DeepCopy(), pods); err != nil {
 		return err
@@ -312,3 +342,16 @@ func (ssc *ReconcileStatefulSet) syncStatefulSet(set *appsv1alpha1.StatefulSet,
 	klog.V(4).Infof("Successfully synced StatefulSet %s/%s successful", set.Namespace, set.Name)
 	return nil
 }
+
+// syncHeadlessService creates or updates the headless Service of set if it is managed by the controller.
+func (ssc *ReconcileStatefulSet) syncHeadlessService(set *appsv1alpha1.StatefulSet) error {
+	if set.Spec.ServiceManagementPolicy != appsv1alpha1.ManagedServiceManagementPolicyType ||
+		set.Spec.ServiceName == "" || set.DeletionTimestamp != nil {
+		return nil
+	}
+	svc := headlessservice.NewHeadlessService(set, controllerKind, set.Spec.ServiceName, set.Spec.Selector, &set.Spec.Template)
+	if err := headlessservice.Reconcile(ssc.client, ssc.recorder, set, svc); err != nil {
+		return fmt.Errorf("failed to sync headless Service %s for StatefulSet %s/%s: %v", svc.Name, set.Namespace, set.Name, err)
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package statefulset
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"sort"
@@ -43,6 +44,7 @@ import (
 	"k8s.io/client-go/informers"
 	appsinformers "k8s.io/client-go/informers/apps/v1"
 	coreinformers "k8s.io/client-go/informers/core/v1"
+	storageinformers "k8s.io/client-go/informers/storage/v1"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -54,6 +56,7 @@ import (
 	"k8s.io/kubernetes/pkg/controller"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
 	"k8s.io/kubernetes/pkg/controller/history"
+	runtimefake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -228,6 +231,36 @@ func TestStatefulSetControllerDeletionTimestamp(t *testing.T) {
 	}
 }
 
+func TestStatefulSetControllerSyncHeadlessService(t *testing.T) {
+	set := newStatefulSet(3)
+	set.Spec.ServiceName = "foo-headless"
+	set.Spec.Template.Spec.Containers[0].Ports = []v1.ContainerPort{{Name: "web", ContainerPort: 80}}
+	ssc := &ReconcileStatefulSet{client: runtimefake.NewFakeClient(), recorder: record.NewFakeRecorder(10)}
+
+	if err := ssc.syncHeadlessService(set); err != nil {
+		t.Fatal(err)
+	}
+	svc := &v1.Service{}
+	key := types.NamespacedName{Namespace: set.Namespace, Name: set.Spec.ServiceName}
+	if err := ssc.client.Get(context.TODO(), key, svc); err == nil {
+		t.Fatalf("expected no Service created for Manual policy, got %v", svc)
+	}
+
+	set.Spec.ServiceManagementPolicy = appsv1alpha1.ManagedServiceManagementPolicyType
+	if err := ssc.syncHeadlessService(set); err != nil {
+		t.Fatal(err)
+	}
+	if err := ssc.client.Get(context.TODO(), key, svc); err != nil {
+		t.Fatal(err)
+	}
+	if svc.Spec.ClusterIP != v1.ClusterIPNone || len(svc.Spec.Ports) != 1 || svc.Spec.Ports[0].Port != 80 {
+		t.Errorf("unexpected Service spec %v", svc.Spec)
+	}
+	if ref := metav1.GetControllerOf(svc); ref == nil || ref.UID != set.UID {
+		t.Errorf("expected Service controlled by StatefulSet, got %v", ref)
+	}
+}
+
 func TestStatefulSetControllerDeletionTimestampRace(t *testing.T) {
 	set := newStatefulSet(3)
 	// The bare client says it IS deleted.
@@ -624,6 +657,7 @@ func newFakeStatefulSetController(initialObjects ...runtime.Object) (*StatefulSe
 		informerFactory.Core().V1().Pods(),
 		kruiseInformerFactory.Apps().V1alpha1().StatefulSets(),
 		informerFactory.Core().V1().PersistentVolumeClaims(),
+		informerFactory.Storage().V1().StorageClasses(),
 		informerFactory.Apps().V1().ControllerRevisions(),
 		client,
 		kruiseClient,
@@ -772,6 +806,7 @@ func NewStatefulSetController(
 	podInformer coreinformers.PodInformer,
 	setInformer kruiseappsinformers.StatefulSetInformer,
 	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+	scInformer storageinformers.StorageClassInformer,
 	revInformer appsinformers.ControllerRevisionInformer,
 	kubeClient clientset.Interface,
 	kruiseClient kruiseclientset.Interface,
@@ -790,6 +825,7 @@ func NewStatefulSetController(
 					setInformer.Lister(),
 					podInformer.Lister(),
 					pvcInformer.Lister(),
+					scInformer.Lister(),
 					recorder),
 				inplaceupdate.NewForTypedClient(kubeClient, apps.ControllerRevisionHashLabelKey),
 				NewRealStatefulSetStatusUpdater(kruiseClient, setInformer.Lister()),
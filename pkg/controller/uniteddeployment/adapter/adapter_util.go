@@ -22,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
 )
@@ -95,3 +96,8 @@ func getCurrentPartition(pods []*corev1.Pod, revision string) *int32 {
 
 	return &partition
 }
+
+// isPodReady returns true if the pod is ready and has no container being recreated.
+func isPodReady(pod *corev1.Pod) bool {
+	return podutil.IsPodReady(pod) && pod.Annotations[appsv1alpha1.ContainerRecreatingKey] == ""
+}
@@ -63,6 +63,22 @@ func TestGetCurrentPartitionForStrategyOnDelete(t *testing.T) {
 	}
 }
 
+func TestCalculateReplicasWithContainerRecreating(t *testing.T) {
+	pods := buildPodList([]int{0, 1, 2, 3}, []string{"v1", "v2", "v2", "v2"}, t)
+	for _, pod := range pods {
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	}
+	pods[2].Annotations = map[string]string{appsv1alpha1.ContainerRecreatingKey: "crr"}
+	pods[3].Status.Conditions = nil
+
+	if readyReplicas := calculateReadyReplicas(pods); readyReplicas != 2 {
+		t.Fatalf("expected ready replicas 2, got %d", readyReplicas)
+	}
+	if updatedReplicas, updatedReadyReplicas := calculateUpdatedReplicas(pods, "v2"); updatedReplicas != 3 || updatedReadyReplicas != 1 {
+		t.Fatalf("expected updated replicas 3 and updated ready replicas 1, got %d and %d", updatedReplicas, updatedReadyReplicas)
+	}
+}
+
 func buildPodList(ordinals []int, revisions []string, t *testing.T) []*corev1.Pod {
 	if len(ordinals) != len(revisions) {
 		t.Fatalf("ordinals count should equals to revision count")
@@ -74,7 +74,8 @@ func (a *StatefulSetAdapter) GetReplicaDetails(obj metav1.Object, updatedRevisio
 	}
 
 	statusReplicas = set.Status.Replicas
-	statusReadyReplicas = set.Status.ReadyReplicas
+	// the ready replicas in status of StatefulSet include the pods with containers being recreated
+	statusReadyReplicas = calculateReadyReplicas(pods)
 	statusUpdatedReplicas, statusUpdatedReadyReplicas = calculateUpdatedReplicas(pods, updatedRevision)
 
 	return
@@ -209,12 +210,21 @@ func (a *StatefulSetAdapter) getStatefulSetPods(set *appsv1.StatefulSet) ([]*cor
 	return claimedPods, nil
 }
 
+func calculateReadyReplicas(podList []*corev1.Pod) (readyReplicas int32) {
+	for _, pod := range podList {
+		if isPodReady(pod) {
+			readyReplicas++
+		}
+	}
+	return
+}
+
 func calculateUpdatedReplicas(podList []*corev1.Pod, updatedRevision string) (updatedReplicas, updatedReadyReplicas int32) {
 	for _, pod := range podList {
 		revision := getRevision(&pod.ObjectMeta)
 		if revision == updatedRevision {
 			updatedReplicas++
-			if podutil.IsPodReady(pod) {
+			if isPodReady(pod) {
 				updatedReadyReplicas++
 			}
 		}
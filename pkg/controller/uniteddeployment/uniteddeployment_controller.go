@@ -36,8 +36,10 @@ import (
 
 	"github.com/openkruise/kruise/pkg/controller/uniteddeployment/adapter"
 	"github.com/openkruise/kruise/pkg/util/gate"
+	"github.com/openkruise/kruise/pkg/util/headlessservice"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/condition"
 )
 
 const (
@@ -48,12 +50,15 @@ const (
 	eventTypeDupSubsetsDelete       = "DeleteDuplicatedSubsets"
 	eventTypeSubsetsUpdate          = "UpdateSubset"
 	eventTypeSpecifySubbsetReplicas = "SpecifySubsetReplicas"
+	eventTypeHeadlessServiceSync    = "SyncHeadlessService"
 
 	slowStartInitialBatchSize = 1
 )
 
 type subSetType string
 
+var controllerKind = appsv1alpha1.SchemeGroupVersion.WithKind("UnitedDeployment")
+
 const (
 	statefulSetSubSetType         subSetType = "StatefulSet"
 	advancedStatefulSetSubSetType subSetType = "AdvancedStatefulSet"
@@ -112,6 +117,14 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &appsv1alpha1.UnitedDeployment{},
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -135,6 +148,7 @@ type ReconcileUnitedDeployment struct {
 // +kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=statefulsets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch
 func (r *ReconcileUnitedDeployment) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	klog.V(4).Infof("Reconcile UnitedDeployment %s/%s", request.Namespace, request.Name)
 	// Fetch the UnitedDeployment instance
@@ -152,6 +166,12 @@ func (r *ReconcileUnitedDeployment) Reconcile(request reconcile.Request) (reconc
 	}
 	oldStatus := instance.Status.DeepCopy()
 
+	if err := r.syncHeadlessService(instance); err != nil {
+		klog.Errorf("Fail to sync headless Service of UnitedDeployment %s/%s: %s", instance.Namespace, instance.Name, err)
+		r.recorder.Event(instance.DeepCopy(), corev1.EventTypeWarning, fmt.Sprintf("Failed%s", eventTypeHeadlessServiceSync), err.Error())
+		return reconcile.Result{}, err
+	}
+
 	currentRevision, updatedRevision, _, collisionCount, err := r.constructUnitedDeploymentRevisions(instance)
 	if err != nil {
 		klog.Errorf("Fail to construct controller revision of UnitedDeployment %s/%s: %s", instance.Namespace, instance.Name, err)
@@ -191,6 +211,30 @@ func (r *ReconcileUnitedDeployment) Reconcile(request reconcile.Request) (reconc
 	return r.updateStatus(instance, newStatus, oldStatus, nameToSubset, nextReplicas, nextPartitions, currentRevision, updatedRevision, collisionCount, control)
 }
 
+// syncHeadlessService creates or updates the headless Service that selects the Pods of all the subsets,
+// if it is managed by the UnitedDeployment.
+func (r *ReconcileUnitedDeployment) syncHeadlessService(instance *appsv1alpha1.UnitedDeployment) error {
+	if instance.Spec.ServiceManagementPolicy != appsv1alpha1.ManagedServiceManagementPolicyType {
+		return nil
+	}
+
+	var serviceName string
+	var template *corev1.PodTemplateSpec
+	if instance.Spec.Template.StatefulSetTemplate != nil {
+		serviceName = instance.Spec.Template.StatefulSetTemplate.Spec.ServiceName
+		template = &instance.Spec.Template.StatefulSetTemplate.Spec.Template
+	} else if instance.Spec.Template.AdvancedStatefulSetTemplate != nil {
+		serviceName = instance.Spec.Template.AdvancedStatefulSetTemplate.Spec.ServiceName
+		template = &instance.Spec.Template.AdvancedStatefulSetTemplate.Spec.Template
+	}
+	if serviceName == "" {
+		return nil
+	}
+
+	svc := headlessservice.NewHeadlessService(instance, controllerKind, serviceName, instance.Spec.Selector, template)
+	return headlessservice.Reconcile(r.Client, r.recorder, instance, svc)
+}
+
 func (r *ReconcileUnitedDeployment) getNameToSubset(instance *appsv1alpha1.UnitedDeployment, control ControlInterface, expectedRevision string) (*map[string]*Subset, error) {
 	subSets, err := control.GetAllSubsets(instance, expectedRevision)
 	if err != nil {
@@ -341,6 +385,25 @@ func (r *ReconcileUnitedDeployment) calculateStatus(ud *appsv1alpha1.UnitedDeplo
 		SetUnitedDeploymentCondition(newStatus, NewUnitedDeploymentCondition(appsv1alpha1.SubsetFailure, corev1.ConditionTrue, "Error", *subsetFailure))
 	}
 
+	var replicas, partition int32
+	if ud.Spec.Replicas != nil {
+		replicas = *ud.Spec.Replicas
+	}
+	for _, p := range *nextPartition {
+		partition += p
+	}
+	updateUnitedDeploymentConditions(ud, newStatus, &condition.Progress{
+		Kind:                 "UnitedDeployment",
+		Replicas:             replicas,
+		ActualReplicas:       newStatus.Replicas,
+		AvailableReplicas:    newStatus.ReadyReplicas,
+		MinAvailableReplicas: replicas,
+		UpdatedReplicas:      newStatus.UpdatedReplicas,
+		UpdatedReadyReplicas: newStatus.UpdatedReadyReplicas,
+		Partition:            partition,
+		UpdateRevision:       expectedRevision,
+	})
+
 	return newStatus
 }
 
@@ -364,7 +427,7 @@ func (r *ReconcileUnitedDeployment) updateUnitedDeployment(ud *appsv1alpha1.Unit
 		ud.Generation == newStatus.ObservedGeneration &&
 		reflect.DeepEqual(oldStatus.SubsetReplicas, newStatus.SubsetReplicas) &&
 		reflect.DeepEqual(oldStatus.UpdateStatus, newStatus.UpdateStatus) &&
-		reflect.DeepEqual(oldStatus.Conditions, newStatus.Conditions) {
+		!condition.Inconsistent(toConditions(oldStatus.Conditions), toConditions(newStatus.Conditions)) {
 		return ud, nil
 	}
 
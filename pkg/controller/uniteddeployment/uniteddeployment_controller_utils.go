@@ -22,12 +22,14 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/condition"
 )
 
 const updateRetries = 5
@@ -81,6 +83,7 @@ func NewUnitedDeploymentCondition(condType appsv1alpha1.UnitedDeploymentConditio
 	return &appsv1alpha1.UnitedDeploymentCondition{
 		Type:               condType,
 		Status:             status,
+		LastUpdateTime:     metav1.Now(),
 		LastTransitionTime: metav1.Now(),
 		Reason:             reason,
 		Message:            message,
@@ -128,3 +131,72 @@ func filterOutCondition(conditions []appsv1alpha1.UnitedDeploymentCondition, con
 	}
 	return newConditions
 }
+
+// updateUnitedDeploymentConditions computes the Progressing, Available and ReplicaFailure conditions of the new status
+// by comparing the new progress with the one recorded in the current status of UnitedDeployment.
+func updateUnitedDeploymentConditions(ud *appsv1alpha1.UnitedDeployment, newStatus *appsv1alpha1.UnitedDeploymentStatus, newProgress *condition.Progress) {
+	if cond := GetUnitedDeploymentCondition(*newStatus, appsv1alpha1.SubsetProvisioned); cond != nil && cond.Status == corev1.ConditionFalse {
+		newProgress.FailureReason = appsv1alpha1.WorkloadReasonFailedScale
+		newProgress.FailureMessage = cond.Message
+	} else if cond := GetUnitedDeploymentCondition(*newStatus, appsv1alpha1.SubsetUpdated); cond != nil && cond.Status == corev1.ConditionFalse {
+		newProgress.FailureReason = appsv1alpha1.WorkloadReasonFailedUpdate
+		newProgress.FailureMessage = cond.Message
+	} else if cond := GetUnitedDeploymentCondition(*newStatus, appsv1alpha1.SubsetFailure); cond != nil && cond.Status == corev1.ConditionTrue {
+		newProgress.FailureReason = appsv1alpha1.WorkloadReasonFailedScale
+		newProgress.FailureMessage = cond.Message
+	}
+
+	var updateRevision string
+	if ud.Status.UpdateStatus != nil {
+		updateRevision = ud.Status.UpdateStatus.UpdatedRevision
+	}
+	oldProgress := &condition.Progress{
+		Replicas:             newProgress.Replicas,
+		ActualReplicas:       ud.Status.Replicas,
+		AvailableReplicas:    ud.Status.ReadyReplicas,
+		UpdatedReplicas:      ud.Status.UpdatedReplicas,
+		UpdatedReadyReplicas: ud.Status.UpdatedReadyReplicas,
+		UpdateRevision:       updateRevision,
+	}
+
+	conditions := condition.Compute(toConditions(newStatus.Conditions), oldProgress, newProgress, time.Now())
+	newStatus.Conditions = setWorkloadConditions(newStatus.Conditions, conditions)
+}
+
+// toConditions converts the conditions of UnitedDeployment into the common form of workload conditions.
+func toConditions(list []appsv1alpha1.UnitedDeploymentCondition) []condition.Condition {
+	var conditions []condition.Condition
+	for _, c := range list {
+		conditions = append(conditions, condition.Condition{
+			Type:               string(c.Type),
+			Status:             c.Status,
+			LastUpdateTime:     c.LastUpdateTime,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	return conditions
+}
+
+// setWorkloadConditions returns the conditions of UnitedDeployment in list whose types are not computed by package condition,
+// followed by the given workload conditions.
+func setWorkloadConditions(list []appsv1alpha1.UnitedDeploymentCondition, conditions []condition.Condition) []appsv1alpha1.UnitedDeploymentCondition {
+	var newList []appsv1alpha1.UnitedDeploymentCondition
+	for _, c := range list {
+		if !condition.IsWorkloadConditionType(string(c.Type)) {
+			newList = append(newList, c)
+		}
+	}
+	for _, c := range conditions {
+		newList = append(newList, appsv1alpha1.UnitedDeploymentCondition{
+			Type:               appsv1alpha1.UnitedDeploymentConditionType(c.Type),
+			Status:             c.Status,
+			LastUpdateTime:     c.LastUpdateTime,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		})
+	}
+	return newList
+}
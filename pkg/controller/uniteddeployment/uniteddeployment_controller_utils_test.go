@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uniteddeployment
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/condition"
+)
+
+func TestUpdateUnitedDeploymentConditions(t *testing.T) {
+	ud := &appsv1alpha1.UnitedDeployment{
+		Status: appsv1alpha1.UnitedDeploymentStatus{
+			Replicas:             4,
+			ReadyReplicas:        4,
+			UpdatedReplicas:      4,
+			UpdatedReadyReplicas: 4,
+			UpdateStatus:         &appsv1alpha1.UpdateStatus{UpdatedRevision: "v1"},
+		},
+	}
+	subsetUpdated := appsv1alpha1.UnitedDeploymentCondition{Type: appsv1alpha1.SubsetUpdated, Status: corev1.ConditionFalse, Message: "conflict"}
+	newStatus := &appsv1alpha1.UnitedDeploymentStatus{
+		Conditions: []appsv1alpha1.UnitedDeploymentCondition{subsetUpdated},
+	}
+	updateUnitedDeploymentConditions(ud, newStatus, &condition.Progress{
+		Kind:                 "UnitedDeployment",
+		Replicas:             4,
+		ActualReplicas:       4,
+		AvailableReplicas:    4,
+		MinAvailableReplicas: 4,
+		UpdateRevision:       "v2",
+	})
+
+	if cond := GetUnitedDeploymentCondition(*newStatus, appsv1alpha1.SubsetUpdated); cond == nil || *cond != subsetUpdated {
+		t.Fatalf("expected SubsetUpdated kept, got %+v", cond)
+	}
+	if cond := GetUnitedDeploymentCondition(*newStatus, appsv1alpha1.UnitedDeploymentConditionAvailable); cond == nil || cond.Status != corev1.ConditionTrue {
+		t.Fatalf("expected Available True, got %+v", cond)
+	}
+	if cond := GetUnitedDeploymentCondition(*newStatus, appsv1alpha1.UnitedDeploymentConditionProgressing); cond == nil ||
+		cond.Reason != appsv1alpha1.WorkloadReasonNewRevisionProgressing {
+		t.Fatalf("expected Progressing to new revision, got %+v", cond)
+	}
+	if cond := GetUnitedDeploymentCondition(*newStatus, appsv1alpha1.UnitedDeploymentConditionReplicaFailure); cond == nil ||
+		cond.Reason != appsv1alpha1.WorkloadReasonFailedUpdate || cond.Message != subsetUpdated.Message {
+		t.Fatalf("expected ReplicaFailure from SubsetUpdated, got %+v", cond)
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreate
+
+import (
+	"fmt"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	kruiseclientset "github.com/openkruise/kruise/pkg/client/clientset/versioned"
+	kruiseinformers "github.com/openkruise/kruise/pkg/client/informers/externalversions"
+	kruiselisters "github.com/openkruise/kruise/pkg/client/listers/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/daemon/criruntime/containerruntime"
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+)
+
+const (
+	// workers is the number of requests that can be handled at the same time,
+	// because stopping a container blocks until it exits.
+	workers = 5
+	// recheckDuration is the interval to check the containers that are being recreated,
+	// in case the event of Pod is missed.
+	recheckDuration = 10 * time.Second
+)
+
+// Controller watches the ContainerRecreateRequests of Pods on its own node, recreates the containers through CRI
+// and reports the status.
+type Controller struct {
+	kruiseClient kruiseclientset.Interface
+	crrLister    kruiselisters.ContainerRecreateRequestLister
+	podLister    corelisters.PodLister
+	synced       []cache.InformerSynced
+	queue        workqueue.RateLimitingInterface
+	recreator    *recreator
+}
+
+// NewController returns the containerrecreate Controller. The crrInformerFactory should only watch the requests
+// labeled with this node, and the podInformerFactory should only watch the Pods on this node.
+func NewController(kruiseClient kruiseclientset.Interface, crrInformerFactory kruiseinformers.SharedInformerFactory,
+	podInformerFactory informers.SharedInformerFactory, runtimeService containerruntime.RuntimeService) *Controller {
+
+	crrInformer := crrInformerFactory.Apps().V1alpha1().ContainerRecreateRequests()
+	podInformer := podInformerFactory.Core().V1().Pods()
+	c := &Controller{
+		kruiseClient: kruiseClient,
+		crrLister:    crrInformer.Lister(),
+		podLister:    podInformer.Lister(),
+		synced:       []cache.InformerSynced{crrInformer.Informer().HasSynced, podInformer.Informer().HasSynced},
+		queue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "containerrecreate"),
+		recreator:    newRecreator(runtimeService),
+	}
+
+	crrInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueue(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldCRR, oldOK := oldObj.(*appsv1alpha1.ContainerRecreateRequest)
+			newCRR, newOK := newObj.(*appsv1alpha1.ContainerRecreateRequest)
+			if !oldOK || !newOK || oldCRR.ResourceVersion == newCRR.ResourceVersion {
+				return
+			}
+			c.enqueue(newObj)
+		},
+	})
+	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod, oldOK := oldObj.(*v1.Pod)
+			newPod, newOK := newObj.(*v1.Pod)
+			if !oldOK || !newOK || apiequality.Semantic.DeepEqual(oldPod.Status.ContainerStatuses, newPod.Status.ContainerStatuses) {
+				return
+			}
+			c.enqueueRequestsOfPod(newPod)
+		},
+	})
+	return c
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+func (c *Controller) enqueueRequestsOfPod(pod *v1.Pod) {
+	selector := labels.SelectorFromSet(labels.Set{appsv1alpha1.ContainerRecreateRequestPodUIDKey: string(pod.UID)})
+	crrList, err := c.crrLister.ContainerRecreateRequests(pod.Namespace).List(selector)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, crr := range crrList {
+		if !appsv1alpha1.IsContainerRecreateRequestCompleted(crr) {
+			c.enqueue(crr)
+		}
+	}
+}
+
+// Run starts the controller and blocks until stop channel is closed.
+func (c *Controller) Run(stop <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Info("Starting containerrecreate controller")
+	defer klog.Info("Shutting down containerrecreate controller")
+
+	if !cache.WaitForCacheSync(stop, c.synced...) {
+		utilruntime.HandleError(fmt.Errorf("timed out waiting for caches to sync"))
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(func() {
+			for c.processNextWorkItem() {
+			}
+		}, time.Second, stop)
+	}
+	<-stop
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.sync(key.(string))
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("sync ContainerRecreateRequest %v failed: %v", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+func (c *Controller) sync(key string) (time.Duration, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return 0, err
+	}
+	crr, err := c.crrLister.ContainerRecreateRequests(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	// the request has not been accepted by the controller, or it has already completed
+	if crr.DeletionTimestamp != nil || crr.Status.Phase == "" || appsv1alpha1.IsContainerRecreateRequestCompleted(crr) {
+		return 0, nil
+	}
+
+	pod, err := c.podLister.Pods(namespace).Get(crr.Spec.PodName)
+	if errors.IsNotFound(err) {
+		// the request will be failed by the controller
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if string(pod.UID) != crr.Labels[appsv1alpha1.ContainerRecreateRequestPodUIDKey] {
+		return 0, nil
+	}
+
+	newStatus := c.recreator.Recreate(crr, pod)
+	if apiequality.Semantic.DeepEqual(*newStatus, crr.Status) {
+		return recheckDuration, nil
+	}
+
+	klog.V(3).Infof("Updating ContainerRecreateRequest %s status: phase=%s, states=%+v", key, newStatus.Phase, newStatus.ContainerRecreateStates)
+	newCRR := crr.DeepCopy()
+	newCRR.Status = *newStatus
+	if _, err = c.kruiseClient.AppsV1alpha1().ContainerRecreateRequests(namespace).UpdateStatus(newCRR); err != nil {
+		return 0, err
+	}
+	if appsv1alpha1.IsContainerRecreateRequestCompleted(newCRR) {
+		return 0, nil
+	}
+	return recheckDuration, nil
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreate
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/daemon/criruntime/containerruntime"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog"
+)
+
+const (
+	// minimumGracePeriod is the minimum grace period left to stop a container after its preStop, the same as kubelet.
+	minimumGracePeriod = 2 * time.Second
+)
+
+// recreator recreates containers by stopping them through CRI, then kubelet will start the new containers.
+type recreator struct {
+	runtimeService containerruntime.RuntimeService
+	httpClient     *http.Client
+}
+
+func newRecreator(runtimeService containerruntime.RuntimeService) *recreator {
+	return &recreator{
+		runtimeService: runtimeService,
+		httpClient:     &http.Client{},
+	}
+}
+
+// Recreate moves the containers in request forward and returns the new status. It stops the pending containers,
+// which are all the pending ones or only the next one if orderedRecreate is set, and marks a recreating container
+// as succeeded once the Pod status shows it has been started again.
+func (r *recreator) Recreate(crr *appsv1alpha1.ContainerRecreateRequest, pod *v1.Pod) *appsv1alpha1.ContainerRecreateRequestStatus {
+	status := crr.Status.DeepCopy()
+	status.Phase = appsv1alpha1.ContainerRecreateRequestRecreating
+	status.ContainerRecreateStates = initContainerStates(crr)
+
+	strategy := appsv1alpha1.ContainerRecreateRequestStrategy{FailurePolicy: appsv1alpha1.ContainerRecreateRequestFailurePolicyFail}
+	if crr.Spec.Strategy != nil {
+		strategy = *crr.Spec.Strategy
+	}
+
+	var failed *appsv1alpha1.ContainerRecreateRequestContainerRecreateState
+	for i := range crr.Spec.Containers {
+		container := &crr.Spec.Containers[i]
+		state := &status.ContainerRecreateStates[i]
+		if failed != nil && strategy.FailurePolicy != appsv1alpha1.ContainerRecreateRequestFailurePolicyIgnore {
+			break
+		}
+
+		switch state.Phase {
+		case appsv1alpha1.ContainerRecreateSucceeded:
+			continue
+		case appsv1alpha1.ContainerRecreateFailed:
+			failed = state
+			continue
+		}
+
+		if isContainerRecreated(container.StatusContext, getContainerStatus(pod, container.Name)) {
+			state.Phase = appsv1alpha1.ContainerRecreateSucceeded
+			state.Message = ""
+			continue
+		}
+
+		if state.Phase == appsv1alpha1.ContainerRecreatePending {
+			if err := r.stopContainer(pod, container, getGracePeriod(&strategy, pod)); err != nil {
+				klog.Warningf("Failed to stop container %s of Pod %s/%s for ContainerRecreateRequest %s: %v",
+					container.Name, pod.Namespace, pod.Name, crr.Name, err)
+				state.Phase = appsv1alpha1.ContainerRecreateFailed
+				state.Message = err.Error()
+				failed = state
+				continue
+			}
+			state.Phase = appsv1alpha1.ContainerRecreateRecreating
+		}
+
+		// wait for the container to be started again before the next one
+		if strategy.OrderedRecreate {
+			break
+		}
+	}
+
+	if failed != nil && strategy.FailurePolicy != appsv1alpha1.ContainerRecreateRequestFailurePolicyIgnore {
+		completeStatus(status, appsv1alpha1.ContainerRecreateRequestFailed,
+			fmt.Sprintf("failed to recreate container %s: %s", failed.Name, failed.Message))
+		return status
+	}
+
+	var failedCount int
+	for _, state := range status.ContainerRecreateStates {
+		switch state.Phase {
+		case appsv1alpha1.ContainerRecreatePending, appsv1alpha1.ContainerRecreateRecreating:
+			return status
+		case appsv1alpha1.ContainerRecreateFailed:
+			failedCount++
+		}
+	}
+	if failedCount > 0 {
+		completeStatus(status, appsv1alpha1.ContainerRecreateRequestSucceeded, fmt.Sprintf("%d containers failed to recreate and ignored", failedCount))
+	} else {
+		completeStatus(status, appsv1alpha1.ContainerRecreateRequestSucceeded, "")
+	}
+	return status
+}
+
+func (r *recreator) stopContainer(pod *v1.Pod, container *appsv1alpha1.ContainerRecreateRequestContainer, gracePeriod time.Duration) error {
+	if container.StatusContext == nil {
+		return fmt.Errorf("no status context of container")
+	}
+	containerID := parseContainerID(container.StatusContext.ContainerID)
+
+	if container.PreStop != nil {
+		start := time.Now()
+		// the same as kubelet, the container will be stopped even if preStop failed
+		if err := r.runPreStop(pod, containerID, container, gracePeriod); err != nil {
+			klog.Warningf("PreStop of container %s in Pod %s/%s failed: %v", container.Name, pod.Namespace, pod.Name, err)
+		}
+		gracePeriod -= time.Since(start)
+		if gracePeriod < minimumGracePeriod {
+			gracePeriod = minimumGracePeriod
+		}
+	}
+
+	klog.Infof("Stopping container %s (%s) of Pod %s/%s with grace period %v", container.Name, containerID, pod.Namespace, pod.Name, gracePeriod)
+	ctx, cancel := context.WithTimeout(context.Background(), gracePeriod+minimumGracePeriod)
+	defer cancel()
+	return r.runtimeService.StopContainer(ctx, containerID, gracePeriod)
+}
+
+func (r *recreator) runPreStop(pod *v1.Pod, containerID string, container *appsv1alpha1.ContainerRecreateRequestContainer, timeout time.Duration) error {
+	switch {
+	case container.PreStop.Exec != nil:
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		result, err := r.runtimeService.ExecSync(ctx, containerID, container.PreStop.Exec.Command, timeout)
+		if err != nil {
+			return err
+		}
+		if result.ExitCode != 0 {
+			return fmt.Errorf("exec %v exited with %d: %s", container.PreStop.Exec.Command, result.ExitCode, string(result.Stderr))
+		}
+		return nil
+
+	case container.PreStop.HTTPGet != nil:
+		req, err := newHTTPGetRequest(pod, container.Name, container.PreStop.HTTPGet)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		resp, err := r.httpClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("http get %s returned %d", req.URL, resp.StatusCode)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported preStop handler")
+}
+
+func newHTTPGetRequest(pod *v1.Pod, containerName string, action *v1.HTTPGetAction) (*http.Request, error) {
+	host := action.Host
+	if host == "" {
+		host = pod.Status.PodIP
+	}
+	port, err := resolvePort(pod, containerName, action.Port)
+	if err != nil {
+		return nil, err
+	}
+	path := action.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	scheme := strings.ToLower(string(action.Scheme))
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	u := &url.URL{Scheme: scheme, Host: net.JoinHostPort(host, strconv.Itoa(port)), Path: path}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range action.HTTPHeaders {
+		req.Header.Add(h.Name, h.Value)
+	}
+	return req, nil
+}
+
+func resolvePort(pod *v1.Pod, containerName string, port intstr.IntOrString) (int, error) {
+	if port.Type == intstr.Int {
+		return port.IntValue(), nil
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name != containerName {
+			continue
+		}
+		for _, p := range c.Ports {
+			if p.Name == port.StrVal {
+				return int(p.ContainerPort), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("port %s not found in container %s", port.StrVal, containerName)
+}
+
+// initContainerStates returns the states of containers in request, the missing ones are initialized to be pending.
+func initContainerStates(crr *appsv1alpha1.ContainerRecreateRequest) []appsv1alpha1.ContainerRecreateRequestContainerRecreateState {
+	existing := make(map[string]appsv1alpha1.ContainerRecreateRequestContainerRecreateState, len(crr.Status.ContainerRecreateStates))
+	for _, state := range crr.Status.ContainerRecreateStates {
+		existing[state.Name] = state
+	}
+	states := make([]appsv1alpha1.ContainerRecreateRequestContainerRecreateState, 0, len(crr.Spec.Containers))
+	for _, c := range crr.Spec.Containers {
+		if state, ok := existing[c.Name]; ok {
+			states = append(states, state)
+			continue
+		}
+		states = append(states, appsv1alpha1.ContainerRecreateRequestContainerRecreateState{Name: c.Name, Phase: appsv1alpha1.ContainerRecreatePending})
+	}
+	return states
+}
+
+func completeStatus(status *appsv1alpha1.ContainerRecreateRequestStatus, phase appsv1alpha1.ContainerRecreateRequestPhase, msg string) {
+	now := metav1.Now()
+	status.Phase = phase
+	status.CompletionTime = &now
+	status.Message = msg
+}
+
+// isContainerRecreated returns whether kubelet has started a new container since the request was accepted.
+func isContainerRecreated(statusContext *appsv1alpha1.ContainerRecreateRequestContainerContext, cs *v1.ContainerStatus) bool {
+	if statusContext == nil || cs == nil {
+		return false
+	}
+	return (cs.ContainerID != "" && cs.ContainerID != statusContext.ContainerID) || cs.RestartCount > statusContext.RestartCount
+}
+
+func getGracePeriod(strategy *appsv1alpha1.ContainerRecreateRequestStrategy, pod *v1.Pod) time.Duration {
+	if strategy.TerminationGracePeriodSeconds != nil {
+		return time.Duration(*strategy.TerminationGracePeriodSeconds) * time.Second
+	}
+	if pod.Spec.TerminationGracePeriodSeconds != nil {
+		return time.Duration(*pod.Spec.TerminationGracePeriodSeconds) * time.Second
+	}
+	return v1.DefaultTerminationGracePeriodSeconds * time.Second
+}
+
+func getContainerStatus(pod *v1.Pod, name string) *v1.ContainerStatus {
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == name {
+			return &pod.Status.ContainerStatuses[i]
+		}
+	}
+	return nil
+}
+
+// parseContainerID returns the ID in container runtime from the ID in Pod status, which is like docker://<id>.
+func parseContainerID(containerID string) string {
+	if idx := strings.Index(containerID, "://"); idx >= 0 {
+		return containerID[idx+3:]
+	}
+	return containerID
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreate
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	kruisefake "github.com/openkruise/kruise/pkg/client/clientset/versioned/fake"
+	kruiseinformers "github.com/openkruise/kruise/pkg/client/informers/externalversions"
+	"github.com/openkruise/kruise/pkg/daemon/criruntime/containerruntime"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newPod(statuses ...v1.ContainerStatus) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", UID: "pod-uid"},
+		Spec:       v1.PodSpec{NodeName: "node1", Containers: []v1.Container{{Name: "c0"}, {Name: "c1"}}},
+		Status:     v1.PodStatus{ContainerStatuses: statuses},
+	}
+}
+
+func newCRR(strategy *appsv1alpha1.ContainerRecreateRequestStrategy, states ...appsv1alpha1.ContainerRecreateRequestContainerPhase) *appsv1alpha1.ContainerRecreateRequest {
+	crr := &appsv1alpha1.ContainerRecreateRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "crr",
+			Labels:    map[string]string{appsv1alpha1.ContainerRecreateRequestPodUIDKey: "pod-uid"},
+		},
+		Spec: appsv1alpha1.ContainerRecreateRequestSpec{
+			PodName: "pod",
+			Containers: []appsv1alpha1.ContainerRecreateRequestContainer{
+				{Name: "c0", StatusContext: &appsv1alpha1.ContainerRecreateRequestContainerContext{ContainerID: "docker://id0"}},
+				{Name: "c1", StatusContext: &appsv1alpha1.ContainerRecreateRequestContainerContext{ContainerID: "docker://id1"}},
+			},
+			Strategy: strategy,
+		},
+		Status: appsv1alpha1.ContainerRecreateRequestStatus{Phase: appsv1alpha1.ContainerRecreateRequestPending},
+	}
+	for i, phase := range states {
+		crr.Status.ContainerRecreateStates = append(crr.Status.ContainerRecreateStates,
+			appsv1alpha1.ContainerRecreateRequestContainerRecreateState{Name: crr.Spec.Containers[i].Name, Phase: phase})
+	}
+	return crr
+}
+
+func TestRecreate(t *testing.T) {
+	oldStatuses := []v1.ContainerStatus{{Name: "c0", ContainerID: "docker://id0"}, {Name: "c1", ContainerID: "docker://id1"}}
+	ordered := &appsv1alpha1.ContainerRecreateRequestStrategy{OrderedRecreate: true}
+	ignore := &appsv1alpha1.ContainerRecreateRequestStrategy{FailurePolicy: appsv1alpha1.ContainerRecreateRequestFailurePolicyIgnore}
+
+	cases := []struct {
+		name            string
+		crr             *appsv1alpha1.ContainerRecreateRequest
+		pod             *v1.Pod
+		stopErrors      map[string]error
+		expectedPhase   appsv1alpha1.ContainerRecreateRequestPhase
+		expectedStates  []appsv1alpha1.ContainerRecreateRequestContainerPhase
+		expectedStopped []string
+	}{
+		{
+			name:            "stop all containers",
+			crr:             newCRR(nil),
+			pod:             newPod(oldStatuses...),
+			expectedPhase:   appsv1alpha1.ContainerRecreateRequestRecreating,
+			expectedStates:  []appsv1alpha1.ContainerRecreateRequestContainerPhase{appsv1alpha1.ContainerRecreateRecreating, appsv1alpha1.ContainerRecreateRecreating},
+			expectedStopped: []string{"id0", "id1"},
+		},
+		{
+			name:            "stop the first container in order",
+			crr:             newCRR(ordered),
+			pod:             newPod(oldStatuses...),
+			expectedPhase:   appsv1alpha1.ContainerRecreateRequestRecreating,
+			expectedStates:  []appsv1alpha1.ContainerRecreateRequestContainerPhase{appsv1alpha1.ContainerRecreateRecreating, appsv1alpha1.ContainerRecreatePending},
+			expectedStopped: []string{"id0"},
+		},
+		{
+			name:           "wait for the first container recreated in order",
+			crr:            newCRR(ordered, appsv1alpha1.ContainerRecreateRecreating, appsv1alpha1.ContainerRecreatePending),
+			pod:            newPod(oldStatuses...),
+			expectedPhase:  appsv1alpha1.ContainerRecreateRequestRecreating,
+			expectedStates: []appsv1alpha1.ContainerRecreateRequestContainerPhase{appsv1alpha1.ContainerRecreateRecreating, appsv1alpha1.ContainerRecreatePending},
+		},
+		{
+			name: "stop the next container after the first recreated",
+			crr:  newCRR(ordered, appsv1alpha1.ContainerRecreateRecreating, appsv1alpha1.ContainerRecreatePending),
+			pod: newPod(v1.ContainerStatus{Name: "c0", ContainerID: "docker://new0", RestartCount: 1},
+				v1.ContainerStatus{Name: "c1", ContainerID: "docker://id1"}),
+			expectedPhase:   appsv1alpha1.ContainerRecreateRequestRecreating,
+			expectedStates:  []appsv1alpha1.ContainerRecreateRequestContainerPhase{appsv1alpha1.ContainerRecreateSucceeded, appsv1alpha1.ContainerRecreateRecreating},
+			expectedStopped: []string{"id1"},
+		},
+		{
+			name: "succeed when all containers recreated",
+			crr:  newCRR(nil, appsv1alpha1.ContainerRecreateRecreating, appsv1alpha1.ContainerRecreateRecreating),
+			pod: newPod(v1.ContainerStatus{Name: "c0", ContainerID: "docker://new0", RestartCount: 1},
+				v1.ContainerStatus{Name: "c1", ContainerID: "docker://new1", RestartCount: 1}),
+			expectedPhase:  appsv1alpha1.ContainerRecreateRequestSucceeded,
+			expectedStates: []appsv1alpha1.ContainerRecreateRequestContainerPhase{appsv1alpha1.ContainerRecreateSucceeded, appsv1alpha1.ContainerRecreateSucceeded},
+		},
+		{
+			name:           "fail when stopping failed",
+			crr:            newCRR(nil),
+			pod:            newPod(oldStatuses...),
+			stopErrors:     map[string]error{"id0": fmt.Errorf("stop error")},
+			expectedPhase:  appsv1alpha1.ContainerRecreateRequestFailed,
+			expectedStates: []appsv1alpha1.ContainerRecreateRequestContainerPhase{appsv1alpha1.ContainerRecreateFailed, appsv1alpha1.ContainerRecreatePending},
+		},
+		{
+			name:            "ignore failure of stopping",
+			crr:             newCRR(ignore),
+			pod:             newPod(oldStatuses...),
+			stopErrors:      map[string]error{"id0": fmt.Errorf("stop error")},
+			expectedPhase:   appsv1alpha1.ContainerRecreateRequestRecreating,
+			expectedStates:  []appsv1alpha1.ContainerRecreateRequestContainerPhase{appsv1alpha1.ContainerRecreateFailed, appsv1alpha1.ContainerRecreateRecreating},
+			expectedStopped: []string{"id1"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			runtimeService := containerruntime.NewFakeRuntimeService()
+			if tc.stopErrors != nil {
+				runtimeService.StopErrors = tc.stopErrors
+			}
+			r := newRecreator(runtimeService)
+
+			status := r.Recreate(tc.crr, tc.pod)
+			if status.Phase != tc.expectedPhase {
+				t.Fatalf("expected phase %s, got %s", tc.expectedPhase, status.Phase)
+			}
+			if (status.CompletionTime != nil) != appsv1alpha1.IsContainerRecreateRequestCompleted(&appsv1alpha1.ContainerRecreateRequest{Status: *status}) {
+				t.Fatalf("unexpected completionTime %v", status.CompletionTime)
+			}
+			var states []appsv1alpha1.ContainerRecreateRequestContainerPhase
+			for _, state := range status.ContainerRecreateStates {
+				states = append(states, state.Phase)
+			}
+			if !reflect.DeepEqual(states, tc.expectedStates) {
+				t.Fatalf("expected states %v, got %v", tc.expectedStates, states)
+			}
+			if !reflect.DeepEqual(runtimeService.Stopped, tc.expectedStopped) {
+				t.Fatalf("expected stopped %v, got %v", tc.expectedStopped, runtimeService.Stopped)
+			}
+		})
+	}
+}
+
+func TestRecreateWithPreStop(t *testing.T) {
+	runtimeService := containerruntime.NewFakeRuntimeService()
+	runtimeService.ExecExitCodes["id0"] = 1
+	r := newRecreator(runtimeService)
+
+	crr := newCRR(nil)
+	crr.Spec.Containers = crr.Spec.Containers[:1]
+	crr.Spec.Containers[0].PreStop = &v1.Handler{Exec: &v1.ExecAction{Command: []string{"sh", "-c", "sleep 1"}}}
+	status := r.Recreate(crr, newPod(v1.ContainerStatus{Name: "c0", ContainerID: "docker://id0"}))
+
+	if !reflect.DeepEqual(runtimeService.Executed["id0"], [][]string{{"sh", "-c", "sleep 1"}}) {
+		t.Fatalf("unexpected executed commands %v", runtimeService.Executed)
+	}
+	// the container should be stopped even if its preStop failed
+	if !reflect.DeepEqual(runtimeService.Stopped, []string{"id0"}) {
+		t.Fatalf("unexpected stopped containers %v", runtimeService.Stopped)
+	}
+	if status.ContainerRecreateStates[0].Phase != appsv1alpha1.ContainerRecreateRecreating {
+		t.Fatalf("unexpected states %+v", status.ContainerRecreateStates)
+	}
+}
+
+func TestSync(t *testing.T) {
+	crr := newCRR(nil)
+	pod := newPod(v1.ContainerStatus{Name: "c0", ContainerID: "docker://id0"}, v1.ContainerStatus{Name: "c1", ContainerID: "docker://id1"})
+	kruiseClient := kruisefake.NewSimpleClientset(crr)
+	kubeClient := kubefake.NewSimpleClientset(pod)
+	crrInformerFactory := kruiseinformers.NewSharedInformerFactory(kruiseClient, 0)
+	podInformerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	runtimeService := containerruntime.NewFakeRuntimeService()
+	c := NewController(kruiseClient, crrInformerFactory, podInformerFactory, runtimeService)
+
+	_ = crrInformerFactory.Apps().V1alpha1().ContainerRecreateRequests().Informer().GetIndexer().Add(crr)
+	_ = podInformerFactory.Core().V1().Pods().Informer().GetIndexer().Add(pod)
+
+	requeueAfter, err := c.sync("default/crr")
+	if err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if requeueAfter != recheckDuration {
+		t.Fatalf("expected requeue after %v, got %v", recheckDuration, requeueAfter)
+	}
+	got, err := kruiseClient.AppsV1alpha1().ContainerRecreateRequests("default").Get("crr", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get crr: %v", err)
+	}
+	if got.Status.Phase != appsv1alpha1.ContainerRecreateRequestRecreating || len(got.Status.ContainerRecreateStates) != 2 {
+		t.Fatalf("unexpected status %+v", got.Status)
+	}
+	if len(runtimeService.Stopped) != 2 {
+		t.Fatalf("unexpected stopped containers %v", runtimeService.Stopped)
+	}
+}
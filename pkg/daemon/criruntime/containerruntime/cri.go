@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerruntime
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"google.golang.org/grpc"
+	runtimeapi "k8s.io/kubernetes/pkg/kubelet/apis/cri/runtime/v1alpha2"
+)
+
+// NewCRIRuntimeService creates a RuntimeService that connects to the CRI runtime service on the endpoint.
+func NewCRIRuntimeService(endpoint string, connectionTimeout time.Duration) (RuntimeService, error) {
+	addr, err := parseUnixEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	dialer := func(addr string, timeout time.Duration) (net.Conn, error) {
+		return net.DialTimeout("unix", addr, timeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithDialer(dialer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect %s: %v", endpoint, err)
+	}
+
+	return &criRuntimeService{client: runtimeapi.NewRuntimeServiceClient(conn)}, nil
+}
+
+// parseUnixEndpoint returns the socket path of the endpoint, which should be like unix:///var/run/dockershim.sock.
+// Endpoint without scheme is considered to be a unix socket path.
+func parseUnixEndpoint(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "unix":
+		return u.Path, nil
+	case "":
+		return endpoint, nil
+	default:
+		return "", fmt.Errorf("unsupported protocol %q of endpoint %s, only unix socket is supported", u.Scheme, endpoint)
+	}
+}
+
+type criRuntimeService struct {
+	client runtimeapi.RuntimeServiceClient
+}
+
+func (c *criRuntimeService) StopContainer(ctx context.Context, containerID string, gracePeriod time.Duration) error {
+	_, err := c.client.StopContainer(ctx, &runtimeapi.StopContainerRequest{
+		ContainerId: containerID,
+		Timeout:     int64(gracePeriod / time.Second),
+	})
+	return err
+}
+
+func (c *criRuntimeService) ExecSync(ctx context.Context, containerID string, cmd []string, timeout time.Duration) (*ExecResult, error) {
+	resp, err := c.client.ExecSync(ctx, &runtimeapi.ExecSyncRequest{
+		ContainerId: containerID,
+		Cmd:         cmd,
+		Timeout:     int64(timeout / time.Second),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ExecResult{Stdout: resp.Stdout, Stderr: resp.Stderr, ExitCode: resp.ExitCode}, nil
+}
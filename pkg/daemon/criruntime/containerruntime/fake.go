@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerruntime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeRuntimeService is an in-memory RuntimeService for testing.
+type FakeRuntimeService struct {
+	sync.Mutex
+
+	// Stopped records the IDs of containers that have been stopped in order.
+	Stopped []string
+	// Executed records the commands that have been executed, keyed by container ID.
+	Executed map[string][][]string
+	// StopErrors contains the errors to return when stopping the containers, keyed by container ID.
+	StopErrors map[string]error
+	// ExecExitCodes contains the exit codes of commands executed in the containers, keyed by container ID.
+	ExecExitCodes map[string]int32
+}
+
+var _ RuntimeService = &FakeRuntimeService{}
+
+// NewFakeRuntimeService returns a new FakeRuntimeService.
+func NewFakeRuntimeService() *FakeRuntimeService {
+	return &FakeRuntimeService{
+		Executed:      make(map[string][][]string),
+		StopErrors:    make(map[string]error),
+		ExecExitCodes: make(map[string]int32),
+	}
+}
+
+func (f *FakeRuntimeService) StopContainer(ctx context.Context, containerID string, gracePeriod time.Duration) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.StopErrors[containerID]; err != nil {
+		return err
+	}
+	f.Stopped = append(f.Stopped, containerID)
+	return nil
+}
+
+func (f *FakeRuntimeService) ExecSync(ctx context.Context, containerID string, cmd []string, timeout time.Duration) (*ExecResult, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.Executed[containerID] = append(f.Executed[containerID], cmd)
+	exitCode := f.ExecExitCodes[containerID]
+	result := &ExecResult{ExitCode: exitCode}
+	if exitCode != 0 {
+		result.Stderr = []byte(fmt.Sprintf("command %s exited with %d", strings.Join(cmd, " "), exitCode))
+	}
+	return result, nil
+}
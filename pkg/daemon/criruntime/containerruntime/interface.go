@@ -0,0 +1,38 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerruntime
+
+import (
+	"context"
+	"time"
+)
+
+// ExecResult is the result of a command executed in container.
+type ExecResult struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int32
+}
+
+// RuntimeService is the interface to manage containers on node through container runtime.
+type RuntimeService interface {
+	// StopContainer stops the container with a grace period, and kubelet will start a new one
+	// according to the restartPolicy of Pod.
+	StopContainer(ctx context.Context, containerID string, gracePeriod time.Duration) error
+	// ExecSync executes a command in the container and blocks until it exits or the timeout is reached.
+	ExecSync(ctx context.Context, containerID string, cmd []string, timeout time.Duration) (*ExecResult, error)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageruntime
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"google.golang.org/grpc"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/credentialprovider"
+	credentialprovidersecrets "k8s.io/kubernetes/pkg/credentialprovider/secrets"
+	runtimeapi "k8s.io/kubernetes/pkg/kubelet/apis/cri/runtime/v1alpha2"
+)
+
+const (
+	// maxMsgSize use 16MB as the default message size limit.
+	maxMsgSize = 1024 * 1024 * 16
+)
+
+// NewCRIImageService creates an ImageService that connects to the CRI image service on the endpoint.
+func NewCRIImageService(endpoint string, connectionTimeout time.Duration) (ImageService, error) {
+	addr, err := parseUnixEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	dialer := func(addr string, timeout time.Duration) (net.Conn, error) {
+		return net.DialTimeout("unix", addr, timeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithDialer(dialer),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect %s: %v", endpoint, err)
+	}
+
+	return &criImageService{client: runtimeapi.NewImageServiceClient(conn)}, nil
+}
+
+// parseUnixEndpoint returns the socket path of the endpoint, which should be like unix:///var/run/dockershim.sock.
+// Endpoint without scheme is considered to be a unix socket path.
+func parseUnixEndpoint(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "unix":
+		return u.Path, nil
+	case "":
+		return endpoint, nil
+	default:
+		return "", fmt.Errorf("unsupported protocol %q of endpoint %s, only unix socket is supported", u.Scheme, endpoint)
+	}
+}
+
+type criImageService struct {
+	client runtimeapi.ImageServiceClient
+}
+
+func (c *criImageService) PullImage(ctx context.Context, imageName, tag string, pullSecrets []v1.Secret) error {
+	image := imageName + ":" + tag
+	keyring, err := credentialprovidersecrets.MakeDockerKeyring(pullSecrets, &credentialprovider.BasicDockerKeyring{})
+	if err != nil {
+		return err
+	}
+
+	creds, withCredentials := keyring.Lookup(imageName)
+	if !withCredentials {
+		_, err = c.client.PullImage(ctx, &runtimeapi.PullImageRequest{Image: &runtimeapi.ImageSpec{Image: image}})
+		return err
+	}
+
+	var pullErrs []error
+	for _, cred := range creds {
+		auth := &runtimeapi.AuthConfig{
+			Username:      cred.Username,
+			Password:      cred.Password,
+			Auth:          cred.Auth,
+			ServerAddress: cred.ServerAddress,
+			IdentityToken: cred.IdentityToken,
+			RegistryToken: cred.RegistryToken,
+		}
+		_, err = c.client.PullImage(ctx, &runtimeapi.PullImageRequest{Image: &runtimeapi.ImageSpec{Image: image}, Auth: auth})
+		if err == nil {
+			return nil
+		}
+		klog.V(4).Infof("Failed to pull image %s with credentials for %s: %v", image, cred.ServerAddress, err)
+		pullErrs = append(pullErrs, err)
+	}
+	return fmt.Errorf("failed to pull image %s with all credentials: %v", image, pullErrs)
+}
+
+func (c *criImageService) ListImages(ctx context.Context) ([]ImageInfo, error) {
+	resp, err := c.client.ListImages(ctx, &runtimeapi.ListImagesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	images := make([]ImageInfo, 0, len(resp.Images))
+	for _, img := range resp.Images {
+		images = append(images, ImageInfo{
+			ID:          img.Id,
+			RepoTags:    img.RepoTags,
+			RepoDigests: img.RepoDigests,
+			Size:        int64(img.Size_),
+		})
+	}
+	return images, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageruntime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// FakeImageService is an in-memory ImageService for testing.
+type FakeImageService struct {
+	sync.Mutex
+
+	// Images contains the images that have been pulled, keyed by image:tag.
+	Images map[string]ImageInfo
+	// PullDelay is the time that each pulling takes.
+	PullDelay time.Duration
+	// FailTimes is the number of times that pulling an image:tag should fail before it succeeds.
+	FailTimes map[string]int
+	// PullCount records the number of times that each image:tag has been pulled.
+	PullCount map[string]int
+}
+
+var _ ImageService = &FakeImageService{}
+
+// NewFakeImageService returns a new FakeImageService.
+func NewFakeImageService() *FakeImageService {
+	return &FakeImageService{
+		Images:    make(map[string]ImageInfo),
+		FailTimes: make(map[string]int),
+		PullCount: make(map[string]int),
+	}
+}
+
+func (f *FakeImageService) PullImage(ctx context.Context, imageName, tag string, pullSecrets []v1.Secret) error {
+	image := imageName + ":" + tag
+	f.Lock()
+	f.PullCount[image]++
+	delay := f.PullDelay
+	f.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	if f.FailTimes[image] > 0 {
+		f.FailTimes[image]--
+		return fmt.Errorf("fake failure for pulling %s", image)
+	}
+	f.Images[image] = ImageInfo{ID: image, RepoTags: []string{image}}
+	return nil
+}
+
+func (f *FakeImageService) ListImages(ctx context.Context) ([]ImageInfo, error) {
+	f.Lock()
+	defer f.Unlock()
+	images := make([]ImageInfo, 0, len(f.Images))
+	for _, img := range f.Images {
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+// GetPullCount returns the number of times that the image:tag has been pulled.
+func (f *FakeImageService) GetPullCount(imageName, tag string) int {
+	f.Lock()
+	defer f.Unlock()
+	return f.PullCount[imageName+":"+tag]
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageruntime
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// ImageInfo is the information of an image on node.
+type ImageInfo struct {
+	ID          string
+	RepoTags    []string
+	RepoDigests []string
+	Size        int64
+}
+
+// ImageService is the interface to manage images on node through container runtime.
+type ImageService interface {
+	// PullImage pulls the image with tag, it blocks until the image has been pulled or ctx is done.
+	// The pullSecrets are used to find the auth for image registry.
+	PullImage(ctx context.Context, imageName, tag string, pullSecrets []v1.Secret) error
+	// ListImages returns all images on node.
+	ListImages(ctx context.Context) ([]ImageInfo, error)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	kruiseclientset "github.com/openkruise/kruise/pkg/client/clientset/versioned"
+	kruiseinformers "github.com/openkruise/kruise/pkg/client/informers/externalversions"
+	"github.com/openkruise/kruise/pkg/daemon/containerrecreate"
+	"github.com/openkruise/kruise/pkg/daemon/criruntime/containerruntime"
+	"github.com/openkruise/kruise/pkg/daemon/criruntime/imageruntime"
+	"github.com/openkruise/kruise/pkg/daemon/imagepuller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+)
+
+const (
+	defaultConnectionTimeout = 10 * time.Second
+)
+
+// Daemon runs on each node and manages the node-level resources.
+type Daemon struct {
+	nodeName        string
+	healthProbeAddr string
+
+	informerFactory       kruiseinformers.SharedInformerFactory
+	crrInformerFactory    kruiseinformers.SharedInformerFactory
+	podInformerFactory    informers.SharedInformerFactory
+	imagePullerController *imagepuller.Controller
+	crrController         *containerrecreate.Controller
+}
+
+// NewDaemon creates a Daemon for the node, which uses the CRI image and runtime services on runtimeEndpoint.
+func NewDaemon(cfg *rest.Config, nodeName, runtimeEndpoint, healthProbeAddr string) (*Daemon, error) {
+	if nodeName == "" {
+		return nil, fmt.Errorf("node name cannot be empty")
+	}
+
+	kubeClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	kruiseClient, err := kruiseclientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	imageService, err := imageruntime.NewCRIImageService(runtimeEndpoint, defaultConnectionTimeout)
+	if err != nil {
+		return nil, err
+	}
+	runtimeService, err := containerruntime.NewCRIRuntimeService(runtimeEndpoint, defaultConnectionTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// only watch the NodeImage of this node
+	informerFactory := kruiseinformers.NewFilteredSharedInformerFactory(kruiseClient, 0, "", func(opts *metav1.ListOptions) {
+		opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", nodeName).String()
+	})
+	// only watch the ContainerRecreateRequests and Pods on this node
+	crrInformerFactory := kruiseinformers.NewFilteredSharedInformerFactory(kruiseClient, 0, "", func(opts *metav1.ListOptions) {
+		opts.LabelSelector = labels.SelectorFromSet(labels.Set{appsv1alpha1.ContainerRecreateRequestNodeNameKey: nodeName}).String()
+	})
+	podInformerFactory := informers.NewFilteredSharedInformerFactory(kubeClient, 0, "", func(opts *metav1.ListOptions) {
+		opts.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", nodeName).String()
+	})
+
+	return &Daemon{
+		nodeName:              nodeName,
+		healthProbeAddr:       healthProbeAddr,
+		informerFactory:       informerFactory,
+		crrInformerFactory:    crrInformerFactory,
+		podInformerFactory:    podInformerFactory,
+		imagePullerController: imagepuller.NewController(nodeName, kubeClient, kruiseClient, informerFactory, imageService),
+		crrController:         containerrecreate.NewController(kruiseClient, crrInformerFactory, podInformerFactory, runtimeService),
+	}, nil
+}
+
+// Run starts the daemon and blocks until stop channel is closed.
+func (d *Daemon) Run(stop <-chan struct{}) error {
+	if d.healthProbeAddr != "" {
+		go d.serveHealthz()
+	}
+
+	d.informerFactory.Start(stop)
+	d.crrInformerFactory.Start(stop)
+	d.podInformerFactory.Start(stop)
+	go d.imagePullerController.Run(stop)
+	go d.crrController.Run(stop)
+
+	klog.Infof("Kruise daemon started on node %s", d.nodeName)
+	<-stop
+	return nil
+}
+
+func (d *Daemon) serveHealthz() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	if err := http.ListenAndServe(d.healthProbeAddr, mux); err != nil {
+		klog.Errorf("Failed to serve healthz on %s: %v", d.healthProbeAddr, err)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepuller
+
+import (
+	"fmt"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	kruiseclientset "github.com/openkruise/kruise/pkg/client/clientset/versioned"
+	kruiseinformers "github.com/openkruise/kruise/pkg/client/informers/externalversions"
+	kruiselisters "github.com/openkruise/kruise/pkg/client/listers/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/daemon/criruntime/imageruntime"
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+)
+
+// Controller watches the NodeImage of its own node, pulls the images in spec and reports the status.
+type Controller struct {
+	nodeName     string
+	kruiseClient kruiseclientset.Interface
+	lister       kruiselisters.NodeImageLister
+	synced       cache.InformerSynced
+	queue        workqueue.RateLimitingInterface
+	puller       *puller
+}
+
+// NewController returns the imagepuller Controller. The informerFactory should only watch the NodeImage named nodeName.
+func NewController(nodeName string, kubeClient clientset.Interface, kruiseClient kruiseclientset.Interface,
+	informerFactory kruiseinformers.SharedInformerFactory, imageService imageruntime.ImageService) *Controller {
+
+	informer := informerFactory.Apps().V1alpha1().NodeImages()
+	c := &Controller{
+		nodeName:     nodeName,
+		kruiseClient: kruiseClient,
+		lister:       informer.Lister(),
+		synced:       informer.Informer().HasSynced,
+		queue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "imagepuller"),
+	}
+	getSecret := func(namespace, name string) (*v1.Secret, error) {
+		return kubeClient.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	}
+	c.puller = newPuller(imageService, getSecret, c.enqueue)
+
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueue()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNodeImage, oldOK := oldObj.(*appsv1alpha1.NodeImage)
+			newNodeImage, newOK := newObj.(*appsv1alpha1.NodeImage)
+			if !oldOK || !newOK || oldNodeImage.ResourceVersion == newNodeImage.ResourceVersion {
+				return
+			}
+			c.enqueue()
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueue()
+		},
+	})
+	return c
+}
+
+func (c *Controller) enqueue() {
+	c.queue.Add(c.nodeName)
+}
+
+// Run starts the controller and blocks until stop channel is closed.
+func (c *Controller) Run(stop <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+	defer c.puller.Stop()
+
+	klog.Info("Starting imagepuller controller")
+	defer klog.Info("Shutting down imagepuller controller")
+
+	if !cache.WaitForCacheSync(stop, c.synced) {
+		utilruntime.HandleError(fmt.Errorf("timed out waiting for caches to sync"))
+		return
+	}
+
+	go wait.Until(func() {
+		for c.processNextWorkItem() {
+		}
+	}, time.Second, stop)
+	<-stop
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.sync()
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("sync NodeImage %v failed: %v", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+func (c *Controller) sync() (time.Duration, error) {
+	nodeImage, err := c.lister.Get(c.nodeName)
+	if errors.IsNotFound(err) {
+		c.puller.Sync(nil, nil)
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	requeueAfter := c.puller.Sync(&nodeImage.Spec, &nodeImage.Status)
+	newStatus := c.puller.GetStatus()
+	newStatus.ObservedGeneration = nodeImage.Generation
+	if apiequality.Semantic.DeepEqual(newStatus, nodeImage.Status) {
+		return requeueAfter, nil
+	}
+
+	klog.V(3).Infof("Updating NodeImage %s status: desired=%d, pulling=%d, succeeded=%d, failed=%d",
+		c.nodeName, newStatus.Desired, newStatus.Pulling, newStatus.Succeeded, newStatus.Failed)
+	newNodeImage := nodeImage.DeepCopy()
+	newNodeImage.Status = newStatus
+	_, err = c.kruiseClient.AppsV1alpha1().NodeImages().UpdateStatus(newNodeImage)
+	return requeueAfter, err
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepuller
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/daemon/criruntime/imageruntime"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+)
+
+type secretGetter func(namespace, name string) (*v1.Secret, error)
+
+// puller manages the pull workers for all image tags in NodeImage spec.
+type puller struct {
+	sync.Mutex
+
+	imageService imageruntime.ImageService
+	getSecret    secretGetter
+	notify       func()
+
+	// workers is keyed by image:tag
+	workers map[string]*pullWorker
+	// expired contains the image:tag whose finished status has expired for ttlSecondsAfterFinished,
+	// they will not be pulled again until removed from spec.
+	expired map[string]struct{}
+}
+
+func newPuller(imageService imageruntime.ImageService, getSecret secretGetter, notify func()) *puller {
+	return &puller{
+		imageService: imageService,
+		getSecret:    getSecret,
+		notify:       notify,
+		workers:      make(map[string]*pullWorker),
+		expired:      make(map[string]struct{}),
+	}
+}
+
+// Sync starts workers for new tags, restarts workers whose pull policy or pull secrets changed,
+// and stops workers for tags removed from spec.
+// The current status is used to avoid pulling the finished tags again after restarted.
+// It returns the duration after which the puller should be synced again for expiration.
+func (p *puller) Sync(spec *appsv1alpha1.NodeImageSpec, status *appsv1alpha1.NodeImageStatus) time.Duration {
+	p.Lock()
+	defer p.Unlock()
+
+	desired := make(map[string]struct{})
+	if spec != nil {
+		for name, imageSpec := range spec.Images {
+			secretRefs := append([]appsv1alpha1.ReferenceObject(nil), imageSpec.PullSecrets...)
+			for i := range imageSpec.Tags {
+				// copy the spec to avoid sharing pointers with the caller
+				tagSpec := *imageSpec.Tags[i].DeepCopy()
+				key := name + ":" + tagSpec.Tag
+				desired[key] = struct{}{}
+				if _, ok := p.expired[key]; ok {
+					continue
+				}
+				if w, ok := p.workers[key]; ok {
+					if !w.isSpecChanged(&tagSpec, secretRefs) {
+						continue
+					}
+					// no need to pull a succeeded tag again, only its ttl may be changed
+					if w.isSucceeded() {
+						w.setSpec(tagSpec, secretRefs)
+						continue
+					}
+					klog.Infof("Restart pulling image %s for spec changed", key)
+					w.stop()
+					delete(p.workers, key)
+				} else if tagStatus := getTagStatus(status, name, tagSpec.Tag); tagStatus != nil &&
+					(tagStatus.Phase == appsv1alpha1.ImagePhaseSucceeded || tagStatus.Phase == appsv1alpha1.ImagePhaseFailed) {
+					p.workers[key] = newFinishedWorker(name, tagSpec, secretRefs, *tagStatus)
+					continue
+				}
+
+				klog.Infof("Start pulling image %s", key)
+				w := newPullWorker(name, tagSpec, secretRefs, p.getPullSecrets(secretRefs), p.imageService, p.notify)
+				p.workers[key] = w
+				w.start()
+			}
+		}
+	}
+
+	for key, w := range p.workers {
+		if _, ok := desired[key]; !ok {
+			klog.Infof("Stop pulling image %s for removed from spec", key)
+			w.stop()
+			delete(p.workers, key)
+		}
+	}
+	for key := range p.expired {
+		if _, ok := desired[key]; !ok {
+			delete(p.expired, key)
+		}
+	}
+
+	var requeueAfter time.Duration
+	for key, w := range p.workers {
+		left := w.ttlLeft()
+		if left == nil {
+			continue
+		}
+		if *left <= 0 {
+			klog.Infof("Status of image %s expired for ttlSecondsAfterFinished", key)
+			delete(p.workers, key)
+			p.expired[key] = struct{}{}
+		} else if requeueAfter == 0 || *left < requeueAfter {
+			requeueAfter = *left
+		}
+	}
+	return requeueAfter
+}
+
+// GetStatus returns the NodeImage status calculated from all workers.
+func (p *puller) GetStatus() appsv1alpha1.NodeImageStatus {
+	p.Lock()
+	defer p.Unlock()
+
+	newStatus := appsv1alpha1.NodeImageStatus{Desired: int32(len(p.workers))}
+	for _, w := range p.workers {
+		tagStatus := w.getStatus()
+		switch tagStatus.Phase {
+		case appsv1alpha1.ImagePhaseSucceeded:
+			newStatus.Succeeded++
+		case appsv1alpha1.ImagePhaseFailed:
+			newStatus.Failed++
+		default:
+			newStatus.Pulling++
+		}
+
+		if newStatus.ImageStatuses == nil {
+			newStatus.ImageStatuses = make(map[string]appsv1alpha1.ImageStatus)
+		}
+		imageStatus := newStatus.ImageStatuses[w.name]
+		imageStatus.Tags = append(imageStatus.Tags, tagStatus)
+		newStatus.ImageStatuses[w.name] = imageStatus
+	}
+
+	for name, imageStatus := range newStatus.ImageStatuses {
+		sort.Slice(imageStatus.Tags, func(i, j int) bool { return imageStatus.Tags[i].Tag < imageStatus.Tags[j].Tag })
+		newStatus.ImageStatuses[name] = imageStatus
+	}
+	return newStatus
+}
+
+// Stop stops all workers.
+func (p *puller) Stop() {
+	p.Lock()
+	defer p.Unlock()
+	for _, w := range p.workers {
+		w.stop()
+	}
+}
+
+func (p *puller) getPullSecrets(refs []appsv1alpha1.ReferenceObject) []v1.Secret {
+	var secrets []v1.Secret
+	for _, ref := range refs {
+		secret, err := p.getSecret(ref.Namespace, ref.Name)
+		if err != nil {
+			klog.Warningf("Failed to get pull secret %s/%s: %v", ref.Namespace, ref.Name, err)
+			continue
+		}
+		secrets = append(secrets, *secret)
+	}
+	return secrets
+}
+
+func getTagStatus(status *appsv1alpha1.NodeImageStatus, name, tag string) *appsv1alpha1.ImageTagStatus {
+	if status == nil {
+		return nil
+	}
+	imageStatus, ok := status.ImageStatuses[name]
+	if !ok {
+		return nil
+	}
+	for i := range imageStatus.Tags {
+		if imageStatus.Tags[i].Tag == tag {
+			return &imageStatus.Tags[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepuller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	kruisefake "github.com/openkruise/kruise/pkg/client/clientset/versioned/fake"
+	kruiseinformers "github.com/openkruise/kruise/pkg/client/informers/externalversions"
+	"github.com/openkruise/kruise/pkg/daemon/criruntime/imageruntime"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func init() {
+	backoffBase = 0
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func waitForStatus(t *testing.T, p *puller, condition func(status appsv1alpha1.NodeImageStatus) bool) appsv1alpha1.NodeImageStatus {
+	var status appsv1alpha1.NodeImageStatus
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		status = p.GetStatus()
+		return condition(status), nil
+	})
+	if err != nil {
+		t.Fatalf("timed out waiting for status, got %+v", status)
+	}
+	return status
+}
+
+func TestPullerSync(t *testing.T) {
+	imageService := imageruntime.NewFakeImageService()
+	imageService.FailTimes["nginx:1.18"] = 1
+	imageService.FailTimes["busybox:1.0"] = 10
+	getSecret := func(namespace, name string) (*v1.Secret, error) {
+		return &v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}, nil
+	}
+	p := newPuller(imageService, getSecret, func() {})
+
+	spec := &appsv1alpha1.NodeImageSpec{Images: map[string]appsv1alpha1.ImageSpec{
+		"nginx": {
+			PullSecrets: []appsv1alpha1.ReferenceObject{{Namespace: "default", Name: "secret"}},
+			Tags:        []appsv1alpha1.ImageTagSpec{{Tag: "1.18", PullPolicy: &appsv1alpha1.ImageTagPullPolicy{BackoffLimit: int32Ptr(1)}}},
+		},
+		"busybox": {
+			Tags: []appsv1alpha1.ImageTagSpec{{Tag: "1.0", PullPolicy: &appsv1alpha1.ImageTagPullPolicy{BackoffLimit: int32Ptr(2)}}},
+		},
+	}}
+	p.Sync(spec, nil)
+
+	status := waitForStatus(t, p, func(status appsv1alpha1.NodeImageStatus) bool {
+		return status.Succeeded+status.Failed == 2
+	})
+	if status.Desired != 2 || status.Succeeded != 1 || status.Failed != 1 || status.Pulling != 0 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if tagStatus := getTagStatus(&status, "nginx", "1.18"); tagStatus == nil || tagStatus.Phase != appsv1alpha1.ImagePhaseSucceeded ||
+		tagStatus.StartTime == nil || tagStatus.CompletionTime == nil {
+		t.Fatalf("unexpected nginx status %+v", tagStatus)
+	}
+	if tagStatus := getTagStatus(&status, "busybox", "1.0"); tagStatus == nil || tagStatus.Phase != appsv1alpha1.ImagePhaseFailed || tagStatus.Message == "" {
+		t.Fatalf("unexpected busybox status %+v", tagStatus)
+	}
+	if count := imageService.GetPullCount("nginx", "1.18"); count != 2 {
+		t.Fatalf("expected nginx pulled 2 times, got %d", count)
+	}
+	if count := imageService.GetPullCount("busybox", "1.0"); count != 3 {
+		t.Fatalf("expected busybox pulled 3 times, got %d", count)
+	}
+
+	// remove busybox from spec
+	delete(spec.Images, "busybox")
+	p.Sync(spec, &status)
+	status = p.GetStatus()
+	if status.Desired != 1 || status.Succeeded != 1 || getTagStatus(&status, "busybox", "1.0") != nil {
+		t.Fatalf("unexpected status after removed %+v", status)
+	}
+
+	// a new puller should not pull the finished tags again
+	newP := newPuller(imageService, getSecret, func() {})
+	newP.Sync(spec, &status)
+	if newStatus := newP.GetStatus(); newStatus.Succeeded != 1 || imageService.GetPullCount("nginx", "1.18") != 2 {
+		t.Fatalf("expected finished tag not pulled again, got %+v", newStatus)
+	}
+}
+
+func TestPullerRestartForSpecChanged(t *testing.T) {
+	imageService := imageruntime.NewFakeImageService()
+	imageService.FailTimes["nginx:1.18"] = 2
+	p := newPuller(imageService, nil, func() {})
+
+	spec := &appsv1alpha1.NodeImageSpec{Images: map[string]appsv1alpha1.ImageSpec{
+		"nginx": {Tags: []appsv1alpha1.ImageTagSpec{{Tag: "1.18", PullPolicy: &appsv1alpha1.ImageTagPullPolicy{BackoffLimit: int32Ptr(0)}}}},
+	}}
+	p.Sync(spec, nil)
+	status := waitForStatus(t, p, func(status appsv1alpha1.NodeImageStatus) bool {
+		return status.Failed == 1
+	})
+
+	// the same spec should not restart the failed pulling
+	p.Sync(spec, &status)
+	if count := imageService.GetPullCount("nginx", "1.18"); count != 1 {
+		t.Fatalf("expected nginx pulled 1 time, got %d", count)
+	}
+
+	// a new pull policy should restart the pulling
+	spec.Images["nginx"].Tags[0].PullPolicy.BackoffLimit = int32Ptr(3)
+	p.Sync(spec, &status)
+	status = waitForStatus(t, p, func(status appsv1alpha1.NodeImageStatus) bool {
+		return status.Succeeded == 1
+	})
+	if count := imageService.GetPullCount("nginx", "1.18"); count != 3 {
+		t.Fatalf("expected nginx pulled 3 times, got %d", count)
+	}
+
+	// a new ttl should not pull the succeeded tag again
+	spec.Images["nginx"].Tags[0].PullPolicy.TTLSecondsAfterFinished = int32Ptr(3600)
+	if requeueAfter := p.Sync(spec, &status); requeueAfter <= 0 {
+		t.Fatalf("expected new ttl taken effect, got requeueAfter %v", requeueAfter)
+	}
+	if count := imageService.GetPullCount("nginx", "1.18"); count != 3 {
+		t.Fatalf("expected nginx pulled 3 times, got %d", count)
+	}
+}
+
+func TestPullerTimeoutAndDeadline(t *testing.T) {
+	imageService := imageruntime.NewFakeImageService()
+	imageService.PullDelay = 5 * time.Second
+	p := newPuller(imageService, nil, func() {})
+
+	spec := &appsv1alpha1.NodeImageSpec{Images: map[string]appsv1alpha1.ImageSpec{
+		"nginx": {Tags: []appsv1alpha1.ImageTagSpec{{Tag: "1.18", PullPolicy: &appsv1alpha1.ImageTagPullPolicy{
+			TimeoutSeconds:        int32Ptr(0),
+			BackoffLimit:          int32Ptr(1),
+			ActiveDeadlineSeconds: func() *int64 { v := int64(100); return &v }(),
+		}}}},
+		"busybox": {Tags: []appsv1alpha1.ImageTagSpec{{Tag: "1.0", PullPolicy: &appsv1alpha1.ImageTagPullPolicy{
+			ActiveDeadlineSeconds: func() *int64 { v := int64(1); return &v }(),
+		}}}},
+	}}
+	p.Sync(spec, nil)
+
+	status := waitForStatus(t, p, func(status appsv1alpha1.NodeImageStatus) bool {
+		return status.Failed == 2
+	})
+	if tagStatus := getTagStatus(&status, "busybox", "1.0"); tagStatus.Message != "pulling exceeded activeDeadlineSeconds" {
+		t.Fatalf("unexpected busybox status %+v", tagStatus)
+	}
+	if count := imageService.GetPullCount("nginx", "1.18"); count != 2 {
+		t.Fatalf("expected nginx pulled 2 times, got %d", count)
+	}
+}
+
+func TestPullerTTL(t *testing.T) {
+	imageService := imageruntime.NewFakeImageService()
+	p := newPuller(imageService, nil, func() {})
+
+	completionTime := metav1.NewTime(time.Now().Add(-time.Minute))
+	spec := &appsv1alpha1.NodeImageSpec{Images: map[string]appsv1alpha1.ImageSpec{
+		"nginx": {Tags: []appsv1alpha1.ImageTagSpec{
+			{Tag: "1.18", PullPolicy: &appsv1alpha1.ImageTagPullPolicy{TTLSecondsAfterFinished: int32Ptr(10)}},
+			{Tag: "1.19", PullPolicy: &appsv1alpha1.ImageTagPullPolicy{TTLSecondsAfterFinished: int32Ptr(3600)}},
+		}},
+	}}
+	status := &appsv1alpha1.NodeImageStatus{ImageStatuses: map[string]appsv1alpha1.ImageStatus{
+		"nginx": {Tags: []appsv1alpha1.ImageTagStatus{
+			{Tag: "1.18", Phase: appsv1alpha1.ImagePhaseSucceeded, CompletionTime: &completionTime},
+			{Tag: "1.19", Phase: appsv1alpha1.ImagePhaseSucceeded, CompletionTime: &completionTime},
+		}},
+	}}
+
+	requeueAfter := p.Sync(spec, status)
+	if requeueAfter <= 0 || requeueAfter > time.Hour {
+		t.Fatalf("unexpected requeueAfter %v", requeueAfter)
+	}
+	newStatus := p.GetStatus()
+	if newStatus.Desired != 1 || getTagStatus(&newStatus, "nginx", "1.18") != nil || getTagStatus(&newStatus, "nginx", "1.19") == nil {
+		t.Fatalf("unexpected status %+v", newStatus)
+	}
+
+	// expired tag should not be pulled again
+	p.Sync(spec, &newStatus)
+	if count := imageService.GetPullCount("nginx", "1.18"); count != 0 {
+		t.Fatalf("expected expired tag not pulled, got %d", count)
+	}
+}
+
+func TestController(t *testing.T) {
+	nodeImage := &appsv1alpha1.NodeImage{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Spec: appsv1alpha1.NodeImageSpec{Images: map[string]appsv1alpha1.ImageSpec{
+			"nginx": {Tags: []appsv1alpha1.ImageTagSpec{{Tag: "1.18"}}},
+		}},
+	}
+	kruiseClient := kruisefake.NewSimpleClientset(nodeImage)
+	kubeClient := kubefake.NewSimpleClientset()
+	informerFactory := kruiseinformers.NewSharedInformerFactory(kruiseClient, 0)
+	imageService := imageruntime.NewFakeImageService()
+	c := NewController("node1", kubeClient, kruiseClient, informerFactory, imageService)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	informerFactory.Start(stop)
+	go c.Run(stop)
+
+	var got *appsv1alpha1.NodeImage
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		var err error
+		got, err = kruiseClient.AppsV1alpha1().NodeImages().Get("node1", metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return got.Status.Succeeded == 1, nil
+	})
+	if err != nil {
+		t.Fatalf("failed to wait for NodeImage status: %v, got %+v", err, got.Status)
+	}
+	if got.Status.Desired != 1 || got.Status.Pulling != 0 || got.Status.Failed != 0 {
+		t.Fatalf("unexpected status %+v", got.Status)
+	}
+	if images, _ := imageService.ListImages(context.TODO()); len(images) != 1 || images[0].ID != "nginx:1.18" {
+		t.Fatalf("unexpected images %+v", images)
+	}
+}
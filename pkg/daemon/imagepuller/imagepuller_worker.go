@@ -0,0 +1,246 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepuller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/daemon/criruntime/imageruntime"
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+const (
+	defaultPullTimeoutSeconds = 600
+	defaultPullBackoffLimit   = 3
+)
+
+var (
+	// backoffBase is the initial duration to wait before retrying a failed pulling, it doubles for each retry.
+	backoffBase = 5 * time.Second
+	// backoffMax is the max duration to wait before retrying a failed pulling.
+	backoffMax = time.Minute
+)
+
+// pullWorker pulls an image tag in the background and keeps its status.
+type pullWorker struct {
+	sync.Mutex
+
+	name         string
+	tagSpec      appsv1alpha1.ImageTagSpec
+	secretRefs   []appsv1alpha1.ReferenceObject
+	secrets      []v1.Secret
+	imageService imageruntime.ImageService
+	// notify is called every time the status changes
+	notify func()
+
+	status appsv1alpha1.ImageTagStatus
+	cancel context.CancelFunc
+}
+
+func newPullWorker(name string, tagSpec appsv1alpha1.ImageTagSpec, secretRefs []appsv1alpha1.ReferenceObject, secrets []v1.Secret,
+	imageService imageruntime.ImageService, notify func()) *pullWorker {
+	return &pullWorker{
+		name:         name,
+		tagSpec:      tagSpec,
+		secretRefs:   secretRefs,
+		secrets:      secrets,
+		imageService: imageService,
+		notify:       notify,
+		status:       appsv1alpha1.ImageTagStatus{Tag: tagSpec.Tag, Phase: appsv1alpha1.ImagePhaseWaiting},
+	}
+}
+
+// newFinishedWorker returns a worker that holds the status of a finished pulling, it will never pull again.
+func newFinishedWorker(name string, tagSpec appsv1alpha1.ImageTagSpec, secretRefs []appsv1alpha1.ReferenceObject,
+	status appsv1alpha1.ImageTagStatus) *pullWorker {
+	return &pullWorker{name: name, tagSpec: tagSpec, secretRefs: secretRefs, status: status}
+}
+
+func (w *pullWorker) start() {
+	w.Lock()
+	defer w.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	go w.run(ctx)
+}
+
+func (w *pullWorker) stop() {
+	w.Lock()
+	defer w.Unlock()
+	if w.cancel != nil {
+		w.cancel()
+	}
+}
+
+func (w *pullWorker) getStatus() appsv1alpha1.ImageTagStatus {
+	w.Lock()
+	defer w.Unlock()
+	return *w.status.DeepCopy()
+}
+
+// isSpecChanged returns true if the pull policy or the pull secrets of the tag have been changed,
+// which means the pulling should be restarted with the new spec.
+func (w *pullWorker) isSpecChanged(tagSpec *appsv1alpha1.ImageTagSpec, secretRefs []appsv1alpha1.ReferenceObject) bool {
+	w.Lock()
+	defer w.Unlock()
+	return !apiequality.Semantic.DeepEqual(w.tagSpec.PullPolicy, tagSpec.PullPolicy) ||
+		!apiequality.Semantic.DeepEqual(w.secretRefs, secretRefs)
+}
+
+// isSucceeded returns true if the image tag has been pulled successfully.
+func (w *pullWorker) isSucceeded() bool {
+	w.Lock()
+	defer w.Unlock()
+	return w.status.Phase == appsv1alpha1.ImagePhaseSucceeded
+}
+
+// setSpec updates the spec of a succeeded worker, so that the new ttlSecondsAfterFinished takes effect.
+func (w *pullWorker) setSpec(tagSpec appsv1alpha1.ImageTagSpec, secretRefs []appsv1alpha1.ReferenceObject) {
+	w.Lock()
+	defer w.Unlock()
+	w.tagSpec = tagSpec
+	w.secretRefs = secretRefs
+}
+
+func (w *pullWorker) isFinished() bool {
+	w.Lock()
+	defer w.Unlock()
+	return w.status.Phase == appsv1alpha1.ImagePhaseSucceeded || w.status.Phase == appsv1alpha1.ImagePhaseFailed
+}
+
+// ttlLeft returns the time left before the finished status expires, or nil if it will never expire.
+func (w *pullWorker) ttlLeft() *time.Duration {
+	w.Lock()
+	defer w.Unlock()
+	if w.tagSpec.PullPolicy == nil || w.tagSpec.PullPolicy.TTLSecondsAfterFinished == nil || w.status.CompletionTime == nil {
+		return nil
+	}
+	left := time.Duration(*w.tagSpec.PullPolicy.TTLSecondsAfterFinished)*time.Second - time.Since(w.status.CompletionTime.Time)
+	return &left
+}
+
+func (w *pullWorker) updateStatus(fn func(status *appsv1alpha1.ImageTagStatus)) {
+	w.Lock()
+	fn(&w.status)
+	w.Unlock()
+	w.notify()
+}
+
+func (w *pullWorker) run(ctx context.Context) {
+	image := w.name + ":" + w.tagSpec.Tag
+	timeout := time.Duration(defaultPullTimeoutSeconds) * time.Second
+	backoffLimit := int32(defaultPullBackoffLimit)
+	var activeDeadline *time.Duration
+	if policy := w.tagSpec.PullPolicy; policy != nil {
+		if policy.TimeoutSeconds != nil {
+			timeout = time.Duration(*policy.TimeoutSeconds) * time.Second
+		}
+		if policy.BackoffLimit != nil {
+			backoffLimit = *policy.BackoffLimit
+		}
+		if policy.ActiveDeadlineSeconds != nil {
+			d := time.Duration(*policy.ActiveDeadlineSeconds) * time.Second
+			activeDeadline = &d
+		}
+	}
+
+	startTime := now()
+	w.updateStatus(func(status *appsv1alpha1.ImageTagStatus) {
+		status.Phase = appsv1alpha1.ImagePhasePulling
+		status.StartTime = &startTime
+		status.Message = ""
+	})
+
+	var cancel context.CancelFunc
+	if activeDeadline != nil {
+		ctx, cancel = context.WithDeadline(ctx, startTime.Add(*activeDeadline))
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	var err error
+	for attempt := int32(0); attempt <= backoffLimit; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(getBackoff(attempt)):
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		klog.V(4).Infof("Pulling image %s, attempt %d", image, attempt+1)
+		pullCtx, pullCancel := context.WithTimeout(ctx, timeout)
+		err = w.imageService.PullImage(pullCtx, w.name, w.tagSpec.Tag, w.secrets)
+		pullCancel()
+		if err == nil {
+			klog.Infof("Successfully pulled image %s", image)
+			w.updateStatus(func(status *appsv1alpha1.ImageTagStatus) {
+				completionTime := now()
+				status.Phase = appsv1alpha1.ImagePhaseSucceeded
+				status.CompletionTime = &completionTime
+				status.Message = ""
+			})
+			return
+		}
+		klog.Warningf("Failed to pull image %s, attempt %d: %v", image, attempt+1, err)
+		message := fmt.Sprintf("attempt %d failed: %v", attempt+1, err)
+		w.updateStatus(func(status *appsv1alpha1.ImageTagStatus) { status.Message = message })
+	}
+
+	var message string
+	switch ctx.Err() {
+	case context.Canceled:
+		// stopped by puller
+		return
+	case context.DeadlineExceeded:
+		message = "pulling exceeded activeDeadlineSeconds"
+	default:
+		message = fmt.Sprintf("pulling failed after %d retries: %v", backoffLimit, err)
+	}
+	w.updateStatus(func(status *appsv1alpha1.ImageTagStatus) {
+		completionTime := now()
+		status.Phase = appsv1alpha1.ImagePhaseFailed
+		status.CompletionTime = &completionTime
+		status.Message = message
+	})
+}
+
+func getBackoff(attempt int32) time.Duration {
+	backoff := backoffBase
+	for i := int32(1); i < attempt && backoff < backoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > backoffMax {
+		backoff = backoffMax
+	}
+	return backoff
+}
+
+// now returns the current time truncated to second, which is the precision of metav1.Time in serialization.
+func now() metav1.Time {
+	return metav1.NewTime(time.Now().Truncate(time.Second))
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package condition
+
+import (
+	"fmt"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition is the common form of the conditions in the status of Kruise workloads.
+type Condition struct {
+	Type               string
+	Status             v1.ConditionStatus
+	LastUpdateTime     metav1.Time
+	LastTransitionTime metav1.Time
+	Reason             string
+	Message            string
+}
+
+// Progress is the observed state of a workload, which decides its Progressing, Available and ReplicaFailure conditions.
+type Progress struct {
+	// Kind of the workload, which is used in the condition messages.
+	Kind string
+	// Replicas is the number of desired replicas.
+	Replicas int32
+	// ActualReplicas is the number of replicas that exist.
+	ActualReplicas int32
+	// AvailableReplicas is the number of available replicas.
+	AvailableReplicas int32
+	// MinAvailableReplicas is the number of available replicas required for the workload to be available.
+	MinAvailableReplicas int32
+	// UpdatedReplicas is the number of replicas of the update revision.
+	UpdatedReplicas int32
+	// UpdatedReadyReplicas is the number of replicas of the update revision that are ready or available.
+	UpdatedReadyReplicas int32
+	// Partition is the number of replicas that are expected to be kept in old revisions.
+	Partition int32
+	// UpdateRevision is the revision that the workload is progressing to.
+	UpdateRevision string
+	// Paused indicates the update of workload is paused.
+	Paused bool
+	// ProgressDeadlineSeconds is the maximum time for the workload to make progress, which is optional.
+	ProgressDeadlineSeconds *int32
+	// FailureReason and FailureMessage describe the failure of replicas, which are empty if there is no failure.
+	FailureReason  string
+	FailureMessage string
+}
+
+// IsWorkloadConditionType returns whether the type is one of the conditions computed by this package.
+func IsWorkloadConditionType(condType string) bool {
+	switch condType {
+	case appsv1alpha1.WorkloadConditionProgressing, appsv1alpha1.WorkloadConditionAvailable, appsv1alpha1.WorkloadConditionReplicaFailure:
+		return true
+	}
+	return false
+}
+
+// Compute returns the Progressing, Available and ReplicaFailure conditions of a workload, by comparing
+// its new progress with the old one and the old conditions.
+func Compute(oldConditions []Condition, oldProgress, newProgress *Progress, now time.Time) []Condition {
+	var conditions []Condition
+	if newProgress.AvailableReplicas >= newProgress.MinAvailableReplicas {
+		conditions = setCondition(conditions, oldConditions, appsv1alpha1.WorkloadConditionAvailable, v1.ConditionTrue,
+			appsv1alpha1.WorkloadReasonMinimumReplicasAvailable, fmt.Sprintf("%s has minimum availability.", newProgress.Kind), now)
+	} else {
+		conditions = setCondition(conditions, oldConditions, appsv1alpha1.WorkloadConditionAvailable, v1.ConditionFalse,
+			appsv1alpha1.WorkloadReasonMinimumReplicasUnavailable, fmt.Sprintf("%s does not have minimum availability.", newProgress.Kind), now)
+	}
+
+	oldCondition := GetCondition(oldConditions, appsv1alpha1.WorkloadConditionProgressing)
+	switch {
+	case newProgress.Paused:
+		conditions = setCondition(conditions, oldConditions, appsv1alpha1.WorkloadConditionProgressing, v1.ConditionUnknown,
+			appsv1alpha1.WorkloadReasonPaused, fmt.Sprintf("%s is paused.", newProgress.Kind), now)
+
+	case isCompleted(newProgress, newProgress.Partition):
+		conditions = setCondition(conditions, oldConditions, appsv1alpha1.WorkloadConditionProgressing, v1.ConditionTrue,
+			appsv1alpha1.WorkloadReasonNewRevisionAvailable,
+			fmt.Sprintf("%s has successfully progressed to revision %s.", newProgress.Kind, newProgress.UpdateRevision), now)
+
+	case oldCondition == nil || oldCondition.Status == v1.ConditionUnknown || hasProgressed(oldProgress, newProgress):
+		conditions = setCondition(conditions, oldConditions, appsv1alpha1.WorkloadConditionProgressing, v1.ConditionTrue,
+			appsv1alpha1.WorkloadReasonNewRevisionProgressing,
+			fmt.Sprintf("%s is progressing to revision %s.", newProgress.Kind, newProgress.UpdateRevision), now)
+		// progress has been made, so the deadline is reset
+		conditions[len(conditions)-1].LastUpdateTime = metav1.NewTime(now)
+
+	case newProgress.ProgressDeadlineSeconds != nil && oldCondition.Reason != appsv1alpha1.WorkloadReasonNewRevisionAvailable &&
+		GetProgressDeadlineDuration(oldConditions, newProgress.ProgressDeadlineSeconds, now) == 0:
+		conditions = setCondition(conditions, oldConditions, appsv1alpha1.WorkloadConditionProgressing, v1.ConditionFalse,
+			appsv1alpha1.WorkloadReasonProgressDeadlineExceeded,
+			fmt.Sprintf("%s revision %s has timed out progressing.", newProgress.Kind, newProgress.UpdateRevision), now)
+
+	default:
+		conditions = append(conditions, *oldCondition)
+	}
+
+	if newProgress.FailureReason != "" {
+		conditions = setCondition(conditions, oldConditions, appsv1alpha1.WorkloadConditionReplicaFailure, v1.ConditionTrue,
+			newProgress.FailureReason, newProgress.FailureMessage, now)
+	}
+	return conditions
+}
+
+// GetCondition returns the condition with the given type, or nil if it does not exist.
+func GetCondition(conditions []Condition, condType string) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == condType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// Inconsistent returns whether the conditions have been changed, ignoring the timestamps.
+func Inconsistent(oldConditions, newConditions []Condition) bool {
+	if len(oldConditions) != len(newConditions) {
+		return true
+	}
+	for _, newCondition := range newConditions {
+		oldCondition := GetCondition(oldConditions, newCondition.Type)
+		if oldCondition == nil || oldCondition.Status != newCondition.Status ||
+			oldCondition.Reason != newCondition.Reason || oldCondition.Message != newCondition.Message {
+			return true
+		}
+	}
+	return false
+}
+
+// GetProgressDeadlineDuration returns the duration left before the progress deadline is exceeded,
+// or 0 if there is no deadline or it has already been exceeded.
+func GetProgressDeadlineDuration(conditions []Condition, progressDeadlineSeconds *int32, now time.Time) time.Duration {
+	if progressDeadlineSeconds == nil {
+		return 0
+	}
+	condition := GetCondition(conditions, appsv1alpha1.WorkloadConditionProgressing)
+	if condition == nil || condition.Status != v1.ConditionTrue || condition.Reason != appsv1alpha1.WorkloadReasonNewRevisionProgressing {
+		return 0
+	}
+	deadline := condition.LastUpdateTime.Add(time.Duration(*progressDeadlineSeconds) * time.Second)
+	if leftDuration := deadline.Sub(now); leftDuration > 0 {
+		return leftDuration
+	}
+	return 0
+}
+
+// IsProgressDeadlineExceeded returns whether the update revision has failed to progress before the deadline.
+func IsProgressDeadlineExceeded(conditions []Condition) bool {
+	condition := GetCondition(conditions, appsv1alpha1.WorkloadConditionProgressing)
+	return condition != nil && condition.Reason == appsv1alpha1.WorkloadReasonProgressDeadlineExceeded
+}
+
+// IsRolloutCompleted returns whether all replicas have been updated to the update revision and been ready,
+// regardless of the partition.
+func IsRolloutCompleted(progress *Progress) bool {
+	return isCompleted(progress, 0)
+}
+
+func isCompleted(progress *Progress, partition int32) bool {
+	return progress.ActualReplicas == progress.Replicas &&
+		progress.UpdatedReplicas >= progress.Replicas-partition &&
+		progress.UpdatedReadyReplicas >= progress.Replicas-partition
+}
+
+func hasProgressed(oldProgress, newProgress *Progress) bool {
+	return oldProgress.UpdateRevision != newProgress.UpdateRevision ||
+		oldProgress.ActualReplicas != newProgress.ActualReplicas ||
+		oldProgress.UpdatedReplicas != newProgress.UpdatedReplicas ||
+		oldProgress.UpdatedReadyReplicas != newProgress.UpdatedReadyReplicas ||
+		oldProgress.AvailableReplicas != newProgress.AvailableReplicas
+}
+
+func setCondition(conditions, oldConditions []Condition, condType string, status v1.ConditionStatus, reason, message string, now time.Time) []Condition {
+	condition := Condition{
+		Type:               condType,
+		Status:             status,
+		LastUpdateTime:     metav1.NewTime(now),
+		LastTransitionTime: metav1.NewTime(now),
+		Reason:             reason,
+		Message:            message,
+	}
+	if oldCondition := GetCondition(oldConditions, condType); oldCondition != nil {
+		if oldCondition.Status == status {
+			condition.LastTransitionTime = oldCondition.LastTransitionTime
+		}
+		if oldCondition.Status == status && oldCondition.Reason == reason && oldCondition.Message == message {
+			condition.LastUpdateTime = oldCondition.LastUpdateTime
+		}
+	}
+	return append(conditions, condition)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package condition
+
+import (
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilpointer "k8s.io/utils/pointer"
+)
+
+func TestCompute(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-time.Hour)
+
+	progressing := func(status v1.ConditionStatus, reason string, lastUpdateTime time.Time) Condition {
+		return Condition{
+			Type:           appsv1alpha1.WorkloadConditionProgressing,
+			Status:         status,
+			Reason:         reason,
+			LastUpdateTime: metav1.NewTime(lastUpdateTime),
+		}
+	}
+
+	cases := []struct {
+		name                   string
+		oldConditions          []Condition
+		oldProgress            Progress
+		newProgress            Progress
+		expectedAvailable      v1.ConditionStatus
+		expectedProgressing    Condition
+		expectedReplicaFailure string
+	}{
+		{
+			name:                "begin to progress",
+			oldProgress:         Progress{Replicas: 3, ActualReplicas: 3, AvailableReplicas: 3, UpdatedReplicas: 3, UpdatedReadyReplicas: 3, UpdateRevision: "v1"},
+			newProgress:         Progress{Replicas: 3, ActualReplicas: 3, AvailableReplicas: 3, MinAvailableReplicas: 2, UpdateRevision: "v2"},
+			expectedAvailable:   v1.ConditionTrue,
+			expectedProgressing: progressing(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, now),
+		},
+		{
+			name:                "no progress keeps last update time",
+			oldConditions:       []Condition{progressing(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, before)},
+			oldProgress:         Progress{Replicas: 3, ActualReplicas: 3, AvailableReplicas: 2, UpdatedReplicas: 1, UpdateRevision: "v2"},
+			newProgress:         Progress{Replicas: 3, ActualReplicas: 3, AvailableReplicas: 2, MinAvailableReplicas: 3, UpdatedReplicas: 1, UpdateRevision: "v2"},
+			expectedAvailable:   v1.ConditionFalse,
+			expectedProgressing: progressing(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, before),
+		},
+		{
+			name:                "progress deadline exceeded",
+			oldConditions:       []Condition{progressing(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, before)},
+			oldProgress:         Progress{Replicas: 3, ActualReplicas: 3, AvailableReplicas: 2, UpdatedReplicas: 1, UpdateRevision: "v2"},
+			newProgress:         Progress{Replicas: 3, ActualReplicas: 3, AvailableReplicas: 2, MinAvailableReplicas: 2, UpdatedReplicas: 1, UpdateRevision: "v2", ProgressDeadlineSeconds: utilpointer.Int32Ptr(60)},
+			expectedAvailable:   v1.ConditionTrue,
+			expectedProgressing: progressing(v1.ConditionFalse, appsv1alpha1.WorkloadReasonProgressDeadlineExceeded, now),
+		},
+		{
+			name:                "completed with partition",
+			oldConditions:       []Condition{progressing(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, before)},
+			oldProgress:         Progress{Replicas: 3, ActualReplicas: 3, AvailableReplicas: 3, UpdatedReplicas: 1, UpdateRevision: "v2"},
+			newProgress:         Progress{Replicas: 3, ActualReplicas: 3, AvailableReplicas: 3, UpdatedReplicas: 2, UpdatedReadyReplicas: 2, Partition: 1, UpdateRevision: "v2"},
+			expectedAvailable:   v1.ConditionTrue,
+			expectedProgressing: progressing(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionAvailable, now),
+		},
+		{
+			name:                   "paused with replica failure",
+			oldConditions:          []Condition{progressing(v1.ConditionTrue, appsv1alpha1.WorkloadReasonNewRevisionProgressing, before)},
+			oldProgress:            Progress{Replicas: 3, ActualReplicas: 2, UpdateRevision: "v2"},
+			newProgress:            Progress{Replicas: 3, ActualReplicas: 2, UpdateRevision: "v2", Paused: true, FailureReason: appsv1alpha1.WorkloadReasonFailedScale},
+			expectedAvailable:      v1.ConditionTrue,
+			expectedProgressing:    progressing(v1.ConditionUnknown, appsv1alpha1.WorkloadReasonPaused, now),
+			expectedReplicaFailure: appsv1alpha1.WorkloadReasonFailedScale,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions := Compute(tc.oldConditions, &tc.oldProgress, &tc.newProgress, now)
+
+			if c := GetCondition(conditions, appsv1alpha1.WorkloadConditionAvailable); c == nil || c.Status != tc.expectedAvailable {
+				t.Fatalf("expected Available %v, got %+v", tc.expectedAvailable, c)
+			}
+			c := GetCondition(conditions, appsv1alpha1.WorkloadConditionProgressing)
+			if c == nil || c.Status != tc.expectedProgressing.Status || c.Reason != tc.expectedProgressing.Reason ||
+				!c.LastUpdateTime.Equal(&tc.expectedProgressing.LastUpdateTime) {
+				t.Fatalf("expected Progressing %+v, got %+v", tc.expectedProgressing, c)
+			}
+			c = GetCondition(conditions, appsv1alpha1.WorkloadConditionReplicaFailure)
+			if tc.expectedReplicaFailure == "" && c != nil {
+				t.Fatalf("expected no ReplicaFailure, got %+v", c)
+			} else if tc.expectedReplicaFailure != "" && (c == nil || c.Reason != tc.expectedReplicaFailure) {
+				t.Fatalf("expected ReplicaFailure %v, got %+v", tc.expectedReplicaFailure, c)
+			}
+		})
+	}
+}
+
+func TestInconsistent(t *testing.T) {
+	now := metav1.Now()
+	available := Condition{Type: appsv1alpha1.WorkloadConditionAvailable, Status: v1.ConditionTrue, Reason: appsv1alpha1.WorkloadReasonMinimumReplicasAvailable}
+	availableUpdated := available
+	availableUpdated.LastUpdateTime, availableUpdated.LastTransitionTime = now, now
+	unavailable := available
+	unavailable.Status = v1.ConditionFalse
+	failure := Condition{Type: appsv1alpha1.WorkloadConditionReplicaFailure, Status: v1.ConditionTrue}
+
+	cases := []struct {
+		name     string
+		old, new []Condition
+		expected bool
+	}{
+		{name: "both empty", expected: false},
+		{name: "only timestamps changed", old: []Condition{available}, new: []Condition{availableUpdated}, expected: false},
+		{name: "status changed", old: []Condition{available}, new: []Condition{unavailable}, expected: true},
+		{name: "condition added", old: []Condition{available}, new: []Condition{available, failure}, expected: true},
+		{name: "condition replaced", old: []Condition{failure}, new: []Condition{available}, expected: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Inconsistent(tc.old, tc.new); got != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
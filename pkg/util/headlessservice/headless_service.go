@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package headlessservice
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewHeadlessService returns the headless Service named name that is controlled by owner,
+// selecting the Pods matched by selector and exposing the container ports in template.
+func NewHeadlessService(owner metav1.Object, ownerKind schema.GroupVersionKind, name string, selector *metav1.LabelSelector, template *v1.PodTemplateSpec) *v1.Service {
+	matchLabels := template.Labels
+	if selector != nil && len(selector.MatchLabels) > 0 {
+		matchLabels = selector.MatchLabels
+	}
+	serviceSelector := make(map[string]string, len(matchLabels))
+	for k, v := range matchLabels {
+		serviceSelector[k] = v
+	}
+
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       owner.GetNamespace(),
+			Name:            name,
+			Labels:          serviceSelector,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, ownerKind)},
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP: v1.ClusterIPNone,
+			Selector:  serviceSelector,
+			Ports:     getServicePorts(template),
+		},
+	}
+}
+
+// getServicePorts returns a port for each distinct container port in template, sorted by port and protocol.
+func getServicePorts(template *v1.PodTemplateSpec) []v1.ServicePort {
+	var ports []v1.ServicePort
+	seen := map[string]struct{}{}
+	for _, c := range template.Spec.Containers {
+		for _, p := range c.Ports {
+			protocol := p.Protocol
+			if protocol == "" {
+				protocol = v1.ProtocolTCP
+			}
+			key := fmt.Sprintf("%s-%d", strings.ToLower(string(protocol)), p.ContainerPort)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			// ports of a multi-port Service must all be named, so fall back to a generated name
+			name := p.Name
+			if name == "" {
+				name = key
+			}
+			ports = append(ports, v1.ServicePort{
+				Name:       name,
+				Protocol:   protocol,
+				Port:       p.ContainerPort,
+				TargetPort: intstr.FromInt(int(p.ContainerPort)),
+			})
+		}
+	}
+	sort.SliceStable(ports, func(i, j int) bool {
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		return ports[i].Protocol < ports[j].Protocol
+	})
+	return ports
+}
+
+// Reconcile creates the desired Service if it does not exist, or updates the selector and ports
+// of the existing one if it is controlled by the same owner.
+// An existing Service that is not controlled by the owner is left untouched and a Warning event
+// is recorded on owner.
+func Reconcile(c client.Client, recorder record.EventRecorder, owner runtime.Object, desired *v1.Service) error {
+	ownerRef := metav1.GetControllerOf(desired)
+	if ownerRef == nil {
+		return fmt.Errorf("desired Service %s/%s has no controller", desired.Namespace, desired.Name)
+	}
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		svc := &v1.Service{}
+		err := c.Get(context.TODO(), types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, svc)
+		if errors.IsNotFound(err) {
+			return c.Create(context.TODO(), desired.DeepCopy())
+		} else if err != nil {
+			return err
+		}
+
+		if ref := metav1.GetControllerOf(svc); ref == nil || ref.UID != ownerRef.UID {
+			klog.V(4).Infof("Skip reconciling Service %s/%s not controlled by %s %s", svc.Namespace, svc.Name, ownerRef.Kind, ownerRef.Name)
+			recorder.Eventf(owner, v1.EventTypeWarning, "ServiceNotOwned",
+				"Service %s already exists and is not controlled by %s %s", svc.Name, ownerRef.Kind, ownerRef.Name)
+			return nil
+		}
+		if apiequality.Semantic.DeepEqual(svc.Spec.Selector, desired.Spec.Selector) &&
+			apiequality.Semantic.DeepEqual(svc.Spec.Ports, desired.Spec.Ports) {
+			return nil
+		}
+
+		svc.Spec.Selector = desired.Spec.Selector
+		svc.Spec.Ports = desired.Spec.Ports
+		return c.Update(context.TODO(), svc)
+	})
+}
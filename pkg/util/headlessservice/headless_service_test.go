@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package headlessservice
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestService(uid types.UID, ports ...v1.ContainerPort) *v1.Service {
+	owner := &appsv1alpha1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", UID: uid}}
+	template := &v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "foo", "version": "v1"}},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "main", Ports: ports}}},
+	}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+	return NewHeadlessService(owner, appsv1alpha1.SchemeGroupVersion.WithKind("StatefulSet"), "foo-svc", selector, template)
+}
+
+func TestNewHeadlessService(t *testing.T) {
+	svc := newTestService("uid-1",
+		v1.ContainerPort{Name: "web", ContainerPort: 8080},
+		v1.ContainerPort{ContainerPort: 53, Protocol: v1.ProtocolUDP},
+		v1.ContainerPort{Name: "web-dup", ContainerPort: 8080, Protocol: v1.ProtocolTCP},
+	)
+
+	if svc.Spec.ClusterIP != v1.ClusterIPNone {
+		t.Fatalf("expected headless Service, got clusterIP %q", svc.Spec.ClusterIP)
+	}
+	if !reflect.DeepEqual(svc.Spec.Selector, map[string]string{"app": "foo"}) {
+		t.Fatalf("unexpected selector %v", svc.Spec.Selector)
+	}
+	expectedPorts := []v1.ServicePort{
+		{Name: "udp-53", Protocol: v1.ProtocolUDP, Port: 53, TargetPort: intstr.FromInt(53)},
+		{Name: "web", Protocol: v1.ProtocolTCP, Port: 8080, TargetPort: intstr.FromInt(8080)},
+	}
+	if !reflect.DeepEqual(svc.Spec.Ports, expectedPorts) {
+		t.Fatalf("expected ports %v, got %v", expectedPorts, svc.Spec.Ports)
+	}
+	if ref := metav1.GetControllerOf(svc); ref == nil || ref.UID != "uid-1" || ref.Kind != "StatefulSet" {
+		t.Fatalf("unexpected controller reference %v", ref)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	cases := []struct {
+		name          string
+		existing      *v1.Service
+		desired       *v1.Service
+		expectedPorts []v1.ServicePort
+		expectedEvent bool
+	}{
+		{
+			name:          "create",
+			desired:       newTestService("uid-1", v1.ContainerPort{Name: "web", ContainerPort: 80}),
+			expectedPorts: newTestService("uid-1", v1.ContainerPort{Name: "web", ContainerPort: 80}).Spec.Ports,
+		},
+		{
+			name:          "update controlled",
+			existing:      newTestService("uid-1", v1.ContainerPort{Name: "web", ContainerPort: 80}),
+			desired:       newTestService("uid-1", v1.ContainerPort{Name: "web", ContainerPort: 8080}),
+			expectedPorts: newTestService("uid-1", v1.ContainerPort{Name: "web", ContainerPort: 8080}).Spec.Ports,
+		},
+		{
+			name:          "skip not controlled",
+			existing:      newTestService("uid-2", v1.ContainerPort{Name: "web", ContainerPort: 80}),
+			desired:       newTestService("uid-1", v1.ContainerPort{Name: "web", ContainerPort: 8080}),
+			expectedPorts: newTestService("uid-2", v1.ContainerPort{Name: "web", ContainerPort: 80}).Spec.Ports,
+			expectedEvent: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := fake.NewFakeClient()
+			if tc.existing != nil {
+				c = fake.NewFakeClient(tc.existing)
+			}
+			recorder := record.NewFakeRecorder(10)
+			owner := &appsv1alpha1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", UID: "uid-1"}}
+			if err := Reconcile(c, recorder, owner, tc.desired); err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+
+			svc := &v1.Service{}
+			if err := c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "foo-svc"}, svc); err != nil {
+				t.Fatalf("failed to get Service: %v", err)
+			}
+			if !reflect.DeepEqual(svc.Spec.Ports, tc.expectedPorts) {
+				t.Fatalf("expected ports %v, got %v", tc.expectedPorts, svc.Spec.Ports)
+			}
+			if got := len(recorder.Events) > 0; got != tc.expectedEvent {
+				t.Fatalf("expected event %v, got %v", tc.expectedEvent, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	"github.com/docker/distribution/reference"
+)
+
+// ParseRepositoryTag splits the image into repository and tag.
+// The tag defaults to 'latest' if not specified, and image with digest is not supported.
+func ParseRepositoryTag(image string) (string, string, error) {
+	ref, err := reference.Parse(image)
+	if err != nil {
+		return "", "", err
+	}
+	named, ok := ref.(reference.Named)
+	if !ok {
+		return "", "", fmt.Errorf("image %s has no repository name", image)
+	}
+	if _, ok := ref.(reference.Digested); ok {
+		return "", "", fmt.Errorf("image %s with digest is not supported", image)
+	}
+	tag := "latest"
+	if tagged, ok := ref.(reference.Tagged); ok {
+		tag = tagged.Tag()
+	}
+	return named.Name(), tag, nil
+}
+
+// IsContainerImageEqual returns whether the two images refer to the same image after being normalized,
+// e.g. nginx:1.0 in pod spec and docker.io/library/nginx:1.0 reported by the container runtime in pod status.
+func IsContainerImageEqual(image1, image2 string) bool {
+	if image1 == image2 {
+		return true
+	}
+	ref1, err := reference.ParseNormalizedNamed(image1)
+	if err != nil {
+		return false
+	}
+	ref2, err := reference.ParseNormalizedNamed(image2)
+	if err != nil {
+		return false
+	}
+	return reference.TagNameOnly(ref1).String() == reference.TagNameOnly(ref2).String()
+}
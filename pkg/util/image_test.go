@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "testing"
+
+func TestParseRepositoryTag(t *testing.T) {
+	cases := []struct {
+		image       string
+		expectedRep string
+		expectedTag string
+		expectedErr bool
+	}{
+		{image: "nginx", expectedRep: "nginx", expectedTag: "latest"},
+		{image: "nginx:1.18", expectedRep: "nginx", expectedTag: "1.18"},
+		{image: "registry.example.com:5000/foo/bar:v1", expectedRep: "registry.example.com:5000/foo/bar", expectedTag: "v1"},
+		{image: "registry.example.com:5000/foo/bar", expectedRep: "registry.example.com:5000/foo/bar", expectedTag: "latest"},
+		{image: "nginx@sha256:0123456789012345678901234567890123456789012345678901234567890123", expectedErr: true},
+		{image: "Nginx", expectedErr: true},
+	}
+
+	for _, tc := range cases {
+		rep, tag, err := ParseRepositoryTag(tc.image)
+		if tc.expectedErr {
+			if err == nil {
+				t.Fatalf("%s: expected error, got nil", tc.image)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.image, err)
+		}
+		if rep != tc.expectedRep || tag != tc.expectedTag {
+			t.Fatalf("%s: expected %s:%s, got %s:%s", tc.image, tc.expectedRep, tc.expectedTag, rep, tag)
+		}
+	}
+}
+
+func TestIsContainerImageEqual(t *testing.T) {
+	cases := []struct {
+		image1   string
+		image2   string
+		expected bool
+	}{
+		{image1: "nginx:1.0", image2: "nginx:1.0", expected: true},
+		{image1: "nginx:1.0", image2: "docker.io/library/nginx:1.0", expected: true},
+		{image1: "nginx", image2: "docker.io/library/nginx:latest", expected: true},
+		{image1: "foo/bar:v1", image2: "docker.io/foo/bar:v1", expected: true},
+		{image1: "nginx:1.0", image2: "nginx:1.1", expected: false},
+		{image1: "nginx:1.0", image2: "registry.example.com/library/nginx:1.0", expected: false},
+		{image1: "nginx:1.0", image2: "sha256:0123456789012345678901234567890123456789012345678901234567890123", expected: false},
+	}
+
+	for _, tc := range cases {
+		if got := IsContainerImageEqual(tc.image1, tc.image2); got != tc.expected {
+			t.Fatalf("%s and %s: expected %v, got %v", tc.image1, tc.image2, tc.expected, got)
+		}
+	}
+}
@@ -47,10 +47,19 @@ type (
 
 type UpdateOptions struct {
 	GracePeriodSeconds int32
+	// TimeoutSeconds is the deadline for in-place update to complete after grace period, 0 means no deadline.
+	TimeoutSeconds int32
 
 	CustomizeSpecCalculate        CustomizeSpecCalculateFunc
 	CustomizeSpecPatch            CustomizeSpecPatchFunc
 	CustomizeCheckUpdateCompleted CustomizeCheckUpdateCompletedFunc
+
+	// AdditionalFuncs will be applied to the Pod when it is being updated in-place.
+	AdditionalFuncs []func(*v1.Pod)
+
+	// GetRevision returns the revision value of revisionKey label for the given ControllerRevision.
+	// Defaults to the name of ControllerRevision.
+	GetRevision func(revision *apps.ControllerRevision) string
 }
 
 type RefreshResult struct {
@@ -67,7 +76,11 @@ type UpdateResult struct {
 // Interface for managing pods in-place update.
 type Interface interface {
 	Refresh(pod *v1.Pod, opts *UpdateOptions) RefreshResult
+	CanUpdateInPlace(oldRevision, newRevision *apps.ControllerRevision, opts *UpdateOptions) bool
 	Update(pod *v1.Pod, oldRevision, newRevision *apps.ControllerRevision, opts *UpdateOptions) UpdateResult
+	// IsUpdateTimeout returns true if the in-place update of pod has not completed before the deadline.
+	// Otherwise, it returns the duration left before the deadline, which is zero if there is no deadline.
+	IsUpdateTimeout(pod *v1.Pod, opts *UpdateOptions) (bool, time.Duration)
 }
 
 // UpdateSpec records the images of containers which need to in-place update.
@@ -213,14 +226,13 @@ func (c *realControl) finishGracePeriod(pod *v1.Pod, opts *UpdateOptions) (time.
 	return delayDuration, err
 }
 
+func (c *realControl) CanUpdateInPlace(oldRevision, newRevision *apps.ControllerRevision, opts *UpdateOptions) bool {
+	return calculateUpdateSpec(oldRevision, newRevision, opts) != nil
+}
+
 func (c *realControl) Update(pod *v1.Pod, oldRevision, newRevision *apps.ControllerRevision, opts *UpdateOptions) UpdateResult {
 	// 1. calculate inplace update spec
-	var spec *UpdateSpec
-	if opts == nil || opts.CustomizeSpecCalculate == nil {
-		spec = calculateInPlaceUpdateSpec(oldRevision, newRevision)
-	} else {
-		spec = opts.CustomizeSpecCalculate(oldRevision, newRevision)
-	}
+	spec := calculateUpdateSpec(oldRevision, newRevision, opts)
 	if spec == nil {
 		return UpdateResult{}
 	}
@@ -255,6 +267,37 @@ func (c *realControl) Update(pod *v1.Pod, oldRevision, newRevision *apps.Control
 	return UpdateResult{InPlaceUpdate: true, DelayDuration: delayDuration}
 }
 
+func (c *realControl) IsUpdateTimeout(pod *v1.Pod, opts *UpdateOptions) (bool, time.Duration) {
+	if opts == nil || opts.TimeoutSeconds <= 0 {
+		return false, 0
+	}
+
+	inPlaceUpdateState := appsv1alpha1.InPlaceUpdateState{}
+	if stateStr, ok := pod.Annotations[appsv1alpha1.InPlaceUpdateStateKey]; !ok {
+		return false, 0
+	} else if err := json.Unmarshal([]byte(stateStr), &inPlaceUpdateState); err != nil {
+		return false, 0
+	}
+	// the in-place update state is left by an older revision
+	if c.revisionKey != "" && inPlaceUpdateState.Revision != pod.Labels[c.revisionKey] {
+		return false, 0
+	}
+
+	checkFunc := CheckInPlaceUpdateCompleted
+	if opts.CustomizeCheckUpdateCompleted != nil {
+		checkFunc = opts.CustomizeCheckUpdateCompleted
+	}
+	if pod.Annotations[appsv1alpha1.InPlaceUpdateGraceKey] == "" && checkFunc(pod) == nil {
+		return false, 0
+	}
+
+	timeout := time.Second * time.Duration(opts.GracePeriodSeconds+opts.TimeoutSeconds)
+	if span := c.now().Sub(inPlaceUpdateState.UpdateTimestamp.Time); span < timeout {
+		return false, roundupSeconds(timeout - span)
+	}
+	return true, 0
+}
+
 func (c *realControl) updatePodInPlace(pod *v1.Pod, spec *UpdateSpec, opts *UpdateOptions) error {
 	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		clone, err := c.adp.getPod(pod.Namespace, pod.Name)
@@ -286,6 +329,12 @@ func (c *realControl) updatePodInPlace(pod *v1.Pod, spec *UpdateSpec, opts *Upda
 		inPlaceUpdateStateJSON, _ := json.Marshal(inPlaceUpdateState)
 		clone.Annotations[appsv1alpha1.InPlaceUpdateStateKey] = string(inPlaceUpdateStateJSON)
 
+		if opts != nil {
+			for _, f := range opts.AdditionalFuncs {
+				f(clone)
+			}
+		}
+
 		if spec.GraceSeconds <= 0 {
 			if clone, err = patchUpdateSpecToPod(clone, spec, opts); err != nil {
 				return err
@@ -325,6 +374,23 @@ func patchUpdateSpecToPod(pod *v1.Pod, spec *UpdateSpec, opts *UpdateOptions) (*
 	return pod, nil
 }
 
+func calculateUpdateSpec(oldRevision, newRevision *apps.ControllerRevision, opts *UpdateOptions) *UpdateSpec {
+	if opts == nil {
+		return calculateInPlaceUpdateSpec(oldRevision, newRevision)
+	}
+
+	var spec *UpdateSpec
+	if opts.CustomizeSpecCalculate == nil {
+		spec = calculateInPlaceUpdateSpec(oldRevision, newRevision)
+	} else {
+		spec = opts.CustomizeSpecCalculate(oldRevision, newRevision)
+	}
+	if spec != nil && opts.GetRevision != nil {
+		spec.Revision = opts.GetRevision(newRevision)
+	}
+	return spec
+}
+
 // calculateInPlaceUpdateSpec calculates diff between old and update revisions.
 // If the diff just contains replace operation of spec.containers[x].image, it will returns an UpdateSpec.
 // Otherwise, it returns nil which means can not use in-place update.
@@ -18,6 +18,7 @@ package inplaceupdate
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
@@ -126,6 +127,30 @@ func TestCalculateInPlaceUpdateSpec(t *testing.T) {
 	}
 }
 
+func TestCalculateUpdateSpecWithGetRevision(t *testing.T) {
+	oldRevision := &apps.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-old", Labels: map[string]string{apps.DefaultDaemonSetUniqueLabelKey: "old"}},
+		Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"$patch":"replace","spec":{"containers":[{"name":"c1","image":"foo1"}]}}}}`)},
+	}
+	newRevision := &apps.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-new", Labels: map[string]string{apps.DefaultDaemonSetUniqueLabelKey: "new"}},
+		Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"$patch":"replace","spec":{"containers":[{"name":"c1","image":"foo2"}]}}}}`)},
+	}
+
+	opts := &UpdateOptions{GetRevision: func(revision *apps.ControllerRevision) string {
+		return revision.Labels[apps.DefaultDaemonSetUniqueLabelKey]
+	}}
+	expectedSpec := &UpdateSpec{Revision: "new", ContainerImages: map[string]string{"c1": "foo2"}}
+	if res := calculateUpdateSpec(oldRevision, newRevision, opts); !reflect.DeepEqual(res, expectedSpec) {
+		t.Fatalf("expected %v, got %v", expectedSpec, res)
+	}
+
+	expectedSpec.Revision = "foo-new"
+	if res := calculateUpdateSpec(oldRevision, newRevision, &UpdateOptions{}); !reflect.DeepEqual(res, expectedSpec) {
+		t.Fatalf("expected %v, got %v", expectedSpec, res)
+	}
+}
+
 func TestCheckInPlaceUpdateCompleted(t *testing.T) {
 	succeedPods := []*v1.Pod{
 		{
@@ -442,3 +467,81 @@ func TestRefresh(t *testing.T) {
 		}
 	}
 }
+
+func TestIsUpdateTimeout(t *testing.T) {
+	now := metav1.NewTime(time.Unix(time.Now().Unix(), 0))
+	newPod := func(updateAgo time.Duration, newImageID string, grace bool) *v1.Pod {
+		state := appsv1alpha1.InPlaceUpdateState{
+			Revision:              "new-revision",
+			UpdateTimestamp:       metav1.NewTime(now.Add(-updateAgo)),
+			LastContainerStatuses: map[string]appsv1alpha1.InPlaceUpdateContainerStatus{"c1": {ImageID: "img01"}},
+		}
+		stateJSON, _ := json.Marshal(state)
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{apps.StatefulSetRevisionLabel: "new-revision"},
+				Annotations: map[string]string{appsv1alpha1.InPlaceUpdateStateKey: string(stateJSON)},
+			},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{Name: "c1", ImageID: newImageID}}},
+		}
+		if grace {
+			pod.Annotations[appsv1alpha1.InPlaceUpdateGraceKey] = `{"revision":"new-revision"}`
+		}
+		return pod
+	}
+
+	cases := []struct {
+		name            string
+		pod             *v1.Pod
+		opts            *UpdateOptions
+		expectedTimeout bool
+		expectedLeft    time.Duration
+	}{
+		{
+			name:            "no deadline",
+			pod:             newPod(time.Hour, "img01", false),
+			opts:            &UpdateOptions{},
+			expectedTimeout: false,
+		},
+		{
+			name:            "not in-place updated",
+			pod:             &v1.Pod{},
+			opts:            &UpdateOptions{TimeoutSeconds: 60},
+			expectedTimeout: false,
+		},
+		{
+			name:            "in-place update completed",
+			pod:             newPod(time.Hour, "img02", false),
+			opts:            &UpdateOptions{TimeoutSeconds: 60},
+			expectedTimeout: false,
+		},
+		{
+			name:            "in-place update not completed before deadline",
+			pod:             newPod(30*time.Second, "img01", false),
+			opts:            &UpdateOptions{TimeoutSeconds: 60},
+			expectedTimeout: false,
+			expectedLeft:    30 * time.Second,
+		},
+		{
+			name:            "in-place update in grace period",
+			pod:             newPod(90*time.Second, "img02", true),
+			opts:            &UpdateOptions{GracePeriodSeconds: 60, TimeoutSeconds: 60},
+			expectedTimeout: false,
+			expectedLeft:    30 * time.Second,
+		},
+		{
+			name:            "in-place update timeout",
+			pod:             newPod(90*time.Second, "img01", false),
+			opts:            &UpdateOptions{TimeoutSeconds: 60},
+			expectedTimeout: true,
+		},
+	}
+
+	ctrl := NewForTest(fake.NewFakeClient(), apps.ControllerRevisionHashLabelKey, func() metav1.Time { return now })
+	for _, testCase := range cases {
+		timeout, left := ctrl.IsUpdateTimeout(testCase.pod, testCase.opts)
+		if timeout != testCase.expectedTimeout || left != testCase.expectedLeft {
+			t.Fatalf("%s: expected timeout %v left %v, got %v %v", testCase.name, testCase.expectedTimeout, testCase.expectedLeft, timeout, left)
+		}
+	}
+}
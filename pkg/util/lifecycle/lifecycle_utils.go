@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"time"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetPodLifecycleState returns the lifecycle state of the Pod.
+// Pods without the state label are considered to be Normal.
+func GetPodLifecycleState(pod *v1.Pod) appsv1alpha1.LifecycleStateType {
+	if state := pod.Labels[appsv1alpha1.LifecycleStateKey]; state != "" {
+		return appsv1alpha1.LifecycleStateType(state)
+	}
+	return appsv1alpha1.LifecycleStateNormal
+}
+
+// IsPodHooked returns true if the Pod has any label or finalizer of the hook.
+func IsPodHooked(hook *appsv1alpha1.LifecycleHook, pod *v1.Pod) bool {
+	if hook == nil || pod == nil {
+		return false
+	}
+	for k, v := range hook.LabelsHandler {
+		if pod.Labels[k] == v {
+			return true
+		}
+	}
+	for _, f := range hook.FinalizersHandler {
+		for _, podFinalizer := range pod.Finalizers {
+			if f == podFinalizer {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetPodLifecycle returns a function that sets the lifecycle state and timestamp into the Pod.
+func SetPodLifecycle(state appsv1alpha1.LifecycleStateType) func(*v1.Pod) {
+	return func(pod *v1.Pod) {
+		if pod.Labels == nil {
+			pod.Labels = make(map[string]string)
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Labels[appsv1alpha1.LifecycleStateKey] = string(state)
+		pod.Annotations[appsv1alpha1.LifecycleTimestampKey] = time.Now().Format(time.RFC3339)
+	}
+}
+
+// UpdatePodLifecycle updates the lifecycle state of the Pod.
+// It returns false if the Pod is already in this state.
+func UpdatePodLifecycle(c client.Client, pod *v1.Pod, state appsv1alpha1.LifecycleStateType) (bool, error) {
+	if GetPodLifecycleState(pod) == state {
+		return false, nil
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		clone := &v1.Pod{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}, clone); err != nil {
+			return err
+		}
+		SetPodLifecycle(state)(clone)
+		return c.Update(context.TODO(), clone)
+	})
+	return err == nil, err
+}
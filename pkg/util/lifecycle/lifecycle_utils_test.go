@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIsPodHooked(t *testing.T) {
+	hook := &appsv1alpha1.LifecycleHook{
+		LabelsHandler:     map[string]string{"in-service": "true"},
+		FinalizersHandler: []string{"example.com/unready"},
+	}
+	cases := []struct {
+		name     string
+		hook     *appsv1alpha1.LifecycleHook
+		pod      *v1.Pod
+		expected bool
+	}{
+		{
+			name:     "nil hook",
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"in-service": "true"}}},
+			expected: false,
+		},
+		{
+			name:     "hooked by label",
+			hook:     hook,
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"in-service": "true"}}},
+			expected: true,
+		},
+		{
+			name:     "label with different value",
+			hook:     hook,
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"in-service": "false"}}},
+			expected: false,
+		},
+		{
+			name:     "hooked by finalizer",
+			hook:     hook,
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"foo", "example.com/unready"}}},
+			expected: true,
+		},
+		{
+			name:     "not hooked",
+			hook:     hook,
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"foo": "bar"}, Finalizers: []string{"foo"}}},
+			expected: false,
+		},
+	}
+
+	for _, tc := range cases {
+		if got := IsPodHooked(tc.hook, tc.pod); got != tc.expected {
+			t.Fatalf("%s: expected hooked %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestUpdatePodLifecycle(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-0"}}
+	fakeClient := fake.NewFakeClient(pod)
+
+	if state := GetPodLifecycleState(pod); state != appsv1alpha1.LifecycleStateNormal {
+		t.Fatalf("expected pod without state label in Normal, got %s", state)
+	}
+
+	updated, err := UpdatePodLifecycle(fakeClient, pod, appsv1alpha1.LifecycleStatePreparingDelete)
+	if err != nil || !updated {
+		t.Fatalf("expected updated, got %v, %v", updated, err)
+	}
+
+	newPod := &v1.Pod{}
+	if err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "pod-0"}, newPod); err != nil {
+		t.Fatalf("failed to get pod: %v", err)
+	}
+	if state := GetPodLifecycleState(newPod); state != appsv1alpha1.LifecycleStatePreparingDelete {
+		t.Fatalf("expected pod in PreparingDelete, got %s", state)
+	}
+	if newPod.Annotations[appsv1alpha1.LifecycleTimestampKey] == "" {
+		t.Fatalf("expected lifecycle timestamp in pod annotations")
+	}
+
+	updated, err = UpdatePodLifecycle(fakeClient, newPod, appsv1alpha1.LifecycleStatePreparingDelete)
+	if err != nil || updated {
+		t.Fatalf("expected not updated, got %v, %v", updated, err)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// GetStartOrdinal returns the first ordinal of Pods that set should have, which is 0 by default.
+func GetStartOrdinal(set *appsv1alpha1.StatefulSet) int {
+	if set.Spec.Ordinals != nil {
+		return int(set.Spec.Ordinals.Start)
+	}
+	return 0
+}
+
+// GetOrdinals returns the ordinals of Pods that set should have in ascending order,
+// which are the first set.Spec.Replicas integers from the start ordinal not in set.Spec.ReserveOrdinals.
+func GetOrdinals(set *appsv1alpha1.StatefulSet) []int {
+	replicaCount := int(*set.Spec.Replicas)
+	reserveOrdinals := sets.NewInt(set.Spec.ReserveOrdinals...)
+	ordinals := make([]int, 0, replicaCount)
+	for ord := GetStartOrdinal(set); len(ordinals) < replicaCount; ord++ {
+		if !reserveOrdinals.Has(ord) {
+			ordinals = append(ordinals, ord)
+		}
+	}
+	return ordinals
+}
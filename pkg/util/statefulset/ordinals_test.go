@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+)
+
+func TestGetOrdinals(t *testing.T) {
+	cases := []struct {
+		replicas        int
+		start           *int32
+		reserveOrdinals []int
+		expected        []int
+	}{
+		{replicas: 3, expected: []int{0, 1, 2}},
+		{replicas: 3, reserveOrdinals: []int{1}, expected: []int{0, 2, 3}},
+		{replicas: 3, reserveOrdinals: []int{0, 4, 2}, expected: []int{1, 3, 5}},
+		{replicas: 0, reserveOrdinals: []int{0}, expected: []int{}},
+		{replicas: 3, start: func() *int32 { i := int32(5); return &i }(), expected: []int{5, 6, 7}},
+		{replicas: 3, start: func() *int32 { i := int32(5); return &i }(), reserveOrdinals: []int{1, 6}, expected: []int{5, 7, 8}},
+	}
+	for _, tc := range cases {
+		replicas := int32(tc.replicas)
+		set := &appsv1alpha1.StatefulSet{Spec: appsv1alpha1.StatefulSetSpec{Replicas: &replicas, ReserveOrdinals: tc.reserveOrdinals}}
+		if tc.start != nil {
+			set.Spec.Ordinals = &appsv1alpha1.StatefulSetOrdinals{Start: *tc.start}
+		}
+		if got := GetOrdinals(set); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("replicas=%d start=%v reserveOrdinals=%v: expected %v, got %v", tc.replicas, tc.start, tc.reserveOrdinals, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// ParsePodOverrideOrdinals parses the ordinals of a pod override, which is a comma-separated list of
+// ordinals and ordinal ranges such as "0,2-4", into a list of closed intervals.
+func ParsePodOverrideOrdinals(str string) ([][2]int, error) {
+	var ranges [][2]int
+	for _, part := range strings.Split(str, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid ordinal %q", part)
+		}
+		end := start
+		if len(bounds) == 2 {
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid ordinal range %q", part)
+			}
+		}
+		if start < 0 || end < start {
+			return nil, fmt.Errorf("invalid ordinal range %q", part)
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges, nil
+}
+
+// ApplyPodOverrides returns a copy of template, in which the overrides matching ordinal are applied
+// as strategic merge patches in order.
+func ApplyPodOverrides(template *v1.PodTemplateSpec, overrides []appsv1alpha1.StatefulSetPodOverride, ordinal int) (*v1.PodTemplateSpec, error) {
+	template = template.DeepCopy()
+	for i := range overrides {
+		ranges, err := ParsePodOverrideOrdinals(overrides[i].Ordinals)
+		if err != nil {
+			return nil, err
+		}
+		var matched bool
+		for _, r := range ranges {
+			if ordinal >= r[0] && ordinal <= r[1] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		original, err := json.Marshal(template)
+		if err != nil {
+			return nil, err
+		}
+		patched, err := strategicpatch.StrategicMergePatch(original, overrides[i].Patch.Raw, &v1.PodTemplateSpec{})
+		if err != nil {
+			return nil, err
+		}
+		template = &v1.PodTemplateSpec{}
+		if err := json.Unmarshal(patched, template); err != nil {
+			return nil, err
+		}
+	}
+	return template, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestParsePodOverrideOrdinals(t *testing.T) {
+	cases := []struct {
+		ordinals    string
+		expected    [][2]int
+		expectedErr bool
+	}{
+		{ordinals: "0", expected: [][2]int{{0, 0}}},
+		{ordinals: "0-2", expected: [][2]int{{0, 2}}},
+		{ordinals: "0, 3-5", expected: [][2]int{{0, 0}, {3, 5}}},
+		{ordinals: "", expectedErr: true},
+		{ordinals: "a", expectedErr: true},
+		{ordinals: "-1", expectedErr: true},
+		{ordinals: "3-1", expectedErr: true},
+		{ordinals: "1-", expectedErr: true},
+	}
+
+	for _, tc := range cases {
+		got, err := ParsePodOverrideOrdinals(tc.ordinals)
+		if tc.expectedErr != (err != nil) {
+			t.Fatalf("%q: expected error %v, got %v", tc.ordinals, tc.expectedErr, err)
+		}
+		if err == nil && !reflect.DeepEqual(got, tc.expected) {
+			t.Fatalf("%q: expected %v, got %v", tc.ordinals, tc.expected, got)
+		}
+	}
+}
+
+func TestApplyPodOverrides(t *testing.T) {
+	template := &v1.PodTemplateSpec{
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "nginx", Image: "nginx"}}},
+	}
+	overrides := []appsv1alpha1.StatefulSetPodOverride{
+		{Ordinals: "0-1", Patch: runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"nginx","env":[{"name":"ROLE","value":"seed"}]}]}}`)}},
+		{Ordinals: "0", Patch: runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"nginx","env":[{"name":"ROLE","value":"bootstrap"}]}]}}`)}},
+	}
+
+	expectedRoles := []string{"bootstrap", "seed", "", ""}
+	for ordinal, expected := range expectedRoles {
+		got, err := ApplyPodOverrides(template, overrides, ordinal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var role string
+		for _, env := range got.Spec.Containers[0].Env {
+			if env.Name == "ROLE" {
+				role = env.Value
+			}
+		}
+		if role != expected {
+			t.Errorf("ordinal %d: expected role %q, got %q", ordinal, expected, role)
+		}
+		if got.Spec.Containers[0].Image != template.Spec.Containers[0].Image {
+			t.Errorf("ordinal %d: expected image kept, got %s", ordinal, got.Spec.Containers[0].Image)
+		}
+	}
+	if len(template.Spec.Containers[0].Env) != 0 {
+		t.Errorf("expected template of set not mutated")
+	}
+}
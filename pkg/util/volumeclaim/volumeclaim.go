@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeclaim
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// betaStorageClassAnnotation is the beta annotation of StorageClass name, which takes precedence over the spec field.
+const betaStorageClassAnnotation = "volume.beta.kubernetes.io/storage-class"
+
+// GetStorageRequest returns the storage size requested by claim.
+func GetStorageRequest(claim *v1.PersistentVolumeClaim) resource.Quantity {
+	return claim.Spec.Resources.Requests[v1.ResourceStorage]
+}
+
+// GetStorageClassName returns the StorageClass name of claim, empty if it has none.
+func GetStorageClassName(claim *v1.PersistentVolumeClaim) string {
+	if name, ok := claim.Annotations[betaStorageClassAnnotation]; ok {
+		return name
+	}
+	if claim.Spec.StorageClassName != nil {
+		return *claim.Spec.StorageClassName
+	}
+	return ""
+}
+
+// AllowsExpansion returns true if the StorageClass allows volume expansion.
+func AllowsExpansion(sc *storagev1.StorageClass) bool {
+	return sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion
+}
+
+// IsExpansionRequired returns true if the storage requested by template is larger than the one requested by claim.
+func IsExpansionRequired(claim, template *v1.PersistentVolumeClaim) bool {
+	expected := GetStorageRequest(template)
+	current := GetStorageRequest(claim)
+	return expected.Cmp(current) > 0
+}
+
+// IsExpansionCompleted returns true if the capacity of claim has reached the storage requested by template,
+// and the filesystem has been resized.
+func IsExpansionCompleted(claim, template *v1.PersistentVolumeClaim) bool {
+	if IsExpansionRequired(claim, template) || IsFileSystemResizePending(claim) {
+		return false
+	}
+	expected := GetStorageRequest(template)
+	capacity := claim.Status.Capacity[v1.ResourceStorage]
+	return capacity.Cmp(expected) >= 0
+}
+
+// IsFileSystemResizePending returns true if the volume of claim has been expanded, but the filesystem is waiting
+// to be resized, which requires the volume to be mounted again.
+func IsFileSystemResizePending(claim *v1.PersistentVolumeClaim) bool {
+	for _, c := range claim.Status.Conditions {
+		if c.Type == v1.PersistentVolumeClaimFileSystemResizePending && c.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// NewExpandedClaim returns a copy of claim with the storage requested by template.
+func NewExpandedClaim(claim, template *v1.PersistentVolumeClaim) *v1.PersistentVolumeClaim {
+	clone := claim.DeepCopy()
+	if clone.Spec.Resources.Requests == nil {
+		clone.Spec.Resources.Requests = v1.ResourceList{}
+	}
+	clone.Spec.Resources.Requests[v1.ResourceStorage] = GetStorageRequest(template)
+	return clone
+}
+
+// ValidateVolumeClaimTemplatesUpdate validates the update of volumeClaimTemplates.
+// Only the storage requests of existing templates can be changed, and they can not be decreased.
+func ValidateVolumeClaimTemplatesUpdate(templates, oldTemplates []v1.PersistentVolumeClaim, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(templates) != len(oldTemplates) {
+		return append(allErrs, field.Forbidden(fldPath, "volumeClaimTemplates can not be added or removed"))
+	}
+	for i := range templates {
+		idxPath := fldPath.Index(i)
+		template, oldTemplate := &templates[i], &oldTemplates[i]
+		if template.Name != oldTemplate.Name {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("metadata", "name"), "name of volumeClaimTemplates is immutable"))
+			continue
+		}
+
+		storage, oldStorage := GetStorageRequest(template), GetStorageRequest(oldTemplate)
+		if storage.Cmp(oldStorage) < 0 {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("spec", "resources", "requests", "storage"),
+				fmt.Sprintf("storage request can not be decreased from %s to %s", oldStorage.String(), storage.String())))
+		}
+
+		// ignore the storage request and compare other fields
+		clone := template
+		if _, ok := oldTemplate.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+			clone = NewExpandedClaim(template, oldTemplate)
+		}
+		if !apiequality.Semantic.DeepEqual(clone, oldTemplate) {
+			allErrs = append(allErrs, field.Forbidden(idxPath, "only the storage request of volumeClaimTemplates can be changed"))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeclaim
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func newClaim(name, storage string) v1.PersistentVolumeClaim {
+	return v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse(storage)},
+			},
+		},
+	}
+}
+
+func TestIsExpansionCompleted(t *testing.T) {
+	template := newClaim("data", "2Gi")
+
+	cases := []struct {
+		name              string
+		claim             func() v1.PersistentVolumeClaim
+		expectedRequired  bool
+		expectedCompleted bool
+	}{
+		{
+			name: "not expanded",
+			claim: func() v1.PersistentVolumeClaim {
+				c := newClaim("data-0", "1Gi")
+				c.Status.Capacity = v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")}
+				return c
+			},
+			expectedRequired: true,
+		},
+		{
+			name: "resizing",
+			claim: func() v1.PersistentVolumeClaim {
+				c := newClaim("data-0", "2Gi")
+				c.Status.Capacity = v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")}
+				return c
+			},
+		},
+		{
+			name: "filesystem resize pending",
+			claim: func() v1.PersistentVolumeClaim {
+				c := newClaim("data-0", "2Gi")
+				c.Status.Capacity = v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")}
+				c.Status.Conditions = []v1.PersistentVolumeClaimCondition{
+					{Type: v1.PersistentVolumeClaimFileSystemResizePending, Status: v1.ConditionTrue},
+				}
+				return c
+			},
+		},
+		{
+			name: "completed",
+			claim: func() v1.PersistentVolumeClaim {
+				c := newClaim("data-0", "2Gi")
+				c.Status.Capacity = v1.ResourceList{v1.ResourceStorage: resource.MustParse("2Gi")}
+				return c
+			},
+			expectedCompleted: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			claim := tc.claim()
+			if got := IsExpansionRequired(&claim, &template); got != tc.expectedRequired {
+				t.Fatalf("expected required %v, got %v", tc.expectedRequired, got)
+			}
+			if got := IsExpansionCompleted(&claim, &template); got != tc.expectedCompleted {
+				t.Fatalf("expected completed %v, got %v", tc.expectedCompleted, got)
+			}
+		})
+	}
+}
+
+func TestValidateVolumeClaimTemplatesUpdate(t *testing.T) {
+	cases := []struct {
+		name         string
+		templates    func() []v1.PersistentVolumeClaim
+		oldTemplates []v1.PersistentVolumeClaim
+		expectedErr  bool
+	}{
+		{
+			name:         "unchanged",
+			templates:    func() []v1.PersistentVolumeClaim { return []v1.PersistentVolumeClaim{newClaim("data", "1Gi")} },
+			oldTemplates: []v1.PersistentVolumeClaim{newClaim("data", "1Gi")},
+		},
+		{
+			name:         "increase storage",
+			templates:    func() []v1.PersistentVolumeClaim { return []v1.PersistentVolumeClaim{newClaim("data", "2Gi")} },
+			oldTemplates: []v1.PersistentVolumeClaim{newClaim("data", "1Gi")},
+		},
+		{
+			name:         "decrease storage",
+			templates:    func() []v1.PersistentVolumeClaim { return []v1.PersistentVolumeClaim{newClaim("data", "500Mi")} },
+			oldTemplates: []v1.PersistentVolumeClaim{newClaim("data", "1Gi")},
+			expectedErr:  true,
+		},
+		{
+			name: "add template",
+			templates: func() []v1.PersistentVolumeClaim {
+				return []v1.PersistentVolumeClaim{newClaim("data", "1Gi"), newClaim("log", "1Gi")}
+			},
+			oldTemplates: []v1.PersistentVolumeClaim{newClaim("data", "1Gi")},
+			expectedErr:  true,
+		},
+		{
+			name:         "rename template",
+			templates:    func() []v1.PersistentVolumeClaim { return []v1.PersistentVolumeClaim{newClaim("log", "1Gi")} },
+			oldTemplates: []v1.PersistentVolumeClaim{newClaim("data", "1Gi")},
+			expectedErr:  true,
+		},
+		{
+			name: "change access modes",
+			templates: func() []v1.PersistentVolumeClaim {
+				c := newClaim("data", "2Gi")
+				c.Spec.AccessModes = []v1.PersistentVolumeAccessMode{v1.ReadWriteMany}
+				return []v1.PersistentVolumeClaim{c}
+			},
+			oldTemplates: []v1.PersistentVolumeClaim{newClaim("data", "1Gi")},
+			expectedErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateVolumeClaimTemplatesUpdate(tc.templates(), tc.oldTemplates, field.NewPath("spec", "volumeClaimTemplates"))
+			if tc.expectedErr != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", tc.expectedErr, errs)
+			}
+		})
+	}
+}
@@ -8,12 +8,14 @@ import (
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
 	clonesetcore "github.com/openkruise/kruise/pkg/controller/cloneset/core"
 	"github.com/openkruise/kruise/pkg/util"
+	"github.com/openkruise/kruise/pkg/util/volumeclaim"
 	v1 "k8s.io/api/core/v1"
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	unversionedvalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/types"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
 	"k8s.io/kubernetes/pkg/apis/core"
@@ -59,9 +61,37 @@ func (h *CloneSetCreateUpdateHandler) validateCloneSetSpec(spec *appsv1alpha1.Cl
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("template", "spec", "activeDeadlineSeconds"), "activeDeadlineSeconds in cloneset is not Supported"))
 	}
 
+	if spec.ProgressDeadlineSeconds != nil {
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(*spec.ProgressDeadlineSeconds), fldPath.Child("progressDeadlineSeconds"))...)
+		if *spec.ProgressDeadlineSeconds <= spec.MinReadySeconds {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("progressDeadlineSeconds"), *spec.ProgressDeadlineSeconds, "must be greater than minReadySeconds"))
+		}
+	} else if spec.UpdateStrategy.AutoRollback {
+		allErrs = append(allErrs, field.Required(fldPath.Child("progressDeadlineSeconds"), "autoRollback requires progressDeadlineSeconds"))
+	}
+
 	allErrs = append(allErrs, h.validateScaleStrategy(&spec.ScaleStrategy, metadata, fldPath.Child("scaleStrategy"))...)
 	allErrs = append(allErrs, h.validateUpdateStrategy(&spec.UpdateStrategy, int(*spec.Replicas), fldPath.Child("updateStrategy"))...)
 
+	if spec.Lifecycle != nil {
+		allErrs = append(allErrs, validateLifecycleHook(spec.Lifecycle.PreDelete, fldPath.Child("lifecycle", "preDelete"))...)
+		allErrs = append(allErrs, validateLifecycleHook(spec.Lifecycle.InPlaceUpdate, fldPath.Child("lifecycle", "inPlaceUpdate"))...)
+	}
+
+	return allErrs
+}
+
+func validateLifecycleHook(hook *appsv1alpha1.LifecycleHook, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if hook == nil {
+		return allErrs
+	}
+
+	if len(hook.LabelsHandler) == 0 && len(hook.FinalizersHandler) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "labelsHandler or finalizersHandler must be set"))
+	}
+	allErrs = append(allErrs, unversionedvalidation.ValidateLabels(hook.LabelsHandler, fldPath.Child("labelsHandler"))...)
+	allErrs = append(allErrs, apimachineryvalidation.ValidateFinalizers(hook.FinalizersHandler, fldPath.Child("finalizersHandler"))...)
 	return allErrs
 }
 
@@ -84,6 +114,20 @@ func (h *CloneSetCreateUpdateHandler) validateScaleStrategy(strategy *appsv1alph
 		}
 	}
 
+	deletionPriority := &appsv1alpha1.UpdatePriorityStrategy{WeightPriority: strategy.DeletionPriority}
+	if err := deletionPriority.FieldsValidation(); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("deletionPriority"), strategy.DeletionPriority, err.Error()))
+	}
+
+	if list := util.CheckDuplicate(strategy.TopologyKeys); len(list) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("topologyKeys"), strategy.TopologyKeys, fmt.Sprintf("duplicated items %v", list)))
+	}
+	for i, key := range strategy.TopologyKeys {
+		for _, msg := range validation.IsQualifiedName(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("topologyKeys").Index(i), key, msg))
+		}
+	}
+
 	return allErrs
 }
 
@@ -139,6 +183,40 @@ func (h *CloneSetCreateUpdateHandler) validateUpdateStrategy(strategy *appsv1alp
 			"maxUnavailable and maxSurge should not both be less than 1"))
 	}
 
+	if strategy.ImagePreDownload != nil && strategy.ImagePreDownload.Parallelism != nil {
+		parallelism, err := intstrutil.GetValueFromIntOrPercent(strategy.ImagePreDownload.Parallelism, replicas, true)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("imagePreDownload", "parallelism"), strategy.ImagePreDownload.Parallelism.String(),
+				fmt.Sprintf("failed getValueFromIntOrPercent for parallelism: %v", err)))
+		} else if parallelism < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("imagePreDownload", "parallelism"), strategy.ImagePreDownload.Parallelism.String(),
+				"parallelism should not be negative"))
+		}
+	}
+
+	lastStepReplicas := 0
+	for i := range strategy.CanarySteps {
+		step := &strategy.CanarySteps[i]
+		stepPath := fldPath.Child("canarySteps").Index(i)
+		stepReplicas, err := intstrutil.GetValueFromIntOrPercent(&step.Replicas, replicas, true)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(stepPath.Child("replicas"), step.Replicas.String(),
+				fmt.Sprintf("failed getValueFromIntOrPercent for replicas: %v", err)))
+		} else if stepReplicas < 0 {
+			allErrs = append(allErrs, field.Invalid(stepPath.Child("replicas"), step.Replicas.String(),
+				"replicas should not be negative"))
+		} else if stepReplicas < lastStepReplicas {
+			allErrs = append(allErrs, field.Invalid(stepPath.Child("replicas"), step.Replicas.String(),
+				"replicas should not be less than the previous step"))
+		} else {
+			lastStepReplicas = stepReplicas
+		}
+		if step.Pause != nil && step.Pause.Duration != nil && step.Pause.Duration.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(stepPath.Child("pause", "duration"), step.Pause.Duration.String(),
+				"duration should not be negative"))
+		}
+	}
+
 	return allErrs
 }
 
@@ -159,9 +237,15 @@ func (h *CloneSetCreateUpdateHandler) validateCloneSetUpdate(cloneSet, oldCloneS
 	clone.Spec.ScaleStrategy = oldCloneSet.Spec.ScaleStrategy
 	clone.Spec.UpdateStrategy = oldCloneSet.Spec.UpdateStrategy
 	clone.Spec.MinReadySeconds = oldCloneSet.Spec.MinReadySeconds
+	clone.Spec.ProgressDeadlineSeconds = oldCloneSet.Spec.ProgressDeadlineSeconds
+	clone.Spec.Lifecycle = oldCloneSet.Spec.Lifecycle
+	clone.Spec.VolumeClaimTemplates = oldCloneSet.Spec.VolumeClaimTemplates
+	clone.Spec.VolumeClaimUpdateStrategy = oldCloneSet.Spec.VolumeClaimUpdateStrategy
 	if !reflect.DeepEqual(clone.Spec, oldCloneSet.Spec) {
-		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to cloneset spec for fields other than 'replicas', 'template', 'scaleStrategy', and 'updateStrategy' are forbidden"))
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to cloneset spec for fields other than 'replicas', 'template', 'volumeClaimTemplates', 'volumeClaimUpdateStrategy', 'lifecycle', 'scaleStrategy', 'updateStrategy', 'minReadySeconds' and 'progressDeadlineSeconds' are forbidden"))
 	}
+	allErrs = append(allErrs, volumeclaim.ValidateVolumeClaimTemplatesUpdate(
+		cloneSet.Spec.VolumeClaimTemplates, oldCloneSet.Spec.VolumeClaimTemplates, field.NewPath("spec", "volumeClaimTemplates"))...)
 
 	coreControl := clonesetcore.New(cloneSet)
 	if err := coreControl.ValidateCloneSetUpdate(oldCloneSet, cloneSet); err != nil {
@@ -9,6 +9,7 @@ import (
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -181,6 +182,30 @@ func TestValidate(t *testing.T) {
 				},
 			},
 		},
+		{
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas:             &val1,
+				Selector:             &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:             validPodTemplate.Template,
+				VolumeClaimTemplates: []v1.PersistentVolumeClaim{newVolumeClaimTemplate("2Gi")},
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.RecreateCloneSetUpdateStrategyType,
+					Partition:      &val2,
+					MaxUnavailable: &intOrStr1,
+				},
+			},
+			oldSpec: &appsv1alpha1.CloneSetSpec{
+				Replicas:             &val1,
+				Selector:             &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:             validPodTemplate.Template,
+				VolumeClaimTemplates: []v1.PersistentVolumeClaim{newVolumeClaimTemplate("1Gi")},
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.RecreateCloneSetUpdateStrategyType,
+					Partition:      &val2,
+					MaxUnavailable: &intOrStr1,
+				},
+			},
+		},
 	}
 
 	for i, successCase := range successCases {
@@ -368,6 +393,30 @@ func TestValidate(t *testing.T) {
 				},
 			},
 		},
+		"decrease-volume-claim-templates-storage": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas:             &val1,
+				Selector:             &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:             validPodTemplate.Template,
+				VolumeClaimTemplates: []v1.PersistentVolumeClaim{newVolumeClaimTemplate("1Gi")},
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.RecreateCloneSetUpdateStrategyType,
+					Partition:      &val2,
+					MaxUnavailable: &intOrStr1,
+				},
+			},
+			oldSpec: &appsv1alpha1.CloneSetSpec{
+				Replicas:             &val1,
+				Selector:             &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:             validPodTemplate.Template,
+				VolumeClaimTemplates: []v1.PersistentVolumeClaim{newVolumeClaimTemplate("2Gi")},
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.RecreateCloneSetUpdateStrategyType,
+					Partition:      &val2,
+					MaxUnavailable: &intOrStr1,
+				},
+			},
+		},
 	}
 
 	for k, v := range errorCases {
@@ -393,3 +442,15 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func newVolumeClaimTemplate(storage string) v1.PersistentVolumeClaim {
+	return v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse(storage)},
+			},
+		},
+	}
+}
@@ -19,11 +19,15 @@ package mutating
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"net/http"
+	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog"
 
@@ -43,6 +47,9 @@ func init() {
 		HandlerMap[webhookName] = []admission.Handler{}
 	}
 	HandlerMap[webhookName] = append(HandlerMap[webhookName], &PodCreateHandler{})
+
+	flag.Var(&ignoredNamespacesFlag{}, "sidecarset-ignored-namespaces",
+		"Comma-separated namespaces where Pods won't get injected by SidecarSets, defaults to kube-system,kube-public.")
 }
 
 var (
@@ -52,6 +59,26 @@ var (
 	SidecarEnvKey = "IS_INJECTED"
 )
 
+// SidecarSetInjectionIgnoreAnnotation is the annotation that Pods with value "true" won't get injected by any SidecarSet
+const SidecarSetInjectionIgnoreAnnotation = "kruise.io/sidecarset-injection-ignore"
+
+// ignoredNamespacesFlag sets SidecarIgnoredNamespaces from the comma-separated flag value.
+type ignoredNamespacesFlag struct{}
+
+func (f *ignoredNamespacesFlag) String() string {
+	return strings.Join(SidecarIgnoredNamespaces, ",")
+}
+
+func (f *ignoredNamespacesFlag) Set(value string) error {
+	SidecarIgnoredNamespaces = []string{}
+	for _, namespace := range strings.Split(value, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			SidecarIgnoredNamespaces = append(SidecarIgnoredNamespaces, namespace)
+		}
+	}
+	return nil
+}
+
 // PodCreateHandler handles Pod
 type PodCreateHandler struct {
 	// To use the client, you need to do the following:
@@ -69,10 +96,8 @@ func (h *PodCreateHandler) mutatingPodFn(ctx context.Context, obj *corev1.Pod) e
 }
 
 func (h *PodCreateHandler) sidecarsetMutatingPod(ctx context.Context, pod *corev1.Pod) error {
-	for _, namespace := range SidecarIgnoredNamespaces {
-		if pod.Namespace == namespace {
-			return nil
-		}
+	if IsPodIgnored(pod) {
+		return nil
 	}
 
 	klog.V(3).Infof("[sidecar inject] begin to process %s/%s", pod.Namespace, pod.Name)
@@ -82,13 +107,19 @@ func (h *PodCreateHandler) sidecarsetMutatingPod(ctx context.Context, pod *corev
 		return err
 	}
 
-	var sidecarContainers []corev1.Container
+	// inject the sidecarsets in the order of their names
+	sort.Slice(sidecarSets.Items, func(i, j int) bool {
+		return sidecarSets.Items[i].Name < sidecarSets.Items[j].Name
+	})
+
+	var sidecarInitContainers, beforeAppContainers, afterAppContainers []corev1.Container
 	var sidecarVolumes []corev1.Volume
 	sidecarSetHash := make(map[string]string)
 	sidecarSetHashWithoutImage := make(map[string]string)
+	workingHotUpgradeContainers := make(map[string]string)
 	matchNothing := true
 	for _, sidecarSet := range sidecarSets.Items {
-		needInject, err := PodMatchSidecarSet(pod, sidecarSet)
+		needInject, err := PodMatchSidecarSet(h.Client, pod, sidecarSet)
 		if err != nil {
 			return err
 		}
@@ -100,13 +131,40 @@ func (h *PodCreateHandler) sidecarsetMutatingPod(ctx context.Context, pod *corev
 		sidecarSetHash[sidecarSet.Name] = sidecarSet.Annotations[mutating.SidecarSetHashAnnotation]
 		sidecarSetHashWithoutImage[sidecarSet.Name] = sidecarSet.Annotations[mutating.SidecarSetHashWithoutImageAnnotation]
 
+		for i := range sidecarSet.Spec.InitContainers {
+			initContainer := &sidecarSet.Spec.InitContainers[i]
+
+			// add env to container
+			initContainer.Env = append(initContainer.Env, corev1.EnvVar{Name: SidecarEnvKey, Value: "true"})
+
+			sidecarInitContainers = append(sidecarInitContainers, initContainer.Container)
+		}
+
 		for i := range sidecarSet.Spec.Containers {
 			sidecarContainer := &sidecarSet.Spec.Containers[i]
 
 			// add env to container
 			sidecarContainer.Env = append(sidecarContainer.Env, corev1.EnvVar{Name: SidecarEnvKey, Value: "true"})
 
-			sidecarContainers = append(sidecarContainers, sidecarContainer.Container)
+			containers := []corev1.Container{sidecarContainer.Container}
+			// inject the working and the idle containers for HotUpgrade sidecar
+			if mutating.IsHotUpgradeContainer(sidecarContainer) {
+				name1, name2 := mutating.GetHotUpgradeContainerNames(sidecarContainer.Name)
+				working, idle := sidecarContainer.Container.DeepCopy(), sidecarContainer.Container.DeepCopy()
+				working.Name = name1
+				idle.Name, idle.Image = name2, sidecarContainer.UpgradeStrategy.HotUpgradeEmptyImage
+				// the migration hook is only run by the container that starts the new image during upgrade
+				mutating.SetHotUpgradeMigrationGuard(working)
+				mutating.SetHotUpgradeMigrationGuard(idle)
+				containers = []corev1.Container{*working, *idle}
+				workingHotUpgradeContainers[sidecarContainer.Name] = name1
+			}
+
+			if sidecarContainer.PodInjectPolicy == appsv1alpha1.BeforeAppContainerType {
+				beforeAppContainers = append(beforeAppContainers, containers...)
+			} else {
+				afterAppContainers = append(afterAppContainers, containers...)
+			}
 		}
 
 		sidecarVolumes = append(sidecarVolumes, sidecarSet.Spec.Volumes...)
@@ -117,11 +175,16 @@ func (h *PodCreateHandler) sidecarsetMutatingPod(ctx context.Context, pod *corev
 
 	klog.V(4).Infof("[sidecar inject] before mutating: %v", util.DumpJSON(pod))
 	// apply sidecar info into pod
-	// 1. apply containers
-	pod.Spec.Containers = append(pod.Spec.Containers, sidecarContainers...)
-	// 2. apply volumes
+	// 1. apply init containers after the init containers of pod
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, sidecarInitContainers...)
+	// 2. apply containers before or after the app containers
+	containers := make([]corev1.Container, 0, len(beforeAppContainers)+len(pod.Spec.Containers)+len(afterAppContainers))
+	containers = append(containers, beforeAppContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+	pod.Spec.Containers = append(containers, afterAppContainers...)
+	// 3. apply volumes
 	pod.Spec.Volumes = mergeVolumes(pod.Spec.Volumes, sidecarVolumes)
-	// 3. apply annotations
+	// 4. apply annotations
 	if pod.Annotations == nil {
 		pod.Annotations = make(map[string]string)
 	}
@@ -138,22 +201,58 @@ func (h *PodCreateHandler) sidecarsetMutatingPod(ctx context.Context, pod *corev
 		}
 		pod.Annotations[mutating.SidecarSetHashWithoutImageAnnotation] = string(encodedStr)
 	}
+	if len(workingHotUpgradeContainers) != 0 {
+		if err := mutating.SetPodWorkingHotUpgradeContainers(pod, workingHotUpgradeContainers); err != nil {
+			return err
+		}
+	}
 	klog.V(4).Infof("[sidecar inject] after mutating: %v", util.DumpJSON(pod))
 
 	return nil
 }
 
-// PodMatchSidecarSet determines if pod match Selector of sidecar.
-func PodMatchSidecarSet(pod *corev1.Pod, sidecarSet appsv1alpha1.SidecarSet) (bool, error) {
+// IsPodIgnored returns true if pod is in the ignored namespaces or opts out of sidecar injection by annotation.
+func IsPodIgnored(pod *corev1.Pod) bool {
+	for _, namespace := range SidecarIgnoredNamespaces {
+		if pod.Namespace == namespace {
+			return true
+		}
+	}
+	return pod.Annotations[SidecarSetInjectionIgnoreAnnotation] == "true"
+}
+
+// PodMatchSidecarSet determines if pod match Selector of sidecar, and is in the namespaces selected by sidecar.
+// The namespace of pod is only read if the sidecarset has a namespaceSelector.
+func PodMatchSidecarSet(reader client.Reader, pod *corev1.Pod, sidecarSet appsv1alpha1.SidecarSet) (bool, error) {
+	if IsPodIgnored(pod) {
+		return false, nil
+	}
+	if sidecarSet.Spec.Namespace != "" && sidecarSet.Spec.Namespace != pod.Namespace {
+		return false, nil
+	}
+
 	selector, err := metav1.LabelSelectorAsSelector(sidecarSet.Spec.Selector)
 	if err != nil {
 		return false, err
 	}
+	if selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+		return false, nil
+	}
 
-	if !selector.Empty() && selector.Matches(labels.Set(pod.Labels)) {
-		return true, nil
+	if sidecarSet.Spec.NamespaceSelector != nil {
+		nsSelector, err := metav1.LabelSelectorAsSelector(sidecarSet.Spec.NamespaceSelector)
+		if err != nil {
+			return false, err
+		}
+		namespace := &corev1.Namespace{}
+		if err := reader.Get(context.TODO(), k8stypes.NamespacedName{Name: pod.Namespace}, namespace); err != nil {
+			return false, err
+		}
+		if !nsSelector.Matches(labels.Set(namespace.Labels)) {
+			return false, nil
+		}
 	}
-	return false, nil
+	return true, nil
 }
 
 func mergeVolumes(original []corev1.Volume, additional []corev1.Volume) []corev1.Volume {
@@ -183,6 +282,10 @@ func (h *PodCreateHandler) Handle(ctx context.Context, req types.Request) types.
 	if err != nil {
 		return admission.ErrorResponse(http.StatusBadRequest, err)
 	}
+	// the namespace of pod may be empty in the creation request
+	if obj.Namespace == "" {
+		obj.Namespace = req.AdmissionRequest.Namespace
+	}
 	copy := obj.DeepCopy()
 
 	err = h.mutatingPodFn(ctx, copy)
@@ -151,6 +151,235 @@ func TestSidecarSetMutatePod(t *testing.T) {
 	}
 }
 
+func TestSidecarSetMutatePodOrder(t *testing.T) {
+	newSidecarSet := func(name string, initContainers []string, containers map[string]appsv1alpha1.PodInjectPolicyType) *appsv1alpha1.SidecarSet {
+		sidecarSet := &appsv1alpha1.SidecarSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: appsv1alpha1.SidecarSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}},
+			},
+		}
+		for _, c := range initContainers {
+			sidecarSet.Spec.InitContainers = append(sidecarSet.Spec.InitContainers, appsv1alpha1.SidecarContainer{
+				Container: corev1.Container{Name: c, Image: c + "-image"},
+			})
+		}
+		for _, c := range []string{"proxy", "network", "log"} {
+			if policy, ok := containers[c]; ok {
+				sidecarSet.Spec.Containers = append(sidecarSet.Spec.Containers, appsv1alpha1.SidecarContainer{
+					Container:       corev1.Container{Name: c, Image: c + "-image"},
+					PodInjectPolicy: policy,
+				})
+			}
+		}
+		return sidecarSet
+	}
+	sidecarSet1 := newSidecarSet("sidecarset1", []string{"init-network"},
+		map[string]appsv1alpha1.PodInjectPolicyType{"proxy": appsv1alpha1.BeforeAppContainerType, "log": ""})
+	sidecarSet2 := newSidecarSet("sidecarset2", []string{"init-config"},
+		map[string]appsv1alpha1.PodInjectPolicyType{"network": appsv1alpha1.BeforeAppContainerType})
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "nginx"},
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init", Image: "busybox"}},
+			Containers:     []corev1.Container{{Name: "nginx", Image: "nginx:1.15.1"}},
+		},
+	}
+
+	// list sidecarsets in a reversed order
+	podHandler := &PodCreateHandler{Client: fake.NewFakeClient(sidecarSet2, sidecarSet1)}
+	if err := podHandler.mutatingPodFn(context.TODO(), pod); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var initContainers, containers []string
+	for _, c := range pod.Spec.InitContainers {
+		initContainers = append(initContainers, c.Name)
+	}
+	for _, c := range pod.Spec.Containers {
+		containers = append(containers, c.Name)
+	}
+	if expected := []string{"init", "init-network", "init-config"}; !reflect.DeepEqual(initContainers, expected) {
+		t.Errorf("expected init containers %v, got %v", expected, initContainers)
+	}
+	if expected := []string{"proxy", "network", "nginx", "log"}; !reflect.DeepEqual(containers, expected) {
+		t.Errorf("expected containers %v, got %v", expected, containers)
+	}
+}
+
+func TestSidecarSetMutatePodHotUpgrade(t *testing.T) {
+	sidecarSet := &appsv1alpha1.SidecarSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "sidecarset1"},
+		Spec: appsv1alpha1.SidecarSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}},
+			Containers: []appsv1alpha1.SidecarContainer{
+				{
+					Container: corev1.Container{
+						Name:  "proxy",
+						Image: "proxy:v1",
+						Lifecycle: &corev1.Lifecycle{
+							PostStart: &corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"/migrate.sh"}}},
+						},
+					},
+					UpgradeStrategy: &appsv1alpha1.SidecarContainerUpgradeStrategy{
+						UpgradeType:          appsv1alpha1.SidecarContainerHotUpgrade,
+						HotUpgradeEmptyImage: "empty:v1",
+					},
+				},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "nginx"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "nginx", Image: "nginx:1.15.1"}},
+		},
+	}
+
+	podHandler := &PodCreateHandler{Client: fake.NewFakeClient(sidecarSet)}
+	if err := podHandler.mutatingPodFn(context.TODO(), pod); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var containers []string
+	for _, c := range pod.Spec.Containers {
+		containers = append(containers, c.Name+"="+c.Image)
+	}
+	if expected := []string{"nginx=nginx:1.15.1", "proxy-1=proxy:v1", "proxy-2=empty:v1"}; !reflect.DeepEqual(containers, expected) {
+		t.Errorf("expected containers %v, got %v", expected, containers)
+	}
+	if value := pod.Annotations[mutating.SidecarSetWorkingHotUpgradeContainerAnnotation]; value != `{"proxy":"proxy-1"}` {
+		t.Errorf("unexpected working hot upgrade containers %v", value)
+	}
+	if _, ok := pod.Annotations[mutating.SidecarSetHotUpgradeMigratingAnnotation]; ok {
+		t.Errorf("expected no migrating containers, got %v", pod.Annotations)
+	}
+	// the migration hook of both containers is guarded, so that it is not run by the first working container
+	// and the container running the empty image
+	for _, c := range pod.Spec.Containers[1:] {
+		command := c.Lifecycle.PostStart.Exec.Command
+		if len(command) != 5 || command[0] != "/bin/sh" || command[4] != "/migrate.sh" {
+			t.Errorf("unexpected postStart command of %s: %v", c.Name, command)
+		}
+		var names []string
+		for _, env := range c.Env {
+			names = append(names, env.Name)
+		}
+		if expected := []string{SidecarEnvKey, mutating.HotUpgradeContainerNameEnvKey, mutating.HotUpgradeMigratingContainersEnvKey}; !reflect.DeepEqual(names, expected) {
+			t.Errorf("expected env %v of %s, got %v", expected, c.Name, names)
+		}
+	}
+}
+
+func TestPodMatchSidecarSet(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ns1", Labels: map[string]string{"sidecar": "enabled"}},
+	}
+	reader := fake.NewFakeClient(namespace)
+
+	newSidecarSet := func(ns string, nsSelector map[string]string) appsv1alpha1.SidecarSet {
+		sidecarSet := appsv1alpha1.SidecarSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "sidecarset1"},
+			Spec: appsv1alpha1.SidecarSetSpec{
+				Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}},
+				Namespace: ns,
+			},
+		}
+		if nsSelector != nil {
+			sidecarSet.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: nsSelector}
+		}
+		return sidecarSet
+	}
+	newPod := func(ns string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-pod",
+				Namespace:   ns,
+				Labels:      map[string]string{"app": "nginx"},
+				Annotations: annotations,
+			},
+		}
+	}
+
+	cases := []struct {
+		name       string
+		pod        *corev1.Pod
+		sidecarSet appsv1alpha1.SidecarSet
+		expected   bool
+	}{
+		{
+			name:       "match all namespaces",
+			pod:        newPod("ns1", nil),
+			sidecarSet: newSidecarSet("", nil),
+			expected:   true,
+		},
+		{
+			name:       "ignored namespace",
+			pod:        newPod("kube-system", nil),
+			sidecarSet: newSidecarSet("", nil),
+			expected:   false,
+		},
+		{
+			name:       "pod opts out",
+			pod:        newPod("ns1", map[string]string{SidecarSetInjectionIgnoreAnnotation: "true"}),
+			sidecarSet: newSidecarSet("", nil),
+			expected:   false,
+		},
+		{
+			name:       "different namespace",
+			pod:        newPod("ns2", nil),
+			sidecarSet: newSidecarSet("ns1", nil),
+			expected:   false,
+		},
+		{
+			name:       "namespace selector matched",
+			pod:        newPod("ns1", nil),
+			sidecarSet: newSidecarSet("", map[string]string{"sidecar": "enabled"}),
+			expected:   true,
+		},
+		{
+			name:       "namespace selector not matched",
+			pod:        newPod("ns1", nil),
+			sidecarSet: newSidecarSet("", map[string]string{"sidecar": "disabled"}),
+			expected:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		matched, err := PodMatchSidecarSet(reader, tc.pod, tc.sidecarSet)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if matched != tc.expected {
+			t.Errorf("%s: expected matched %v, got %v", tc.name, tc.expected, matched)
+		}
+	}
+}
+
+func TestIgnoredNamespacesFlag(t *testing.T) {
+	defer func(namespaces []string) { SidecarIgnoredNamespaces = namespaces }(SidecarIgnoredNamespaces)
+
+	f := &ignoredNamespacesFlag{}
+	if err := f.Set("kube-system, monitoring,,"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"kube-system", "monitoring"}; !reflect.DeepEqual(SidecarIgnoredNamespaces, expected) {
+		t.Errorf("expected %v, got %v", expected, SidecarIgnoredNamespaces)
+	}
+	if f.String() != "kube-system,monitoring" {
+		t.Errorf("unexpected flag value %s", f.String())
+	}
+}
+
 func isMarkedSidecar(container corev1.Container) bool {
 	for _, env := range container.Env {
 		if env.Name == SidecarEnvKey && env.Value == "true" {
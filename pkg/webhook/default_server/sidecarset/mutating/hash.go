@@ -27,7 +27,7 @@ import (
 )
 
 // SidecarSetHash returns a hash of the SidecarSet.
-// The Containers are taken into account.
+// Only the Containers are taken into account, so changing the InitContainers never updates existing pods.
 func SidecarSetHash(sidecarSet *appsv1alpha1.SidecarSet) (string, error) {
 	encoded, err := encodeSidecarSet(sidecarSet)
 	if err != nil {
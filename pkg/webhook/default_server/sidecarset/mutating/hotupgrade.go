@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+)
+
+// SidecarSetWorkingHotUpgradeContainerAnnotation represents the key of the working containers of HotUpgrade sidecars
+// in pod, whose value is a JSON map from the sidecar name to its working container name
+const SidecarSetWorkingHotUpgradeContainerAnnotation = "kruise.io/sidecarset-working-hotupgrade-container"
+
+// SidecarSetHotUpgradeMigratingAnnotation represents the key of the containers of HotUpgrade sidecars in pod that are
+// starting the new image and should run the migration hook, whose value is a comma-separated list of container names
+const SidecarSetHotUpgradeMigratingAnnotation = "kruise.io/sidecarset-hotupgrade-migrating-containers"
+
+// SidecarSetHotUpgradeLastImageIDsAnnotation represents the key of the image IDs of the idle containers of HotUpgrade
+// sidecars in pod before they start the new image, whose value is a JSON map from the container name to its image ID
+const SidecarSetHotUpgradeLastImageIDsAnnotation = "kruise.io/sidecarset-hotupgrade-last-image-ids"
+
+const (
+	// HotUpgradeContainerNameEnvKey is the env of HotUpgrade containers holding the name of the container itself.
+	HotUpgradeContainerNameEnvKey = "KRUISE_HOTUPGRADE_CONTAINER_NAME"
+	// HotUpgradeMigratingContainersEnvKey is the env of HotUpgrade containers holding the migrating containers
+	// recorded in pod annotations when the container starts.
+	HotUpgradeMigratingContainersEnvKey = "KRUISE_HOTUPGRADE_MIGRATING_CONTAINERS"
+)
+
+// hotUpgradeMigrationGuard is the shell script that runs the migration hook given in its arguments,
+// only if the container is one of the migrating containers.
+var hotUpgradeMigrationGuard = fmt.Sprintf(`case ",$%s," in *",$%s,"*) exec "$@";; esac`,
+	HotUpgradeMigratingContainersEnvKey, HotUpgradeContainerNameEnvKey)
+
+// IsHotUpgradeContainer returns true if the sidecar container is upgraded by HotUpgrade.
+func IsHotUpgradeContainer(sidecarContainer *appsv1alpha1.SidecarContainer) bool {
+	return sidecarContainer.UpgradeStrategy != nil &&
+		sidecarContainer.UpgradeStrategy.UpgradeType == appsv1alpha1.SidecarContainerHotUpgrade
+}
+
+// GetHotUpgradeContainerNames returns the names of the two containers injected for a HotUpgrade sidecar.
+func GetHotUpgradeContainerNames(sidecarName string) (string, string) {
+	return fmt.Sprintf("%s-1", sidecarName), fmt.Sprintf("%s-2", sidecarName)
+}
+
+// GetPodHotUpgradeContainers returns the working and idle containers of the HotUpgrade sidecar in pod.
+func GetPodHotUpgradeContainers(sidecarName string, pod *corev1.Pod) (working, idle string, err error) {
+	name1, name2 := GetHotUpgradeContainerNames(sidecarName)
+	workingContainers, err := GetPodWorkingHotUpgradeContainers(pod)
+	if err != nil {
+		return "", "", err
+	}
+	if workingContainers[sidecarName] == name2 {
+		return name2, name1, nil
+	}
+	return name1, name2, nil
+}
+
+// GetPodWorkingHotUpgradeContainers returns the working containers of the HotUpgrade sidecars recorded in pod.
+func GetPodWorkingHotUpgradeContainers(pod *corev1.Pod) (map[string]string, error) {
+	workingContainers := make(map[string]string)
+	if value := pod.Annotations[SidecarSetWorkingHotUpgradeContainerAnnotation]; value != "" {
+		if err := json.Unmarshal([]byte(value), &workingContainers); err != nil {
+			return nil, err
+		}
+	}
+	return workingContainers, nil
+}
+
+// SetPodWorkingHotUpgradeContainers records the working containers of the HotUpgrade sidecars in pod.
+func SetPodWorkingHotUpgradeContainers(pod *corev1.Pod, workingContainers map[string]string) error {
+	encoded, err := json.Marshal(workingContainers)
+	if err != nil {
+		return err
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[SidecarSetWorkingHotUpgradeContainerAnnotation] = string(encoded)
+	return nil
+}
+
+// SetHotUpgradeMigrationGuard makes the exec postStart hook of the injected HotUpgrade container only run when the
+// container starts the new image to take over the working one. The hook is skipped when the container starts the
+// empty image, or starts as the first working container of pod, for there is nothing to migrate from.
+func SetHotUpgradeMigrationGuard(container *corev1.Container) {
+	container.Env = append(container.Env,
+		corev1.EnvVar{Name: HotUpgradeContainerNameEnvKey, Value: container.Name},
+		corev1.EnvVar{
+			Name: HotUpgradeMigratingContainersEnvKey,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: fmt.Sprintf("metadata.annotations['%s']", SidecarSetHotUpgradeMigratingAnnotation),
+				},
+			},
+		},
+	)
+
+	if container.Lifecycle == nil || container.Lifecycle.PostStart == nil || container.Lifecycle.PostStart.Exec == nil {
+		return
+	}
+	exec := container.Lifecycle.PostStart.Exec
+	exec.Command = append([]string{"/bin/sh", "-c", hotUpgradeMigrationGuard, "migration-hook"}, exec.Command...)
+}
+
+// SetPodHotUpgradeMigratingContainer adds the container into, or removes it from, the migrating containers of pod.
+func SetPodHotUpgradeMigratingContainer(pod *corev1.Pod, containerName string, migrating bool) {
+	containers := sets.NewString()
+	if value := pod.Annotations[SidecarSetHotUpgradeMigratingAnnotation]; value != "" {
+		containers.Insert(strings.Split(value, ",")...)
+	}
+	if migrating {
+		containers.Insert(containerName)
+	} else {
+		containers.Delete(containerName)
+	}
+
+	if containers.Len() == 0 {
+		delete(pod.Annotations, SidecarSetHotUpgradeMigratingAnnotation)
+		return
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[SidecarSetHotUpgradeMigratingAnnotation] = strings.Join(containers.List(), ",")
+}
+
+// GetPodHotUpgradeLastImageIDs returns the image IDs of the idle containers recorded in pod before they start the new image.
+func GetPodHotUpgradeLastImageIDs(pod *corev1.Pod) (map[string]string, error) {
+	lastImageIDs := make(map[string]string)
+	if value := pod.Annotations[SidecarSetHotUpgradeLastImageIDsAnnotation]; value != "" {
+		if err := json.Unmarshal([]byte(value), &lastImageIDs); err != nil {
+			return nil, err
+		}
+	}
+	return lastImageIDs, nil
+}
+
+// SetPodHotUpgradeLastImageID records in pod the image ID of the container before it starts the new image,
+// or removes the record if imageID is empty.
+func SetPodHotUpgradeLastImageID(pod *corev1.Pod, containerName, imageID string) error {
+	lastImageIDs, err := GetPodHotUpgradeLastImageIDs(pod)
+	if err != nil {
+		return err
+	}
+	if imageID == "" {
+		delete(lastImageIDs, containerName)
+	} else {
+		lastImageIDs[containerName] = imageID
+	}
+
+	if len(lastImageIDs) == 0 {
+		delete(pod.Annotations, SidecarSetHotUpgradeLastImageIDsAnnotation)
+		return nil
+	}
+	encoded, err := json.Marshal(lastImageIDs)
+	if err != nil {
+		return err
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[SidecarSetHotUpgradeLastImageIDsAnnotation] = string(encoded)
+	return nil
+}
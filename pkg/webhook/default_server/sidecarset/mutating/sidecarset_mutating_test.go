@@ -1,6 +1,7 @@
 package mutating
 
 import (
+	"os/exec"
 	"reflect"
 	"testing"
 
@@ -64,3 +65,85 @@ func TestSidecarSetHash(t *testing.T) {
 		t.Errorf("\nexpect:\n%+v\nbut got:\n%+v", expectedOutputSidecarSet, sidecarSet)
 	}
 }
+
+func TestSidecarSetHashIgnoreInitContainers(t *testing.T) {
+	sidecarSet := sidecarSetDemo.DeepCopy()
+	if err := setHashSidecarSet(sidecarSet); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	withInitContainers := sidecarSetDemo.DeepCopy()
+	withInitContainers.Spec.InitContainers = []appsv1alpha1.SidecarContainer{
+		{
+			Container: corev1.Container{
+				Name:  "test-init",
+				Image: "test-init-image:v1",
+			},
+		},
+	}
+	if err := setHashSidecarSet(withInitContainers); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if !reflect.DeepEqual(sidecarSet.Annotations, withInitContainers.Annotations) {
+		t.Errorf("expect hash not changed by init containers, but got:\n%v\n%v", sidecarSet.Annotations, withInitContainers.Annotations)
+	}
+}
+
+func TestHotUpgradeMigrationGuard(t *testing.T) {
+	container := &corev1.Container{
+		Name: "proxy-2",
+		Lifecycle: &corev1.Lifecycle{
+			PostStart: &corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"echo", "migrated"}}},
+		},
+	}
+	SetHotUpgradeMigrationGuard(container)
+
+	command := container.Lifecycle.PostStart.Exec.Command
+	if len(container.Env) != 2 || container.Env[0].Value != "proxy-2" || container.Env[1].ValueFrom == nil {
+		t.Fatalf("unexpected env %+v", container.Env)
+	}
+	if _, err := exec.LookPath(command[0]); err != nil {
+		t.Skipf("skip running the hook: %v", err)
+	}
+
+	cases := []struct {
+		name      string
+		migrating string
+		expected  string
+	}{
+		{name: "no migrating container", migrating: "", expected: ""},
+		{name: "other container migrating", migrating: "proxy-1,sidecar-2", expected: ""},
+		{name: "container migrating", migrating: "proxy-2,sidecar-2", expected: "migrated\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := exec.Command(command[0], command[1:]...)
+			cmd.Env = []string{
+				HotUpgradeContainerNameEnvKey + "=" + container.Name,
+				HotUpgradeMigratingContainersEnvKey + "=" + tc.migrating,
+			}
+			output, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("failed to run the hook: %v", err)
+			}
+			if string(output) != tc.expected {
+				t.Fatalf("expected output %q, got %q", tc.expected, output)
+			}
+		})
+	}
+}
+
+func TestSetPodHotUpgradeMigratingContainer(t *testing.T) {
+	pod := &corev1.Pod{}
+	SetPodHotUpgradeMigratingContainer(pod, "proxy-2", true)
+	SetPodHotUpgradeMigratingContainer(pod, "sidecar-1", true)
+	if value := pod.Annotations[SidecarSetHotUpgradeMigratingAnnotation]; value != "proxy-2,sidecar-1" {
+		t.Fatalf("unexpected migrating containers %q", value)
+	}
+	SetPodHotUpgradeMigratingContainer(pod, "proxy-2", false)
+	SetPodHotUpgradeMigratingContainer(pod, "sidecar-1", false)
+	if _, ok := pod.Annotations[SidecarSetHotUpgradeMigratingAnnotation]; ok {
+		t.Fatalf("expected migrating containers removed, got %v", pod.Annotations)
+	}
+}
@@ -39,6 +39,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
 
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	sidecarsetmutating "github.com/openkruise/kruise/pkg/webhook/default_server/sidecarset/mutating"
 )
 
 func init() {
@@ -108,10 +109,22 @@ func validateSidecarSetSpec(obj *appsv1alpha1.SidecarSet, fldPath *field.Path) f
 		}
 	}
 
+	if spec.Namespace != "" {
+		for _, msg := range genericvalidation.ValidateNamespaceName(spec.Namespace, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("namespace"), spec.Namespace, msg))
+		}
+	}
+	if spec.NamespaceSelector != nil {
+		if spec.Namespace != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("namespaceSelector"), "namespace and namespaceSelector can not be set at the same time"))
+		}
+		allErrs = append(allErrs, metavalidation.ValidateLabelSelector(spec.NamespaceSelector, fldPath.Child("namespaceSelector"))...)
+	}
+
 	allErrs = append(allErrs, validateSidecarSetStratety(&spec.Strategy, fldPath.Child("strategy"))...)
 	vols, vErrs := getCoreVolumes(spec.Volumes, fldPath.Child("volumes"))
 	allErrs = append(allErrs, vErrs...)
-	allErrs = append(allErrs, validateContainersForSidecarSet(spec.Containers, vols, fldPath.Child("containers"))...)
+	allErrs = append(allErrs, validateContainersForSidecarSet(spec.InitContainers, spec.Containers, vols, fldPath)...)
 
 	return allErrs
 }
@@ -143,11 +156,31 @@ func getCoreVolumes(volumes []v1.Volume, fldPath *field.Path) ([]core.Volume, fi
 }
 
 func validateContainersForSidecarSet(
-	containers []appsv1alpha1.SidecarContainer, coreVolumes []core.Volume, fldPath *field.Path) field.ErrorList {
+	initContainers, containers []appsv1alpha1.SidecarContainer, coreVolumes []core.Volume, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
+	coreInitContainers := []core.Container{}
+	for i, container := range initContainers {
+		if sidecarsetmutating.IsHotUpgradeContainer(&container) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("initContainers").Index(i).Child("upgradeStrategy"), "init containers can not be hot upgraded"))
+		}
+		coreContainer := core.Container{}
+		if err := corev1.Convert_v1_Container_To_core_Container(&container.Container, &coreContainer, nil); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Root(), container.Container, fmt.Sprintf("Convert_v1_Container_To_core_Container failed: %v", err)))
+			return allErrs
+		}
+		coreInitContainers = append(coreInitContainers, coreContainer)
+	}
+
 	coreContainers := []core.Container{}
-	for _, container := range containers {
+	for i, container := range containers {
+		switch container.PodInjectPolicy {
+		case "", appsv1alpha1.BeforeAppContainerType, appsv1alpha1.AfterAppContainerType:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("containers").Index(i).Child("podInjectPolicy"), container.PodInjectPolicy,
+				[]string{string(appsv1alpha1.BeforeAppContainerType), string(appsv1alpha1.AfterAppContainerType)}))
+		}
+		allErrs = append(allErrs, validateSidecarContainerUpgradeStrategy(&container, fldPath.Child("containers").Index(i))...)
 		coreContainer := core.Container{}
 		if err := corev1.Convert_v1_Container_To_core_Container(&container.Container, &coreContainer, nil); err != nil {
 			allErrs = append(allErrs, field.Invalid(fldPath.Root(), container.Container, fmt.Sprintf("Convert_v1_Container_To_core_Container failed: %v", err)))
@@ -160,10 +193,11 @@ func validateContainersForSidecarSet(
 	fakePod := &core.Pod{
 		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
 		Spec: core.PodSpec{
-			DNSPolicy:     core.DNSClusterFirst,
-			RestartPolicy: core.RestartPolicyAlways,
-			Containers:    coreContainers,
-			Volumes:       coreVolumes,
+			DNSPolicy:      core.DNSClusterFirst,
+			RestartPolicy:  core.RestartPolicyAlways,
+			InitContainers: coreInitContainers,
+			Containers:     coreContainers,
+			Volumes:        coreVolumes,
 		},
 	}
 	allErrs = append(allErrs, corevalidation.ValidatePod(fakePod)...)
@@ -171,6 +205,43 @@ func validateContainersForSidecarSet(
 	return allErrs
 }
 
+func validateSidecarContainerUpgradeStrategy(container *appsv1alpha1.SidecarContainer, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if container.UpgradeStrategy == nil {
+		return allErrs
+	}
+
+	strategyPath := fldPath.Child("upgradeStrategy")
+	switch container.UpgradeStrategy.UpgradeType {
+	case "", appsv1alpha1.SidecarContainerColdUpgrade:
+	case appsv1alpha1.SidecarContainerHotUpgrade:
+		if container.UpgradeStrategy.HotUpgradeEmptyImage == "" {
+			allErrs = append(allErrs, field.Required(strategyPath.Child("hotUpgradeEmptyImage"), "hotUpgradeEmptyImage is required for HotUpgrade"))
+		} else if container.UpgradeStrategy.HotUpgradeEmptyImage == container.Image {
+			allErrs = append(allErrs, field.Invalid(strategyPath.Child("hotUpgradeEmptyImage"), container.UpgradeStrategy.HotUpgradeEmptyImage,
+				"hotUpgradeEmptyImage must be different from the image of sidecar"))
+		}
+		name1, _ := sidecarsetmutating.GetHotUpgradeContainerNames(container.Name)
+		for _, msg := range validationutil.IsDNS1123Label(name1) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), container.Name, "name of the hot upgrade container: "+msg))
+		}
+		// the two containers injected for the sidecar would conflict on the same host ports
+		for j, port := range container.Ports {
+			if port.HostPort != 0 {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("ports").Index(j).Child("hostPort"), "hostPort is not supported for HotUpgrade"))
+			}
+		}
+		// only the exec hook can be skipped by the containers that have nothing to migrate
+		if container.Lifecycle != nil && container.Lifecycle.PostStart != nil && container.Lifecycle.PostStart.Exec == nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("lifecycle", "postStart"), "only exec postStart hook is supported for HotUpgrade"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(strategyPath.Child("upgradeType"), container.UpgradeStrategy.UpgradeType,
+			[]string{string(appsv1alpha1.SidecarContainerColdUpgrade), string(appsv1alpha1.SidecarContainerHotUpgrade)}))
+	}
+	return allErrs
+}
+
 var _ admission.Handler = &SidecarSetCreateUpdateHandler{}
 
 // Handle handles admission requests.
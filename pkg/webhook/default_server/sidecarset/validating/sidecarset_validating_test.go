@@ -111,6 +111,141 @@ func TestValidateSidecarSet(t *testing.T) {
 				},
 			},
 		},
+		"wrong-podInjectPolicy": {
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+			Spec: appsv1alpha1.SidecarSetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"a": "b"},
+				},
+				Strategy: appsv1alpha1.SidecarSetUpdateStrategy{
+					RollingUpdate: &appsv1alpha1.RollingUpdateSidecarSet{
+						MaxUnavailable: &maxUnavailable,
+					},
+				},
+				Containers: []appsv1alpha1.SidecarContainer{
+					{
+						Container: corev1.Container{
+							Name:                     "test-sidecar",
+							Image:                    "test-image",
+							ImagePullPolicy:          corev1.PullIfNotPresent,
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+						},
+						PodInjectPolicy: "Middle",
+					},
+				},
+			},
+		},
+		"missing-hotUpgradeEmptyImage": {
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+			Spec: appsv1alpha1.SidecarSetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"a": "b"},
+				},
+				Strategy: appsv1alpha1.SidecarSetUpdateStrategy{
+					RollingUpdate: &appsv1alpha1.RollingUpdateSidecarSet{
+						MaxUnavailable: &maxUnavailable,
+					},
+				},
+				Containers: []appsv1alpha1.SidecarContainer{
+					{
+						Container: corev1.Container{
+							Name:                     "test-sidecar",
+							Image:                    "test-image",
+							ImagePullPolicy:          corev1.PullIfNotPresent,
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+						},
+						UpgradeStrategy: &appsv1alpha1.SidecarContainerUpgradeStrategy{
+							UpgradeType: appsv1alpha1.SidecarContainerHotUpgrade,
+						},
+					},
+				},
+			},
+		},
+		"hotUpgrade-with-hostPort": {
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+			Spec: appsv1alpha1.SidecarSetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"a": "b"},
+				},
+				Strategy: appsv1alpha1.SidecarSetUpdateStrategy{
+					RollingUpdate: &appsv1alpha1.RollingUpdateSidecarSet{
+						MaxUnavailable: &maxUnavailable,
+					},
+				},
+				Containers: []appsv1alpha1.SidecarContainer{
+					{
+						Container: corev1.Container{
+							Name:                     "test-sidecar",
+							Image:                    "test-image",
+							ImagePullPolicy:          corev1.PullIfNotPresent,
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							Ports:                    []corev1.ContainerPort{{ContainerPort: 80, HostPort: 80, Protocol: corev1.ProtocolTCP}},
+						},
+						UpgradeStrategy: &appsv1alpha1.SidecarContainerUpgradeStrategy{
+							UpgradeType:          appsv1alpha1.SidecarContainerHotUpgrade,
+							HotUpgradeEmptyImage: "empty-image",
+						},
+					},
+				},
+			},
+		},
+		"hotUpgrade-with-httpGet-postStart": {
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+			Spec: appsv1alpha1.SidecarSetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"a": "b"},
+				},
+				Strategy: appsv1alpha1.SidecarSetUpdateStrategy{
+					RollingUpdate: &appsv1alpha1.RollingUpdateSidecarSet{
+						MaxUnavailable: &maxUnavailable,
+					},
+				},
+				Containers: []appsv1alpha1.SidecarContainer{
+					{
+						Container: corev1.Container{
+							Name:                     "test-sidecar",
+							Image:                    "test-image",
+							ImagePullPolicy:          corev1.PullIfNotPresent,
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							Lifecycle: &corev1.Lifecycle{
+								PostStart: &corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/migrate", Port: intstr.FromInt(80), Scheme: corev1.URISchemeHTTP}},
+							},
+						},
+						UpgradeStrategy: &appsv1alpha1.SidecarContainerUpgradeStrategy{
+							UpgradeType:          appsv1alpha1.SidecarContainerHotUpgrade,
+							HotUpgradeEmptyImage: "empty-image",
+						},
+					},
+				},
+			},
+		},
+		"both-namespace-and-namespaceSelector": {
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+			Spec: appsv1alpha1.SidecarSetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"a": "b"},
+				},
+				Namespace: "test-ns",
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"c": "d"},
+				},
+				Strategy: appsv1alpha1.SidecarSetUpdateStrategy{
+					RollingUpdate: &appsv1alpha1.RollingUpdateSidecarSet{
+						MaxUnavailable: &maxUnavailable,
+					},
+				},
+				Containers: []appsv1alpha1.SidecarContainer{
+					{
+						Container: corev1.Container{
+							Name:                     "test-sidecar",
+							Image:                    "test-image",
+							ImagePullPolicy:          corev1.PullIfNotPresent,
+							TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, sidecarSet := range errorCases {
@@ -7,13 +7,17 @@ import (
 
 	"github.com/appscode/jsonpatch"
 	appsv1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	statefulsetutil "github.com/openkruise/kruise/pkg/util/statefulset"
+	"github.com/openkruise/kruise/pkg/util/volumeclaim"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	unversionedvalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/labels"
 	intstr "k8s.io/apimachinery/pkg/util/intstr"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
 	"k8s.io/kubernetes/pkg/apis/core"
@@ -35,6 +39,33 @@ func validateStatefulSetSpec(spec *appsv1alpha1.StatefulSetSpec, fldPath *field.
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("podManagementPolicy"), spec.PodManagementPolicy, fmt.Sprintf("must be '%s' or '%s'", apps.OrderedReadyPodManagement, apps.ParallelPodManagement)))
 	}
 
+	if spec.ScaleStrategy != nil && spec.ScaleStrategy.MaxUnavailable != nil {
+		allErrs = append(allErrs,
+			appsvalidation.ValidatePositiveIntOrPercent(
+				*spec.ScaleStrategy.MaxUnavailable,
+				fldPath.Child("scaleStrategy").Child("maxUnavailable"))...)
+		if maxUnavailable, err := intstrutil.GetValueFromIntOrPercent(spec.ScaleStrategy.MaxUnavailable, 1, true); err != nil {
+			allErrs = append(allErrs, field.Invalid(
+				fldPath.Child("scaleStrategy").Child("maxUnavailable"),
+				spec.ScaleStrategy.MaxUnavailable,
+				fmt.Sprintf("failed getValueFromIntOrPercent for maxUnavailable: %v", err),
+			))
+		} else if maxUnavailable < 1 {
+			allErrs = append(allErrs, field.Invalid(
+				fldPath.Child("scaleStrategy").Child("maxUnavailable"),
+				spec.ScaleStrategy.MaxUnavailable,
+				"getValueFromIntOrPercent for maxUnavailable should not be less than 1",
+			))
+		}
+		if spec.PodManagementPolicy == apps.ParallelPodManagement {
+			allErrs = append(allErrs, field.Invalid(
+				fldPath.Child("scaleStrategy").Child("maxUnavailable"),
+				spec.ScaleStrategy.MaxUnavailable,
+				"maxUnavailable of scaleStrategy can just work with OrderedReady PodManagementPolicyType",
+			))
+		}
+	}
+
 	switch spec.UpdateStrategy.Type {
 	case "":
 		allErrs = append(allErrs, field.Required(fldPath.Child("updateStrategy"), ""))
@@ -142,6 +173,35 @@ func validateStatefulSetSpec(spec *appsv1alpha1.StatefulSetSpec, fldPath *field.
 	}
 
 	allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(*spec.Replicas), fldPath.Child("replicas"))...)
+	reserveOrdinals := make(map[int]struct{}, len(spec.ReserveOrdinals))
+	for i, ord := range spec.ReserveOrdinals {
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(ord), fldPath.Child("reserveOrdinals").Index(i))...)
+		if _, ok := reserveOrdinals[ord]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("reserveOrdinals").Index(i), ord))
+		}
+		reserveOrdinals[ord] = struct{}{}
+	}
+	allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(spec.MinReadySeconds), fldPath.Child("minReadySeconds"))...)
+	if spec.Ordinals != nil {
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(spec.Ordinals.Start), fldPath.Child("ordinals", "start"))...)
+	}
+	switch spec.ServiceManagementPolicy {
+	case "", appsv1alpha1.ManualServiceManagementPolicyType:
+	case appsv1alpha1.ManagedServiceManagementPolicyType:
+		if spec.ServiceName == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("serviceName"), "serviceName is required when serviceManagementPolicy is Managed"))
+		}
+		if spec.Selector != nil && len(spec.Selector.MatchExpressions) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("selector", "matchExpressions"), "matchExpressions is not supported when serviceManagementPolicy is Managed"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("serviceManagementPolicy"), spec.ServiceManagementPolicy,
+			[]string{string(appsv1alpha1.ManualServiceManagementPolicyType), string(appsv1alpha1.ManagedServiceManagementPolicyType)}))
+	}
+	if spec.PersistentVolumeClaimRetentionPolicy != nil {
+		allErrs = append(allErrs, validatePersistentVolumeClaimRetentionPolicyType(spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted, fldPath.Child("persistentVolumeClaimRetentionPolicy", "whenDeleted"))...)
+		allErrs = append(allErrs, validatePersistentVolumeClaimRetentionPolicyType(spec.PersistentVolumeClaimRetentionPolicy.WhenScaled, fldPath.Child("persistentVolumeClaimRetentionPolicy", "whenScaled"))...)
+	}
 	if spec.Selector == nil {
 		allErrs = append(allErrs, field.Required(fldPath.Child("selector"), ""))
 	} else {
@@ -169,10 +229,88 @@ func validateStatefulSetSpec(spec *appsv1alpha1.StatefulSetSpec, fldPath *field.
 	if spec.Template.Spec.ActiveDeadlineSeconds != nil {
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("template", "spec", "activeDeadlineSeconds"), "activeDeadlineSeconds in StatefulSet is not Supported"))
 	}
+	allErrs = append(allErrs, validatePodOverrides(spec, selector, fldPath.Child("podOverrides"))...)
+
+	return allErrs
+}
+
+func validatePodOverrides(spec *appsv1alpha1.StatefulSetSpec, selector labels.Selector, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, override := range spec.PodOverrides {
+		idxPath := fldPath.Index(i)
+		ranges, err := statefulsetutil.ParsePodOverrideOrdinals(override.Ordinals)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("ordinals"), override.Ordinals, err.Error()))
+			continue
+		}
+		if len(override.Patch.Raw) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("patch"), ""))
+			continue
+		}
+		overrides := []appsv1alpha1.StatefulSetPodOverride{override}
+		if _, err := statefulsetutil.ApplyPodOverrides(&spec.Template, overrides, ranges[0][0]); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("patch"), string(override.Patch.Raw), err.Error()))
+		}
+	}
+	if len(allErrs) > 0 || selector == nil {
+		return allErrs
+	}
+
+	// The set of overrides applied to a pod only changes at the bounds of the
+	// ordinal ranges, so validating the templates of those ordinals covers
+	// every combined template the controller may create pods from.
+	boundOwners := map[int]int{}
+	bounds := sets.NewInt()
+	for i, override := range spec.PodOverrides {
+		ranges, _ := statefulsetutil.ParsePodOverrideOrdinals(override.Ordinals)
+		for _, r := range ranges {
+			for _, ordinal := range []int{r[0], r[1] + 1} {
+				if !bounds.Has(ordinal) {
+					bounds.Insert(ordinal)
+					boundOwners[ordinal] = i
+				}
+			}
+		}
+	}
+
+	var validated []*v1.PodTemplateSpec
+	for _, ordinal := range bounds.List() {
+		patchPath := fldPath.Index(boundOwners[ordinal]).Child("patch")
+		template, err := statefulsetutil.ApplyPodOverrides(&spec.Template, spec.PodOverrides, ordinal)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(patchPath, string(spec.PodOverrides[boundOwners[ordinal]].Patch.Raw), err.Error()))
+			continue
+		}
+		if apiequality.Semantic.DeepEqual(template, &spec.Template) || containsPodTemplate(validated, template) {
+			continue
+		}
+		validated = append(validated, template)
 
+		coreTemplate, err := convertPodTemplateSpec(template)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(patchPath, string(spec.PodOverrides[boundOwners[ordinal]].Patch.Raw), fmt.Sprintf("Convert_v1_PodTemplateSpec_To_core_PodTemplateSpec failed: %v", err)))
+			continue
+		}
+		allErrs = append(allErrs, appsvalidation.ValidatePodTemplateSpecForStatefulSet(coreTemplate, selector, patchPath)...)
+		if template.Spec.RestartPolicy != v1.RestartPolicyAlways {
+			allErrs = append(allErrs, field.NotSupported(patchPath.Child("spec", "restartPolicy"), template.Spec.RestartPolicy, []string{string(v1.RestartPolicyAlways)}))
+		}
+		if template.Spec.ActiveDeadlineSeconds != nil {
+			allErrs = append(allErrs, field.Forbidden(patchPath.Child("spec", "activeDeadlineSeconds"), "activeDeadlineSeconds in StatefulSet is not Supported"))
+		}
+	}
 	return allErrs
 }
 
+func containsPodTemplate(templates []*v1.PodTemplateSpec, template *v1.PodTemplateSpec) bool {
+	for _, t := range templates {
+		if apiequality.Semantic.DeepEqual(t, template) {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateStatefulSet validates a StatefulSet.
 func validateStatefulSet(statefulSet *appsv1alpha1.StatefulSet) field.ErrorList {
 	allErrs := apivalidation.ValidateObjectMeta(&statefulSet.ObjectMeta, true, appsvalidation.ValidateStatefulSetName, field.NewPath("metadata"))
@@ -193,14 +331,84 @@ func ValidateStatefulSetUpdate(statefulSet, oldStatefulSet *appsv1alpha1.Statefu
 	restoreStrategy := statefulSet.Spec.UpdateStrategy
 	statefulSet.Spec.UpdateStrategy = oldStatefulSet.Spec.UpdateStrategy
 
+	restoreReserveOrdinals := statefulSet.Spec.ReserveOrdinals
+	statefulSet.Spec.ReserveOrdinals = oldStatefulSet.Spec.ReserveOrdinals
+
+	restoreRetentionPolicy := statefulSet.Spec.PersistentVolumeClaimRetentionPolicy
+	statefulSet.Spec.PersistentVolumeClaimRetentionPolicy = oldStatefulSet.Spec.PersistentVolumeClaimRetentionPolicy
+
+	restoreScaleStrategy := statefulSet.Spec.ScaleStrategy
+	statefulSet.Spec.ScaleStrategy = oldStatefulSet.Spec.ScaleStrategy
+
+	restoreOrdinals := statefulSet.Spec.Ordinals
+	statefulSet.Spec.Ordinals = oldStatefulSet.Spec.Ordinals
+
+	restoreVolumeClaimTemplates := statefulSet.Spec.VolumeClaimTemplates
+	statefulSet.Spec.VolumeClaimTemplates = oldStatefulSet.Spec.VolumeClaimTemplates
+
+	restoreVolumeClaimUpdateStrategy := statefulSet.Spec.VolumeClaimUpdateStrategy
+	statefulSet.Spec.VolumeClaimUpdateStrategy = oldStatefulSet.Spec.VolumeClaimUpdateStrategy
+
+	restoreMinReadySeconds := statefulSet.Spec.MinReadySeconds
+	statefulSet.Spec.MinReadySeconds = oldStatefulSet.Spec.MinReadySeconds
+
+	restorePodOverrides := statefulSet.Spec.PodOverrides
+	statefulSet.Spec.PodOverrides = oldStatefulSet.Spec.PodOverrides
+
+	restoreServiceManagementPolicy := statefulSet.Spec.ServiceManagementPolicy
+	statefulSet.Spec.ServiceManagementPolicy = oldStatefulSet.Spec.ServiceManagementPolicy
+
 	if !apiequality.Semantic.DeepEqual(statefulSet.Spec, oldStatefulSet.Spec) {
-		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to statefulset spec for fields other than 'replicas', 'template', and 'updateStrategy' are forbidden"))
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to statefulset spec for fields other than 'replicas', 'template', 'volumeClaimTemplates', 'volumeClaimUpdateStrategy', 'updateStrategy', 'scaleStrategy', 'minReadySeconds', 'podOverrides', 'serviceManagementPolicy', 'ordinals', 'reserveOrdinals' and 'persistentVolumeClaimRetentionPolicy' are forbidden"))
 	}
 	statefulSet.Spec.Replicas = restoreReplicas
 	statefulSet.Spec.Template = restoreTemplate
 	statefulSet.Spec.UpdateStrategy = restoreStrategy
+	statefulSet.Spec.ReserveOrdinals = restoreReserveOrdinals
+	statefulSet.Spec.PersistentVolumeClaimRetentionPolicy = restoreRetentionPolicy
+	statefulSet.Spec.ScaleStrategy = restoreScaleStrategy
+	statefulSet.Spec.Ordinals = restoreOrdinals
+	statefulSet.Spec.VolumeClaimTemplates = restoreVolumeClaimTemplates
+	statefulSet.Spec.VolumeClaimUpdateStrategy = restoreVolumeClaimUpdateStrategy
+	statefulSet.Spec.MinReadySeconds = restoreMinReadySeconds
+	statefulSet.Spec.PodOverrides = restorePodOverrides
+	statefulSet.Spec.ServiceManagementPolicy = restoreServiceManagementPolicy
 
 	allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(int64(*statefulSet.Spec.Replicas), field.NewPath("spec", "replicas"))...)
+	allErrs = append(allErrs, volumeclaim.ValidateVolumeClaimTemplatesUpdate(
+		statefulSet.Spec.VolumeClaimTemplates, oldStatefulSet.Spec.VolumeClaimTemplates, field.NewPath("spec", "volumeClaimTemplates"))...)
+
+	// start ordinal can only be changed with the highest ordinal unchanged, which means Pods are moved in or out
+	// only from the start of the range, instead of all of them being renamed
+	if statefulsetutil.GetStartOrdinal(statefulSet) != statefulsetutil.GetStartOrdinal(oldStatefulSet) && *statefulSet.Spec.Replicas >= 0 {
+		if getMaxOrdinal(statefulSet) != getMaxOrdinal(oldStatefulSet) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "ordinals", "start"),
+				"ordinals.start can only be changed together with replicas to keep the highest ordinal of Pods unchanged"))
+		}
+	}
+	return allErrs
+}
+
+// getMaxOrdinal returns the highest ordinal of Pods that set should have, which is the start ordinal minus 1
+// if set has no replicas.
+func getMaxOrdinal(set *appsv1alpha1.StatefulSet) int {
+	ordinals := statefulsetutil.GetOrdinals(set)
+	if len(ordinals) == 0 {
+		return statefulsetutil.GetStartOrdinal(set) - 1
+	}
+	return ordinals[len(ordinals)-1]
+}
+
+func validatePersistentVolumeClaimRetentionPolicyType(policy appsv1alpha1.PersistentVolumeClaimRetentionPolicyType, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch policy {
+	case "", appsv1alpha1.RetainPersistentVolumeClaimRetentionPolicyType, appsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath, policy, []string{
+			string(appsv1alpha1.RetainPersistentVolumeClaimRetentionPolicyType),
+			string(appsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType),
+		}))
+	}
 	return allErrs
 }
 
@@ -27,6 +27,7 @@ import (
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func TestValidateStatefulSet(t *testing.T) {
@@ -78,6 +79,31 @@ func TestValidateStatefulSet(t *testing.T) {
 	maxUnavailable1 := intstr.FromInt(1)
 	maxUnavailable120Percent := intstr.FromString("120%")
 	successCases := []appsv1alpha1.StatefulSet{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy:     apps.OrderedReadyPodManagement,
+				Selector:                &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:                validPodTemplate.Template,
+				ServiceName:             "abc",
+				UpdateStrategy:          appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				ServiceManagementPolicy: appsv1alpha1.ManagedServiceManagementPolicyType,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				PodOverrides: []appsv1alpha1.StatefulSetPodOverride{
+					{Ordinals: "0", Patch: runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"abc","env":[{"name":"ROLE","value":"seed"}]}]}}`)}},
+					{Ordinals: "1-2", Patch: runtime.RawExtension{Raw: []byte(`{"metadata":{"annotations":{"role":"member"}}}`)}},
+				},
+			},
+		},
 		{
 			ObjectMeta: metav1.ObjectMeta{Name: "abc", Namespace: metav1.NamespaceDefault},
 			Spec: appsv1alpha1.StatefulSetSpec{
@@ -132,6 +158,20 @@ func TestValidateStatefulSet(t *testing.T) {
 					}()},
 			},
 		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				UpdateStrategy:      appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				ScaleStrategy:       &appsv1alpha1.StatefulSetScaleStrategy{MaxUnavailable: &maxUnavailable1},
+				PersistentVolumeClaimRetentionPolicy: &appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+					WhenDeleted: appsv1alpha1.RetainPersistentVolumeClaimRetentionPolicyType,
+					WhenScaled:  appsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType,
+				},
+			},
+		},
 	}
 
 	for i, successCase := range successCases {
@@ -401,6 +441,146 @@ func TestValidateStatefulSet(t *testing.T) {
 				UpdateStrategy:      appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
 			},
 		},
+		"scale maxUnavailable with parallel": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy: apps.ParallelPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				ScaleStrategy:       &appsv1alpha1.StatefulSetScaleStrategy{MaxUnavailable: &maxUnavailable1},
+			},
+		},
+		"invalid scale maxUnavailable": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				ScaleStrategy: &appsv1alpha1.StatefulSetScaleStrategy{MaxUnavailable: func() *intstr.IntOrString {
+					v := intstr.FromString("0%")
+					return &v
+				}()},
+			},
+		},
+		"invalid pvc retention policy": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				PersistentVolumeClaimRetentionPolicy: &appsv1alpha1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+					WhenDeleted: appsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType,
+					WhenScaled:  "foo",
+				},
+			},
+		},
+		"negative minReadySeconds": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				MinReadySeconds:     -1,
+			},
+		},
+		"invalid podOverrides ordinals": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				PodOverrides: []appsv1alpha1.StatefulSetPodOverride{
+					{Ordinals: "2-1", Patch: runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"role":"seed"}}}`)}},
+				},
+			},
+		},
+		"podOverrides patch breaks selector": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				PodOverrides: []appsv1alpha1.StatefulSetPodOverride{
+					{Ordinals: "1", Patch: runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"a":null}}}`)}},
+				},
+			},
+		},
+		"podOverrides patch sets invalid restartPolicy": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				PodOverrides: []appsv1alpha1.StatefulSetPodOverride{
+					{Ordinals: "0-1", Patch: runtime.RawExtension{Raw: []byte(`{"spec":{"restartPolicy":"Never"}}`)}},
+				},
+			},
+		},
+		"managed service without serviceName": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy:     apps.OrderedReadyPodManagement,
+				Selector:                &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:                validPodTemplate.Template,
+				Replicas:                &val3,
+				UpdateStrategy:          appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				ServiceManagementPolicy: appsv1alpha1.ManagedServiceManagementPolicyType,
+			},
+		},
+		"managed service with selector matchExpressions": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector: &metav1.LabelSelector{
+					MatchLabels:      validLabels,
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "c", Operator: metav1.LabelSelectorOpDoesNotExist}},
+				},
+				Template:                validPodTemplate.Template,
+				Replicas:                &val3,
+				ServiceName:             "abc",
+				UpdateStrategy:          appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				ServiceManagementPolicy: appsv1alpha1.ManagedServiceManagementPolicyType,
+			},
+		},
+		"invalid serviceManagementPolicy": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy:     apps.OrderedReadyPodManagement,
+				Selector:                &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:                validPodTemplate.Template,
+				Replicas:                &val3,
+				ServiceName:             "abc",
+				UpdateStrategy:          appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				ServiceManagementPolicy: "foo",
+			},
+		},
+		"empty podOverrides patch": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc-123", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.StatefulSetSpec{
+				PodManagementPolicy: apps.OrderedReadyPodManagement,
+				Selector:            &metav1.LabelSelector{MatchLabels: validLabels},
+				Template:            validPodTemplate.Template,
+				Replicas:            &val3,
+				UpdateStrategy:      appsv1alpha1.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType},
+				PodOverrides: []appsv1alpha1.StatefulSetPodOverride{
+					{Ordinals: "0"},
+				},
+			},
+		},
 	}
 
 	for k, v := range errorCases {
@@ -414,6 +594,7 @@ func TestValidateStatefulSet(t *testing.T) {
 			for i := range errs {
 				field := errs[i].Field
 				if !strings.HasPrefix(field, "spec.template.") &&
+					!strings.HasPrefix(field, "spec.podOverrides[0].patch.") &&
 					field != "metadata.name" &&
 					field != "metadata.namespace" &&
 					field != "spec.selector" &&
@@ -431,6 +612,14 @@ func TestValidateStatefulSet(t *testing.T) {
 					field != "spec.updateStrategy.rollingUpdate.podUpdatePolicy" &&
 					field != "spec.template.spec.readinessGates" &&
 					field != "spec.podManagementPolicy" &&
+					field != "spec.scaleStrategy.maxUnavailable" &&
+					field != "spec.persistentVolumeClaimRetentionPolicy.whenScaled" &&
+					field != "spec.minReadySeconds" &&
+					field != "spec.podOverrides[0].ordinals" &&
+					field != "spec.serviceName" &&
+					field != "spec.serviceManagementPolicy" &&
+					field != "spec.selector.matchExpressions" &&
+					field != "spec.podOverrides[0].patch" &&
 					field != "spec.template.spec.activeDeadlineSeconds" {
 					t.Errorf("%s: missing prefix for: %v", k, errs[i])
 				}
@@ -449,3 +638,46 @@ func setTestDefault(obj *appsv1alpha1.StatefulSet) {
 		*obj.Spec.RevisionHistoryLimit = 0
 	}
 }
+
+func TestValidateStatefulSetUpdateOrdinals(t *testing.T) {
+	newSet := func(start *int32, replicas int32, reserveOrdinals ...int) *appsv1alpha1.StatefulSet {
+		set := &appsv1alpha1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "abc", Namespace: metav1.NamespaceDefault, ResourceVersion: "1"},
+			Spec:       appsv1alpha1.StatefulSetSpec{Replicas: &replicas, ReserveOrdinals: reserveOrdinals},
+		}
+		if start != nil {
+			set.Spec.Ordinals = &appsv1alpha1.StatefulSetOrdinals{Start: *start}
+		}
+		return set
+	}
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	cases := []struct {
+		name     string
+		old      *appsv1alpha1.StatefulSet
+		new      *appsv1alpha1.StatefulSet
+		expected bool
+	}{
+		{name: "scale without start", old: newSet(nil, 5), new: newSet(nil, 3), expected: true},
+		{name: "set start to 0", old: newSet(nil, 5), new: newSet(int32Ptr(0), 5), expected: true},
+		{name: "scale with start", old: newSet(int32Ptr(5), 5), new: newSet(int32Ptr(5), 8), expected: true},
+		{name: "move ordinals out from start", old: newSet(int32Ptr(5), 5), new: newSet(int32Ptr(7), 3), expected: true},
+		{name: "move ordinals in from start", old: newSet(int32Ptr(5), 5), new: newSet(int32Ptr(3), 7), expected: true},
+		{name: "set start with ordinals moved out", old: newSet(nil, 5), new: newSet(int32Ptr(2), 3), expected: true},
+		{name: "shift all ordinals", old: newSet(int32Ptr(5), 5), new: newSet(int32Ptr(7), 5), expected: false},
+		{name: "unset start", old: newSet(int32Ptr(5), 5), new: newSet(nil, 5), expected: false},
+		{name: "move ordinals out from start with reserved ordinal", old: newSet(int32Ptr(5), 5, 6), new: newSet(int32Ptr(8), 3, 6), expected: true},
+		{name: "shift ordinals with reserved ordinal", old: newSet(int32Ptr(5), 5), new: newSet(int32Ptr(7), 3, 8), expected: false},
+		{name: "scale to zero with start", old: newSet(int32Ptr(5), 5), new: newSet(int32Ptr(10), 0), expected: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateStatefulSetUpdate(tc.new, tc.old)
+			if tc.expected && len(errs) != 0 {
+				t.Fatalf("expected success: %v", errs)
+			} else if !tc.expected && len(errs) == 0 {
+				t.Fatalf("expected failure")
+			}
+		})
+	}
+}
@@ -123,6 +123,26 @@ func validateUnitedDeploymentSpec(spec *appsv1alpha1.UnitedDeploymentSpec, fldPa
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("topology", "subsets"), sumReplicas, fmt.Sprintf("if replicas of all subsets are provided, the sum of indicated subset replicas %d should equal UnitedDeployment replicas %d", sumReplicas, expectedReplicas)))
 	}
 
+	switch spec.ServiceManagementPolicy {
+	case "", appsv1alpha1.ManualServiceManagementPolicyType:
+	case appsv1alpha1.ManagedServiceManagementPolicyType:
+		if (spec.Template.StatefulSetTemplate != nil && spec.Template.StatefulSetTemplate.Spec.ServiceName == "") ||
+			(spec.Template.AdvancedStatefulSetTemplate != nil && spec.Template.AdvancedStatefulSetTemplate.Spec.ServiceName == "") {
+			allErrs = append(allErrs, field.Required(fldPath.Child("template"), "serviceName is required when serviceManagementPolicy is Managed"))
+		}
+		if spec.Selector != nil && len(spec.Selector.MatchExpressions) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("selector", "matchExpressions"), "matchExpressions is not supported when serviceManagementPolicy is Managed"))
+		}
+		if spec.Template.AdvancedStatefulSetTemplate != nil &&
+			spec.Template.AdvancedStatefulSetTemplate.Spec.ServiceManagementPolicy == appsv1alpha1.ManagedServiceManagementPolicyType {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("template", "advancedStatefulSetTemplate", "spec", "serviceManagementPolicy"),
+				"the headless Service can not be managed by both UnitedDeployment and its subsets"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("serviceManagementPolicy"), spec.ServiceManagementPolicy,
+			[]string{string(appsv1alpha1.ManualServiceManagementPolicyType), string(appsv1alpha1.ManagedServiceManagementPolicyType)}))
+	}
+
 	if spec.UpdateStrategy.ManualUpdate != nil {
 		for subset := range spec.UpdateStrategy.ManualUpdate.Partitions {
 			if !subSetNames.Has(subset) {
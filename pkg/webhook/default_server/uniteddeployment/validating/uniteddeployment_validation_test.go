@@ -51,6 +51,25 @@ func TestValidateUnitedDeployment(t *testing.T) {
 	replicas3 := intstr.FromString("71%")
 	replicas4 := intstr.FromString("29%")
 	successCases := []appsv1alpha1.UnitedDeployment{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "abc", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.UnitedDeploymentSpec{
+				Replicas:                &val,
+				Selector:                &metav1.LabelSelector{MatchLabels: validLabels},
+				ServiceManagementPolicy: appsv1alpha1.ManagedServiceManagementPolicyType,
+				Template: appsv1alpha1.SubsetTemplate{
+					StatefulSetTemplate: &appsv1alpha1.StatefulSetTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: validLabels,
+						},
+						Spec: apps.StatefulSetSpec{
+							Template:    validPodTemplate.Template,
+							ServiceName: "abc",
+						},
+					},
+				},
+			},
+		},
 		{
 			ObjectMeta: metav1.ObjectMeta{Name: "abc", Namespace: metav1.NamespaceDefault},
 			Spec: appsv1alpha1.UnitedDeploymentSpec{
@@ -190,6 +209,86 @@ func TestValidateUnitedDeployment(t *testing.T) {
 	}
 
 	errorCases := map[string]appsv1alpha1.UnitedDeployment{
+		"managed service without serviceName": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.UnitedDeploymentSpec{
+				Replicas:                &val,
+				Selector:                &metav1.LabelSelector{MatchLabels: validLabels},
+				ServiceManagementPolicy: appsv1alpha1.ManagedServiceManagementPolicyType,
+				Template: appsv1alpha1.SubsetTemplate{
+					StatefulSetTemplate: &appsv1alpha1.StatefulSetTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: validLabels,
+						},
+						Spec: apps.StatefulSetSpec{
+							Template:    validPodTemplate.Template,
+							ServiceName: "",
+						},
+					},
+				},
+			},
+		},
+		"managed service with selector matchExpressions": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.UnitedDeploymentSpec{
+				Replicas: &val,
+				Selector: &metav1.LabelSelector{
+					MatchLabels:      validLabels,
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "c", Operator: metav1.LabelSelectorOpDoesNotExist}},
+				},
+				ServiceManagementPolicy: appsv1alpha1.ManagedServiceManagementPolicyType,
+				Template: appsv1alpha1.SubsetTemplate{
+					StatefulSetTemplate: &appsv1alpha1.StatefulSetTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: validLabels,
+						},
+						Spec: apps.StatefulSetSpec{
+							Template:    validPodTemplate.Template,
+							ServiceName: "abc",
+						},
+					},
+				},
+			},
+		},
+		"managed service by both UnitedDeployment and subsets": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.UnitedDeploymentSpec{
+				Replicas:                &val,
+				Selector:                &metav1.LabelSelector{MatchLabels: validLabels},
+				ServiceManagementPolicy: appsv1alpha1.ManagedServiceManagementPolicyType,
+				Template: appsv1alpha1.SubsetTemplate{
+					AdvancedStatefulSetTemplate: &appsv1alpha1.AdvancedStatefulSetTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: validLabels,
+						},
+						Spec: appsv1alpha1.StatefulSetSpec{
+							Template:                validPodTemplate.Template,
+							ServiceName:             "abc",
+							ServiceManagementPolicy: appsv1alpha1.ManagedServiceManagementPolicyType,
+						},
+					},
+				},
+			},
+		},
+		"invalid serviceManagementPolicy": {
+			ObjectMeta: metav1.ObjectMeta{Name: "abc", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.UnitedDeploymentSpec{
+				Replicas:                &val,
+				Selector:                &metav1.LabelSelector{MatchLabels: validLabels},
+				ServiceManagementPolicy: "foo",
+				Template: appsv1alpha1.SubsetTemplate{
+					StatefulSetTemplate: &appsv1alpha1.StatefulSetTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: validLabels,
+						},
+						Spec: apps.StatefulSetSpec{
+							Template:    validPodTemplate.Template,
+							ServiceName: "abc",
+						},
+					},
+				},
+			},
+		},
 		"no pod template label": {
 			ObjectMeta: metav1.ObjectMeta{Name: "abc", Namespace: metav1.NamespaceDefault},
 			Spec: appsv1alpha1.UnitedDeploymentSpec{
@@ -436,6 +535,8 @@ func TestValidateUnitedDeployment(t *testing.T) {
 					field != "spec.topology.subsets[0]" &&
 					field != "spec.topology.subsets[0].name" &&
 					field != "spec.updateStrategy.partitions" &&
+					field != "spec.serviceManagementPolicy" &&
+					field != "spec.selector.matchExpressions" &&
 					field != "spec.topology.subsets[0].nodeSelectorTerm.matchExpressions[0].values" {
 					t.Errorf("%s: missing prefix for: %v", k, errs[i])
 				}